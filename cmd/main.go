@@ -17,10 +17,12 @@ import (
 	"codematic/internal/infrastructure/db"
 	"codematic/internal/infrastructure/events"
 	"codematic/internal/infrastructure/events/kafka"
+	"codematic/internal/middleware"
 	"codematic/internal/router"
 
 	"codematic/internal/shared/utils"
 	"context"
+	"log"
 	"os"
 	"os/signal"
 	"syscall"
@@ -29,9 +31,12 @@ import (
 )
 
 func main() {
-	cfg := config.LoadAppConfig()
+	cfg, err := config.LoadAppConfig()
+	if err != nil {
+		log.Fatal("Failed to load app config: ", err)
+	}
 
-	zapLogger := config.InitLogger()
+	zapLogger := config.InitLogger(cfg.LogRedactKeys...)
 	defer zapLogger.Close()
 
 	redisCache := cache.InitRedis(cfg)
@@ -40,8 +45,9 @@ func main() {
 	store := db.InitDB(cfg, zapLogger.Logger)
 
 	JWTManager := utils.NewJWTManager(
-		cfg.JwtSecret,
 		cfg.RefreshTokenSecret,
+		cfg.AccessTokenTTL(),
+		cfg.RefreshTokenTTL(),
 	)
 
 	cacheManager := cache.NewRedisCacheManager(
@@ -53,6 +59,13 @@ func main() {
 
 	appEnv := router.InitRouterWithConfig(cfg, redisCache, zapLogger.Logger)
 
+	// Operational endpoints go on a separate internal listener when OPS_PORT is set
+	opsApp := appEnv
+	if cfg.OpsPort != "" && cfg.OpsPort != cfg.PORT {
+		opsApp = router.InitOpsRouter(zapLogger.Logger)
+	}
+	router.MountOpsRoutes(opsApp, middleware.OpsMiddleware(cfg, JWTManager, cacheManager))
+
 	// Initialize services
 	services := app.InitServices(
 		zapLogger.Logger,
@@ -64,7 +77,7 @@ func main() {
 	)
 
 	// Initialize scheduler
-	app.InitScheduler(zapLogger.Logger)
+	sched := app.InitScheduler(zapLogger.Logger, services, cfg)
 
 	// Start consumers
 	app.StartConsumers(context.Background(), cfg.KAFKA_BROKER, services, zapLogger.Logger)
@@ -72,27 +85,49 @@ func main() {
 	env := handler.NewEnvironment(
 		cfg,
 		appEnv,
+		opsApp,
 		store,
 		redisCache,
 		zapLogger.Logger,
 		JWTManager,
 		cacheManager,
 		kafkaProducer,
+		sched,
 		services,
 	)
 
+	// Wraps every route so requests made with impersonation tokens are audited
+	appEnv.Use(middleware.ImpersonationAudit(services.Audit))
+
 	router.InitHandlers(env, []handler.IHandler{
 		&handler.Auth{},
+		&handler.JWKS{},
+		&handler.User{},
+		&handler.Invites{},
 		&handler.Tenants{},
 		&handler.Wallet{},
 		&handler.Webhook{},
 		&handler.Transactions{},
+		&handler.APIKeys{},
+		&handler.MFA{},
+		&handler.PIN{},
+		&handler.Roles{},
+		&handler.Audit{},
+		&handler.Reconciliation{},
+		&handler.Fees{},
+		&handler.Ops{},
 	})
 
 	go func() {
 		router.RunWithGracefulShutdown(appEnv, cfg.PORT, zapLogger.Logger)
 	}()
 
+	if opsApp != appEnv {
+		go func() {
+			router.RunWithGracefulShutdown(opsApp, cfg.OpsPort, zapLogger.Logger)
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -3,8 +3,19 @@ package app
 import (
 	"codematic/internal/config"
 	"codematic/internal/consumers"
+	"codematic/internal/domain/apikeys"
+	"codematic/internal/domain/audit"
 	"codematic/internal/domain/auth"
+	"codematic/internal/domain/exports"
+	"codematic/internal/domain/fees"
+	"codematic/internal/domain/invites"
+	"codematic/internal/domain/mfa"
+	"codematic/internal/domain/pin"
 	"codematic/internal/domain/provider"
+	"codematic/internal/domain/rbac"
+	"codematic/internal/domain/reconciliation"
+	"codematic/internal/domain/signingkeys"
+	"codematic/internal/domain/statements"
 	"codematic/internal/domain/tenants"
 	"codematic/internal/domain/transactions"
 	"codematic/internal/domain/user"
@@ -13,6 +24,8 @@ import (
 	"codematic/internal/infrastructure/cache"
 	"codematic/internal/infrastructure/db"
 	"codematic/internal/infrastructure/events/kafka"
+	"codematic/internal/infrastructure/notifications"
+	"codematic/internal/infrastructure/storage"
 	"codematic/internal/scheduler"
 	"codematic/internal/scheduler/jobs"
 	"codematic/internal/shared/utils"
@@ -22,13 +35,24 @@ import (
 )
 
 type Services struct {
-	Wallet       wallet.Service
-	User         user.Service
-	Provider     provider.Service
-	Transactions transactions.Service
-	Tenants      tenants.Service
-	Auth         auth.Service
-	Webhook      webhook.Service
+	APIKeys        apikeys.Service
+	Audit          audit.Service
+	Wallet         wallet.Service
+	User           user.Service
+	Provider       provider.Service
+	Transactions   transactions.Service
+	Tenants        tenants.Service
+	Auth           auth.Service
+	Exports        exports.Service
+	Fees           fees.Service
+	MFA            mfa.Service
+	PIN            pin.Service
+	RBAC           rbac.Service
+	Reconciliation reconciliation.Service
+	Invites        invites.Service
+	SigningKeys    signingkeys.Service
+	Statements     statements.Service
+	Webhook        webhook.Service
 }
 
 func InitServices(
@@ -42,6 +66,14 @@ func InitServices(
 
 	logger.Info("initializing services...")
 
+	// Access tokens cannot be issued or verified until a signing key is loaded
+	signingKeysService := signingkeys.NewService(store, jwtManager, cfg, logger)
+	if err := signingKeysService.Rotate(context.Background()); err != nil {
+		logger.Fatal("failed to load JWT signing keys", zap.Error(err))
+	}
+
+	auditService := audit.NewService(store, logger)
+
 	providerService := provider.NewService(
 		store,
 		cacheManager,
@@ -53,22 +85,46 @@ func InitServices(
 
 	tenantsService := tenants.NewService(store, jwtManager, logger)
 
+	feesService := fees.NewService(store, providerService, logger)
+
 	walletService := wallet.NewService(
 		logger,
 		providerService,
 		userService,
+		feesService,
 		store,
 		kafkaProducer,
 		cacheManager,
+		auditService,
 	)
 
+	mfaService := mfa.NewService(store, cfg, logger)
+
+	rbacService := rbac.NewService(store, userService, logger)
+
+	mailer := notifications.NewMailer(cfg, logger)
+
 	authService := auth.NewService(
 		store,
 		userService,
 		walletService,
 		tenantsService,
+		mfaService,
+		rbacService,
 		cacheManager,
 		jwtManager,
+		mailer,
+		cfg, logger,
+	)
+
+	pinService := pin.NewService(store, userService, cacheManager, mailer, cfg, logger)
+
+	invitesService := invites.NewService(
+		store,
+		authService,
+		userService,
+		tenantsService,
+		mailer,
 		cfg, logger,
 	)
 
@@ -81,20 +137,41 @@ func InitServices(
 
 	transactionsService := transactions.NewService(store, cacheManager)
 
+	blobStore := storage.NewBlobStore(cfg, logger)
+
+	exportsService := exports.NewService(store, transactionsService, blobStore, cfg, logger)
+
+	statementsService := statements.NewService(store, blobStore, mailer, cfg, logger)
+
+	apiKeysService := apikeys.NewService(store, logger)
+
+	reconciliationService := reconciliation.NewService(store, providerService, logger)
+
 	logger.Info("services initialized.")
 
 	return &Services{
-		Wallet:       walletService,
-		User:         userService,
-		Provider:     providerService,
-		Tenants:      tenantsService,
-		Auth:         authService,
-		Transactions: transactionsService,
-		Webhook:      webhookService,
+		Wallet:         walletService,
+		User:           userService,
+		Provider:       providerService,
+		Tenants:        tenantsService,
+		Auth:           authService,
+		MFA:            mfaService,
+		PIN:            pinService,
+		RBAC:           rbacService,
+		Reconciliation: reconciliationService,
+		Invites:        invitesService,
+		SigningKeys:    signingKeysService,
+		Statements:     statementsService,
+		Transactions:   transactionsService,
+		Exports:        exportsService,
+		Fees:           feesService,
+		Webhook:        webhookService,
+		APIKeys:        apiKeysService,
+		Audit:          auditService,
 	}
 }
 
-func InitScheduler(logger *zap.Logger) *scheduler.Scheduler {
+func InitScheduler(logger *zap.Logger, services *Services, cfg *config.Config) *scheduler.Scheduler {
 
 	logger.Info("initializing scheduler...")
 
@@ -105,6 +182,17 @@ func InitScheduler(logger *zap.Logger) *scheduler.Scheduler {
 
 	jobList := []scheduler.Job{
 		jobs.HelloJob{},
+		jobs.SigningKeysJob{Service: services.SigningKeys, Logger: logger},
+		jobs.TransactionExportsJob{Service: services.Exports, Logger: logger},
+		jobs.WalletStatementsJob{Service: services.Statements, Logger: logger},
+		jobs.DepositReconciliationJob{
+			Service:     services.Wallet,
+			VerifyAfter: cfg.DepositVerifyAfter,
+			ExpireAfter: cfg.DepositExpireAfter,
+			Logger:      logger,
+		},
+		jobs.SettlementReconciliationJob{Service: services.Reconciliation, Logger: logger},
+		jobs.ReversalRetryJob{Service: services.Wallet, RetryAfter: cfg.ReversalRetryAfter, Logger: logger},
 	}
 
 	if err := sched.RegisterJobs(context.Background(), jobList); err != nil {
@@ -125,4 +213,8 @@ func StartConsumers(ctx context.Context, broker string, services *Services, logg
 	consumers.StartWalletPaystackConsumer(ctx, broker, services.Wallet, logger)
 
 	logger.Info("wallet Paystack consumer started.", zap.String("consumer", "wallet_paystack"))
+
+	consumers.StartWalletReversalConsumer(ctx, broker, services.Webhook, logger)
+
+	logger.Info("wallet reversal webhook consumer started.", zap.String("consumer", "wallet_reversal_webhooks"))
 }
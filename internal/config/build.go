@@ -0,0 +1,50 @@
+package config
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, injected at link time, e.g.
+//
+//	go build -ldflags="-X 'codematic/internal/config.GitHash=abc123'" ./cmd
+var (
+	Version   = "dev"
+	GitHash   = ""
+	BuildTime = ""
+)
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitHash   string `json:"git_hash"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// GetBuildInfo returns the build metadata of the running binary, falling back
+// to the VCS information embedded by the Go toolchain when no ldflags were set.
+func GetBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		GitHash:   GitHash,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.GitHash == "" {
+					info.GitHash = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	return info
+}
@@ -2,12 +2,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 )
 
 func parseEnv() error {
@@ -35,41 +40,201 @@ func parseEnv() error {
 	return fmt.Errorf("missing env vars: %v", missing)
 }
 
-func LoadAppConfig() *Config {
-	env := parseEnv()
-	if env != nil {
-		log.Fatal("Failed to load app config:", env)
+// LoadAppConfig reads the configuration from the environment and .env. Unset
+// values fall back to defaults; malformed ones are reported as an error.
+func LoadAppConfig() (*Config, error) {
+	if err := parseEnv(); err != nil {
+		return nil, err
 	}
 
-	jwtExpiry, _ := strconv.ParseInt(os.Getenv("JWT_EXPIRY"), 10, 64)
+	jwtExpiry, _ := strconv.ParseInt(os.Getenv("JWT_TOKEN_EXPIRY"), 10, 64)
 
-	if jwtExpiry == 0 {
+	if jwtExpiry <= 0 {
 		jwtExpiry = 1 // Default to 1 hour
 	}
 
-	jwtRefreshExpiry, _ := strconv.ParseInt(os.Getenv("JWT_EXPIRY"), 10, 64)
-	if jwtRefreshExpiry == 0 {
+	jwtRefreshExpiry, _ := strconv.ParseInt(os.Getenv("JWT_TOKEN_REFRESH_EXPIRY"), 10, 64)
+	if jwtRefreshExpiry <= 0 {
 		jwtRefreshExpiry = 7 // Default to 1 week
 	}
 
+	jwtSigningAlg := os.Getenv("JWT_SIGNING_ALG")
+	if jwtSigningAlg == "" {
+		jwtSigningAlg = "EdDSA"
+	}
+	if jwtSigningAlg != "EdDSA" && jwtSigningAlg != "RS256" {
+		return nil, errors.New("JWT_SIGNING_ALG must be EdDSA or RS256")
+	}
+
+	jwtKeyEncryptionKey := os.Getenv("JWT_KEY_ENCRYPTION_KEY")
+	if jwtKeyEncryptionKey == "" {
+		jwtKeyEncryptionKey = os.Getenv("JWT_SECRET")
+	}
+
+	maxSessions, _ := strconv.Atoi(os.Getenv("MAX_SESSIONS_PER_USER"))
+	if maxSessions <= 0 {
+		maxSessions = 5
+	}
+
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = "Codematic"
+	}
+
+	mfaKey := os.Getenv("MFA_ENCRYPTION_KEY")
+	if mfaKey == "" {
+		mfaKey = os.Getenv("JWT_SECRET")
+	}
+
+	stepUpAmount, err := decimal.NewFromString(os.Getenv("MFA_STEP_UP_WITHDRAWAL_AMOUNT"))
+	if err != nil {
+		stepUpAmount = decimal.Zero
+	}
+
+	lockoutThreshold, _ := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_THRESHOLD"))
+	if lockoutThreshold <= 0 {
+		lockoutThreshold = 5
+	}
+
+	pinMaxAttempts, _ := strconv.Atoi(os.Getenv("TRANSACTION_PIN_MAX_ATTEMPTS"))
+	if pinMaxAttempts <= 0 {
+		pinMaxAttempts = 5
+	}
+
+	exportSigningKey := os.Getenv("EXPORT_SIGNING_KEY")
+	if exportSigningKey == "" {
+		exportSigningKey = os.Getenv("JWT_SECRET")
+	}
+
+	apiBaseURL := strings.TrimRight(os.Getenv("API_BASE_URL"), "/")
+	if apiBaseURL == "" {
+		apiBaseURL = "http://localhost:" + os.Getenv("PORT")
+	}
+	if u, err := url.Parse(apiBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("API_BASE_URL: %q is not an absolute http(s) URL", apiBaseURL)
+	}
+
+	var rateLimits rateLimitParser
+
 	config := Config{
-		KAFKA_BROKER:          os.Getenv("KAFKA_BROKER"),
-		PostgresDB:            os.Getenv("POSTGRES_DB"),
-		PostgresUser:          os.Getenv("POSTGRES_USER"),
-		PostgresPass:          os.Getenv("POSTGRES_PASSWORD"),
-		PostgresDSN:           os.Getenv("POSTGRES_DSN"),
-		RedisAddr:             os.Getenv("REDIS_ADDR"),
-		RedisPassword:         os.Getenv("REDIS_PASSWORD"),
-		PORT:                  os.Getenv("PORT"),
-		ORIGINS:               os.Getenv("ORIGINS"),
-		PostgresHost:          os.Getenv("POSTGRES_HOST"),
-		PostgresPort:          os.Getenv("POSTGRES_PORT"),
-		JwtSecret:             os.Getenv("JWT_SECRET"),
-		RefreshTokenSecret:    os.Getenv("REFRESH_TOKEN_SECRET"),
-		EnableDBQueryLogging:  os.Getenv("ENABLE_DB_QUERY_LOGGING") == "true",
-		JwtTokenRefreshExpiry: jwtRefreshExpiry,
-		JwtTokenExpiry:        jwtExpiry,
-	}
-
-	return &config
+		KAFKA_BROKER:              os.Getenv("KAFKA_BROKER"),
+		PostgresDB:                os.Getenv("POSTGRES_DB"),
+		PostgresUser:              os.Getenv("POSTGRES_USER"),
+		PostgresPass:              os.Getenv("POSTGRES_PASSWORD"),
+		PostgresDSN:               os.Getenv("POSTGRES_DSN"),
+		RedisAddr:                 os.Getenv("REDIS_ADDR"),
+		RedisPassword:             os.Getenv("REDIS_PASSWORD"),
+		PORT:                      os.Getenv("PORT"),
+		ORIGINS:                   os.Getenv("ORIGINS"),
+		PostgresHost:              os.Getenv("POSTGRES_HOST"),
+		PostgresPort:              os.Getenv("POSTGRES_PORT"),
+		JwtSecret:                 os.Getenv("JWT_SECRET"),
+		RefreshTokenSecret:        os.Getenv("REFRESH_TOKEN_SECRET"),
+		EnableDBQueryLogging:      os.Getenv("ENABLE_DB_QUERY_LOGGING") == "true",
+		JwtTokenRefreshExpiry:     jwtRefreshExpiry,
+		JwtTokenExpiry:            jwtExpiry,
+		JwtSigningAlgorithm:       jwtSigningAlg,
+		JwtKeyRotationInterval:    parseDuration(os.Getenv("JWT_KEY_ROTATION_INTERVAL"), 30*24*time.Hour),
+		JwtKeyEncryptionKey:       jwtKeyEncryptionKey,
+		OpsPort:                   os.Getenv("OPS_PORT"),
+		OpsBasicAuthUser:          os.Getenv("OPS_BASIC_AUTH_USER"),
+		OpsBasicAuthPass:          os.Getenv("OPS_BASIC_AUTH_PASSWORD"),
+		OpsAllowedIPs:             splitList(os.Getenv("OPS_ALLOWED_IPS")),
+		LogRedactKeys:             splitList(os.Getenv("LOG_REDACT_KEYS")),
+		MaxSessionsPerUser:        maxSessions,
+		MailDriver:                os.Getenv("MAIL_DRIVER"),
+		MailFrom:                  os.Getenv("MAIL_FROM"),
+		MailOutboxDir:             os.Getenv("MAIL_OUTBOX_DIR"),
+		SMTPHost:                  os.Getenv("SMTP_HOST"),
+		SMTPPort:                  os.Getenv("SMTP_PORT"),
+		SMTPUsername:              os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:              os.Getenv("SMTP_PASSWORD"),
+		AppBaseURL:                os.Getenv("APP_BASE_URL"),
+		APIBaseURL:                apiBaseURL,
+		InviteTTL:                 parseDuration(os.Getenv("INVITE_TTL"), 72*time.Hour),
+		MFAIssuer:                 mfaIssuer,
+		MFAEncryptionKey:          mfaKey,
+		MFAStepUpWithdrawalAmount: stepUpAmount,
+		RateLimitLoginIP:          rateLimits.parse("RATE_LIMIT_LOGIN_IP", RateLimit{20, time.Minute}),
+		RateLimitLoginEmail:       rateLimits.parse("RATE_LIMIT_LOGIN_EMAIL", RateLimit{5, time.Minute}),
+		RateLimitLoginTenant:      rateLimits.parse("RATE_LIMIT_LOGIN_TENANT", RateLimit{200, time.Minute}),
+		RateLimitWithdraw:         rateLimits.parse("RATE_LIMIT_WITHDRAW", RateLimit{5, time.Minute}),
+		RateLimitTransfer:         rateLimits.parse("RATE_LIMIT_TRANSFER", RateLimit{10, time.Minute}),
+		RateLimitRecipientLookup:  rateLimits.parse("RATE_LIMIT_RECIPIENT_LOOKUP", RateLimit{30, time.Minute}),
+		RateLimitPIN:              rateLimits.parse("RATE_LIMIT_PIN", RateLimit{10, time.Minute}),
+		LoginLockoutThreshold:     lockoutThreshold,
+		LoginLockoutBase:          parseDuration(os.Getenv("LOGIN_LOCKOUT_BASE"), time.Minute),
+		LoginLockoutMax:           parseDuration(os.Getenv("LOGIN_LOCKOUT_MAX"), time.Hour),
+		TransactionPINMaxAttempts: pinMaxAttempts,
+		TransactionPINLockout:     parseDuration(os.Getenv("TRANSACTION_PIN_LOCKOUT"), 30*time.Minute),
+		ImpersonationMaxTTL:       parseDuration(os.Getenv("IMPERSONATION_MAX_TTL"), 15*time.Minute),
+		ImpersonationTenantAdmins: os.Getenv("IMPERSONATION_TENANT_ADMINS") == "true",
+		StorageDriver:             os.Getenv("STORAGE_DRIVER"),
+		StorageLocalDir:           os.Getenv("STORAGE_LOCAL_DIR"),
+		ExportRetention:           parseDuration(os.Getenv("EXPORT_RETENTION"), 24*time.Hour),
+		ExportLinkTTL:             parseDuration(os.Getenv("EXPORT_LINK_TTL"), 15*time.Minute),
+		ExportSigningKey:          exportSigningKey,
+		StatementEmailEnabled:     os.Getenv("STATEMENT_EMAIL_ENABLED") == "true",
+		DepositVerifyAfter:        parseDuration(os.Getenv("DEPOSIT_VERIFY_AFTER"), 15*time.Minute),
+		DepositExpireAfter:        parseDuration(os.Getenv("DEPOSIT_EXPIRE_AFTER"), 24*time.Hour),
+		ReversalRetryAfter:        parseDuration(os.Getenv("REVERSAL_RETRY_AFTER"), 15*time.Minute),
+	}
+	if err := errors.Join(rateLimits.errs...); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// splitList parses a comma separated env value, dropping empty entries.
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// rateLimitParser reads RATE_LIMIT_* variables, collecting every malformed one
+// so they can be reported together
+type rateLimitParser struct {
+	errs []error
+}
+
+// parse reads "<requests>/<window>" from the env var key, e.g. "5/1m"; "0"
+// disables the limit and an unset value uses fallback.
+func (p *rateLimitParser) parse(key string, fallback RateLimit) RateLimit {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	if value == "0" {
+		return RateLimit{}
+	}
+
+	count, window, ok := strings.Cut(value, "/")
+	limit, err := strconv.Atoi(strings.TrimSpace(count))
+	if !ok || err != nil || limit < 0 {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid rate limit %q, want <requests>/<window> such as 5/1m", key, value))
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || duration <= 0 {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid rate limit window %q", key, value))
+		return fallback
+	}
+
+	return RateLimit{Limit: limit, Window: duration}
+}
+
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
 }
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"codematic/internal/shared/utils"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultRedactKeys are field/JSON key segments whose values are always redacted.
+// Extra segments can be added with LOG_REDACT_KEYS.
+var DefaultRedactKeys = []string{
+	"authorization",
+	"cookie",
+	"password",
+	"password_hash",
+	"secret",
+	"secret_key",
+	"api_key",
+	"apikey",
+	"token",
+	"signature",
+	"cvv",
+	"pin",
+	"otp",
+	"private_key",
+	"encryption_key",
+}
+
+var (
+	emailKeys = []string{"email", "customer_email"}
+	phoneKeys = []string{"phone", "phone_number", "mobile", "msisdn"}
+	cardKeys  = []string{"card", "card_number", "pan"}
+
+	bearerPattern    = regexp.MustCompile(`(?i)(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`)
+	apiKeyPattern    = regexp.MustCompile(`\b(cm_sk_[0-9a-f]+_|(sk|rk)_(live|test)_)[A-Za-z0-9]+`)
+	flwKeyPattern    = regexp.MustCompile(`FLWSECK(_TEST)?-?[A-Za-z0-9\-]+`)
+	jwtPattern       = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+	emailPattern     = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	cardPattern      = regexp.MustCompile(`\b\d{13,19}\b`)
+	jsonValuePattern = regexp.MustCompile(`"([A-Za-z0-9_\-]+)"\s*:\s*"([^"]*)"`)
+)
+
+// Redactor scrubs secrets and PII from log messages and fields
+type Redactor struct {
+	keys []string
+}
+
+func NewRedactor(extraKeys ...string) *Redactor {
+	keys := make([]string, 0, len(DefaultRedactKeys)+len(extraKeys))
+	for _, k := range append(append([]string{}, DefaultRedactKeys...), extraKeys...) {
+		if k = normalizeKey(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return &Redactor{keys: keys}
+}
+
+// IsSensitiveKey reports whether values stored under key must be redacted
+func (r *Redactor) IsSensitiveKey(key string) bool {
+	return matchesKey(normalizeKey(key), r.keys)
+}
+
+// String scrubs credentials, card numbers and emails from free text
+func (r *Redactor) String(s string) string {
+	if s == "" {
+		return s
+	}
+
+	s = jsonValuePattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := jsonValuePattern.FindStringSubmatch(m)
+		masked, changed := r.maskValue(parts[1], parts[2])
+		if !changed {
+			return m
+		}
+		return `"` + parts[1] + `":"` + masked + `"`
+	})
+	s = bearerPattern.ReplaceAllString(s, "$1 "+RedactedValue)
+	s = jwtPattern.ReplaceAllString(s, RedactedValue)
+	s = apiKeyPattern.ReplaceAllString(s, RedactedValue)
+	s = flwKeyPattern.ReplaceAllString(s, RedactedValue)
+	s = emailPattern.ReplaceAllStringFunc(s, utils.MaskEmail)
+	s = cardPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if utils.IsLuhnValid(m) {
+			return utils.MaskCard(m)
+		}
+		return m
+	})
+
+	return s
+}
+
+// Fields returns a redacted copy of fields
+func (r *Redactor) Fields(fields []zapcore.Field) []zapcore.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		out[i] = r.field(f)
+	}
+	return out
+}
+
+func (r *Redactor) field(f zapcore.Field) zapcore.Field {
+	if r.IsSensitiveKey(f.Key) && f.Type != zapcore.ErrorType {
+		return zap.String(f.Key, RedactedValue)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if masked, changed := r.maskValue(f.Key, f.String); changed {
+			return zap.String(f.Key, masked)
+		}
+		return zap.String(f.Key, r.String(f.String))
+
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return zap.String(f.Key, r.String(err.Error()))
+		}
+
+	case zapcore.StringerType, zapcore.ReflectType:
+		if f.Interface == nil {
+			return f
+		}
+		raw, err := json.Marshal(f.Interface)
+		if err != nil {
+			return f
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return f
+		}
+		return zap.Any(f.Key, r.value("", value))
+	}
+
+	return f
+}
+
+// value walks decoded JSON, redacting by key and scrubbing strings
+func (r *Redactor) value(key string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if r.IsSensitiveKey(k) {
+				val[k] = RedactedValue
+				continue
+			}
+			val[k] = r.value(k, item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = r.value(key, item)
+		}
+		return val
+	case string:
+		if masked, changed := r.maskValue(key, val); changed {
+			return masked
+		}
+		return r.String(val)
+	}
+	return v
+}
+
+// maskValue applies key based redaction/masking to a single string value
+func (r *Redactor) maskValue(key, value string) (string, bool) {
+	k := normalizeKey(key)
+	switch {
+	case matchesKey(k, r.keys):
+		return RedactedValue, true
+	case matchesKey(k, emailKeys):
+		return utils.MaskEmail(value), true
+	case matchesKey(k, phoneKeys):
+		return utils.MaskPhone(value), true
+	case matchesKey(k, cardKeys):
+		return utils.MaskCard(value), true
+	}
+	return value, false
+}
+
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ':', ' ', '.':
+			return '_'
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(key)))
+}
+
+// matchesKey matches whole underscore separated segments, so "token" matches
+// "refresh_token" and "header_x_token" but not "tokenizer".
+func matchesKey(key string, patterns []string) bool {
+	for _, p := range patterns {
+		if key == p ||
+			strings.HasPrefix(key, p+"_") ||
+			strings.HasSuffix(key, "_"+p) ||
+			strings.Contains(key, "_"+p+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// redactingCore wraps a zapcore.Core and scrubs every entry before it is encoded
+type redactingCore struct {
+	zapcore.Core
+	redactor *Redactor
+}
+
+func newRedactingCore(core zapcore.Core, redactor *Redactor) zapcore.Core {
+	return &redactingCore{Core: core, redactor: redactor}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{
+		Core:     c.Core.With(c.redactor.Fields(fields)),
+		redactor: c.redactor,
+	}
+}
+
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.redactor.String(ent.Message)
+	return c.Core.Write(ent, c.redactor.Fields(fields))
+}
@@ -23,10 +23,12 @@ var (
 	once          sync.Once
 )
 
-// InitLogger initializes a new logger with file and console output
-func InitLogger() *LoggerManager {
+// InitLogger initializes a new logger with file and console output.
+// Every entry is passed through a Redactor; redactKeys extends the default
+// list of sensitive field names and only takes effect on the first call.
+func InitLogger(redactKeys ...string) *LoggerManager {
 	once.Do(func() {
-		loggerManager = initLoggerInternal()
+		loggerManager = initLoggerInternal(NewRedactor(redactKeys...))
 	})
 	return loggerManager
 }
@@ -40,7 +42,7 @@ func GetLogger() *zap.Logger {
 }
 
 // initLoggerInternal creates and configures the logger
-func initLoggerInternal() *LoggerManager {
+func initLoggerInternal(redactor *Redactor) *LoggerManager {
 	logPath := "logs/app.log"
 	logDir := filepath.Dir(logPath)
 
@@ -56,20 +58,7 @@ func initLoggerInternal() *LoggerManager {
 	}
 	fileSyncer := zapcore.AddSync(fileWriter)
 
-	// JSON encoder for file
-	jsonEncoder := zapcore.EncoderConfig{
-		TimeKey:       "time",
-		LevelKey:      "level",
-		NameKey:       "logger",
-		CallerKey:     "caller",
-		MessageKey:    "msg",
-		StacktraceKey: "stacktrace",
-		EncodeLevel:   zapcore.CapitalLevelEncoder,
-		EncodeTime:    zapcore.ISO8601TimeEncoder,
-		EncodeCaller:  zapcore.ShortCallerEncoder,
-		LineEnding:    zapcore.DefaultLineEnding,
-	}
-	fileCore := zapcore.NewCore(zapcore.NewJSONEncoder(jsonEncoder), fileSyncer, zap.DebugLevel)
+	fileCore := newFileCore(fileSyncer)
 
 	// Console encoder with color and custom format
 	consoleEncoder := zapcore.EncoderConfig{
@@ -88,8 +77,8 @@ func initLoggerInternal() *LoggerManager {
 	}
 	consoleCore := zapcore.NewCore(zapcore.NewConsoleEncoder(consoleEncoder), zapcore.AddSync(os.Stdout), zap.DebugLevel)
 
-	// Combine both cores
-	core := zapcore.NewTee(fileCore, consoleCore)
+	// Combine both cores and scrub secrets/PII before anything is encoded
+	core := newRedactingCore(zapcore.NewTee(fileCore, consoleCore), redactor)
 
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 
@@ -100,6 +89,23 @@ func initLoggerInternal() *LoggerManager {
 	}
 }
 
+// newFileCore encodes entries as JSON lines, the format of logs/app.log
+func newFileCore(w zapcore.WriteSyncer) zapcore.Core {
+	jsonEncoder := zapcore.EncoderConfig{
+		TimeKey:       "time",
+		LevelKey:      "level",
+		NameKey:       "logger",
+		CallerKey:     "caller",
+		MessageKey:    "msg",
+		StacktraceKey: "stacktrace",
+		EncodeLevel:   zapcore.CapitalLevelEncoder,
+		EncodeTime:    zapcore.ISO8601TimeEncoder,
+		EncodeCaller:  zapcore.ShortCallerEncoder,
+		LineEnding:    zapcore.DefaultLineEnding,
+	}
+	return zapcore.NewCore(zapcore.NewJSONEncoder(jsonEncoder), w, zap.DebugLevel)
+}
+
 func (lm *LoggerManager) Close() error {
 	lm.mu.Lock()
 	defer lm.mu.Unlock()
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type Config struct {
 	KAFKA_BROKER          string `mapstructure:"KAFKA_BROKER"`
 	PostgresDB            string `mapstructure:"POSTGRES_DB"`
@@ -17,7 +23,124 @@ type Config struct {
 	JwtTokenRefreshExpiry int64  `mapstructure:"JWT_TOKEN_REFRESH_EXPIRY"`
 	JwtTokenExpiry        int64  `mapstructure:"JWT_TOKEN_EXPIRY"`
 	EnableDBQueryLogging  bool   `mapstructure:"ENABLE_DB_QUERY_LOGGING"`
+	MaxSessionsPerUser    int    `mapstructure:"MAX_SESSIONS_PER_USER"`
+
+	// Access tokens are signed with JwtSigningAlgorithm (EdDSA or RS256) keys
+	// that rotate every JwtKeyRotationInterval; private keys are encrypted at
+	// rest with JwtKeyEncryptionKey
+	JwtSigningAlgorithm    string        `mapstructure:"JWT_SIGNING_ALG"`
+	JwtKeyRotationInterval time.Duration `mapstructure:"JWT_KEY_ROTATION_INTERVAL"`
+	JwtKeyEncryptionKey    string        `mapstructure:"JWT_KEY_ENCRYPTION_KEY"`
 
 	PstkSecretHash string `mapstructure:"PSTK_SECRET_HASH"`
 	FlwSecretHash  string `mapstructure:"FLW_SECRET_HASH"`
+
+	// Operational endpoints (metrics, dashboard, swagger, ops API)
+	OpsPort          string   `mapstructure:"OPS_PORT"`
+	OpsBasicAuthUser string   `mapstructure:"OPS_BASIC_AUTH_USER"`
+	OpsBasicAuthPass string   `mapstructure:"OPS_BASIC_AUTH_PASSWORD"`
+	OpsAllowedIPs    []string `mapstructure:"OPS_ALLOWED_IPS"`
+
+	// Outgoing email: MAIL_DRIVER=smtp sends via SMTP, otherwise mail is
+	// written to MAIL_OUTBOX_DIR
+	MailDriver    string `mapstructure:"MAIL_DRIVER"`
+	MailFrom      string `mapstructure:"MAIL_FROM"`
+	MailOutboxDir string `mapstructure:"MAIL_OUTBOX_DIR"`
+	SMTPHost      string `mapstructure:"SMTP_HOST"`
+	SMTPPort      string `mapstructure:"SMTP_PORT"`
+	SMTPUsername  string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword  string `mapstructure:"SMTP_PASSWORD"`
+
+	// Public URL of the frontend, used to build links in emails
+	AppBaseURL string `mapstructure:"APP_BASE_URL"`
+
+	// Public URL of this API, used to build links that point back at it
+	APIBaseURL string `mapstructure:"API_BASE_URL"`
+
+	// How long an emailed tenant invite stays valid
+	InviteTTL time.Duration `mapstructure:"INVITE_TTL"`
+
+	// Two-factor authentication: issuer shown in authenticator apps, key used
+	// to encrypt TOTP secrets at rest, and the withdrawal amount from which a
+	// fresh TOTP code is required (0 disables step-up)
+	MFAIssuer                 string          `mapstructure:"MFA_ISSUER"`
+	MFAEncryptionKey          string          `mapstructure:"MFA_ENCRYPTION_KEY"`
+	MFAStepUpWithdrawalAmount decimal.Decimal `mapstructure:"MFA_STEP_UP_WITHDRAWAL_AMOUNT"`
+
+	// Sliding-window rate limits, configured as "<requests>/<window>" (e.g.
+	// "5/1m"); a zero limit disables the rule
+	RateLimitLoginIP     RateLimit `mapstructure:"RATE_LIMIT_LOGIN_IP"`
+	RateLimitLoginEmail  RateLimit `mapstructure:"RATE_LIMIT_LOGIN_EMAIL"`
+	RateLimitLoginTenant RateLimit `mapstructure:"RATE_LIMIT_LOGIN_TENANT"`
+	RateLimitWithdraw    RateLimit `mapstructure:"RATE_LIMIT_WITHDRAW"`
+	RateLimitTransfer    RateLimit `mapstructure:"RATE_LIMIT_TRANSFER"`
+	// RateLimitRecipientLookup limits P2P recipient lookups per user, so
+	// users cannot be enumerated by email or phone
+	RateLimitRecipientLookup RateLimit `mapstructure:"RATE_LIMIT_RECIPIENT_LOOKUP"`
+	// RateLimitPIN limits every /pin request per user, on top of the wrong
+	// PIN lockout
+	RateLimitPIN RateLimit `mapstructure:"RATE_LIMIT_PIN"`
+
+	// Accounts lock for LoginLockoutBase after LoginLockoutThreshold
+	// consecutive failed logins, doubling with each further failure up to
+	// LoginLockoutMax
+	LoginLockoutThreshold int           `mapstructure:"LOGIN_LOCKOUT_THRESHOLD"`
+	LoginLockoutBase      time.Duration `mapstructure:"LOGIN_LOCKOUT_BASE"`
+	LoginLockoutMax       time.Duration `mapstructure:"LOGIN_LOCKOUT_MAX"`
+
+	// Transaction PIN entry locks for TransactionPINLockout after
+	// TransactionPINMaxAttempts consecutive wrong PINs
+	TransactionPINMaxAttempts int           `mapstructure:"TRANSACTION_PIN_MAX_ATTEMPTS"`
+	TransactionPINLockout     time.Duration `mapstructure:"TRANSACTION_PIN_LOCKOUT"`
+
+	// Support impersonation: the longest an impersonation token may live, and
+	// whether tenant admins may impersonate users of their own tenant
+	ImpersonationMaxTTL       time.Duration `mapstructure:"IMPERSONATION_MAX_TTL"`
+	ImpersonationTenantAdmins bool          `mapstructure:"IMPERSONATION_TENANT_ADMINS"`
+
+	// Generated files: STORAGE_DRIVER=local keeps them under STORAGE_LOCAL_DIR
+	StorageDriver   string `mapstructure:"STORAGE_DRIVER"`
+	StorageLocalDir string `mapstructure:"STORAGE_LOCAL_DIR"`
+
+	// Transaction exports are kept for ExportRetention and downloaded through
+	// links signed with ExportSigningKey that expire after ExportLinkTTL
+	ExportRetention  time.Duration `mapstructure:"EXPORT_RETENTION"`
+	ExportLinkTTL    time.Duration `mapstructure:"EXPORT_LINK_TTL"`
+	ExportSigningKey string        `mapstructure:"EXPORT_SIGNING_KEY"`
+
+	// Deposits pending for DepositVerifyAfter are checked with their provider in
+	// case the webhook was lost; abandoned ones expire after DepositExpireAfter
+	DepositVerifyAfter time.Duration `mapstructure:"DEPOSIT_VERIFY_AFTER"`
+	DepositExpireAfter time.Duration `mapstructure:"DEPOSIT_EXPIRE_AFTER"`
+
+	// Reversals still pending after ReversalRetryAfter, such as a refund
+	// that went out but could not be settled, are retried
+	ReversalRetryAfter time.Duration `mapstructure:"REVERSAL_RETRY_AFTER"`
+
+	// Whether monthly wallet statements are emailed to users with a verified email
+	StatementEmailEnabled bool `mapstructure:"STATEMENT_EMAIL_ENABLED"`
+
+	// Extra field names whose values are redacted from logs
+	LogRedactKeys []string `mapstructure:"LOG_REDACT_KEYS"`
+}
+
+// AccessTokenTTL is the lifetime of access tokens (JwtTokenExpiry is in hours)
+func (c *Config) AccessTokenTTL() time.Duration {
+	return time.Duration(c.JwtTokenExpiry) * time.Hour
+}
+
+// RefreshTokenTTL is the lifetime of refresh tokens and their sessions
+// (JwtTokenRefreshExpiry is in days)
+func (c *Config) RefreshTokenTTL() time.Duration {
+	return time.Duration(c.JwtTokenRefreshExpiry) * 24 * time.Hour
+}
+
+// RateLimit allows Limit requests per sliding Window
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (r RateLimit) Enabled() bool {
+	return r.Limit > 0 && r.Window > 0
 }
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+const RedactedValue = "[REDACTED]"
+
+// secretKeyParts marks config keys whose values must never be exposed.
+var secretKeyParts = map[string]bool{
+	"SECRET":   true,
+	"PASSWORD": true,
+	"PASS":     true,
+	"DSN":      true,
+	"HASH":     true,
+	"KEY":      true,
+}
+
+// IsSecretKey reports whether a config key (e.g. JWT_SECRET) holds a secret.
+func IsSecretKey(key string) bool {
+	for _, part := range strings.Split(strings.ToUpper(key), "_") {
+		if secretKeyParts[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns the config keyed by env var name with secret values masked.
+func (c *Config) Redacted() map[string]interface{} {
+	result := make(map[string]interface{})
+
+	v := reflect.ValueOf(*c)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" {
+			key = field.Name
+		}
+
+		value := v.Field(i).Interface()
+		if IsSecretKey(key) && !v.Field(i).IsZero() {
+			value = RedactedValue
+		}
+		result[key] = value
+	}
+
+	return result
+}
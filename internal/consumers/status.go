@@ -0,0 +1,86 @@
+package consumers
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConsumerStatus is a point-in-time snapshot of a Kafka consumer
+type ConsumerStatus struct {
+	Name              string     `json:"name"`
+	Topic             string     `json:"topic"`
+	GroupID           string     `json:"group_id"`
+	Running           bool       `json:"running"`
+	StartedAt         *time.Time `json:"started_at"`
+	LastMessageAt     *time.Time `json:"last_message_at"`
+	MessagesProcessed int64      `json:"messages_processed"`
+	LastError         string     `json:"last_error,omitempty"`
+}
+
+type tracker struct {
+	mu     sync.Mutex
+	status ConsumerStatus
+}
+
+var (
+	trackersMu sync.RWMutex
+	trackers   = map[string]*tracker{}
+)
+
+// register creates (or resets) the status tracker for a consumer
+func register(name, topic, groupID string) *tracker {
+	t := &tracker{status: ConsumerStatus{
+		Name:    name,
+		Topic:   topic,
+		GroupID: groupID,
+	}}
+
+	trackersMu.Lock()
+	trackers[name] = t
+	trackersMu.Unlock()
+
+	return t
+}
+
+func (t *tracker) started() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.status.Running = true
+	t.status.StartedAt = &now
+}
+
+func (t *tracker) failed(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.Running = false
+	t.status.LastError = err.Error()
+}
+
+func (t *tracker) processed() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.status.LastMessageAt = &now
+	t.status.MessagesProcessed++
+}
+
+// Statuses returns the status of every started consumer, ordered by name
+func Statuses() []ConsumerStatus {
+	trackersMu.RLock()
+	defer trackersMu.RUnlock()
+
+	statuses := make([]ConsumerStatus, 0, len(trackers))
+	for _, t := range trackers {
+		t.mu.Lock()
+		statuses = append(statuses, t.status)
+		t.mu.Unlock()
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
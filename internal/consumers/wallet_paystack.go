@@ -18,6 +18,8 @@ func StartWalletPaystackConsumer(
 	walletService wallet.Service,
 	logger *zap.Logger,
 ) {
+	status := register("wallet_paystack", kafka.PaystackWalletEventTopic, walletGroupID)
+
 	go func() {
 		err := kafka.Subscribe(
 			ctx,
@@ -26,10 +28,14 @@ func StartWalletPaystackConsumer(
 			walletGroupID,
 			func(key, value []byte) {
 				walletService.HandlePaystackKafkaEvent(ctx, key, value)
+				status.processed()
 			},
 		)
 		if err != nil {
+			status.failed(err)
 			logger.Sugar().Errorf("Failed to subscribe to Paystack wallet events: %v", err)
+			return
 		}
+		status.started()
 	}()
 }
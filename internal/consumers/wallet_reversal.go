@@ -0,0 +1,49 @@
+package consumers
+
+import (
+	"codematic/internal/domain/webhook"
+	"codematic/internal/infrastructure/events/kafka"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+const (
+	reversalWebhookGroupID = "webhook-wallet-reversal-group"
+
+	// EventTransactionReversal is the event type tenants receive for reversals
+	EventTransactionReversal = "transaction.reversal"
+)
+
+// StartWalletReversalConsumer forwards reversal events to the webhook URL of
+// the tenant they belong to. Events are keyed by tenant ID.
+func StartWalletReversalConsumer(
+	ctx context.Context,
+	broker string,
+	webhookService webhook.Service,
+	logger *zap.Logger,
+) {
+	status := register("wallet_reversal_webhooks", kafka.WalletReversalTopic, reversalWebhookGroupID)
+
+	go func() {
+		err := kafka.Subscribe(
+			ctx,
+			broker,
+			kafka.WalletReversalTopic,
+			reversalWebhookGroupID,
+			func(key, value []byte) {
+				if err := webhookService.DeliverTenantEvent(ctx, string(key), EventTransactionReversal,
+					value); err != nil {
+					logger.Sugar().Errorf("Failed to deliver reversal webhook: %v", err)
+				}
+				status.processed()
+			},
+		)
+		if err != nil {
+			status.failed(err)
+			logger.Sugar().Errorf("Failed to subscribe to wallet reversal events: %v", err)
+			return
+		}
+		status.started()
+	}()
+}
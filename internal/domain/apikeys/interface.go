@@ -0,0 +1,29 @@
+package apikeys
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"time"
+)
+
+type Service interface {
+	CreateKey(ctx context.Context, tenantID, createdBy string, req CreateAPIKeyRequest) (CreatedAPIKey, error)
+	ListKeys(ctx context.Context, tenantID string) ([]APIKey, error)
+	GetKey(ctx context.Context, tenantID, id string) (APIKey, error)
+	RollKey(ctx context.Context, tenantID, id, createdBy string, req RollAPIKeyRequest) (CreatedAPIKey, error)
+	RevokeKey(ctx context.Context, tenantID, id string) error
+	Authenticate(ctx context.Context, rawKey string) (*Principal, error)
+	WithTx(q *db.Queries) Service
+}
+
+type Repository interface {
+	Create(ctx context.Context, arg CreateParams) (db.ApiKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (db.ApiKey, error)
+	GetByID(ctx context.Context, tenantID, id string) (db.ApiKey, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]db.ApiKey, error)
+	Revoke(ctx context.Context, tenantID, id string) error
+	SetExpiry(ctx context.Context, tenantID, id string, expiresAt time.Time) error
+	TouchLastUsed(ctx context.Context, id string) error
+	CreatorActive(ctx context.Context, userID string) (bool, error)
+	WithTx(q *db.Queries) Repository
+}
@@ -0,0 +1,140 @@
+package apikeys
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	KeyTypePublishable = "publishable"
+	KeyTypeSecret      = "secret"
+
+	// KeyPrefix namespaces every key so it is recognisable in headers and logs,
+	// e.g. cm_sk_1a2b3c4d_<secret>.
+	KeyPrefix = "cm_"
+)
+
+// Scopes that can be granted to an API key
+const (
+	ScopeTransactionsRead = "transactions:read"
+	ScopeUsersWrite       = "users:write"
+)
+
+// publishableScopes are the only scopes a publishable key may carry. Publishable
+// keys are embedded in clients, so anything that mutates state or exposes
+// tenant-wide data needs a secret key; none of the current scopes qualify.
+var publishableScopes = map[string]bool{}
+
+var validScopes = map[string]bool{
+	ScopeTransactionsRead: true,
+	ScopeUsersWrite:       true,
+}
+
+type (
+	CreateAPIKeyRequest struct {
+		Name       string     `json:"name" validate:"required,max=100"`
+		Type       string     `json:"type" validate:"required,oneof=publishable secret"`
+		Scopes     []string   `json:"scopes"`
+		AllowedIPs []string   `json:"allowed_ips"`
+		ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	}
+
+	RollAPIKeyRequest struct {
+		// GracePeriodMinutes keeps the old key usable for a while so callers
+		// can deploy the new one. Zero revokes it immediately.
+		GracePeriodMinutes int `json:"grace_period_minutes" validate:"gte=0,lte=10080"`
+	}
+
+	APIKey struct {
+		ID         string     `json:"id"`
+		TenantID   string     `json:"tenant_id"`
+		CreatedBy  string     `json:"created_by,omitempty"`
+		Name       string     `json:"name"`
+		Type       string     `json:"type"`
+		Prefix     string     `json:"prefix"`
+		Scopes     []string   `json:"scopes"`
+		AllowedIPs []string   `json:"allowed_ips"`
+		LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+		ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+		RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+	}
+
+	// CreatedAPIKey is returned once on create/roll; the plaintext key is never stored
+	CreatedAPIKey struct {
+		APIKey
+		Key string `json:"key"`
+	}
+
+	// Principal is the identity an authenticated API key acts as
+	Principal struct {
+		Key    APIKey
+		Claims *model.Claims
+	}
+
+	CreateParams struct {
+		ID         string
+		TenantID   string
+		CreatedBy  string
+		Name       string
+		KeyType    string
+		Prefix     string
+		KeyHash    string
+		Scopes     []string
+		AllowedIPs []string
+		ExpiresAt  *time.Time
+	}
+)
+
+// HasScope reports whether the key was granted scope
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func toDomainAPIKey(k db.ApiKey) APIKey {
+	return APIKey{
+		ID:         utils.FromPgUUID(k.ID),
+		TenantID:   utils.FromPgUUID(k.TenantID),
+		CreatedBy:  utils.FromPgUUID(k.CreatedBy),
+		Name:       k.Name,
+		Type:       k.KeyType,
+		Prefix:     k.Prefix,
+		Scopes:     nonNil(k.Scopes),
+		AllowedIPs: nonNil(k.AllowedIps),
+		LastUsedAt: timePtr(k.LastUsedAt),
+		ExpiresAt:  timePtr(k.ExpiresAt),
+		RevokedAt:  timePtr(k.RevokedAt),
+		CreatedAt:  utils.FromPgTimestamptz(k.CreatedAt),
+	}
+}
+
+func toDomainAPIKeys(keys []db.ApiKey) []APIKey {
+	out := make([]APIKey, len(keys))
+	for i, k := range keys {
+		out[i] = toDomainAPIKey(k)
+	}
+	return out
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,146 @@
+package apikeys
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) Create(ctx context.Context, arg CreateParams) (db.ApiKey, error) {
+	id, err := utils.StringToPgUUID(arg.ID)
+	if err != nil {
+		return db.ApiKey{}, err
+	}
+	tid, err := utils.StringToPgUUID(arg.TenantID)
+	if err != nil {
+		return db.ApiKey{}, err
+	}
+
+	var createdBy pgtype.UUID
+	if arg.CreatedBy != "" {
+		createdBy, err = utils.StringToPgUUID(arg.CreatedBy)
+		if err != nil {
+			return db.ApiKey{}, err
+		}
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if arg.ExpiresAt != nil {
+		expiresAt = utils.ToPgTimestamptz(*arg.ExpiresAt)
+	}
+
+	return r.q.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		ID:         id,
+		TenantID:   tid,
+		CreatedBy:  createdBy,
+		Name:       arg.Name,
+		KeyType:    arg.KeyType,
+		Prefix:     arg.Prefix,
+		KeyHash:    arg.KeyHash,
+		Scopes:     nonNil(arg.Scopes),
+		AllowedIps: nonNil(arg.AllowedIPs),
+		ExpiresAt:  expiresAt,
+	})
+}
+
+func (r *repository) GetByPrefix(ctx context.Context, prefix string) (db.ApiKey, error) {
+	return r.q.GetAPIKeyByPrefix(ctx, prefix)
+}
+
+func (r *repository) GetByID(ctx context.Context, tenantID, id string) (db.ApiKey, error) {
+	kid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.ApiKey{}, err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.ApiKey{}, err
+	}
+	return r.q.GetAPIKeyByID(ctx, db.GetAPIKeyByIDParams{ID: kid, TenantID: tid})
+}
+
+func (r *repository) ListByTenant(ctx context.Context, tenantID string) ([]db.ApiKey, error) {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListAPIKeysByTenant(ctx, tid)
+}
+
+func (r *repository) Revoke(ctx context.Context, tenantID, id string) error {
+	kid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return err
+	}
+	return r.q.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{ID: kid, TenantID: tid})
+}
+
+func (r *repository) SetExpiry(ctx context.Context, tenantID, id string, expiresAt time.Time) error {
+	kid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return err
+	}
+	return r.q.SetAPIKeyExpiry(ctx, db.SetAPIKeyExpiryParams{
+		ID:        kid,
+		TenantID:  tid,
+		ExpiresAt: utils.ToPgTimestamptz(expiresAt),
+	})
+}
+
+func (r *repository) TouchLastUsed(ctx context.Context, id string) error {
+	kid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	return r.q.TouchAPIKeyLastUsed(ctx, kid)
+}
+
+// CreatorActive reports whether the user who created a key still exists and
+// is active
+func (r *repository) CreatorActive(ctx context.Context, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	u, err := r.q.GetUserByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return u.IsActive.Bool, nil
+}
@@ -0,0 +1,277 @@
+package apikeys
+
+import (
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type apiKeyService struct {
+	DB     *db.DBConn
+	Repo   Repository
+	logger *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the API key service.
+func NewService(db *db.DBConn, logger *zap.Logger) Service {
+	return &apiKeyService{
+		DB:     db,
+		Repo:   NewRepository(db.Queries, db.Pool),
+		logger: logger,
+	}
+}
+
+func (s *apiKeyService) WithTx(q *dbsqlc.Queries) Service {
+	return &apiKeyService{
+		DB:     s.DB,
+		Repo:   NewRepository(q, s.DB.Pool),
+		logger: s.logger,
+	}
+}
+
+func (s *apiKeyService) CreateKey(ctx context.Context, tenantID, createdBy string,
+	req CreateAPIKeyRequest) (CreatedAPIKey, error) {
+	if err := validateKeyRequest(req); err != nil {
+		return CreatedAPIKey{}, err
+	}
+	return s.issue(ctx, s.Repo, tenantID, createdBy, req)
+}
+
+func (s *apiKeyService) ListKeys(ctx context.Context, tenantID string) ([]APIKey, error) {
+	keys, err := s.Repo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainAPIKeys(keys), nil
+}
+
+func (s *apiKeyService) GetKey(ctx context.Context, tenantID, id string) (APIKey, error) {
+	key, err := s.Repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return APIKey{}, model.ErrAPIKeyNotFound
+	}
+	return toDomainAPIKey(key), nil
+}
+
+// RollKey issues a replacement with the same name, type, scopes and allow-list,
+// then revokes the old key or lets it expire after the grace period.
+func (s *apiKeyService) RollKey(ctx context.Context, tenantID, id, createdBy string,
+	req RollAPIKeyRequest) (CreatedAPIKey, error) {
+	var result CreatedAPIKey
+
+	err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+
+		old, err := repo.GetByID(ctx, tenantID, id)
+		if err != nil {
+			return model.ErrAPIKeyNotFound
+		}
+		current := toDomainAPIKey(old)
+		if current.RevokedAt != nil {
+			return model.ErrAPIKeyNotFound
+		}
+
+		result, err = s.issue(ctx, repo, tenantID, createdBy, CreateAPIKeyRequest{
+			Name:       current.Name,
+			Type:       current.Type,
+			Scopes:     current.Scopes,
+			AllowedIPs: current.AllowedIPs,
+			ExpiresAt:  current.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if req.GracePeriodMinutes == 0 {
+			return repo.Revoke(ctx, tenantID, id)
+		}
+
+		graceEnd := time.Now().Add(time.Duration(req.GracePeriodMinutes) * time.Minute)
+		if current.ExpiresAt != nil && current.ExpiresAt.Before(graceEnd) {
+			return nil
+		}
+		return repo.SetExpiry(ctx, tenantID, id, graceEnd)
+	})
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+
+	s.logger.Info("api key rolled",
+		zap.String("tenant_id", tenantID),
+		zap.String("old_key_id", id),
+		zap.String("new_key_id", result.ID),
+	)
+
+	return result, nil
+}
+
+func (s *apiKeyService) RevokeKey(ctx context.Context, tenantID, id string) error {
+	if _, err := s.Repo.GetByID(ctx, tenantID, id); err != nil {
+		return model.ErrAPIKeyNotFound
+	}
+	if err := s.Repo.Revoke(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("api key revoked", zap.String("tenant_id", tenantID), zap.String("key_id", id))
+	return nil
+}
+
+// Authenticate resolves a raw key to the tenant identity it acts as. Keys act
+// on behalf of the user who created them with the API_KEY role, holding only
+// their scopes, and stop working once that user is deactivated.
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*Principal, error) {
+	prefix, ok := splitKey(rawKey)
+	if !ok {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	stored, err := s.Repo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	hash := utils.HashString(rawKey)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(stored.KeyHash)) != 1 {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	key := toDomainAPIKey(stored)
+	if key.RevokedAt != nil {
+		return nil, model.ErrInvalidAPIKey
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	active, err := s.Repo.CreatorActive(ctx, key.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, model.ErrInvalidAPIKey
+	}
+
+	if err := s.Repo.TouchLastUsed(ctx, key.ID); err != nil {
+		s.logger.Warn("failed to update api key last used", zap.String("key_id", key.ID), zap.Error(err))
+	}
+
+	return &Principal{
+		Key: key,
+		Claims: &model.Claims{
+			UserID:      key.CreatedBy,
+			TenantID:    key.TenantID,
+			Role:        model.RoleAPIKey.String(),
+			Permissions: key.Scopes,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:      key.ID,
+				Subject: key.CreatedBy,
+			},
+		},
+	}, nil
+}
+
+func (s *apiKeyService) issue(ctx context.Context, repo Repository, tenantID, createdBy string,
+	req CreateAPIKeyRequest) (CreatedAPIKey, error) {
+	prefix, rawKey, err := generateKey(req.Type)
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+
+	stored, err := repo.Create(ctx, CreateParams{
+		ID:         uuid.NewString(),
+		TenantID:   tenantID,
+		CreatedBy:  createdBy,
+		Name:       req.Name,
+		KeyType:    req.Type,
+		Prefix:     prefix,
+		KeyHash:    utils.HashString(rawKey),
+		Scopes:     req.Scopes,
+		AllowedIPs: req.AllowedIPs,
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+
+	return CreatedAPIKey{APIKey: toDomainAPIKey(stored), Key: rawKey}, nil
+}
+
+func validateKeyRequest(req CreateAPIKeyRequest) error {
+	if req.Type == KeyTypeSecret && len(req.Scopes) == 0 {
+		return model.ErrInvalidAPIKeyScope
+	}
+	for _, scope := range req.Scopes {
+		if !validScopes[scope] {
+			return model.ErrInvalidAPIKeyScope
+		}
+		if req.Type == KeyTypePublishable && !publishableScopes[scope] {
+			return model.ErrInvalidAPIKeyScope
+		}
+	}
+
+	for _, entry := range req.AllowedIPs {
+		if net.ParseIP(entry) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err != nil {
+			return model.ErrInvalidAllowedIP
+		}
+	}
+
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		return model.ErrInvalidInputError
+	}
+
+	return nil
+}
+
+// generateKey returns the lookup prefix (cm_sk_1a2b3c4d) and the full key
+// (cm_sk_1a2b3c4d_<64 hex chars>).
+func generateKey(keyType string) (string, string, error) {
+	typeTag := "pk"
+	if keyType == KeyTypeSecret {
+		typeTag = "sk"
+	}
+
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix := KeyPrefix + typeTag + "_" + hex.EncodeToString(id)
+	return prefix, prefix + "_" + hex.EncodeToString(secret), nil
+}
+
+// splitKey extracts the lookup prefix from a raw key
+func splitKey(rawKey string) (string, bool) {
+	if !IsAPIKey(rawKey) {
+		return "", false
+	}
+	idx := strings.LastIndex(rawKey, "_")
+	if idx <= len(KeyPrefix) {
+		return "", false
+	}
+	return rawKey[:idx], true
+}
+
+// IsAPIKey reports whether a credential looks like one of our API keys
+func IsAPIKey(value string) bool {
+	return strings.HasPrefix(value, KeyPrefix+"sk_") || strings.HasPrefix(value, KeyPrefix+"pk_")
+}
@@ -0,0 +1,20 @@
+package audit
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"io"
+)
+
+type Service interface {
+	Record(ctx context.Context, entry Entry)
+	List(ctx context.Context, filter Filter) (LogList, error)
+	ExportCSV(ctx context.Context, filter Filter, w io.Writer) error
+}
+
+type Repository interface {
+	Create(ctx context.Context, arg db.CreateAuditLogParams) error
+	Search(ctx context.Context, filter Filter) ([]db.AuditLog, error)
+	Count(ctx context.Context, filter Filter) (int64, error)
+	WithTx(q *db.Queries) Repository
+}
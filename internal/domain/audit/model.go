@@ -0,0 +1,182 @@
+package audit
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"encoding/json"
+	"time"
+)
+
+// Actions are "<area>.<verb>"; filtering by an area (e.g. "tenant") matches
+// all of its actions.
+const (
+	ActionLogin         = "auth.login"
+	ActionLoginFailed   = "auth.login_failed"
+	ActionAccountUnlock = "auth.unlock"
+
+	ActionImpersonationStart   = "impersonation.start"
+	ActionImpersonationEnd     = "impersonation.end"
+	ActionImpersonationRequest = "impersonation.request"
+
+	ActionUserCreate     = "user.create"
+	ActionUserDeactivate = "user.deactivate"
+	ActionUserReactivate = "user.reactivate"
+	ActionUserRoleChange = "user.role_change"
+	ActionUsernameChange = "user.username_change"
+
+	ActionRoleCreate   = "role.create"
+	ActionRoleUpdate   = "role.update"
+	ActionRoleDelete   = "role.delete"
+	ActionRoleAssign   = "role.assign"
+	ActionRoleUnassign = "role.unassign"
+
+	ActionPINSet    = "pin.set"
+	ActionPINChange = "pin.change"
+	ActionPINReset  = "pin.reset"
+
+	ActionInviteCreate = "invite.create"
+	ActionInviteRevoke = "invite.revoke"
+
+	ActionAPIKeyCreate = "api_key.create"
+	ActionAPIKeyRoll   = "api_key.roll"
+	ActionAPIKeyRevoke = "api_key.revoke"
+
+	ActionTenantCreate         = "tenant.create"
+	ActionTenantUpdate         = "tenant.update"
+	ActionTenantSettingsUpdate = "tenant.settings_update"
+	ActionTenantDelete         = "tenant.delete"
+
+	ActionProviderConfigUpdate = "provider.config_update"
+	ActionWebhookConfigUpdate  = "webhook.config_update"
+
+	ActionWalletStatusChange = "wallet.status_change"
+	ActionDepositInitiate    = "wallet.deposit_initiate"
+	ActionDepositComplete    = "wallet.deposit_complete"
+	ActionDepositFail        = "wallet.deposit_fail"
+	ActionDepositExpire      = "wallet.deposit_expire"
+	ActionDepositFlag        = "wallet.deposit_flag"
+	ActionDepositReview      = "wallet.deposit_review"
+	ActionWithdraw           = "wallet.withdraw"
+	ActionTransfer           = "wallet.transfer"
+
+	ActionTransactionExport  = "transaction.export"
+	ActionTransactionReverse = "transaction.reverse"
+
+	ActionReconciliationRun     = "reconciliation.run"
+	ActionReconciliationResolve = "reconciliation.resolve"
+
+	ActionPricingPlanCreate = "pricing.plan_create"
+	ActionPricingPlanUpdate = "pricing.plan_update"
+	ActionPricingPlanDelete = "pricing.plan_delete"
+	ActionPricingPlanAssign = "pricing.plan_assign"
+)
+
+const (
+	TargetUser        = "user"
+	TargetRole        = "role"
+	TargetInvite      = "invite"
+	TargetAPIKey      = "api_key"
+	TargetTenant      = "tenant"
+	TargetProvider    = "provider"
+	TargetWallet      = "wallet"
+	TargetTransaction = "transaction"
+	TargetExport      = "export"
+
+	TargetReconciliationRun       = "reconciliation_run"
+	TargetReconciliationException = "reconciliation_exception"
+	TargetPricingPlan             = "pricing_plan"
+)
+
+// ActorSystem is the actor role recorded for actions taken by background
+// consumers and jobs rather than a user
+const ActorSystem = "SYSTEM"
+
+type (
+	// Entry describes one audited action. Before and After are snapshots of
+	// the target (any JSON-serializable value); only the fields that differ
+	// are stored.
+	Entry struct {
+		TenantID   string
+		ActorID    string
+		ActorRole  string
+		Action     string
+		TargetType string
+		TargetID   string
+		Before     any
+		After      any
+		Metadata   map[string]any
+		IPAddress  string
+		UserAgent  string
+	}
+
+	Change struct {
+		From any `json:"from,omitempty"`
+		To   any `json:"to,omitempty"`
+	}
+
+	Log struct {
+		ID         string            `json:"id"`
+		TenantID   string            `json:"tenant_id,omitempty"`
+		ActorID    string            `json:"actor_id,omitempty"`
+		ActorRole  string            `json:"actor_role,omitempty"`
+		Action     string            `json:"action"`
+		TargetType string            `json:"target_type,omitempty"`
+		TargetID   string            `json:"target_id,omitempty"`
+		Changes    map[string]Change `json:"changes,omitempty"`
+		Metadata   map[string]any    `json:"metadata,omitempty"`
+		IPAddress  string            `json:"ip_address,omitempty"`
+		UserAgent  string            `json:"user_agent,omitempty"`
+		CreatedAt  time.Time         `json:"created_at"`
+	}
+
+	LogList struct {
+		Logs   []Log `json:"logs"`
+		Total  int64 `json:"total"`
+		Limit  int   `json:"limit"`
+		Offset int   `json:"offset"`
+	}
+
+	// Filter narrows a log search; empty fields match everything. Action
+	// matches exactly or by area prefix, and the range is [From, To).
+	Filter struct {
+		TenantID   string
+		ActorID    string
+		Action     string
+		TargetType string
+		TargetID   string
+		From       *time.Time
+		To         *time.Time
+		Limit      int
+		Offset     int
+	}
+)
+
+func toDomainLog(l db.AuditLog) Log {
+	log := Log{
+		ID:         utils.FromPgUUID(l.ID),
+		TenantID:   utils.FromPgUUID(l.TenantID),
+		ActorID:    utils.FromPgUUID(l.UserID),
+		ActorRole:  l.ActorRole.String,
+		Action:     l.Action,
+		TargetType: l.TargetType.String,
+		TargetID:   l.TargetID.String,
+		IPAddress:  l.IpAddress.String,
+		UserAgent:  l.UserAgent.String,
+		CreatedAt:  utils.FromPgTimestamptz(l.CreatedAt),
+	}
+	if len(l.Changes) > 0 {
+		_ = json.Unmarshal(l.Changes, &log.Changes)
+	}
+	if len(l.Metadata) > 0 {
+		_ = json.Unmarshal(l.Metadata, &log.Metadata)
+	}
+	return log
+}
+
+func toDomainLogs(rows []db.AuditLog) []Log {
+	logs := make([]Log, 0, len(rows))
+	for _, r := range rows {
+		logs = append(logs, toDomainLog(r))
+	}
+	return logs
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) Create(ctx context.Context, arg db.CreateAuditLogParams) error {
+	return r.q.CreateAuditLog(ctx, arg)
+}
+
+func (r *repository) Search(ctx context.Context, filter Filter) ([]db.AuditLog, error) {
+	p := toCountParams(filter)
+	return r.q.SearchAuditLogs(ctx, db.SearchAuditLogsParams{
+		TenantID:    p.TenantID,
+		UserID:      p.UserID,
+		Action:      p.Action,
+		TargetType:  p.TargetType,
+		TargetID:    p.TargetID,
+		CreatedFrom: p.CreatedFrom,
+		CreatedTo:   p.CreatedTo,
+		RowLimit:    int32(filter.Limit),
+		RowOffset:   int32(filter.Offset),
+	})
+}
+
+func (r *repository) Count(ctx context.Context, filter Filter) (int64, error) {
+	return r.q.CountAuditLogs(ctx, toCountParams(filter))
+}
+
+func toCountParams(filter Filter) db.CountAuditLogsParams {
+	p := db.CountAuditLogsParams{
+		TenantID:   optionalUUID(filter.TenantID),
+		UserID:     optionalUUID(filter.ActorID),
+		Action:     optionalText(filter.Action),
+		TargetType: optionalText(filter.TargetType),
+		TargetID:   optionalText(filter.TargetID),
+	}
+	if filter.From != nil {
+		p.CreatedFrom = utils.ToPgTimestamptz(*filter.From)
+	}
+	if filter.To != nil {
+		p.CreatedTo = utils.ToPgTimestamptz(*filter.To)
+	}
+	return p
+}
+
+// optionalUUID maps an empty or malformed ID to NULL
+func optionalUUID(id string) pgtype.UUID {
+	if id == "" {
+		return pgtype.UUID{}
+	}
+	u, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return pgtype.UUID{}
+	}
+	return u
+}
+
+func optionalText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{}
+	}
+	return utils.ToPgxText(s)
+}
@@ -0,0 +1,226 @@
+package audit
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+
+	// Exports page through the table so memory stays flat; the cap keeps a
+	// single request from dumping the whole history
+	exportPageSize = 500
+	maxExportRows  = 50000
+)
+
+var csvHeader = []string{
+	"id", "created_at", "tenant_id", "actor_id", "actor_role", "action",
+	"target_type", "target_id", "changes", "metadata", "ip_address", "user_agent",
+}
+
+type auditService struct {
+	DB     *db.DBConn
+	Repo   Repository
+	logger *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the audit service.
+func NewService(db *db.DBConn, logger *zap.Logger) Service {
+	return &auditService{
+		DB:     db,
+		Repo:   NewRepository(db.Queries, db.Pool),
+		logger: logger,
+	}
+}
+
+// Record stores an audit entry. Auditing must never fail the audited action,
+// so errors are logged rather than returned.
+func (s *auditService) Record(ctx context.Context, entry Entry) {
+	arg := dbsqlc.CreateAuditLogParams{
+		ID:         utils.ToUUID(uuid.New()),
+		TenantID:   optionalUUID(entry.TenantID),
+		UserID:     optionalUUID(entry.ActorID),
+		ActorRole:  optionalText(entry.ActorRole),
+		Action:     entry.Action,
+		TargetType: optionalText(entry.TargetType),
+		TargetID:   optionalText(entry.TargetID),
+		IpAddress:  optionalText(entry.IPAddress),
+		UserAgent:  optionalText(entry.UserAgent),
+	}
+
+	if changes := diff(entry.Before, entry.After); len(changes) > 0 {
+		arg.Changes, _ = json.Marshal(changes)
+	}
+	if len(entry.Metadata) > 0 {
+		arg.Metadata, _ = json.Marshal(redact(entry.Metadata))
+	}
+
+	if err := s.Repo.Create(ctx, arg); err != nil {
+		s.logger.Error("Failed to record audit log", zap.String("action", entry.Action),
+			zap.String("actorID", entry.ActorID), zap.Error(err))
+	}
+}
+
+func (s *auditService) List(ctx context.Context, filter Filter) (LogList, error) {
+	if err := validateFilter(filter); err != nil {
+		return LogList{}, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	rows, err := s.Repo.Search(ctx, filter)
+	if err != nil {
+		return LogList{}, err
+	}
+
+	total, err := s.Repo.Count(ctx, filter)
+	if err != nil {
+		return LogList{}, err
+	}
+
+	return LogList{
+		Logs:   toDomainLogs(rows),
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// ExportCSV writes the logs matching filter to w as CSV, newest first, up to
+// maxExportRows rows. Limit and Offset of the filter are ignored.
+func (s *auditService) ExportCSV(ctx context.Context, filter Filter, w io.Writer) error {
+	if err := validateFilter(filter); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	filter.Limit = exportPageSize
+	for filter.Offset = 0; filter.Offset < maxExportRows; filter.Offset += exportPageSize {
+		rows, err := s.Repo.Search(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			l := toDomainLog(row)
+			if err := cw.Write([]string{
+				l.ID,
+				l.CreatedAt.UTC().Format(time.RFC3339),
+				l.TenantID,
+				l.ActorID,
+				l.ActorRole,
+				l.Action,
+				l.TargetType,
+				utils.SpreadsheetText(l.TargetID),
+				string(row.Changes),
+				string(row.Metadata),
+				l.IPAddress,
+				utils.SpreadsheetText(l.UserAgent),
+			}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+
+		if len(rows) < exportPageSize {
+			break
+		}
+	}
+
+	return nil
+}
+
+// validateFilter rejects malformed IDs; treating them as "no filter" would
+// widen the search.
+func validateFilter(filter Filter) error {
+	for _, id := range []string{filter.TenantID, filter.ActorID} {
+		if id == "" {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			return model.ErrInvalidInputError
+		}
+	}
+	return nil
+}
+
+// diff returns the top-level fields that differ between two snapshots. A
+// missing snapshot records every field of the other one, as for creations and
+// deletions.
+func diff(before, after any) map[string]Change {
+	from := toFields(before)
+	to := toFields(after)
+	if from == nil && to == nil {
+		return nil
+	}
+
+	changes := make(map[string]Change)
+	for k, v := range from {
+		if w, ok := to[k]; !ok || !reflect.DeepEqual(v, w) {
+			changes[k] = Change{From: v, To: to[k]}
+		}
+	}
+	for k, w := range to {
+		if _, ok := from[k]; !ok {
+			changes[k] = Change{To: w}
+		}
+	}
+	return changes
+}
+
+// toFields flattens a snapshot to its JSON fields with secrets masked
+func toFields(v any) map[string]any {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	return redact(fields)
+}
+
+func redact(fields map[string]any) map[string]any {
+	masked := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if config.IsSecretKey(k) {
+			v = config.RedactedValue
+		}
+		masked[k] = v
+	}
+	return masked
+}
@@ -1,21 +1,44 @@
 package auth
 
 import (
+	"codematic/internal/domain/mfa"
 	db "codematic/internal/infrastructure/db/sqlc"
 	"codematic/internal/shared/model"
 	"context"
+	"time"
 )
 
 type Service interface {
 	Signup(ctx context.Context, req *SignupRequest) (User, error)
+	SignupWith(ctx context.Context, req *SignupRequest,
+		within func(q *db.Queries, created User) error) (User, error)
 	Login(ctx context.Context, req *LoginRequest,
 		sessionInfo model.UserSessionInfo) (interface{}, error)
 	AdminLogin(ctx context.Context, req *LoginRequest,
 		sessionInfo model.UserSessionInfo) (interface{}, error)
-	Logout(ctx context.Context, userId string) error
+	Logout(ctx context.Context, tokenID string) error
 	RefreshToken(ctx context.Context, refreshToken string) (JwtAuthData, error)
+	ListSessions(ctx context.Context, userID, currentTokenID string) ([]Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID, currentTokenID string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+	RequestPasswordReset(ctx context.Context, req *ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *ResetPasswordRequest) error
+	SendEmailVerification(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, token string) error
+	StartMFAEnrollment(ctx context.Context, challengeToken string) (mfa.Enrollment, error)
+	VerifyMFAChallenge(ctx context.Context, req *MFAVerifyRequest) (LoginResponse, error)
+	UnlockAccount(ctx context.Context, tenantID, userID string) error
+	Impersonate(ctx context.Context, impersonator *model.Claims, req *ImpersonateRequest,
+		sessionInfo model.UserSessionInfo) (ImpersonationResponse, error)
+	EndImpersonation(ctx context.Context, claims *model.Claims) error
 }
 
 type Repository interface {
+	CreateUserToken(ctx context.Context, userID, purpose, tokenHash string,
+		expiresAt time.Time) (db.UserToken, error)
+	GetUserTokenByHash(ctx context.Context, tokenHash, purpose string) (db.UserToken, error)
+	ConsumeUserToken(ctx context.Context, id string) (bool, error)
+	InvalidateUserTokens(ctx context.Context, userID, purpose string) error
 	WithTx(q *db.Queries) Repository
 }
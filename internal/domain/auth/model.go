@@ -1,5 +1,38 @@
 package auth
 
+import (
+	"codematic/internal/shared/model"
+	"time"
+)
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+
+	passwordResetTTL     = 30 * time.Minute
+	emailVerificationTTL = 24 * time.Hour
+
+	mfaChallengeTTL         = 5 * time.Minute
+	maxMFAChallengeAttempts = 5
+
+	// loginFailureTTL is how long failed attempts are remembered without a success
+	loginFailureTTL = 24 * time.Hour
+)
+
+// AccountLockedError is returned while an account is locked after repeated
+// failed logins; it matches model.ErrAccountLocked with errors.Is.
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return model.ErrAccountLocked.Error()
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return model.ErrAccountLocked
+}
+
 type (
 	LoginRequest struct {
 		Email    string `json:"email" validate:"required,email"`
@@ -17,6 +50,27 @@ type (
 	LoginResponse struct {
 		Auth JwtAuthData `json:"auth"`
 		User User        `json:"user"`
+
+		// RecoveryCodes is only set when 2FA was enrolled as part of this login
+		RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	}
+
+	// MFAChallengeResponse is returned by Login/AdminLogin instead of tokens
+	// when a second factor is needed
+	MFAChallengeResponse struct {
+		MFARequired        bool   `json:"mfa_required"`
+		EnrollmentRequired bool   `json:"enrollment_required"`
+		ChallengeToken     string `json:"challenge_token"`
+		ExpiresIn          int    `json:"expires_in"`
+	}
+
+	MFAChallengeRequest struct {
+		ChallengeToken string `json:"challenge_token" validate:"required"`
+	}
+
+	MFAVerifyRequest struct {
+		ChallengeToken string `json:"challenge_token" validate:"required"`
+		Code           string `json:"code" validate:"required"`
 	}
 
 	User struct {
@@ -35,13 +89,88 @@ type (
 		Phone     string `json:"phone" validate:"required"`
 		Password  string `json:"password" validate:"required,min=8"`
 		TenantID  string `json:"tenant_id"`
+
+		// Role is set by tenant admin user management; public signup creates USERs
+		Role model.UserRole `json:"-"`
+
+		// EmailVerified skips the verification email when the address was
+		// already proven, e.g. by following an invite link
+		EmailVerified bool `json:"-"`
+	}
+
+	Session struct {
+		ID        string    `json:"id"`
+		UserAgent string    `json:"user_agent"`
+		IPAddress string    `json:"ip_address"`
+		LoginTime time.Time `json:"login_time"`
+		LastSeen  time.Time `json:"last_seen"`
+		Current   bool      `json:"current"`
+
+		// ImpersonatedBy is the admin who opened the session as the user
+		ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	}
+
+	// ImpersonateRequest asks for a token to act as another user. Tokens are
+	// read-only unless AllowWrites is set; AllowMoneyMovement also requires
+	// AllowWrites and is reserved to platform admins.
+	ImpersonateRequest struct {
+		UserID             string `json:"user_id" validate:"required,uuid"`
+		Reason             string `json:"reason" validate:"required,min=5,max=500"`
+		AllowWrites        bool   `json:"allow_writes"`
+		AllowMoneyMovement bool   `json:"allow_money_movement"`
+
+		// TTLMinutes defaults to, and is capped at, IMPERSONATION_MAX_TTL
+		TTLMinutes int `json:"ttl_minutes" validate:"omitempty,min=1"`
+	}
+
+	ImpersonationResponse struct {
+		AccessToken        string    `json:"access_token"`
+		ExpiresIn          int       `json:"expires_in"`
+		ExpiresAt          time.Time `json:"expires_at"`
+		TokenType          string    `json:"token_type"`
+		ReadOnly           bool      `json:"read_only"`
+		AllowMoneyMovement bool      `json:"allow_money_movement"`
+		User               User      `json:"user"`
+	}
+
+	ForgotPasswordRequest struct {
+		Email    string `json:"email" validate:"required,email"`
+		TenantID string `json:"tenant_id" validate:"required,uuid"`
+	}
+
+	ResetPasswordRequest struct {
+		Token    string `json:"token" validate:"required"`
+		Password string `json:"password" validate:"required,min=8"`
+	}
+
+	VerifyEmailRequest struct {
+		Token string `json:"token" validate:"required"`
 	}
 
 	RefreshTokenRequest struct {
-		RefreshToken string `json:"refresh_token"`
+		RefreshToken string `json:"refresh_token" validate:"required"`
 	}
 )
 
+func toSession(info *model.UserSessionInfo, currentTokenID string) Session {
+	// The refresh family ID stays stable across token refreshes, unlike the token ID
+	id := info.FamilyID
+	if id == "" {
+		id = info.TokenID
+	}
+
+	return Session{
+		ID:        id,
+		UserAgent: info.UserAgent,
+		IPAddress: info.IPAddress,
+		LoginTime: info.LoginTime,
+		LastSeen:  info.LastSeen,
+		Current:   info.TokenID == currentTokenID,
+
+		ImpersonatedBy: info.ImpersonatorID,
+	}
+}
+
 // {
 //   "user": {
 //     "id": "user-uuid",
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"codematic/internal/domain/rbac"
+	"codematic/internal/infrastructure/cache"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type stubRBAC struct {
+	rbac.Service
+}
+
+func (stubRBAC) PermissionsFor(ctx context.Context, userID, role string) ([]string, error) {
+	return nil, nil
+}
+
+func newTestAuthService(t *testing.T) *authService {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rc.Close() })
+
+	logger := zap.NewNop()
+	cacheManager := cache.NewCacheManager(
+		cache.NewRedisSessionStore(rc, logger),
+		cache.NewRedisProviderCacheStore(rc, logger),
+		cache.NewRedisWalletCacheStore(rc, logger),
+		cache.NewRedisTransactionCacheStore(rc, logger),
+		cache.NewRedisRefreshTokenStore(rc, logger),
+		cache.NewRedisMFAChallengeStore(rc, logger),
+		cache.NewRedisRateLimitStore(rc, logger),
+		cache.NewRedisLockoutStore(rc, logger),
+	)
+
+	key, err := utils.GenerateSigningKey(utils.AlgorithmEdDSA)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+	jwtManager := utils.NewJWTManager("test-refresh-secret", 15*time.Minute, time.Hour)
+	jwtManager.SetKeys([]*utils.SigningKey{key})
+
+	return &authService{
+		rbacService:  stubRBAC{},
+		cacheManager: cacheManager,
+		JwtManager:   jwtManager,
+		logger:       logger,
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	s := newTestAuthService(t)
+
+	identity := model.JWTData{UserID: "user-1", Email: "jane@example.com", TenantID: "tenant-1", Role: "USER"}
+	first, err := s.startSession(ctx, identity, model.UserSessionInfo{UserID: identity.UserID})
+	if err != nil {
+		t.Fatalf("startSession: %v", err)
+	}
+
+	rotated, err := s.RefreshToken(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("first refresh: %v", err)
+	}
+
+	claims, err := s.JwtManager.VerifyRefreshToken(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("VerifyRefreshToken: %v", err)
+	}
+
+	// Replaying the rotated token is treated as theft
+	if _, err := s.RefreshToken(ctx, first.RefreshToken); !errors.Is(err, model.ErrRefreshTokenReused) {
+		t.Fatalf("replayed refresh error = %v, want %v", err, model.ErrRefreshTokenReused)
+	}
+
+	if _, err := s.cacheManager.GetRefreshFamily(ctx, claims.FamilyID); !errors.Is(err, cache.ErrRefreshFamilyNotFound) {
+		t.Errorf("family after reuse: err = %v, want %v", err, cache.ErrRefreshFamilyNotFound)
+	}
+	sessions, err := s.cacheManager.ListSessionsForUser(ctx, identity.UserID)
+	if err != nil {
+		t.Fatalf("ListSessionsForUser: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("sessions after reuse = %d, want 0", len(sessions))
+	}
+
+	// The legitimate holder's newer token died with the family
+	if _, err := s.RefreshToken(ctx, rotated.RefreshToken); !errors.Is(err, model.ErrInvalidRefreshToken) {
+		t.Errorf("refresh with revoked family error = %v, want %v", err, model.ErrInvalidRefreshToken)
+	}
+}
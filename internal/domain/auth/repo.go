@@ -2,7 +2,11 @@ package auth
 
 import (
 	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,3 +25,47 @@ func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
 func (r *authRepository) WithTx(q *db.Queries) Repository {
 	return NewRepository(q, r.p)
 }
+
+func (r *authRepository) CreateUserToken(ctx context.Context, userID, purpose, tokenHash string,
+	expiresAt time.Time) (db.UserToken, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.UserToken{}, err
+	}
+	return r.q.CreateUserToken(ctx, db.CreateUserTokenParams{
+		ID:        utils.ToUUID(uuid.New()),
+		UserID:    uid,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: utils.ToPgTimestamptz(expiresAt),
+	})
+}
+
+func (r *authRepository) GetUserTokenByHash(ctx context.Context, tokenHash,
+	purpose string) (db.UserToken, error) {
+	return r.q.GetUserTokenByHash(ctx, db.GetUserTokenByHashParams{
+		TokenHash: tokenHash,
+		Purpose:   purpose,
+	})
+}
+
+// ConsumeUserToken marks a token used; it returns false if it was already used
+func (r *authRepository) ConsumeUserToken(ctx context.Context, id string) (bool, error) {
+	tid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.MarkUserTokenUsed(ctx, tid)
+	return rows == 1, err
+}
+
+func (r *authRepository) InvalidateUserTokens(ctx context.Context, userID, purpose string) error {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	return r.q.InvalidateUserTokens(ctx, db.InvalidateUserTokensParams{
+		UserID:  uid,
+		Purpose: purpose,
+	})
+}
@@ -2,17 +2,23 @@ package auth
 
 import (
 	"codematic/internal/config"
+	"codematic/internal/domain/mfa"
+	"codematic/internal/domain/rbac"
 	"codematic/internal/domain/tenants"
 	"codematic/internal/domain/user"
 	"codematic/internal/domain/wallet"
 	"codematic/internal/infrastructure/cache"
 	"codematic/internal/infrastructure/db"
 	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/infrastructure/notifications"
 	"codematic/internal/shared/model"
 	"codematic/internal/shared/utils"
 
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,8 +33,11 @@ type authService struct {
 	userService   user.Service
 	walletService wallet.Service
 	tenantService tenants.Service
+	mfaService    mfa.Service
+	rbacService   rbac.Service
 	cacheManager  cache.CacheManager
 	JwtManager    *utils.JWTManager
+	mailer        notifications.Mailer
 	cfg           *config.Config
 	logger        *zap.Logger
 }
@@ -39,8 +48,11 @@ func NewService(
 	userService user.Service,
 	walletService wallet.Service,
 	tenantService tenants.Service,
+	mfaService mfa.Service,
+	rbacService rbac.Service,
 	cacheManager cache.CacheManager,
 	jwtManager *utils.JWTManager,
+	mailer notifications.Mailer,
 	cfg *config.Config,
 	logger *zap.Logger) Service {
 	return &authService{
@@ -49,8 +61,11 @@ func NewService(
 		tenantService: tenantService,
 		userService:   userService,
 		walletService: walletService,
+		mfaService:    mfaService,
+		rbacService:   rbacService,
 		cacheManager:  cacheManager,
 		JwtManager:    jwtManager,
+		mailer:        mailer,
 		cfg:           cfg,
 		logger:        logger,
 	}
@@ -62,6 +77,13 @@ func (s *authService) externalServicesWithTx(q *dbsqlc.Queries) (user.Service, w
 }
 
 func (s *authService) Signup(ctx context.Context, req *SignupRequest) (User, error) {
+	return s.SignupWith(ctx, req, nil)
+}
+
+// SignupWith creates the user and wallet like Signup, then calls within in the
+// same transaction so callers can commit related changes atomically.
+func (s *authService) SignupWith(ctx context.Context, req *SignupRequest,
+	within func(q *dbsqlc.Queries, created User) error) (User, error) {
 	var result User
 
 	s.logger.Debug("Starting Signup", zap.String("email", req.Email), zap.String("tenantID", req.TenantID))
@@ -76,11 +98,14 @@ func (s *authService) Signup(ctx context.Context, req *SignupRequest) (User, err
 		userTx, walletTx := s.externalServicesWithTx(q)
 
 		userReq := &user.CreateUserRequest{
-			TenantID: tenant.ID,
-			Email:    req.Email,
-			Phone:    req.Phone,
-			Password: req.Password,
-			IsActive: true,
+			TenantID:  tenant.ID,
+			Email:     req.Email,
+			Phone:     req.Phone,
+			Password:  req.Password,
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+			IsActive:  true,
+			Role:      req.Role,
 		}
 
 		created, err := userTx.CreateUser(ctx, userReq)
@@ -102,6 +127,16 @@ func (s *authService) Signup(ctx context.Context, req *SignupRequest) (User, err
 			TenantID:  created.TenantID.String(),
 			Role:      created.Role.String,
 		}
+
+		if req.EmailVerified {
+			if err := userTx.MarkEmailVerified(ctx, result.ID); err != nil {
+				return err
+			}
+		}
+
+		if within != nil {
+			return within(q, result)
+		}
 		return nil
 	})
 
@@ -111,6 +146,15 @@ func (s *authService) Signup(ctx context.Context, req *SignupRequest) (User, err
 	}
 
 	s.logger.Info("Signup successful", zap.String("userID", result.ID))
+
+	if req.EmailVerified {
+		return result, nil
+	}
+
+	if err := s.SendEmailVerification(ctx, result.ID); err != nil {
+		s.logger.Warn("Failed to send verification email", zap.String("userID", result.ID), zap.Error(err))
+	}
+
 	return result, nil
 }
 
@@ -119,202 +163,820 @@ func (s *authService) Login(ctx context.Context, req *LoginRequest,
 	sessionInfo model.UserSessionInfo) (interface{}, error) {
 
 	email := strings.ToLower(strings.TrimSpace(req.Email))
+	lockKey := tenantLockoutKey(req.TenantID, email)
+
+	if err := s.checkLockout(ctx, lockKey); err != nil {
+		return nil, err
+	}
 
 	user, err := s.userService.GetUserByEmailAndTenantID(ctx, email, req.TenantID)
 	if err != nil || !user.IsActive.Bool {
-		return nil, errors.New(model.InvalidCredentials)
+		return nil, s.loginFailed(ctx, lockKey)
 	}
 
 	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
-		return nil, errors.New(model.InvalidCredentials)
+		return nil, s.loginFailed(ctx, lockKey)
+	}
+
+	_ = s.cacheManager.ResetLoginFailures(ctx, lockKey)
+
+	if !user.EmailVerifiedAt.Valid {
+		tenant, err := s.tenantService.GetTenantByID(ctx, req.TenantID)
+		if err != nil {
+			return nil, errors.New(model.InvalidCredentials)
+		}
+		if tenant.RequireEmailVerification {
+			return nil, model.ErrEmailNotVerified
+		}
 	}
 
-	existingTokenID, err := s.cacheManager.GetTokenIDForUser(ctx, user.ID.String())
-	var session *model.UserSessionInfo
-	if err == nil && existingTokenID != "" {
-		session, _ = s.cacheManager.GetSession(ctx, existingTokenID)
-		_ = s.cacheManager.DeleteSession(ctx, existingTokenID)
+	if user.Role.String != model.RoleTenantAdmin.String() && user.Role.String != model.RoleUser.String() {
+		return nil, errors.New("only tenant admin or user can login here")
 	}
 
-	tokenID := uuid.New().String()
-	jwtData := model.JWTData{
+	return s.completeLogin(ctx, model.JWTData{
 		UserID:   user.ID.String(),
 		Email:    user.Email,
 		TenantID: user.TenantID.String(),
-		TokenID:  tokenID,
 		Role:     user.Role.String,
+	}, sessionInfo)
+}
+
+// AdminLogin authenticates a platform admin and returns tokens
+func (s *authService) AdminLogin(ctx context.Context, req *LoginRequest,
+	sessionInfo model.UserSessionInfo) (interface{}, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	lockKey := adminLockoutKey(email)
+
+	if err := s.checkLockout(ctx, lockKey); err != nil {
+		return nil, err
 	}
 
-	jwt, err := s.JwtManager.GenerateJWT(jwtData)
+	user, err := s.userService.GetUserByEmail(ctx, email)
+	if err != nil || !user.IsActive.Bool {
+		return nil, s.loginFailed(ctx, lockKey)
+	}
+
+	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
+		return nil, s.loginFailed(ctx, lockKey)
+	}
+
+	_ = s.cacheManager.ResetLoginFailures(ctx, lockKey)
+
+	if user.Role.String != model.RolePlatformAdmin.String() {
+		return nil, errors.New("not an admin user")
+	}
+
+	return s.completeLogin(ctx, model.JWTData{
+		UserID:   user.ID.String(),
+		Email:    user.Email,
+		TenantID: "",
+		Role:     user.Role.String,
+	}, sessionInfo)
+}
+
+// Lockouts are keyed by email (and tenant) rather than user ID so unknown
+// accounts lock exactly like real ones and cannot be told apart.
+func tenantLockoutKey(tenantID, email string) string {
+	return tenantID + ":" + email
+}
+
+func adminLockoutKey(email string) string {
+	return "platform:" + email
+}
+
+func (s *authService) checkLockout(ctx context.Context, key string) error {
+	remaining, err := s.cacheManager.GetLockout(ctx, key)
+	if err != nil || remaining <= 0 {
+		return nil
+	}
+	return &AccountLockedError{RetryAfter: remaining}
+}
+
+// loginFailed counts a failed attempt and, from LoginLockoutThreshold
+// consecutive failures on, locks the account for LoginLockoutBase doubled for
+// every further failure, capped at LoginLockoutMax.
+func (s *authService) loginFailed(ctx context.Context, key string) error {
+	failures, err := s.cacheManager.RecordLoginFailure(ctx, key, loginFailureTTL)
 	if err != nil {
-		return nil, errors.New("failed to generate token")
+		return errors.New(model.InvalidCredentials)
+	}
+
+	threshold := int64(s.cfg.LoginLockoutThreshold)
+	if threshold <= 0 || failures < threshold {
+		return errors.New(model.InvalidCredentials)
+	}
+
+	duration := s.cfg.LoginLockoutMax
+	if shift := failures - threshold; shift < 32 {
+		duration = min(s.cfg.LoginLockoutBase<<shift, s.cfg.LoginLockoutMax)
 	}
 
-	refresh, err := s.JwtManager.GenerateRefreshToken(jwtData)
+	if err := s.cacheManager.LockAccount(ctx, key, duration); err != nil {
+		return errors.New(model.InvalidCredentials)
+	}
+
+	s.logger.Warn("Account locked after repeated login failures",
+		zap.Int64("failures", failures), zap.Duration("duration", duration))
+	return &AccountLockedError{RetryAfter: duration}
+}
+
+// UnlockAccount clears a login lockout. Tenant admins pass their tenant ID and
+// may only unlock users of that tenant; platform admins pass an empty tenantID.
+func (s *authService) UnlockAccount(ctx context.Context, tenantID, userID string) error {
+	user, err := s.userService.GetUserByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("failed to generate refresh token")
+		return model.ErrUserNotFound
+	}
+	if tenantID != "" && user.TenantID.String() != tenantID {
+		return model.ErrUserNotFound
 	}
 
-	var sessionToStore model.UserSessionInfo
-	if session != nil {
-		sessionToStore = *session
-		sessionToStore.TokenID = tokenID
-		sessionToStore.LoginTime = time.Now()
-		sessionToStore.LastSeen = time.Now()
-		sessionToStore.IsActive = true
-	} else {
-		sessionToStore = sessionInfo
-		sessionToStore.UserID = user.ID.String()
-		sessionToStore.TokenID = tokenID
-		sessionToStore.LoginTime = time.Now()
-		sessionToStore.LastSeen = time.Now()
-		sessionToStore.IsActive = true
+	email := strings.ToLower(user.Email)
+	if err := s.cacheManager.UnlockAccount(ctx, tenantLockoutKey(user.TenantID.String(), email)); err != nil {
+		return err
+	}
+	if err := s.cacheManager.UnlockAccount(ctx, adminLockoutKey(email)); err != nil {
+		return err
 	}
 
-	s.cacheManager.SetSession(ctx, tokenID, &sessionToStore, utils.SessionExpiry)
+	s.logger.Info("Account unlocked", zap.String("userID", userID))
+	return nil
+}
 
-	return LoginResponse{
-		Auth: JwtAuthData{
-			AccessToken:  jwt,
-			RefreshToken: refresh,
-			ExpiresIn:    int(utils.SessionExpiry.Seconds()),
-			TokenType:    "Bearer",
-		},
+// Impersonate issues a short-lived access token that lets a support admin act
+// as a user. Platform admins may impersonate anyone but another platform
+// admin; tenant admins, when enabled, only the users of their own tenant. The
+// token has no refresh token, and its session belongs to the user so that
+// signing the user out everywhere also ends the impersonation.
+func (s *authService) Impersonate(ctx context.Context, impersonator *model.Claims,
+	req *ImpersonateRequest, sessionInfo model.UserSessionInfo) (ImpersonationResponse, error) {
+	target, err := s.userService.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		return ImpersonationResponse{}, model.ErrUserNotFound
+	}
+	if err := s.checkImpersonation(impersonator, target, req); err != nil {
+		return ImpersonationResponse{}, err
+	}
+
+	ttl := s.cfg.ImpersonationMaxTTL
+	if requested := time.Duration(req.TTLMinutes) * time.Minute; requested > 0 && requested < ttl {
+		ttl = requested
+	}
+
+	identity, err := s.withPermissions(ctx, model.JWTData{
+		UserID:             target.ID.String(),
+		Email:              target.Email,
+		TenantID:           target.TenantID.String(),
+		Role:               target.Role.String,
+		TokenID:            uuid.New().String(),
+		ImpersonatorID:     impersonator.UserID,
+		ImpersonationWrite: req.AllowWrites,
+		ImpersonationMoney: req.AllowMoneyMovement,
+		ExpiresIn:          ttl,
+	})
+	if err != nil {
+		return ImpersonationResponse{}, err
+	}
+
+	token, err := s.JwtManager.GenerateJWT(identity)
+	if err != nil {
+		return ImpersonationResponse{}, errors.New("failed to generate token")
+	}
+
+	now := time.Now()
+	sessionInfo.UserID = identity.UserID
+	sessionInfo.TokenID = identity.TokenID
+	sessionInfo.ImpersonatorID = impersonator.UserID
+	sessionInfo.LoginTime = now
+	sessionInfo.LastSeen = now
+	sessionInfo.IsActive = true
+	if err := s.cacheManager.SetSession(ctx, identity.TokenID, &sessionInfo, ttl); err != nil {
+		return ImpersonationResponse{}, errors.New("failed to store session")
+	}
+
+	s.logger.Info("Impersonation started", zap.String("impersonatorID", impersonator.UserID),
+		zap.String("userID", identity.UserID), zap.Bool("allowWrites", req.AllowWrites),
+		zap.Bool("allowMoneyMovement", req.AllowMoneyMovement))
+
+	return ImpersonationResponse{
+		AccessToken:        token,
+		ExpiresIn:          int(ttl.Seconds()),
+		ExpiresAt:          now.Add(ttl),
+		TokenType:          "Bearer",
+		ReadOnly:           !req.AllowWrites,
+		AllowMoneyMovement: req.AllowMoneyMovement,
 		User: User{
-			ID:        user.ID.String(),
-			Email:     user.Email,
-			FirstName: "",
-			LastName:  "",
-			TenantID:  user.TenantID.String(),
-			Role:      user.Role.String,
+			ID:       identity.UserID,
+			Email:    identity.Email,
+			TenantID: identity.TenantID,
+			Role:     identity.Role,
 		},
 	}, nil
 }
 
-// AdminLogin authenticates a platform admin and returns tokens
-func (s *authService) AdminLogin(ctx context.Context, req *LoginRequest,
+func (s *authService) checkImpersonation(impersonator *model.Claims, target dbsqlc.User,
+	req *ImpersonateRequest) error {
+	// Impersonation tokens cannot be used to start another impersonation
+	if impersonator.IsImpersonated() || impersonator.UserID == target.ID.String() {
+		return model.ErrImpersonationNotAllowed
+	}
+	if !target.IsActive.Bool || target.Role.String == model.RolePlatformAdmin.String() {
+		return model.ErrImpersonationNotAllowed
+	}
+	if req.AllowMoneyMovement && !req.AllowWrites {
+		return model.ErrInvalidInputError
+	}
+
+	switch impersonator.Role {
+	case model.RolePlatformAdmin.String():
+		return nil
+	case model.RoleTenantAdmin.String():
+		if !s.cfg.ImpersonationTenantAdmins || req.AllowMoneyMovement {
+			return model.ErrImpersonationNotAllowed
+		}
+		if target.Role.String != model.RoleUser.String() ||
+			target.TenantID.String() != impersonator.TenantID {
+			return model.ErrImpersonationNotAllowed
+		}
+		return nil
+	default:
+		return model.ErrImpersonationNotAllowed
+	}
+}
+
+// EndImpersonation revokes the impersonation session claims belongs to
+func (s *authService) EndImpersonation(ctx context.Context, claims *model.Claims) error {
+	if !claims.IsImpersonated() {
+		return model.ErrNotImpersonating
+	}
+	return s.revokeSession(ctx, claims.ID)
+}
+
+// completeLogin issues tokens once the password has been verified, unless the
+// user has TOTP enabled (or their role requires it), in which case it returns
+// a challenge to be completed with VerifyMFAChallenge.
+func (s *authService) completeLogin(ctx context.Context, identity model.JWTData,
 	sessionInfo model.UserSessionInfo) (interface{}, error) {
-	email := strings.ToLower(strings.TrimSpace(req.Email))
+	enrolled, err := s.mfaService.IsEnabled(ctx, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
 
-	user, err := s.userService.GetUserByEmail(ctx, email)
-	if err != nil || !user.IsActive.Bool {
-		return nil, errors.New(model.InvalidCredentials)
+	if enrolled || mfa.RequiredForRole(identity.Role) {
+		return s.createMFAChallenge(ctx, identity, sessionInfo, enrolled)
 	}
 
-	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
-		return nil, errors.New(model.InvalidCredentials)
+	return s.loginResponse(ctx, identity, sessionInfo)
+}
+
+func (s *authService) loginResponse(ctx context.Context, identity model.JWTData,
+	sessionInfo model.UserSessionInfo) (LoginResponse, error) {
+	identity, err := s.withPermissions(ctx, identity)
+	if err != nil {
+		return LoginResponse{}, err
 	}
 
-	if user.Role.String != model.RolePlatformAdmin.String() {
-		return nil, errors.New("not an admin user")
+	session := s.newSessionInfo(ctx, identity.UserID, sessionInfo)
+
+	authData, err := s.startSession(ctx, identity, session)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	return LoginResponse{
+		Auth: authData,
+		User: User{
+			ID:       identity.UserID,
+			Email:    identity.Email,
+			TenantID: identity.TenantID,
+			Role:     identity.Role,
+		},
+	}, nil
+}
+
+// withPermissions resolves the user's current permissions into the token
+// identity, so role changes take effect on the next login or refresh.
+func (s *authService) withPermissions(ctx context.Context, identity model.JWTData) (model.JWTData, error) {
+	permissions, err := s.rbacService.PermissionsFor(ctx, identity.UserID, identity.Role)
+	if err != nil {
+		s.logger.Error("Failed to resolve permissions", zap.String("userID", identity.UserID), zap.Error(err))
+		return identity, errors.New("failed to resolve permissions")
 	}
+	identity.Permissions = permissions
+	return identity, nil
+}
 
-	existingTokenID, err := s.cacheManager.GetTokenIDForUser(ctx, user.ID.String())
-	var session *model.UserSessionInfo
-	if err == nil && existingTokenID != "" {
-		session, _ = s.cacheManager.GetSession(ctx, existingTokenID)
-		_ = s.cacheManager.DeleteSession(ctx, existingTokenID)
+func (s *authService) createMFAChallenge(ctx context.Context, identity model.JWTData,
+	sessionInfo model.UserSessionInfo, enrolled bool) (MFAChallengeResponse, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return MFAChallengeResponse{}, err
+	}
+	challengeToken := hex.EncodeToString(raw)
+
+	challenge := &model.MFAChallenge{
+		UserID:   identity.UserID,
+		Email:    identity.Email,
+		TenantID: identity.TenantID,
+		Role:     identity.Role,
+		Enrolled: enrolled,
+		Session:  sessionInfo,
+	}
+	if err := s.cacheManager.SetMFAChallenge(ctx, challengeToken, challenge, mfaChallengeTTL); err != nil {
+		return MFAChallengeResponse{}, errors.New("failed to start two-factor challenge")
 	}
 
-	tokenID := uuid.New().String()
-	jwtData := model.JWTData{
-		UserID:   user.ID.String(),
-		Email:    user.Email,
-		TenantID: "",
-		TokenID:  tokenID,
-		Role:     user.Role.String,
+	return MFAChallengeResponse{
+		MFARequired:        true,
+		EnrollmentRequired: !enrolled,
+		ChallengeToken:     challengeToken,
+		ExpiresIn:          int(mfaChallengeTTL.Seconds()),
+	}, nil
+}
+
+// StartMFAEnrollment lets a user whose role requires 2FA set up TOTP in the
+// middle of logging in, using the challenge token as proof of the password.
+func (s *authService) StartMFAEnrollment(ctx context.Context, challengeToken string) (mfa.Enrollment, error) {
+	challenge, err := s.cacheManager.GetMFAChallenge(ctx, challengeToken)
+	if err != nil {
+		return mfa.Enrollment{}, model.ErrInvalidMFAChallenge
+	}
+	if challenge.Enrolled {
+		return mfa.Enrollment{}, model.ErrMFAAlreadyEnabled
 	}
 
-	jwt, err := s.JwtManager.GenerateJWT(jwtData)
+	return s.mfaService.Enroll(ctx, challenge.UserID, challenge.Email)
+}
+
+// VerifyMFAChallenge completes a two-step login. For users enrolling during
+// login the code also confirms the enrollment and recovery codes are returned.
+func (s *authService) VerifyMFAChallenge(ctx context.Context,
+	req *MFAVerifyRequest) (LoginResponse, error) {
+	challenge, err := s.cacheManager.GetMFAChallenge(ctx, req.ChallengeToken)
 	if err != nil {
-		return nil, errors.New("failed to generate token")
+		return LoginResponse{}, model.ErrInvalidMFAChallenge
 	}
 
-	refresh, err := s.JwtManager.GenerateRefreshToken(jwtData)
+	attempts, err := s.cacheManager.IncrMFAChallengeAttempts(ctx, req.ChallengeToken, mfaChallengeTTL)
 	if err != nil {
-		return nil, errors.New("failed to generate refresh token")
+		return LoginResponse{}, err
+	}
+	if attempts > maxMFAChallengeAttempts {
+		_ = s.cacheManager.DeleteMFAChallenge(ctx, req.ChallengeToken)
+		return LoginResponse{}, model.ErrInvalidMFAChallenge
 	}
 
-	var sessionToStore model.UserSessionInfo
-	if session != nil {
-		sessionToStore = *session
-		sessionToStore.TokenID = tokenID
-		sessionToStore.LoginTime = time.Now()
-		sessionToStore.LastSeen = time.Now()
-		sessionToStore.IsActive = true
+	var recoveryCodes []string
+	if challenge.Enrolled {
+		err = s.mfaService.Verify(ctx, challenge.UserID, req.Code)
 	} else {
-		sessionToStore = sessionInfo
-		sessionToStore.UserID = user.ID.String()
-		sessionToStore.TokenID = tokenID
-		sessionToStore.LoginTime = time.Now()
-		sessionToStore.LastSeen = time.Now()
-		sessionToStore.IsActive = true
+		recoveryCodes, err = s.mfaService.Confirm(ctx, challenge.UserID, req.Code)
+	}
+	if err != nil {
+		s.logger.Warn("MFA challenge failed", zap.String("userID", challenge.UserID), zap.Error(err))
+		return LoginResponse{}, err
 	}
 
-	s.cacheManager.SetSession(ctx, tokenID, &sessionToStore, utils.SessionExpiry)
+	if err := s.cacheManager.DeleteMFAChallenge(ctx, req.ChallengeToken); err != nil {
+		return LoginResponse{}, err
+	}
 
-	return LoginResponse{
-		Auth: JwtAuthData{
-			AccessToken:  jwt,
-			RefreshToken: refresh,
-			ExpiresIn:    int(utils.SessionExpiry.Seconds()),
-			TokenType:    "Bearer",
-		},
-		User: User{
-			ID:    user.ID.String(),
-			Email: user.Email,
-			Role:  user.Role.String,
-		},
-	}, nil
+	resp, err := s.loginResponse(ctx, model.JWTData{
+		UserID:   challenge.UserID,
+		Email:    challenge.Email,
+		TenantID: challenge.TenantID,
+		Role:     challenge.Role,
+	}, challenge.Session)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	resp.RecoveryCodes = recoveryCodes
+	return resp, nil
+}
+
+// newSessionInfo prepares session metadata for a new login and evicts the
+// user's least recently used sessions once MaxSessionsPerUser is reached.
+func (s *authService) newSessionInfo(ctx context.Context, userID string,
+	sessionInfo model.UserSessionInfo) model.UserSessionInfo {
+	sessionInfo.UserID = userID
+	sessionInfo.LoginTime = time.Now()
+
+	limit := s.cfg.MaxSessionsPerUser
+	if limit <= 0 {
+		return sessionInfo
+	}
+
+	sessions, err := s.cacheManager.ListSessionsForUser(ctx, userID)
+	if err != nil || len(sessions) < limit {
+		return sessionInfo
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen.Before(sessions[j].LastSeen)
+	})
+	for _, session := range sessions[:len(sessions)-limit+1] {
+		s.logger.Info("session cap reached, evicting oldest session",
+			zap.String("user_id", userID), zap.String("token_id", session.TokenID))
+		_ = s.revokeSession(ctx, session.TokenID)
+	}
+
+	return sessionInfo
+}
+
+// startSession issues an access token and the first refresh token of a new
+// refresh family, and stores both the session and the family in cache.
+func (s *authService) startSession(ctx context.Context, identity model.JWTData,
+	session model.UserSessionInfo) (JwtAuthData, error) {
+	familyID := uuid.New().String()
+	refreshTokenID := uuid.New().String()
+
+	authData, tokenID, err := s.issueTokens(identity, familyID, refreshTokenID)
+	if err != nil {
+		return JwtAuthData{}, err
+	}
+
+	family := &model.RefreshFamily{
+		FamilyID:       familyID,
+		UserID:         identity.UserID,
+		CurrentTokenID: refreshTokenID,
+		SessionTokenID: tokenID,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.cacheManager.SetRefreshFamily(ctx, family, s.JwtManager.RefreshTokenExpiry); err != nil {
+		return JwtAuthData{}, errors.New("failed to store refresh token")
+	}
+
+	session.TokenID = tokenID
+	session.FamilyID = familyID
+	session.LastSeen = time.Now()
+	session.IsActive = true
+	s.cacheManager.SetSession(ctx, tokenID, &session, s.JwtManager.RefreshTokenExpiry)
+
+	return authData, nil
+}
+
+// issueTokens signs a fresh access token (new session token ID) and a refresh
+// token with the given family and token ID.
+func (s *authService) issueTokens(identity model.JWTData, familyID,
+	refreshTokenID string) (JwtAuthData, string, error) {
+	tokenID := uuid.New().String()
+
+	accessData := identity
+	accessData.TokenID = tokenID
+	accessData.FamilyID = ""
+
+	jwt, err := s.JwtManager.GenerateJWT(accessData)
+	if err != nil {
+		return JwtAuthData{}, "", errors.New("failed to generate token")
+	}
+
+	refreshData := identity
+	refreshData.TokenID = refreshTokenID
+	refreshData.FamilyID = familyID
+
+	refresh, err := s.JwtManager.GenerateRefreshToken(refreshData)
+	if err != nil {
+		return JwtAuthData{}, "", errors.New("failed to generate refresh token")
+	}
+
+	return JwtAuthData{
+		AccessToken:  jwt,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.JwtManager.AccessTokenExpiry.Seconds()),
+		TokenType:    "Bearer",
+	}, tokenID, nil
 }
 
-// RefreshToken generates a new access and refresh token pair from a valid refresh token.
+// RefreshToken exchanges the current refresh token of a family for a new
+// access/refresh pair. Every refresh token is single use: presenting one that
+// has already been rotated revokes the whole family and every session of the user.
 func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (JwtAuthData, error) {
 	claims, err := s.JwtManager.VerifyRefreshToken(refreshToken)
-	if err != nil {
-		return JwtAuthData{}, errors.New("invalid or expired refresh token")
+	if err != nil || claims.FamilyID == "" {
+		return JwtAuthData{}, model.ErrInvalidRefreshToken
+	}
+
+	family, err := s.cacheManager.GetRefreshFamily(ctx, claims.FamilyID)
+	if err != nil || family.UserID != claims.UserID {
+		return JwtAuthData{}, model.ErrInvalidRefreshToken
+	}
+
+	if family.CurrentTokenID != claims.ID {
+		s.handleRefreshReuse(ctx, claims)
+		return JwtAuthData{}, model.ErrRefreshTokenReused
 	}
 
-	jwtData := model.JWTData{
+	identity, err := s.withPermissions(ctx, model.JWTData{
 		UserID:   claims.UserID,
 		Email:    claims.Email,
 		TenantID: claims.TenantID,
-		TokenID:  claims.ID,
 		Role:     claims.Role,
+	})
+	if err != nil {
+		return JwtAuthData{}, err
 	}
 
-	jwt, err := s.JwtManager.GenerateJWT(jwtData)
+	newRefreshTokenID := uuid.New().String()
+	authData, newTokenID, err := s.issueTokens(identity, family.FamilyID, newRefreshTokenID)
 	if err != nil {
-		return JwtAuthData{}, errors.New("failed to generate token")
+		return JwtAuthData{}, err
 	}
 
-	refresh, err := s.JwtManager.GenerateRefreshToken(jwtData)
+	err = s.cacheManager.RotateRefreshToken(ctx, family.FamilyID, claims.ID,
+		newRefreshTokenID, newTokenID, s.JwtManager.RefreshTokenExpiry)
+	switch {
+	case errors.Is(err, cache.ErrRefreshTokenMismatch):
+		// Lost a race with another refresh using the same token
+		s.handleRefreshReuse(ctx, claims)
+		return JwtAuthData{}, model.ErrRefreshTokenReused
+	case errors.Is(err, cache.ErrRefreshFamilyNotFound):
+		return JwtAuthData{}, model.ErrInvalidRefreshToken
+	case err != nil:
+		return JwtAuthData{}, errors.New("failed to rotate refresh token")
+	}
+
+	// Move the session onto the new access token and retire the old one
+	session, _ := s.cacheManager.GetSession(ctx, family.SessionTokenID)
+	if session == nil {
+		session = &model.UserSessionInfo{
+			UserID:    claims.UserID,
+			LoginTime: family.CreatedAt,
+		}
+	}
+	_ = s.cacheManager.DeleteSession(ctx, family.SessionTokenID)
+
+	session.TokenID = newTokenID
+	session.FamilyID = family.FamilyID
+	session.LastSeen = time.Now()
+	session.IsActive = true
+	s.cacheManager.SetSession(ctx, newTokenID, session, s.JwtManager.RefreshTokenExpiry)
+
+	return authData, nil
+}
+
+// handleRefreshReuse treats replay of a rotated refresh token as token theft.
+func (s *authService) handleRefreshReuse(ctx context.Context, claims *model.Claims) {
+	s.logger.Warn("refresh token reuse detected, revoking all sessions",
+		zap.String("user_id", claims.UserID),
+		zap.String("family_id", claims.FamilyID),
+	)
+
+	if err := s.revokeAllSessions(ctx, claims.UserID); err != nil {
+		s.logger.Error("failed to revoke sessions after refresh token reuse", zap.Error(err))
+	}
+}
+
+// revokeSession deletes a session and the refresh family attached to it
+func (s *authService) revokeSession(ctx context.Context, tokenID string) error {
+	session, err := s.cacheManager.GetSession(ctx, tokenID)
+	if err != nil || session == nil {
+		return model.ErrSessionNotFound
+	}
+
+	if session.FamilyID != "" {
+		if err := s.cacheManager.DeleteRefreshFamily(ctx, session.FamilyID); err != nil {
+			return err
+		}
+	}
+
+	return s.cacheManager.DeleteSession(ctx, tokenID)
+}
+
+// revokeAllSessions deletes every refresh family and session of a user
+func (s *authService) revokeAllSessions(ctx context.Context, userID string) error {
+	return s.revokeSessionsExcept(ctx, userID, "")
+}
+
+// revokeSessionsExcept deletes every session (and refresh family) of a user
+// other than keepTokenID.
+func (s *authService) revokeSessionsExcept(ctx context.Context, userID, keepTokenID string) error {
+	var keepFamilyID string
+
+	sessions, err := s.cacheManager.ListSessionsForUser(ctx, userID)
 	if err != nil {
-		return JwtAuthData{}, errors.New("failed to generate refresh token")
+		return err
+	}
+	for _, session := range sessions {
+		if session.TokenID == keepTokenID {
+			keepFamilyID = session.FamilyID
+			continue
+		}
+		_ = s.revokeSession(ctx, session.TokenID)
 	}
 
-	return JwtAuthData{
-		AccessToken:  jwt,
-		RefreshToken: refresh,
-		ExpiresIn:    int(utils.SessionExpiry.Seconds()),
-		TokenType:    "Bearer",
-	}, nil
+	// Families whose session already expired can still mint tokens; drop them too
+	familyIDs, err := s.cacheManager.ListRefreshFamiliesForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, familyID := range familyIDs {
+		if keepFamilyID != "" && familyID == keepFamilyID {
+			continue
+		}
+		if err := s.cacheManager.DeleteRefreshFamily(ctx, familyID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// Logout handles user logout
-func (s *authService) Logout(ctx context.Context, userId string) error {
-	tokenID, err := s.cacheManager.GetTokenIDForUser(ctx, userId)
+// ListSessions returns the user's active sessions, most recently used first
+func (s *authService) ListSessions(ctx context.Context, userID,
+	currentTokenID string) ([]Session, error) {
+	sessions, err := s.cacheManager.ListSessionsForUser(ctx, userID)
 	if err != nil {
-		return errors.New("failed to get token id for user")
+		return nil, err
 	}
 
-	if tokenID == "" {
-		return errors.New("no active session found for user")
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen.After(sessions[j].LastSeen)
+	})
+
+	result := make([]Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, toSession(session, currentTokenID))
 	}
+	return result, nil
+}
 
-	if err := s.cacheManager.DeleteSession(ctx, tokenID); err != nil {
+// RevokeSession revokes one of the user's own sessions by its session ID
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	sessions, err := s.cacheManager.ListSessionsForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.FamilyID == sessionID || session.TokenID == sessionID {
+			return s.revokeSession(ctx, session.TokenID)
+		}
+	}
+	return model.ErrSessionNotFound
+}
+
+// RevokeUserSessions signs a user out of every session, e.g. after an admin
+// deactivates them or changes their role.
+func (s *authService) RevokeUserSessions(ctx context.Context, userID string) error {
+	return s.revokeAllSessions(ctx, userID)
+}
+
+// RevokeOtherSessions signs the user out everywhere except the current session
+func (s *authService) RevokeOtherSessions(ctx context.Context, userID, currentTokenID string) error {
+	return s.revokeSessionsExcept(ctx, userID, currentTokenID)
+}
+
+// Logout revokes the session of the presented access token and its refresh family
+func (s *authService) Logout(ctx context.Context, tokenID string) error {
+	if err := s.revokeSession(ctx, tokenID); err != nil {
+		if errors.Is(err, model.ErrSessionNotFound) {
+			return errors.New("no active session found for user")
+		}
 		return errors.New("failed to delete session")
 	}
 
 	return nil
 }
+
+// RequestPasswordReset emails a single-use reset link. It succeeds whether or
+// not the account exists so the endpoint cannot be used to probe for emails.
+func (s *authService) RequestPasswordReset(ctx context.Context, req *ForgotPasswordRequest) error {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	user, err := s.userService.GetUserByEmailAndTenantID(ctx, email, req.TenantID)
+	if err != nil || !user.IsActive.Bool {
+		s.logger.Info("Password reset requested for unknown or inactive user")
+		return nil
+	}
+
+	userID := user.ID.String()
+	token, err := s.issueUserToken(ctx, userID, TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.cfg.AppBaseURL + "/reset-password?token=" + token
+	if err := s.mailer.Send(ctx, notifications.Message{
+		To:      []string{user.Email},
+		Subject: "Reset your password",
+		Text: "We received a request to reset your password.\n\n" +
+			"Use the link below within 30 minutes to choose a new one:\n" + link + "\n\n" +
+			"If you did not request this, you can ignore this email.",
+	}); err != nil {
+		s.logger.Error("Failed to send password reset email", zap.String("userID", userID), zap.Error(err))
+	}
+
+	return nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and signs the
+// user out of every session.
+func (s *authService) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	var userID string
+
+	err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		var err error
+		userID, err = s.consumeUserToken(ctx, s.Repo.WithTx(q), req.Token, TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		userTx := s.userService.WithTx(q)
+		if err := userTx.UpdatePassword(ctx, userID, req.Password); err != nil {
+			return err
+		}
+
+		// Following the emailed link proves ownership of the address
+		return userTx.MarkEmailVerified(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.revokeAllSessions(ctx, userID); err != nil {
+		s.logger.Error("Failed to revoke sessions after password reset", zap.String("userID", userID), zap.Error(err))
+	}
+
+	s.logger.Info("Password reset", zap.String("userID", userID))
+	return nil
+}
+
+// SendEmailVerification emails a verification link to the user
+func (s *authService) SendEmailVerification(ctx context.Context, userID string) error {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return model.ErrUserNotFound
+	}
+	if user.EmailVerifiedAt.Valid {
+		return model.ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueUserToken(ctx, userID, TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.cfg.AppBaseURL + "/verify-email?token=" + token
+	return s.mailer.Send(ctx, notifications.Message{
+		To:      []string{user.Email},
+		Subject: "Verify your email address",
+		Text: "Please confirm your email address by opening the link below within 24 hours:\n" +
+			link,
+	})
+}
+
+// VerifyEmail consumes a verification token and marks the email verified
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	return utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		userID, err := s.consumeUserToken(ctx, s.Repo.WithTx(q), token, TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+		return s.userService.WithTx(q).MarkEmailVerified(ctx, userID)
+	})
+}
+
+// issueUserToken invalidates outstanding tokens for the purpose and stores the
+// hash of a fresh random token, returning the plaintext for the email link.
+func (s *authService) issueUserToken(ctx context.Context, userID, purpose string,
+	ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := s.Repo.InvalidateUserTokens(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+	if _, err := s.Repo.CreateUserToken(ctx, userID, purpose, utils.HashString(token),
+		time.Now().Add(ttl)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeUserToken validates a token and marks it used, returning its user ID
+func (s *authService) consumeUserToken(ctx context.Context, repo Repository, token,
+	purpose string) (string, error) {
+	stored, err := repo.GetUserTokenByHash(ctx, utils.HashString(token), purpose)
+	if err != nil {
+		return "", model.ErrInvalidUserToken
+	}
+	if stored.UsedAt.Valid || time.Now().After(stored.ExpiresAt.Time) {
+		return "", model.ErrInvalidUserToken
+	}
+
+	ok, err := repo.ConsumeUserToken(ctx, stored.ID.String())
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", model.ErrInvalidUserToken
+	}
+
+	return stored.UserID.String(), nil
+}
@@ -0,0 +1,34 @@
+package exports
+
+import (
+	"codematic/internal/domain/transactions"
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"time"
+)
+
+type Service interface {
+	Request(ctx context.Context, req Request) (*Export, error)
+	Get(ctx context.Context, id, userID string) (*Export, error)
+	List(ctx context.Context, userID string) ([]*Export, error)
+	Open(ctx context.Context, id string, expires int64, signature string) (*Download, error)
+	ProcessPending(ctx context.Context) error
+	Cleanup(ctx context.Context) error
+}
+
+type Repository interface {
+	Create(ctx context.Context, id, tenantID, userID, format string, filters []byte) (db.TransactionExport, error)
+	Get(ctx context.Context, id string) (db.TransactionExport, error)
+	ListByUser(ctx context.Context, userID string, limit int) ([]db.TransactionExport, error)
+	Claim(ctx context.Context, staleBefore time.Time) (db.TransactionExport, error)
+	Complete(ctx context.Context, id, fileKey string, rowCount, fileSize int64, expiresAt time.Time) error
+	Fail(ctx context.Context, id, reason string) error
+	ListExpired(ctx context.Context, staleBefore time.Time) ([]db.TransactionExport, error)
+	Expire(ctx context.Context, id string) error
+	WithTx(q *db.Queries) Repository
+}
+
+// Lister streams the transactions an export contains
+type Lister interface {
+	Each(ctx context.Context, filter transactions.SearchFilter, fn func(*transactions.Transaction) error) error
+}
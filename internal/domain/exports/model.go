@@ -0,0 +1,77 @@
+package exports
+
+import (
+	"codematic/internal/domain/transactions"
+	"io"
+	"time"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+	StatusExpired    = "expired"
+
+	// DownloadPath is where signed download links point, relative to API_BASE_URL
+	DownloadPath = "/api/transactions/exports/%s/download"
+
+	// A job that has been processing this long is assumed to have died with
+	// its instance and is picked up again, up to maxAttempts times
+	staleAfter  = 30 * time.Minute
+	maxAttempts = 3
+
+	// Exports handled per scheduler run, so one busy run cannot starve the others
+	maxPerRun = 10
+
+	listLimit = 50
+)
+
+var contentTypes = map[string]string{
+	FormatCSV:  "text/csv",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var csvHeader = []string{
+	"id", "created_at", "reference", "type", "status", "amount", "fee", "currency",
+	"wallet_id", "provider_id", "tenant_id", "error_reason", "metadata",
+}
+
+// Request asks for the transactions matching Filter in Format. Filter must
+// already be scoped to what the requester may see.
+type Request struct {
+	UserID   string
+	TenantID string
+	Format   string
+	Filter   transactions.SearchFilter
+}
+
+type Export struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id,omitempty"`
+	UserID      string     `json:"user_id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	RowCount    int64      `json:"row_count"`
+	FileSize    int64      `json:"file_size"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+
+	// Signed link to the file, relative to the API host; only set on
+	// completed exports and valid until LinkExpiresAt
+	DownloadURL   string     `json:"download_url,omitempty"`
+	LinkExpiresAt *time.Time `json:"link_expires_at,omitempty"`
+}
+
+// Download is an open export file. The caller must close Body.
+type Download struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
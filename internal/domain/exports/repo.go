@@ -0,0 +1,121 @@
+package exports
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) Create(ctx context.Context, id, tenantID, userID, format string,
+	filters []byte) (db.TransactionExport, error) {
+	exportID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.TransactionExport{}, err
+	}
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.TransactionExport{}, err
+	}
+
+	var tid pgtype.UUID
+	if tenantID != "" {
+		if tid, err = utils.StringToPgUUID(tenantID); err != nil {
+			return db.TransactionExport{}, err
+		}
+	}
+
+	return r.q.CreateTransactionExport(ctx, db.CreateTransactionExportParams{
+		ID:       exportID,
+		TenantID: tid,
+		UserID:   uid,
+		Format:   format,
+		Filters:  filters,
+	})
+}
+
+func (r *repository) Get(ctx context.Context, id string) (db.TransactionExport, error) {
+	exportID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.TransactionExport{}, err
+	}
+	return r.q.GetTransactionExport(ctx, exportID)
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID string, limit int) ([]db.TransactionExport, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListTransactionExportsByUser(ctx, db.ListTransactionExportsByUserParams{
+		UserID:   uid,
+		RowLimit: int32(limit),
+	})
+}
+
+func (r *repository) Claim(ctx context.Context, staleBefore time.Time) (db.TransactionExport, error) {
+	return r.q.ClaimTransactionExport(ctx, db.ClaimTransactionExportParams{
+		StaleBefore: utils.ToPgTimestamptz(staleBefore),
+		MaxAttempts: maxAttempts,
+	})
+}
+
+func (r *repository) Complete(ctx context.Context, id, fileKey string, rowCount, fileSize int64,
+	expiresAt time.Time) error {
+	exportID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	return r.q.CompleteTransactionExport(ctx, db.CompleteTransactionExportParams{
+		FileKey:   utils.ToPgxText(fileKey),
+		RowCount:  rowCount,
+		FileSize:  fileSize,
+		ExpiresAt: utils.ToPgTimestamptz(expiresAt),
+		ID:        exportID,
+	})
+}
+
+func (r *repository) Fail(ctx context.Context, id, reason string) error {
+	exportID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	return r.q.FailTransactionExport(ctx, db.FailTransactionExportParams{
+		Error: utils.ToPgxText(reason),
+		ID:    exportID,
+	})
+}
+
+func (r *repository) ListExpired(ctx context.Context, staleBefore time.Time) ([]db.TransactionExport, error) {
+	return r.q.ListExpiredTransactionExports(ctx, db.ListExpiredTransactionExportsParams{
+		StaleBefore: utils.ToPgTimestamptz(staleBefore),
+		MaxAttempts: maxAttempts,
+	})
+}
+
+func (r *repository) Expire(ctx context.Context, id string) error {
+	exportID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	return r.q.ExpireTransactionExport(ctx, exportID)
+}
@@ -0,0 +1,356 @@
+package exports
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/domain/transactions"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/infrastructure/storage"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// maxRows caps a single export; narrower filters are needed beyond it
+const maxRows = 1000000
+
+type exportService struct {
+	DB     *db.DBConn
+	Repo   Repository
+	lister Lister
+	store  storage.BlobStore
+	cfg    *config.Config
+	logger *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the export service.
+func NewService(db *db.DBConn, lister Lister, store storage.BlobStore, cfg *config.Config,
+	logger *zap.Logger) Service {
+	return &exportService{
+		DB:     db,
+		Repo:   NewRepository(db.Queries, db.Pool),
+		lister: lister,
+		store:  store,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Request queues an export; the file is generated in the background by
+// ProcessPending.
+func (s *exportService) Request(ctx context.Context, req Request) (*Export, error) {
+	if _, ok := contentTypes[req.Format]; !ok {
+		return nil, fmt.Errorf("%w: format must be %s or %s", model.ErrInvalidInputError, FormatCSV, FormatXLSX)
+	}
+
+	req.Filter.Cursor, req.Filter.Limit = "", 0
+	if err := transactions.ValidateSearchFilter(&req.Filter); err != nil {
+		return nil, err
+	}
+
+	filters, err := json.Marshal(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.Repo.Create(ctx, uuid.NewString(), req.TenantID, req.UserID, req.Format, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainExport(row), nil
+}
+
+// Get returns one of the user's exports, with a fresh download link once it
+// has completed.
+func (s *exportService) Get(ctx context.Context, id, userID string) (*Export, error) {
+	row, err := s.getOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := toDomainExport(row)
+	if export.Status == StatusCompleted {
+		s.sign(export)
+	}
+	return export, nil
+}
+
+func (s *exportService) List(ctx context.Context, userID string) ([]*Export, error) {
+	rows, err := s.Repo.ListByUser(ctx, userID, listLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]*Export, len(rows))
+	for i, row := range rows {
+		exports[i] = toDomainExport(row)
+	}
+	return exports, nil
+}
+
+// Open checks a signed download link and opens the export file it points to.
+func (s *exportService) Open(ctx context.Context, id string, expires int64,
+	signature string) (*Download, error) {
+	if time.Now().Unix() > expires || !utils.VerifySignature(s.cfg.ExportSigningKey, linkPayload(id, expires), signature) {
+		return nil, model.ErrInvalidDownloadLink
+	}
+
+	row, err := s.Repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrExportNotFound
+		}
+		return nil, err
+	}
+	if row.Status != StatusCompleted || !row.FileKey.Valid {
+		return nil, model.ErrExportNotFound
+	}
+
+	body, err := s.store.Open(ctx, row.FileKey.String)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, model.ErrExportNotFound
+		}
+		return nil, err
+	}
+
+	return &Download{
+		FileName:    fmt.Sprintf("transactions-%s.%s", utils.FromPgTimestamptz(row.CreatedAt).UTC().Format("20060102-150405"), row.Format),
+		ContentType: contentTypes[row.Format],
+		Size:        row.FileSize,
+		Body:        body,
+	}, nil
+}
+
+// ProcessPending generates queued exports, including ones whose worker died
+// mid-way, up to maxPerRun per call. Instances claim exports with row locks,
+// so any number of them can run this concurrently.
+func (s *exportService) ProcessPending(ctx context.Context) error {
+	for i := 0; i < maxPerRun; i++ {
+		row, err := s.Repo.Claim(ctx, time.Now().Add(-staleAfter))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		id := utils.FromPgUUID(row.ID)
+		if err := s.generate(ctx, row); err != nil {
+			s.logger.Error("Failed to generate transaction export", zap.String("exportID", id), zap.Error(err))
+
+			reason := "export failed"
+			if errors.Is(err, model.ErrExportTooLarge) {
+				reason = err.Error()
+			}
+			if err := s.Repo.Fail(ctx, id, reason); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Cleanup deletes the files of exports past their retention, and gives up on
+// exports that kept dying mid-way.
+func (s *exportService) Cleanup(ctx context.Context) error {
+	rows, err := s.Repo.ListExpired(ctx, time.Now().Add(-staleAfter))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if row.FileKey.Valid {
+			if err := s.store.Delete(ctx, row.FileKey.String); err != nil {
+				return err
+			}
+		}
+		if err := s.Repo.Expire(ctx, utils.FromPgUUID(row.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate streams the matching transactions into the blob store
+func (s *exportService) generate(ctx context.Context, row dbsqlc.TransactionExport) error {
+	var filter transactions.SearchFilter
+	if err := json.Unmarshal(row.Filters, &filter); err != nil {
+		return err
+	}
+
+	id := utils.FromPgUUID(row.ID)
+	key := fmt.Sprintf("exports/%s.%s", id, row.Format)
+
+	var count int64
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := s.write(ctx, pw, row.Format, filter, &count)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	size, err := s.store.Put(ctx, key, pr)
+	// Unblocks the writer if the store gave up early
+	pr.CloseWithError(err)
+	if writeErr := <-written; writeErr != nil {
+		err = writeErr
+	}
+	if err != nil {
+		_ = s.store.Delete(ctx, key)
+		return err
+	}
+
+	return s.Repo.Complete(ctx, id, key, count, size, time.Now().Add(s.cfg.ExportRetention))
+}
+
+func (s *exportService) write(ctx context.Context, w io.Writer, format string,
+	filter transactions.SearchFilter, count *int64) error {
+	if format == FormatXLSX {
+		xw, err := utils.NewXLSXWriter(w, "Transactions")
+		if err != nil {
+			return err
+		}
+		header := make([]any, len(csvHeader))
+		for i, h := range csvHeader {
+			header[i] = h
+		}
+		if err := xw.WriteRow(header...); err != nil {
+			return err
+		}
+
+		err = s.each(ctx, filter, count, func(tx *transactions.Transaction) error {
+			return xw.WriteRow(tx.ID, tx.CreatedAt.UTC().Format(time.RFC3339),
+				utils.SpreadsheetText(tx.Reference), tx.Type, tx.Status, tx.Amount, tx.Fee, tx.CurrencyCode,
+				tx.WalletID, tx.ProviderID, tx.TenantID, utils.SpreadsheetText(tx.ErrorReason), metadataJSON(tx))
+		})
+		if err != nil {
+			return err
+		}
+		return xw.Close()
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	err := s.each(ctx, filter, count, func(tx *transactions.Transaction) error {
+		return cw.Write([]string{
+			tx.ID,
+			tx.CreatedAt.UTC().Format(time.RFC3339),
+			utils.SpreadsheetText(tx.Reference),
+			tx.Type,
+			tx.Status,
+			tx.Amount.String(),
+			tx.Fee.String(),
+			tx.CurrencyCode,
+			tx.WalletID,
+			tx.ProviderID,
+			tx.TenantID,
+			utils.SpreadsheetText(tx.ErrorReason),
+			metadataJSON(tx),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// each walks the export's transactions, counting them and enforcing maxRows
+func (s *exportService) each(ctx context.Context, filter transactions.SearchFilter, count *int64,
+	fn func(*transactions.Transaction) error) error {
+	return s.lister.Each(ctx, filter, func(tx *transactions.Transaction) error {
+		if *count >= maxRows {
+			return fmt.Errorf("%w: more than %d transactions match, narrow the filters",
+				model.ErrExportTooLarge, maxRows)
+		}
+		*count++
+		return fn(tx)
+	})
+}
+
+func (s *exportService) getOwned(ctx context.Context, id, userID string) (dbsqlc.TransactionExport, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return dbsqlc.TransactionExport{}, model.ErrExportNotFound
+	}
+
+	row, err := s.Repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbsqlc.TransactionExport{}, model.ErrExportNotFound
+		}
+		return dbsqlc.TransactionExport{}, err
+	}
+
+	// Exports are private to whoever requested them
+	if utils.FromPgUUID(row.UserID) != userID {
+		return dbsqlc.TransactionExport{}, model.ErrExportNotFound
+	}
+	return row, nil
+}
+
+// sign attaches a download link that expires after ExportLinkTTL, or with the
+// export, whichever is sooner
+func (s *exportService) sign(export *Export) {
+	expiresAt := time.Now().Add(s.cfg.ExportLinkTTL)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expiresAt) {
+		expiresAt = *export.ExpiresAt
+	}
+
+	expires := expiresAt.Unix()
+	signature := utils.SignString(s.cfg.ExportSigningKey, linkPayload(export.ID, expires))
+
+	export.DownloadURL = s.cfg.APIBaseURL + fmt.Sprintf(DownloadPath, export.ID) +
+		"?expires=" + strconv.FormatInt(expires, 10) + "&signature=" + signature
+	export.LinkExpiresAt = &expiresAt
+}
+
+func linkPayload(id string, expires int64) string {
+	return "export:" + id + ":" + strconv.FormatInt(expires, 10)
+}
+
+func metadataJSON(tx *transactions.Transaction) string {
+	if len(tx.Metadata) == 0 {
+		return ""
+	}
+	raw, _ := json.Marshal(tx.Metadata)
+	return string(raw)
+}
+
+func toDomainExport(row dbsqlc.TransactionExport) *Export {
+	export := &Export{
+		ID:        utils.FromPgUUID(row.ID),
+		TenantID:  utils.FromPgUUID(row.TenantID),
+		UserID:    utils.FromPgUUID(row.UserID),
+		Format:    row.Format,
+		Status:    row.Status,
+		RowCount:  row.RowCount,
+		FileSize:  row.FileSize,
+		Error:     row.Error.String,
+		CreatedAt: utils.FromPgTimestamptz(row.CreatedAt),
+	}
+	if row.CompletedAt.Valid {
+		t := row.CompletedAt.Time
+		export.CompletedAt = &t
+	}
+	if row.ExpiresAt.Valid {
+		t := row.ExpiresAt.Time
+		export.ExpiresAt = &t
+	}
+	return export
+}
@@ -0,0 +1,42 @@
+package fees
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+)
+
+type Service interface {
+	Quote(ctx context.Context, in QuoteInput) (*Quote, error)
+
+	CreatePlan(ctx context.Context, req PlanRequest) (*Plan, error)
+	UpdatePlan(ctx context.Context, id string, req PlanRequest) (*Plan, error)
+	GetPlan(ctx context.Context, id string) (*Plan, error)
+	ListPlans(ctx context.Context) ([]*Plan, error)
+	DeletePlan(ctx context.Context, id string) error
+	AssignPlan(ctx context.Context, tenantID string, req AssignPlanRequest) (*Plan, error)
+	GetTenantPlan(ctx context.Context, tenantID string) (*Plan, error)
+
+	ListRevenue(ctx context.Context) ([]RevenueBalance, error)
+}
+
+type Repository interface {
+	CreatePlan(ctx context.Context, arg db.CreatePricingPlanParams) (db.PricingPlan, error)
+	UpdatePlan(ctx context.Context, arg db.UpdatePricingPlanParams) (db.PricingPlan, error)
+	// ClearDefaultPlan unsets the default flag on every plan but keepID
+	ClearDefaultPlan(ctx context.Context, keepID string) error
+	GetPlan(ctx context.Context, id string) (db.PricingPlan, error)
+	GetDefaultPlan(ctx context.Context) (db.PricingPlan, error)
+	ListPlans(ctx context.Context) ([]db.PricingPlan, error)
+	DeletePlan(ctx context.Context, id string) (bool, error)
+
+	CreateRule(ctx context.Context, arg db.CreatePricingRuleParams) error
+	DeleteRules(ctx context.Context, planID string) error
+	ListRules(ctx context.Context, planID string) ([]db.PricingRule, error)
+
+	GetTenantPlan(ctx context.Context, tenantID string) (db.PricingPlan, error)
+	SetTenantPlan(ctx context.Context, tenantID, planID string) error
+	ClearTenantPlan(ctx context.Context, tenantID string) error
+
+	ListRevenueWallets(ctx context.Context) ([]db.RevenueWallet, error)
+	WithTx(q *db.Queries) Repository
+}
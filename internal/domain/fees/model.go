@@ -0,0 +1,133 @@
+package fees
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TypeDeposit    = "deposit"
+	TypeWithdrawal = "withdrawal"
+	TypeTransfer   = "transfer"
+
+	FeeFlat       = "flat"
+	FeePercentage = "percentage"
+	FeeTiered     = "tiered"
+
+	// BearerPayer adds the fee to what the payer is charged; BearerReceiver
+	// takes it out of what the receiver gets
+	BearerPayer    = "payer"
+	BearerReceiver = "receiver"
+
+	// Most rules a single plan may hold
+	maxRulesPerPlan = 100
+)
+
+var hundred = decimal.NewFromInt(100)
+
+type (
+	// Tier prices amounts up to and including UpTo. A nil UpTo has no upper
+	// bound and must be the last tier.
+	Tier struct {
+		UpTo       *decimal.Decimal `json:"up_to,omitempty"`
+		Flat       decimal.Decimal  `json:"flat"`
+		Percentage decimal.Decimal  `json:"percentage"`
+	}
+
+	// Rule prices one transaction type. Channel, CurrencyCode and ProviderID
+	// narrow it when set, and the most specific matching rule applies.
+	Rule struct {
+		ID              string           `json:"id"`
+		TransactionType string           `json:"transaction_type"`
+		Channel         string           `json:"channel,omitempty"`
+		CurrencyCode    string           `json:"currency_code,omitempty"`
+		ProviderID      string           `json:"provider_id,omitempty"`
+		FeeType         string           `json:"fee_type"`
+		FlatAmount      decimal.Decimal  `json:"flat_amount"`
+		Percentage      decimal.Decimal  `json:"percentage"`
+		Tiers           []Tier           `json:"tiers,omitempty"`
+		MinFee          *decimal.Decimal `json:"min_fee,omitempty"`
+		MaxFee          *decimal.Decimal `json:"max_fee,omitempty"`
+		Bearer          string           `json:"bearer"`
+	}
+
+	Plan struct {
+		ID          string    `json:"id"`
+		Name        string    `json:"name"`
+		Description string    `json:"description,omitempty"`
+		IsDefault   bool      `json:"is_default"`
+		Rules       []Rule    `json:"rules"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+
+	// PlanRequest creates a plan or replaces one, rules included. A default
+	// plan applies to every tenant without a plan of its own.
+	PlanRequest struct {
+		Name        string        `json:"name" validate:"required,max=100"`
+		Description string        `json:"description" validate:"max=500"`
+		IsDefault   bool          `json:"is_default"`
+		Rules       []RuleRequest `json:"rules" validate:"dive"`
+	}
+
+	RuleRequest struct {
+		TransactionType string           `json:"transaction_type" validate:"required,oneof=deposit withdrawal transfer"`
+		Channel         string           `json:"channel" validate:"max=50"`
+		CurrencyCode    string           `json:"currency_code" validate:"omitempty,len=3"`
+		ProviderID      string           `json:"provider_id" validate:"omitempty,uuid"`
+		FeeType         string           `json:"fee_type" validate:"required,oneof=flat percentage tiered"`
+		FlatAmount      decimal.Decimal  `json:"flat_amount"`
+		Percentage      decimal.Decimal  `json:"percentage"`
+		Tiers           []Tier           `json:"tiers"`
+		MinFee          *decimal.Decimal `json:"min_fee"`
+		MaxFee          *decimal.Decimal `json:"max_fee"`
+		Bearer          string           `json:"bearer" validate:"omitempty,oneof=payer receiver"`
+	}
+
+	// AssignPlanRequest sets a tenant's plan; an empty PlanID returns the
+	// tenant to the default plan
+	AssignPlanRequest struct {
+		PlanID string `json:"plan_id" validate:"omitempty,uuid"`
+	}
+
+	QuoteRequest struct {
+		TransactionType string `json:"transaction_type" validate:"required,oneof=deposit withdrawal transfer"`
+		Amount          string `json:"amount" validate:"required,numeric"`
+		Currency        string `json:"currency" validate:"required,len=3"`
+		Channel         string `json:"channel" validate:"max=50"`
+	}
+
+	// QuoteInput is what a fee is priced on. Deposits without a ProviderID
+	// are priced on the provider that would be chosen for them now.
+	QuoteInput struct {
+		TenantID        string
+		TransactionType string
+		Amount          decimal.Decimal
+		Currency        string
+		Channel         string
+		ProviderID      string
+	}
+
+	// Quote is the fee on an amount and who bears it. Debit is what the payer
+	// is charged and Credit what the receiver gets.
+	Quote struct {
+		TransactionType string          `json:"transaction_type"`
+		Currency        string          `json:"currency"`
+		Amount          decimal.Decimal `json:"amount"`
+		Fee             decimal.Decimal `json:"fee"`
+		Bearer          string          `json:"bearer"`
+		Debit           decimal.Decimal `json:"debit"`
+		Credit          decimal.Decimal `json:"credit"`
+		ProviderID      string          `json:"provider_id,omitempty"`
+		PlanID          string          `json:"plan_id,omitempty"`
+		RuleID          string          `json:"rule_id,omitempty"`
+	}
+
+	// RevenueBalance is the platform's fee revenue in one currency
+	RevenueBalance struct {
+		Currency  string          `json:"currency"`
+		Balance   decimal.Decimal `json:"balance"`
+		UpdatedAt time.Time       `json:"updated_at"`
+	}
+)
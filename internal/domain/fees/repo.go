@@ -0,0 +1,118 @@
+package fees
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) CreatePlan(ctx context.Context, arg db.CreatePricingPlanParams) (db.PricingPlan, error) {
+	return r.q.CreatePricingPlan(ctx, arg)
+}
+
+func (r *repository) UpdatePlan(ctx context.Context, arg db.UpdatePricingPlanParams) (db.PricingPlan, error) {
+	return r.q.UpdatePricingPlan(ctx, arg)
+}
+
+func (r *repository) ClearDefaultPlan(ctx context.Context, keepID string) error {
+	id, err := utils.StringToPgUUID(keepID)
+	if err != nil {
+		return err
+	}
+	return r.q.ClearDefaultPricingPlan(ctx, id)
+}
+
+func (r *repository) GetPlan(ctx context.Context, id string) (db.PricingPlan, error) {
+	planID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.PricingPlan{}, err
+	}
+	return r.q.GetPricingPlan(ctx, planID)
+}
+
+func (r *repository) GetDefaultPlan(ctx context.Context) (db.PricingPlan, error) {
+	return r.q.GetDefaultPricingPlan(ctx)
+}
+
+func (r *repository) ListPlans(ctx context.Context) ([]db.PricingPlan, error) {
+	return r.q.ListPricingPlans(ctx)
+}
+
+func (r *repository) DeletePlan(ctx context.Context, id string) (bool, error) {
+	planID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.DeletePricingPlan(ctx, planID)
+	return n > 0, err
+}
+
+func (r *repository) CreateRule(ctx context.Context, arg db.CreatePricingRuleParams) error {
+	return r.q.CreatePricingRule(ctx, arg)
+}
+
+func (r *repository) DeleteRules(ctx context.Context, planID string) error {
+	id, err := utils.StringToPgUUID(planID)
+	if err != nil {
+		return err
+	}
+	return r.q.DeletePricingRules(ctx, id)
+}
+
+func (r *repository) ListRules(ctx context.Context, planID string) ([]db.PricingRule, error) {
+	id, err := utils.StringToPgUUID(planID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListPricingRules(ctx, id)
+}
+
+func (r *repository) GetTenantPlan(ctx context.Context, tenantID string) (db.PricingPlan, error) {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.PricingPlan{}, err
+	}
+	return r.q.GetTenantPricingPlan(ctx, tid)
+}
+
+func (r *repository) SetTenantPlan(ctx context.Context, tenantID, planID string) error {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return err
+	}
+	pid, err := utils.StringToPgUUID(planID)
+	if err != nil {
+		return err
+	}
+	return r.q.SetTenantPricingPlan(ctx, db.SetTenantPricingPlanParams{TenantID: tid, PlanID: pid})
+}
+
+func (r *repository) ClearTenantPlan(ctx context.Context, tenantID string) error {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return err
+	}
+	return r.q.ClearTenantPricingPlan(ctx, tid)
+}
+
+func (r *repository) ListRevenueWallets(ctx context.Context) ([]db.RevenueWallet, error) {
+	return r.q.ListRevenueWallets(ctx)
+}
@@ -0,0 +1,528 @@
+package fees
+
+import (
+	"codematic/internal/domain/provider"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type feeService struct {
+	DB       *db.DBConn
+	Repo     Repository
+	provider provider.Service
+	logger   *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the fee service.
+func NewService(db *db.DBConn, providerService provider.Service, logger *zap.Logger) Service {
+	return &feeService{
+		DB:       db,
+		Repo:     NewRepository(db.Queries, db.Pool),
+		provider: providerService,
+		logger:   logger,
+	}
+}
+
+// Quote prices a transaction under its tenant's plan, falling back to the
+// default plan. Without either, or without a matching rule, it is free.
+func (s *feeService) Quote(ctx context.Context, in QuoteInput) (*Quote, error) {
+	if !in.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: amount must be positive", model.ErrInvalidInputError)
+	}
+	switch in.TransactionType {
+	case TypeDeposit, TypeWithdrawal, TypeTransfer:
+	default:
+		return nil, fmt.Errorf("%w: unknown transaction type %q", model.ErrInvalidInputError, in.TransactionType)
+	}
+	in.Currency = strings.ToUpper(in.Currency)
+
+	if in.TransactionType == TypeDeposit && in.ProviderID == "" && in.Channel != "" {
+		// Best effort: without a provider, provider-specific rules are skipped
+		if id, err := s.provider.SelectDepositProvider(ctx, in.Currency, in.Channel); err == nil {
+			in.ProviderID = id
+		}
+	}
+
+	quote := &Quote{
+		TransactionType: in.TransactionType,
+		Currency:        in.Currency,
+		Amount:          in.Amount,
+		Fee:             decimal.Zero,
+		Bearer:          BearerPayer,
+		ProviderID:      in.ProviderID,
+	}
+
+	plan, err := s.effectivePlan(ctx, in.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		quote.PlanID = utils.FromPgUUID(plan.ID)
+		rows, err := s.Repo.ListRules(ctx, quote.PlanID)
+		if err != nil {
+			return nil, err
+		}
+		if rule := matchRule(toRules(rows), in); rule != nil {
+			quote.RuleID = rule.ID
+			quote.Bearer = rule.Bearer
+			quote.Fee = rule.compute(in.Amount, in.Currency)
+		}
+	}
+
+	quote.Debit, quote.Credit = in.Amount, in.Amount
+	if quote.Bearer == BearerReceiver {
+		if quote.Fee.GreaterThanOrEqual(in.Amount) {
+			return nil, fmt.Errorf("%w: fee of %s %s leaves nothing of %s", model.ErrFeeExceedsAmount,
+				quote.Fee.String(), in.Currency, in.Amount.String())
+		}
+		quote.Credit = in.Amount.Sub(quote.Fee)
+	} else {
+		quote.Debit = in.Amount.Add(quote.Fee)
+	}
+	return quote, nil
+}
+
+// effectivePlan returns the tenant's plan, the default plan, or nil
+func (s *feeService) effectivePlan(ctx context.Context, tenantID string) (*dbsqlc.PricingPlan, error) {
+	if tenantID != "" {
+		plan, err := s.Repo.GetTenantPlan(ctx, tenantID)
+		if err == nil {
+			return &plan, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	plan, err := s.Repo.GetDefaultPlan(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// matchRule returns the most specific rule matching in. Rules are in plan
+// order, so among equally specific rules the first one wins.
+func matchRule(rules []Rule, in QuoteInput) *Rule {
+	var best *Rule
+	bestScore := -1
+	for i := range rules {
+		r := &rules[i]
+		if r.TransactionType != in.TransactionType {
+			continue
+		}
+		score := 0
+		if r.Channel != "" {
+			if !strings.EqualFold(r.Channel, in.Channel) {
+				continue
+			}
+			score++
+		}
+		if r.CurrencyCode != "" {
+			if r.CurrencyCode != in.Currency {
+				continue
+			}
+			score++
+		}
+		if r.ProviderID != "" {
+			if r.ProviderID != in.ProviderID {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+	return best
+}
+
+// compute returns the rule's fee on amount, capped to its minimum and
+// maximum and rounded to the currency's minor unit
+func (r *Rule) compute(amount decimal.Decimal, currency string) decimal.Decimal {
+	var fee decimal.Decimal
+	switch r.FeeType {
+	case FeeFlat:
+		fee = r.FlatAmount
+	case FeePercentage:
+		fee = amount.Mul(r.Percentage).Div(hundred).Add(r.FlatAmount)
+	case FeeTiered:
+		for _, t := range r.Tiers {
+			if t.UpTo == nil || amount.LessThanOrEqual(*t.UpTo) {
+				fee = amount.Mul(t.Percentage).Div(hundred).Add(t.Flat)
+				break
+			}
+		}
+	}
+
+	if r.MinFee != nil && fee.LessThan(*r.MinFee) {
+		fee = *r.MinFee
+	}
+	if r.MaxFee != nil && fee.GreaterThan(*r.MaxFee) {
+		fee = *r.MaxFee
+	}
+	return fee.Round(utils.MinorUnits(currency))
+}
+
+func (s *feeService) CreatePlan(ctx context.Context, req PlanRequest) (*Plan, error) {
+	if err := normalizePlan(&req); err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewString()
+	err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+		if req.IsDefault {
+			if err := repo.ClearDefaultPlan(ctx, id); err != nil {
+				return err
+			}
+		}
+		pid, _ := utils.StringToPgUUID(id)
+		if _, err := repo.CreatePlan(ctx, dbsqlc.CreatePricingPlanParams{
+			ID:          pid,
+			Name:        req.Name,
+			Description: optionalText(req.Description),
+			IsDefault:   req.IsDefault,
+		}); err != nil {
+			return err
+		}
+		return createRules(ctx, repo, pid, req.Rules)
+	})
+	if err != nil {
+		return nil, mapPlanWriteError(err)
+	}
+	return s.GetPlan(ctx, id)
+}
+
+// UpdatePlan replaces a plan's details and all of its rules
+func (s *feeService) UpdatePlan(ctx context.Context, id string, req PlanRequest) (*Plan, error) {
+	pid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return nil, model.ErrPricingPlanNotFound
+	}
+	if err := normalizePlan(&req); err != nil {
+		return nil, err
+	}
+
+	err = utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+		if req.IsDefault {
+			if err := repo.ClearDefaultPlan(ctx, id); err != nil {
+				return err
+			}
+		}
+		if _, err := repo.UpdatePlan(ctx, dbsqlc.UpdatePricingPlanParams{
+			ID:          pid,
+			Name:        req.Name,
+			Description: optionalText(req.Description),
+			IsDefault:   req.IsDefault,
+		}); err != nil {
+			return err
+		}
+		if err := repo.DeleteRules(ctx, id); err != nil {
+			return err
+		}
+		return createRules(ctx, repo, pid, req.Rules)
+	})
+	if err != nil {
+		return nil, mapPlanWriteError(err)
+	}
+	return s.GetPlan(ctx, id)
+}
+
+func createRules(ctx context.Context, repo Repository, planID pgtype.UUID, rules []RuleRequest) error {
+	for i, r := range rules {
+		var tiers []byte
+		if r.FeeType == FeeTiered {
+			tiers, _ = json.Marshal(r.Tiers)
+		}
+		providerID := pgtype.UUID{}
+		if r.ProviderID != "" {
+			providerID, _ = utils.StringToPgUUID(r.ProviderID)
+		}
+		rid, _ := utils.StringToPgUUID(uuid.NewString())
+		if err := repo.CreateRule(ctx, dbsqlc.CreatePricingRuleParams{
+			ID:              rid,
+			PlanID:          planID,
+			TransactionType: r.TransactionType,
+			Channel:         optionalText(r.Channel),
+			CurrencyCode:    optionalText(r.CurrencyCode),
+			ProviderID:      providerID,
+			FeeType:         r.FeeType,
+			FlatAmount:      r.FlatAmount,
+			Percentage:      r.Percentage,
+			Tiers:           tiers,
+			MinFee:          r.MinFee,
+			MaxFee:          r.MaxFee,
+			Bearer:          r.Bearer,
+			Position:        int32(i),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizePlan checks a plan's rules beyond what struct validation can,
+// filling in defaults
+func normalizePlan(req *PlanRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("%w: name is required", model.ErrInvalidInputError)
+	}
+	if len(req.Rules) > maxRulesPerPlan {
+		return fmt.Errorf("%w: a plan may have at most %d rules", model.ErrInvalidInputError, maxRulesPerPlan)
+	}
+	for i := range req.Rules {
+		if err := normalizeRule(&req.Rules[i]); err != nil {
+			return fmt.Errorf("%w: rule %d: %s", model.ErrInvalidInputError, i+1, err.Error())
+		}
+	}
+	return nil
+}
+
+func normalizeRule(r *RuleRequest) error {
+	r.Channel = strings.ToLower(strings.TrimSpace(r.Channel))
+	r.CurrencyCode = strings.ToUpper(r.CurrencyCode)
+	if r.Bearer == "" {
+		r.Bearer = BearerPayer
+	}
+
+	if r.FlatAmount.IsNegative() {
+		return errors.New("flat_amount cannot be negative")
+	}
+	if err := checkPercentage(r.Percentage); err != nil {
+		return err
+	}
+	if r.MinFee != nil && r.MinFee.IsNegative() || r.MaxFee != nil && r.MaxFee.IsNegative() {
+		return errors.New("min_fee and max_fee cannot be negative")
+	}
+	if r.MinFee != nil && r.MaxFee != nil && r.MinFee.GreaterThan(*r.MaxFee) {
+		return errors.New("min_fee cannot exceed max_fee")
+	}
+
+	switch r.FeeType {
+	case FeePercentage:
+		if !r.Percentage.IsPositive() {
+			return errors.New("percentage rules need a positive percentage")
+		}
+	case FeeTiered:
+		if len(r.Tiers) == 0 {
+			return errors.New("tiered rules need at least one tier")
+		}
+		var prev *decimal.Decimal
+		for i, t := range r.Tiers {
+			if t.Flat.IsNegative() {
+				return fmt.Errorf("tier %d: flat cannot be negative", i+1)
+			}
+			if err := checkPercentage(t.Percentage); err != nil {
+				return fmt.Errorf("tier %d: %s", i+1, err.Error())
+			}
+			last := i == len(r.Tiers)-1
+			if t.UpTo == nil {
+				if !last {
+					return fmt.Errorf("tier %d: only the last tier may omit up_to", i+1)
+				}
+				continue
+			}
+			if last {
+				return errors.New("the last tier must omit up_to so every amount is priced")
+			}
+			if !t.UpTo.IsPositive() || prev != nil && !t.UpTo.GreaterThan(*prev) {
+				return fmt.Errorf("tier %d: up_to must be positive and increasing", i+1)
+			}
+			prev = t.UpTo
+		}
+	}
+	return nil
+}
+
+func checkPercentage(p decimal.Decimal) error {
+	if p.IsNegative() || p.GreaterThan(hundred) {
+		return errors.New("percentage must be between 0 and 100")
+	}
+	return nil
+}
+
+func (s *feeService) GetPlan(ctx context.Context, id string) (*Plan, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrPricingPlanNotFound
+	}
+	row, err := s.Repo.GetPlan(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrPricingPlanNotFound
+		}
+		return nil, err
+	}
+	return s.withRules(ctx, row)
+}
+
+func (s *feeService) ListPlans(ctx context.Context) ([]*Plan, error) {
+	rows, err := s.Repo.ListPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]*Plan, 0, len(rows))
+	for _, row := range rows {
+		plan, err := s.withRules(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// DeletePlan removes a plan. Tenants assigned to it go back to the default
+// plan.
+func (s *feeService) DeletePlan(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return model.ErrPricingPlanNotFound
+	}
+	deleted, err := s.Repo.DeletePlan(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return model.ErrPricingPlanNotFound
+	}
+	return nil
+}
+
+// AssignPlan sets the plan a tenant is priced on and returns the plan that
+// now applies to it, which is nil if it has none and there is no default
+func (s *feeService) AssignPlan(ctx context.Context, tenantID string, req AssignPlanRequest) (*Plan, error) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return nil, model.ErrInvalidTenantIDFormat
+	}
+
+	if req.PlanID == "" {
+		if err := s.Repo.ClearTenantPlan(ctx, tenantID); err != nil {
+			return nil, err
+		}
+		return s.GetTenantPlan(ctx, tenantID)
+	}
+
+	if _, err := s.GetPlan(ctx, req.PlanID); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.SetTenantPlan(ctx, tenantID, req.PlanID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("%w: unknown tenant", model.ErrInvalidInputError)
+		}
+		return nil, err
+	}
+	return s.GetTenantPlan(ctx, tenantID)
+}
+
+// GetTenantPlan returns the plan a tenant is priced on, which is nil if it
+// has none and there is no default
+func (s *feeService) GetTenantPlan(ctx context.Context, tenantID string) (*Plan, error) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return nil, model.ErrInvalidTenantIDFormat
+	}
+	row, err := s.effectivePlan(ctx, tenantID)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return s.withRules(ctx, *row)
+}
+
+func (s *feeService) ListRevenue(ctx context.Context) ([]RevenueBalance, error) {
+	rows, err := s.Repo.ListRevenueWallets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]RevenueBalance, 0, len(rows))
+	for _, row := range rows {
+		balances = append(balances, RevenueBalance{
+			Currency:  row.CurrencyCode,
+			Balance:   row.Balance,
+			UpdatedAt: utils.FromPgTimestamptz(row.UpdatedAt),
+		})
+	}
+	return balances, nil
+}
+
+func (s *feeService) withRules(ctx context.Context, row dbsqlc.PricingPlan) (*Plan, error) {
+	plan := &Plan{
+		ID:          utils.FromPgUUID(row.ID),
+		Name:        row.Name,
+		Description: row.Description.String,
+		IsDefault:   row.IsDefault,
+		CreatedAt:   utils.FromPgTimestamptz(row.CreatedAt),
+		UpdatedAt:   utils.FromPgTimestamptz(row.UpdatedAt),
+	}
+	rules, err := s.Repo.ListRules(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	plan.Rules = toRules(rules)
+	return plan, nil
+}
+
+func toRules(rows []dbsqlc.PricingRule) []Rule {
+	rules := make([]Rule, 0, len(rows))
+	for _, row := range rows {
+		r := Rule{
+			ID:              utils.FromPgUUID(row.ID),
+			TransactionType: row.TransactionType,
+			Channel:         row.Channel.String,
+			CurrencyCode:    row.CurrencyCode.String,
+			ProviderID:      utils.FromPgUUID(row.ProviderID),
+			FeeType:         row.FeeType,
+			FlatAmount:      row.FlatAmount,
+			Percentage:      row.Percentage,
+			MinFee:          row.MinFee,
+			MaxFee:          row.MaxFee,
+			Bearer:          row.Bearer,
+		}
+		if len(row.Tiers) > 0 {
+			_ = json.Unmarshal(row.Tiers, &r.Tiers)
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+func mapPlanWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return model.ErrPricingPlanNameTaken
+		case "23503":
+			return fmt.Errorf("%w: unknown currency or provider in rules", model.ErrInvalidInputError)
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ErrPricingPlanNotFound
+	}
+	return err
+}
+
+func optionalText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{}
+	}
+	return utils.ToPgxText(s)
+}
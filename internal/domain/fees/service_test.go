@@ -0,0 +1,260 @@
+package fees
+
+import (
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultPlanID = "00000000-0000-0000-0000-000000000001"
+	tenantPlanID  = "00000000-0000-0000-0000-000000000002"
+	pricedTenant  = "00000000-0000-0000-0000-0000000000aa"
+	otherTenant   = "00000000-0000-0000-0000-0000000000bb"
+)
+
+// stubRepo serves plans and rules from memory. Only the lookups Quote makes
+// are implemented.
+type stubRepo struct {
+	Repository
+	defaultPlan bool
+	tenantPlans map[string]string
+	rules       map[string][]dbsqlc.PricingRule
+}
+
+func (r *stubRepo) GetTenantPlan(ctx context.Context, tenantID string) (dbsqlc.PricingPlan, error) {
+	id, ok := r.tenantPlans[tenantID]
+	if !ok {
+		return dbsqlc.PricingPlan{}, pgx.ErrNoRows
+	}
+	return plan(id), nil
+}
+
+func (r *stubRepo) GetDefaultPlan(ctx context.Context) (dbsqlc.PricingPlan, error) {
+	if !r.defaultPlan {
+		return dbsqlc.PricingPlan{}, pgx.ErrNoRows
+	}
+	return plan(defaultPlanID), nil
+}
+
+func (r *stubRepo) ListRules(ctx context.Context, planID string) ([]dbsqlc.PricingRule, error) {
+	return r.rules[planID], nil
+}
+
+func plan(id string) dbsqlc.PricingPlan {
+	pid, _ := utils.StringToPgUUID(id)
+	return dbsqlc.PricingPlan{ID: pid, Name: id}
+}
+
+func flatRule(txType, amount, bearer string) dbsqlc.PricingRule {
+	return dbsqlc.PricingRule{
+		TransactionType: txType,
+		FeeType:         FeeFlat,
+		FlatAmount:      dec(amount),
+		Bearer:          bearer,
+	}
+}
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func TestQuoteBearer(t *testing.T) {
+	tests := []struct {
+		name        string
+		bearer      string
+		amount      string
+		fee         string
+		debit       string
+		credit      string
+		wantErr     error
+		txType      string
+		noPlanAtAll bool
+	}{
+		{name: "payer pays on top", bearer: BearerPayer, amount: "1000", fee: "50", debit: "1050", credit: "1000"},
+		{name: "receiver gets less", bearer: BearerReceiver, amount: "1000", fee: "50", debit: "1000", credit: "950"},
+		{name: "receiver fee equal to amount", bearer: BearerReceiver, amount: "50", wantErr: model.ErrFeeExceedsAmount},
+		{name: "receiver fee above amount", bearer: BearerReceiver, amount: "20", wantErr: model.ErrFeeExceedsAmount},
+		{name: "payer fee above amount", bearer: BearerPayer, amount: "20", fee: "50", debit: "70", credit: "20"},
+		{name: "no matching rule is free", bearer: BearerPayer, txType: TypeWithdrawal, amount: "1000", fee: "0", debit: "1000", credit: "1000"},
+		{name: "no plan is free", noPlanAtAll: true, amount: "1000", fee: "0", debit: "1000", credit: "1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepo{
+				defaultPlan: !tt.noPlanAtAll,
+				rules:       map[string][]dbsqlc.PricingRule{defaultPlanID: {flatRule(TypeTransfer, "50", tt.bearer)}},
+			}
+			s := &feeService{Repo: repo}
+
+			txType := tt.txType
+			if txType == "" {
+				txType = TypeTransfer
+			}
+			quote, err := s.Quote(context.Background(), QuoteInput{
+				TransactionType: txType,
+				Amount:          dec(tt.amount),
+				Currency:        "ngn",
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Quote error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Quote: %v", err)
+			}
+			if quote.Currency != "NGN" {
+				t.Errorf("currency = %s, want NGN", quote.Currency)
+			}
+			if !quote.Fee.Equal(dec(tt.fee)) || !quote.Debit.Equal(dec(tt.debit)) || !quote.Credit.Equal(dec(tt.credit)) {
+				t.Errorf("fee, debit, credit = %s, %s, %s, want %s, %s, %s",
+					quote.Fee, quote.Debit, quote.Credit, tt.fee, tt.debit, tt.credit)
+			}
+		})
+	}
+}
+
+func TestQuoteRejectsBadInput(t *testing.T) {
+	s := &feeService{Repo: &stubRepo{}}
+	for _, in := range []QuoteInput{
+		{TransactionType: TypeTransfer, Amount: decimal.Zero, Currency: "NGN"},
+		{TransactionType: TypeTransfer, Amount: dec("-1"), Currency: "NGN"},
+		{TransactionType: "refund", Amount: dec("1"), Currency: "NGN"},
+	} {
+		if _, err := s.Quote(context.Background(), in); !errors.Is(err, model.ErrInvalidInputError) {
+			t.Errorf("Quote(%s %s) error = %v, want %v", in.TransactionType, in.Amount, err, model.ErrInvalidInputError)
+		}
+	}
+}
+
+func TestQuoteTenantPlanOverridesDefault(t *testing.T) {
+	repo := &stubRepo{
+		defaultPlan: true,
+		tenantPlans: map[string]string{pricedTenant: tenantPlanID},
+		rules: map[string][]dbsqlc.PricingRule{
+			defaultPlanID: {flatRule(TypeTransfer, "50", BearerPayer)},
+			tenantPlanID:  {flatRule(TypeTransfer, "10", BearerPayer)},
+		},
+	}
+	s := &feeService{Repo: repo}
+
+	tests := []struct {
+		tenantID string
+		planID   string
+		fee      string
+	}{
+		{pricedTenant, tenantPlanID, "10"},
+		{otherTenant, defaultPlanID, "50"},
+		{"", defaultPlanID, "50"},
+	}
+	for _, tt := range tests {
+		quote, err := s.Quote(context.Background(), QuoteInput{
+			TenantID:        tt.tenantID,
+			TransactionType: TypeTransfer,
+			Amount:          dec("1000"),
+			Currency:        "NGN",
+		})
+		if err != nil {
+			t.Fatalf("Quote for tenant %q: %v", tt.tenantID, err)
+		}
+		if quote.PlanID != tt.planID || !quote.Fee.Equal(dec(tt.fee)) {
+			t.Errorf("tenant %q: plan, fee = %s, %s, want %s, %s", tt.tenantID, quote.PlanID, quote.Fee, tt.planID, tt.fee)
+		}
+	}
+}
+
+func TestRuleCompute(t *testing.T) {
+	tiers := []Tier{
+		{UpTo: decPtr("5000"), Flat: dec("10")},
+		{UpTo: decPtr("50000"), Flat: dec("25"), Percentage: dec("0.5")},
+		{Percentage: dec("1")},
+	}
+	tests := []struct {
+		name     string
+		rule     Rule
+		amount   string
+		currency string
+		want     string
+	}{
+		{"flat", Rule{FeeType: FeeFlat, FlatAmount: dec("100")}, "5000", "NGN", "100"},
+		{"percentage", Rule{FeeType: FeePercentage, Percentage: dec("1.5")}, "1000", "NGN", "15"},
+		{"percentage plus flat", Rule{FeeType: FeePercentage, Percentage: dec("1.5"), FlatAmount: dec("100")}, "1000", "NGN", "115"},
+
+		// Percentages are rounded half away from zero to the minor unit
+		{"percentage rounds down to kobo", Rule{FeeType: FeePercentage, Percentage: dec("1.5")}, "1000.33", "NGN", "15"},
+		{"percentage rounds up to kobo", Rule{FeeType: FeePercentage, Percentage: dec("1.5")}, "1000.99", "NGN", "15.01"},
+		{"percentage rounds half up", Rule{FeeType: FeePercentage, Percentage: dec("1")}, "0.5", "USD", "0.01"},
+		{"percentage in a 0-decimal currency", Rule{FeeType: FeePercentage, Percentage: dec("1.5")}, "1001", "JPY", "15"},
+		{"percentage in a 3-decimal currency", Rule{FeeType: FeePercentage, Percentage: dec("1.5")}, "10.001", "KWD", "0.15"},
+		{"percentage in a 3-decimal currency keeps fils", Rule{FeeType: FeePercentage, Percentage: dec("1.25")}, "10.1", "KWD", "0.126"},
+
+		{"min fee lifts a small fee", Rule{FeeType: FeePercentage, Percentage: dec("1"), MinFee: decPtr("50")}, "1000", "NGN", "50"},
+		{"max fee caps a large fee", Rule{FeeType: FeePercentage, Percentage: dec("1"), MaxFee: decPtr("2000")}, "1000000", "NGN", "2000"},
+		{"fee within caps is kept", Rule{FeeType: FeePercentage, Percentage: dec("1"), MinFee: decPtr("50"), MaxFee: decPtr("2000")}, "10000", "NGN", "100"},
+		{"min fee applies to flat fees", Rule{FeeType: FeeFlat, FlatAmount: dec("10"), MinFee: decPtr("25")}, "1000", "NGN", "25"},
+		{"caps are rounded too", Rule{FeeType: FeePercentage, Percentage: dec("1"), MaxFee: decPtr("2.005")}, "1000", "USD", "2.01"},
+
+		{"first tier", Rule{FeeType: FeeTiered, Tiers: tiers}, "5000", "NGN", "10"},
+		{"middle tier", Rule{FeeType: FeeTiered, Tiers: tiers}, "5000.01", "NGN", "50"},
+		{"last tier", Rule{FeeType: FeeTiered, Tiers: tiers}, "100000", "NGN", "1000"},
+		{"tier capped", Rule{FeeType: FeeTiered, Tiers: tiers, MaxFee: decPtr("500")}, "100000", "NGN", "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.compute(dec(tt.amount), tt.currency)
+			if !got.Equal(dec(tt.want)) {
+				t.Errorf("compute(%s %s) = %s, want %s", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchRule(t *testing.T) {
+	const providerID = "00000000-0000-0000-0000-0000000000cc"
+	rules := []Rule{
+		{ID: "transfer", TransactionType: TypeTransfer},
+		{ID: "deposit", TransactionType: TypeDeposit},
+		{ID: "deposit-first", TransactionType: TypeDeposit},
+		{ID: "deposit-ngn", TransactionType: TypeDeposit, CurrencyCode: "NGN"},
+		{ID: "deposit-card", TransactionType: TypeDeposit, Channel: "card"},
+		{ID: "deposit-card-ngn", TransactionType: TypeDeposit, Channel: "card", CurrencyCode: "NGN"},
+		{ID: "deposit-card-ngn-provider", TransactionType: TypeDeposit, Channel: "card", CurrencyCode: "NGN", ProviderID: providerID},
+	}
+	tests := []struct {
+		name string
+		in   QuoteInput
+		want string
+	}{
+		{"only the type matches", QuoteInput{TransactionType: TypeTransfer, Currency: "USD"}, "transfer"},
+		{"first of equally specific rules wins", QuoteInput{TransactionType: TypeDeposit, Currency: "USD", Channel: "bank"}, "deposit"},
+		{"currency beats a general rule", QuoteInput{TransactionType: TypeDeposit, Currency: "NGN", Channel: "bank"}, "deposit-ngn"},
+		{"channel matches case-insensitively", QuoteInput{TransactionType: TypeDeposit, Currency: "USD", Channel: "CARD"}, "deposit-card"},
+		{"channel and currency beat either", QuoteInput{TransactionType: TypeDeposit, Currency: "NGN", Channel: "card"}, "deposit-card-ngn"},
+		{"provider is the most specific", QuoteInput{TransactionType: TypeDeposit, Currency: "NGN", Channel: "card", ProviderID: providerID}, "deposit-card-ngn-provider"},
+		{"no rule for the type", QuoteInput{TransactionType: TypeWithdrawal, Currency: "NGN"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if r := matchRule(rules, tt.in); r != nil {
+				got = r.ID
+			}
+			if got != tt.want {
+				t.Errorf("matchRule = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
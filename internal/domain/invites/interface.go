@@ -0,0 +1,29 @@
+package invites
+
+import (
+	"codematic/internal/domain/auth"
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"time"
+)
+
+type Service interface {
+	CreateInvite(ctx context.Context, tenantID, invitedBy string, req CreateInviteRequest) (Invite, error)
+	ListInvites(ctx context.Context, tenantID string, limit, offset int) ([]Invite, error)
+	ResendInvite(ctx context.Context, tenantID, id string) (Invite, error)
+	RevokeInvite(ctx context.Context, tenantID, id string) error
+	LookupInvite(ctx context.Context, token string) (Invite, error)
+	AcceptInvite(ctx context.Context, req AcceptInviteRequest) (auth.User, error)
+}
+
+type Repository interface {
+	Create(ctx context.Context, arg CreateParams) (db.UserInvite, error)
+	GetByID(ctx context.Context, tenantID, id string) (db.UserInvite, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (db.UserInvite, error)
+	ListByTenant(ctx context.Context, tenantID string, limit, offset int) ([]db.UserInvite, error)
+	Renew(ctx context.Context, tenantID, id, tokenHash string, expiresAt time.Time) (db.UserInvite, error)
+	Revoke(ctx context.Context, tenantID, id string) (bool, error)
+	RevokePending(ctx context.Context, tenantID, email string) error
+	Accept(ctx context.Context, id, userID string) (bool, error)
+	WithTx(q *db.Queries) Repository
+}
@@ -0,0 +1,97 @@
+package invites
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusRevoked  = "revoked"
+	StatusExpired  = "expired"
+)
+
+type (
+	CreateInviteRequest struct {
+		Email string `json:"email" validate:"required,email"`
+		Role  string `json:"role" validate:"omitempty,oneof=USER TENANT_ADMIN"`
+	}
+
+	AcceptInviteRequest struct {
+		Token     string `json:"token" validate:"required"`
+		FirstName string `json:"first_name" validate:"required"`
+		LastName  string `json:"last_name" validate:"required"`
+		Phone     string `json:"phone" validate:"required"`
+		Password  string `json:"password" validate:"required,min=8"`
+	}
+
+	Invite struct {
+		ID         string     `json:"id"`
+		TenantID   string     `json:"tenant_id"`
+		Email      string     `json:"email"`
+		Role       string     `json:"role"`
+		Status     string     `json:"status"`
+		InvitedBy  string     `json:"invited_by,omitempty"`
+		UserID     string     `json:"user_id,omitempty"`
+		ExpiresAt  time.Time  `json:"expires_at"`
+		AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+		RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+	}
+
+	CreateParams struct {
+		ID        string
+		TenantID  string
+		Email     string
+		Role      string
+		TokenHash string
+		InvitedBy string
+		ExpiresAt time.Time
+	}
+)
+
+func toDomainInvite(i db.UserInvite) Invite {
+	invite := Invite{
+		ID:         utils.FromPgUUID(i.ID),
+		TenantID:   utils.FromPgUUID(i.TenantID),
+		Email:      i.Email,
+		Role:       i.Role,
+		InvitedBy:  utils.FromPgUUID(i.InvitedBy),
+		UserID:     utils.FromPgUUID(i.UserID),
+		ExpiresAt:  utils.FromPgTimestamptz(i.ExpiresAt),
+		AcceptedAt: timePtr(i.AcceptedAt),
+		RevokedAt:  timePtr(i.RevokedAt),
+		CreatedAt:  utils.FromPgTimestamptz(i.CreatedAt),
+	}
+
+	switch {
+	case invite.AcceptedAt != nil:
+		invite.Status = StatusAccepted
+	case invite.RevokedAt != nil:
+		invite.Status = StatusRevoked
+	case time.Now().After(invite.ExpiresAt):
+		invite.Status = StatusExpired
+	default:
+		invite.Status = StatusPending
+	}
+	return invite
+}
+
+func toDomainInvites(rows []db.UserInvite) []Invite {
+	out := make([]Invite, len(rows))
+	for i, row := range rows {
+		out[i] = toDomainInvite(row)
+	}
+	return out
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
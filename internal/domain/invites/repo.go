@@ -0,0 +1,140 @@
+package invites
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) Create(ctx context.Context, arg CreateParams) (db.UserInvite, error) {
+	id, err := utils.StringToPgUUID(arg.ID)
+	if err != nil {
+		return db.UserInvite{}, err
+	}
+	tid, err := utils.StringToPgUUID(arg.TenantID)
+	if err != nil {
+		return db.UserInvite{}, err
+	}
+
+	var invitedBy pgtype.UUID
+	if arg.InvitedBy != "" {
+		invitedBy, err = utils.StringToPgUUID(arg.InvitedBy)
+		if err != nil {
+			return db.UserInvite{}, err
+		}
+	}
+
+	return r.q.CreateUserInvite(ctx, db.CreateUserInviteParams{
+		ID:        id,
+		TenantID:  tid,
+		Email:     arg.Email,
+		Role:      arg.Role,
+		TokenHash: arg.TokenHash,
+		InvitedBy: invitedBy,
+		ExpiresAt: utils.ToPgTimestamptz(arg.ExpiresAt),
+	})
+}
+
+func (r *repository) GetByID(ctx context.Context, tenantID, id string) (db.UserInvite, error) {
+	iid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.UserInvite{}, err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.UserInvite{}, err
+	}
+	return r.q.GetUserInviteByID(ctx, db.GetUserInviteByIDParams{ID: iid, TenantID: tid})
+}
+
+func (r *repository) GetByTokenHash(ctx context.Context, tokenHash string) (db.UserInvite, error) {
+	return r.q.GetUserInviteByTokenHash(ctx, tokenHash)
+}
+
+func (r *repository) ListByTenant(ctx context.Context, tenantID string, limit,
+	offset int) ([]db.UserInvite, error) {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListUserInvitesByTenant(ctx, db.ListUserInvitesByTenantParams{
+		TenantID: tid,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+}
+
+func (r *repository) Renew(ctx context.Context, tenantID, id, tokenHash string,
+	expiresAt time.Time) (db.UserInvite, error) {
+	iid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.UserInvite{}, err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.UserInvite{}, err
+	}
+	return r.q.RenewUserInvite(ctx, db.RenewUserInviteParams{
+		ID:        iid,
+		TenantID:  tid,
+		TokenHash: tokenHash,
+		ExpiresAt: utils.ToPgTimestamptz(expiresAt),
+	})
+}
+
+func (r *repository) Revoke(ctx context.Context, tenantID, id string) (bool, error) {
+	iid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.RevokeUserInvite(ctx, db.RevokeUserInviteParams{ID: iid, TenantID: tid})
+	return rows > 0, err
+}
+
+func (r *repository) RevokePending(ctx context.Context, tenantID, email string) error {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return err
+	}
+	return r.q.RevokePendingUserInvites(ctx, db.RevokePendingUserInvitesParams{
+		TenantID: tid,
+		Email:    email,
+	})
+}
+
+func (r *repository) Accept(ctx context.Context, id, userID string) (bool, error) {
+	iid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.AcceptUserInvite(ctx, db.AcceptUserInviteParams{ID: iid, UserID: uid})
+	return rows > 0, err
+}
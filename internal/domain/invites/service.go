@@ -0,0 +1,267 @@
+package invites
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/domain/auth"
+	"codematic/internal/domain/tenants"
+	"codematic/internal/domain/user"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/infrastructure/notifications"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const maxListLimit = 100
+
+type inviteService struct {
+	DB            *db.DBConn
+	Repo          Repository
+	authService   auth.Service
+	userService   user.Service
+	tenantService tenants.Service
+	mailer        notifications.Mailer
+	cfg           *config.Config
+	logger        *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the invite service.
+func NewService(
+	db *db.DBConn,
+	authService auth.Service,
+	userService user.Service,
+	tenantService tenants.Service,
+	mailer notifications.Mailer,
+	cfg *config.Config,
+	logger *zap.Logger,
+) Service {
+	return &inviteService{
+		DB:            db,
+		Repo:          NewRepository(db.Queries, db.Pool),
+		authService:   authService,
+		userService:   userService,
+		tenantService: tenantService,
+		mailer:        mailer,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// CreateInvite emails a signed invite link to email, replacing any invite to
+// the same address that is still open.
+func (s *inviteService) CreateInvite(ctx context.Context, tenantID, invitedBy string,
+	req CreateInviteRequest) (Invite, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !utils.IsValidEmail(email) {
+		return Invite{}, model.ErrInvalidEmailFormat
+	}
+
+	role := req.Role
+	if role == "" {
+		role = model.RoleUser.String()
+	}
+	if role != model.RoleUser.String() && role != model.RoleTenantAdmin.String() {
+		return Invite{}, model.ErrInvalidInputError
+	}
+
+	// Emails are unique across tenants, so an invite to a registered address could never be accepted
+	if _, err := s.userService.GetUserByEmail(ctx, email); err == nil {
+		return Invite{}, model.ErrUserAlreadyExists
+	}
+
+	id := uuid.New().String()
+	token, err := newInviteToken(s.cfg.JwtSecret, id)
+	if err != nil {
+		return Invite{}, err
+	}
+
+	var created dbsqlc.UserInvite
+	err = utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+		if err := repo.RevokePending(ctx, tenantID, email); err != nil {
+			return err
+		}
+
+		created, err = repo.Create(ctx, CreateParams{
+			ID:        id,
+			TenantID:  tenantID,
+			Email:     email,
+			Role:      role,
+			TokenHash: utils.HashString(token),
+			InvitedBy: invitedBy,
+			ExpiresAt: time.Now().Add(s.cfg.InviteTTL),
+		})
+		return err
+	})
+	if err != nil {
+		return Invite{}, err
+	}
+
+	invite := toDomainInvite(created)
+	s.sendInvite(ctx, invite, token)
+
+	s.logger.Info("Invite created", zap.String("inviteID", invite.ID), zap.String("tenantID", tenantID))
+	return invite, nil
+}
+
+func (s *inviteService) ListInvites(ctx context.Context, tenantID string, limit,
+	offset int) ([]Invite, error) {
+	if limit <= 0 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := s.Repo.ListByTenant(ctx, tenantID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainInvites(rows), nil
+}
+
+// ResendInvite issues a fresh link with a new expiry; earlier links stop working
+func (s *inviteService) ResendInvite(ctx context.Context, tenantID, id string) (Invite, error) {
+	token, err := newInviteToken(s.cfg.JwtSecret, id)
+	if err != nil {
+		return Invite{}, err
+	}
+
+	renewed, err := s.Repo.Renew(ctx, tenantID, id, utils.HashString(token),
+		time.Now().Add(s.cfg.InviteTTL))
+	if err != nil {
+		return Invite{}, model.ErrInviteNotFound
+	}
+
+	invite := toDomainInvite(renewed)
+	s.sendInvite(ctx, invite, token)
+
+	s.logger.Info("Invite resent", zap.String("inviteID", id))
+	return invite, nil
+}
+
+func (s *inviteService) RevokeInvite(ctx context.Context, tenantID, id string) error {
+	revoked, err := s.Repo.Revoke(ctx, tenantID, id)
+	if err != nil || !revoked {
+		return model.ErrInviteNotFound
+	}
+
+	s.logger.Info("Invite revoked", zap.String("inviteID", id))
+	return nil
+}
+
+// LookupInvite returns the open invite for a token so the accept page can show
+// who it is for.
+func (s *inviteService) LookupInvite(ctx context.Context, token string) (Invite, error) {
+	stored, err := s.openInvite(ctx, token)
+	if err != nil {
+		return Invite{}, err
+	}
+	return toDomainInvite(stored), nil
+}
+
+// AcceptInvite creates the invited user and their wallet through the signup
+// transaction and marks the invite used in the same transaction. The email
+// comes from the invite, and is treated as verified since the link was
+// delivered to it.
+func (s *inviteService) AcceptInvite(ctx context.Context, req AcceptInviteRequest) (auth.User, error) {
+	stored, err := s.openInvite(ctx, req.Token)
+	if err != nil {
+		return auth.User{}, err
+	}
+	invite := toDomainInvite(stored)
+
+	created, err := s.authService.SignupWith(ctx, &auth.SignupRequest{
+		FirstName:     req.FirstName,
+		LastName:      req.LastName,
+		Email:         invite.Email,
+		Phone:         req.Phone,
+		Password:      req.Password,
+		TenantID:      invite.TenantID,
+		Role:          model.UserRole(invite.Role),
+		EmailVerified: true,
+	}, func(q *dbsqlc.Queries, u auth.User) error {
+		accepted, err := s.Repo.WithTx(q).Accept(ctx, invite.ID, u.ID)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return model.ErrInvalidInvite
+		}
+		return nil
+	})
+	if err != nil {
+		return auth.User{}, err
+	}
+
+	s.logger.Info("Invite accepted", zap.String("inviteID", invite.ID), zap.String("userID", created.ID))
+	return created, nil
+}
+
+// openInvite checks the token signature before touching the database, then
+// requires the invite to be neither used, revoked nor expired.
+func (s *inviteService) openInvite(ctx context.Context, token string) (dbsqlc.UserInvite, error) {
+	id, ok := verifyInviteToken(s.cfg.JwtSecret, token)
+	if !ok {
+		return dbsqlc.UserInvite{}, model.ErrInvalidInvite
+	}
+
+	stored, err := s.Repo.GetByTokenHash(ctx, utils.HashString(token))
+	if err != nil || utils.FromPgUUID(stored.ID) != id {
+		return dbsqlc.UserInvite{}, model.ErrInvalidInvite
+	}
+	if toDomainInvite(stored).Status != StatusPending {
+		return dbsqlc.UserInvite{}, model.ErrInvalidInvite
+	}
+	return stored, nil
+}
+
+func (s *inviteService) sendInvite(ctx context.Context, invite Invite, token string) {
+	tenantName := "your team"
+	if tenant, err := s.tenantService.GetTenantByID(ctx, invite.TenantID); err == nil {
+		tenantName = tenant.Name
+	}
+
+	link := s.cfg.AppBaseURL + "/accept-invite?token=" + token
+	if err := s.mailer.Send(ctx, notifications.Message{
+		To:      []string{invite.Email},
+		Subject: "You have been invited to " + tenantName,
+		Text: "You have been invited to join " + tenantName + ".\n\n" +
+			"Use the link below to set your password and activate your account:\n" + link + "\n\n" +
+			"The link expires on " + invite.ExpiresAt.UTC().Format(time.RFC1123) + ".",
+	}); err != nil {
+		s.logger.Error("Failed to send invite email", zap.String("inviteID", invite.ID), zap.Error(err))
+	}
+}
+
+// newInviteToken returns "<invite id>.<nonce>.<signature>". The nonce changes
+// on every resend so older links stop matching the stored hash.
+func newInviteToken(secret, id string) (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	payload := id + "." + hex.EncodeToString(raw)
+	return payload + "." + utils.SignString(secret, payload), nil
+}
+
+// verifyInviteToken checks the signature and returns the invite ID
+func verifyInviteToken(secret, token string) (string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", false
+	}
+	payload := parts[0] + "." + parts[1]
+	if !utils.VerifySignature(secret, payload, parts[2]) {
+		return "", false
+	}
+	return parts[0], true
+}
@@ -0,0 +1,29 @@
+package mfa
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+)
+
+type Service interface {
+	Status(ctx context.Context, userID, role string) (Status, error)
+	Enroll(ctx context.Context, userID, account string) (Enrollment, error)
+	Confirm(ctx context.Context, userID, code string) ([]string, error)
+	Disable(ctx context.Context, userID, role, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+	Verify(ctx context.Context, userID, code string) error
+	WithTx(q *db.Queries) Service
+}
+
+type Repository interface {
+	Upsert(ctx context.Context, userID, secretEncrypted string) (db.UserMfa, error)
+	Get(ctx context.Context, userID string) (db.UserMfa, error)
+	Enable(ctx context.Context, userID string) error
+	Delete(ctx context.Context, userID string) error
+	MarkStepUsed(ctx context.Context, userID string, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error)
+	WithTx(q *db.Queries) Repository
+}
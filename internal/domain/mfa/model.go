@@ -0,0 +1,41 @@
+package mfa
+
+import (
+	"codematic/internal/shared/model"
+	"time"
+)
+
+const (
+	// RecoveryCodeCount is how many single-use recovery codes are issued
+	RecoveryCodeCount = 10
+
+	// totpSkew accepts codes from one step either side to allow for clock drift
+	totpSkew = 1
+)
+
+// RequiredForRole reports whether the role must have TOTP enabled to log in
+func RequiredForRole(role string) bool {
+	return role == model.RolePlatformAdmin.String() || role == model.RoleTenantAdmin.String()
+}
+
+type (
+	Enrollment struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+
+	Status struct {
+		Enabled                bool       `json:"enabled"`
+		Required               bool       `json:"required"`
+		EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+		RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+	}
+
+	CodeRequest struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	RecoveryCodes struct {
+		Codes []string `json:"recovery_codes"`
+	}
+)
@@ -0,0 +1,119 @@
+package mfa
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) Upsert(ctx context.Context, userID, secretEncrypted string) (db.UserMfa, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.UserMfa{}, err
+	}
+	return r.q.UpsertUserMFA(ctx, db.UpsertUserMFAParams{
+		UserID:          uid,
+		SecretEncrypted: secretEncrypted,
+	})
+}
+
+func (r *repository) Get(ctx context.Context, userID string) (db.UserMfa, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.UserMfa{}, err
+	}
+	return r.q.GetUserMFA(ctx, uid)
+}
+
+func (r *repository) Enable(ctx context.Context, userID string) error {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	return r.q.EnableUserMFA(ctx, uid)
+}
+
+func (r *repository) Delete(ctx context.Context, userID string) error {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteMFARecoveryCodes(ctx, uid); err != nil {
+		return err
+	}
+	return r.q.DeleteUserMFA(ctx, uid)
+}
+
+// MarkStepUsed records the TOTP step of an accepted code; it returns false if
+// that step (or a later one) was already used, i.e. the code is a replay.
+func (r *repository) MarkStepUsed(ctx context.Context, userID string, step int64) (bool, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.UpdateUserMFALastUsedStep(ctx, db.UpdateUserMFALastUsedStepParams{
+		UserID:       uid,
+		LastUsedStep: step,
+	})
+	return rows == 1, err
+}
+
+func (r *repository) ReplaceRecoveryCodes(ctx context.Context, userID string,
+	codeHashes []string) error {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteMFARecoveryCodes(ctx, uid); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if err := r.q.CreateMFARecoveryCode(ctx, db.CreateMFARecoveryCodeParams{
+			ID:       utils.ToUUID(uuid.New()),
+			UserID:   uid,
+			CodeHash: hash,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.UseMFARecoveryCode(ctx, db.UseMFARecoveryCodeParams{
+		UserID:   uid,
+		CodeHash: codeHash,
+	})
+	return rows == 1, err
+}
+
+func (r *repository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return 0, err
+	}
+	return r.q.CountUnusedMFARecoveryCodes(ctx, uid)
+}
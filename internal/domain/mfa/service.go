@@ -0,0 +1,282 @@
+package mfa
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type mfaService struct {
+	DB     *db.DBConn
+	Repo   Repository
+	cfg    *config.Config
+	logger *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the MFA service.
+func NewService(db *db.DBConn, cfg *config.Config, logger *zap.Logger) Service {
+	return &mfaService{
+		DB:     db,
+		Repo:   NewRepository(db.Queries, db.Pool),
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (s *mfaService) WithTx(q *dbsqlc.Queries) Service {
+	return &mfaService{
+		DB:     s.DB,
+		Repo:   NewRepository(q, s.DB.Pool),
+		cfg:    s.cfg,
+		logger: s.logger,
+	}
+}
+
+func (s *mfaService) Status(ctx context.Context, userID, role string) (Status, error) {
+	status := Status{Required: RequiredForRole(role)}
+
+	record, err := s.Repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status, nil
+		}
+		return Status{}, err
+	}
+
+	if record.EnabledAt.Valid {
+		enabledAt := record.EnabledAt.Time
+		status.Enabled = true
+		status.EnabledAt = &enabledAt
+
+		status.RecoveryCodesRemaining, err = s.Repo.CountUnusedRecoveryCodes(ctx, userID)
+		if err != nil {
+			return Status{}, err
+		}
+	}
+
+	return status, nil
+}
+
+// Enroll generates a new TOTP secret for the user. It stays inactive until
+// Confirm is called with a code from the authenticator app.
+func (s *mfaService) Enroll(ctx context.Context, userID, account string) (Enrollment, error) {
+	enabled, err := s.IsEnabled(ctx, userID)
+	if err != nil {
+		return Enrollment{}, err
+	}
+	if enabled {
+		return Enrollment{}, model.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return Enrollment{}, err
+	}
+
+	encrypted, err := utils.EncryptString(s.cfg.MFAEncryptionKey, secret)
+	if err != nil {
+		return Enrollment{}, err
+	}
+
+	if _, err := s.Repo.Upsert(ctx, userID, encrypted); err != nil {
+		return Enrollment{}, err
+	}
+
+	s.logger.Info("MFA enrollment started", zap.String("userID", userID))
+
+	return Enrollment{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.cfg.MFAIssuer, account, secret),
+	}, nil
+}
+
+// Confirm activates a pending enrollment and returns fresh recovery codes
+func (s *mfaService) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	var codes []string
+
+	err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+
+		record, err := repo.Get(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.ErrMFAEnrollmentNotStarted
+			}
+			return err
+		}
+		if record.EnabledAt.Valid {
+			return model.ErrMFAAlreadyEnabled
+		}
+
+		if err := s.verifyTOTP(ctx, repo, record, code); err != nil {
+			return err
+		}
+
+		if err := repo.Enable(ctx, userID); err != nil {
+			return err
+		}
+
+		codes, err = s.replaceRecoveryCodes(ctx, repo, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("MFA enabled", zap.String("userID", userID))
+	return codes, nil
+}
+
+func (s *mfaService) Disable(ctx context.Context, userID, role, code string) error {
+	if RequiredForRole(role) {
+		return model.ErrMFARequired
+	}
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		return s.Repo.WithTx(q).Delete(ctx, userID)
+	}); err != nil {
+		return err
+	}
+
+	s.logger.Info("MFA disabled", zap.String("userID", userID))
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID,
+	code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		var err error
+		codes, err = s.replaceRecoveryCodes(ctx, s.Repo.WithTx(q), userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *mfaService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	record, err := s.Repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return record.EnabledAt.Valid, nil
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code
+func (s *mfaService) Verify(ctx context.Context, userID, code string) error {
+	record, err := s.Repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrMFANotEnabled
+		}
+		return err
+	}
+	if !record.EnabledAt.Valid {
+		return model.ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == utils.TOTPDigits {
+		return s.verifyTOTP(ctx, s.Repo, record, code)
+	}
+
+	used, err := s.Repo.UseRecoveryCode(ctx, userID, utils.HashString(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return model.ErrInvalidMFACode
+	}
+
+	s.logger.Info("MFA recovery code used", zap.String("userID", userID))
+	return nil
+}
+
+// verifyTOTP checks the code and records its time step so it cannot be replayed
+func (s *mfaService) verifyTOTP(ctx context.Context, repo Repository, record dbsqlc.UserMfa,
+	code string) error {
+	secret, err := utils.DecryptString(s.cfg.MFAEncryptionKey, record.SecretEncrypted)
+	if err != nil {
+		s.logger.Error("Failed to decrypt TOTP secret", zap.Error(err))
+		return err
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return model.ErrInvalidMFACode
+	}
+
+	fresh, err := repo.MarkStepUsed(ctx, record.UserID.String(), step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return model.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (s *mfaService) replaceRecoveryCodes(ctx context.Context, repo Repository,
+	userID string) ([]string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = utils.HashString(normalizeRecoveryCode(code))
+	}
+
+	if err := repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// generateRecoveryCode returns a code like "k7m2p-x9qrt"
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, len(raw))
+	for i, b := range raw {
+		code[i] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+	}
+	return string(code[:5]) + "-" + string(code[5:]), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
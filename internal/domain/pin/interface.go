@@ -0,0 +1,27 @@
+package pin
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"time"
+)
+
+type Service interface {
+	Status(ctx context.Context, userID string) (Status, error)
+	Set(ctx context.Context, userID, password, pin string) error
+	Change(ctx context.Context, userID, currentPIN, newPIN string) error
+	Verify(ctx context.Context, userID, pin string) error
+	RequestReset(ctx context.Context, userID string) error
+	Reset(ctx context.Context, userID, token, pin string) error
+}
+
+type Repository interface {
+	Create(ctx context.Context, userID, pinHash string) (bool, error)
+	Get(ctx context.Context, userID string) (db.TransactionPin, error)
+	Update(ctx context.Context, userID, pinHash string) (bool, error)
+	CreateResetToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	GetResetToken(ctx context.Context, tokenHash string) (db.UserToken, error)
+	ConsumeResetToken(ctx context.Context, id string) (bool, error)
+	InvalidateResetTokens(ctx context.Context, userID string) error
+	WithTx(q *db.Queries) Repository
+}
@@ -0,0 +1,58 @@
+package pin
+
+import (
+	"codematic/internal/shared/model"
+	"time"
+)
+
+const (
+	TokenPurposePINReset = "pin_reset"
+
+	pinResetTTL = 30 * time.Minute
+
+	// failureTTL is how long wrong PINs are remembered without a success
+	failureTTL = 24 * time.Hour
+
+	minLength = 4
+	maxLength = 6
+)
+
+// LockedError is returned while PIN entry is locked after repeated wrong
+// PINs; it matches model.ErrTransactionPINLocked with errors.Is.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return model.ErrTransactionPINLocked.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return model.ErrTransactionPINLocked
+}
+
+type (
+	Status struct {
+		IsSet     bool       `json:"is_set"`
+		UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+		// LockedFor is the number of seconds PIN entry stays locked
+		Locked    bool `json:"locked"`
+		LockedFor int  `json:"locked_for,omitempty"`
+	}
+
+	SetRequest struct {
+		PIN      string `json:"pin" validate:"required,numeric,min=4,max=6"`
+		Password string `json:"password" validate:"required"`
+	}
+
+	ChangeRequest struct {
+		CurrentPIN string `json:"current_pin" validate:"required"`
+		NewPIN     string `json:"new_pin" validate:"required,numeric,min=4,max=6"`
+	}
+
+	ResetRequest struct {
+		Token string `json:"token" validate:"required"`
+		PIN   string `json:"pin" validate:"required,numeric,min=4,max=6"`
+	}
+)
@@ -0,0 +1,105 @@
+package pin
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+// Create stores the user's first PIN; it returns false if one is already set
+func (r *repository) Create(ctx context.Context, userID, pinHash string) (bool, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.CreateTransactionPin(ctx, db.CreateTransactionPinParams{
+		UserID:  uid,
+		PinHash: pinHash,
+	})
+	return rows == 1, err
+}
+
+func (r *repository) Get(ctx context.Context, userID string) (db.TransactionPin, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.TransactionPin{}, err
+	}
+	return r.q.GetTransactionPin(ctx, uid)
+}
+
+// Update replaces the user's PIN; it returns false if none is set
+func (r *repository) Update(ctx context.Context, userID, pinHash string) (bool, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.UpdateTransactionPin(ctx, db.UpdateTransactionPinParams{
+		UserID:  uid,
+		PinHash: pinHash,
+	})
+	return rows == 1, err
+}
+
+func (r *repository) CreateResetToken(ctx context.Context, userID, tokenHash string,
+	expiresAt time.Time) error {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	_, err = r.q.CreateUserToken(ctx, db.CreateUserTokenParams{
+		ID:        utils.ToUUID(uuid.New()),
+		UserID:    uid,
+		Purpose:   TokenPurposePINReset,
+		TokenHash: tokenHash,
+		ExpiresAt: utils.ToPgTimestamptz(expiresAt),
+	})
+	return err
+}
+
+func (r *repository) GetResetToken(ctx context.Context, tokenHash string) (db.UserToken, error) {
+	return r.q.GetUserTokenByHash(ctx, db.GetUserTokenByHashParams{
+		TokenHash: tokenHash,
+		Purpose:   TokenPurposePINReset,
+	})
+}
+
+// ConsumeResetToken marks a token used; it returns false if it was already used
+func (r *repository) ConsumeResetToken(ctx context.Context, id string) (bool, error) {
+	tid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.MarkUserTokenUsed(ctx, tid)
+	return rows == 1, err
+}
+
+func (r *repository) InvalidateResetTokens(ctx context.Context, userID string) error {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	return r.q.InvalidateUserTokens(ctx, db.InvalidateUserTokensParams{
+		UserID:  uid,
+		Purpose: TokenPurposePINReset,
+	})
+}
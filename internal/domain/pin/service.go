@@ -0,0 +1,303 @@
+package pin
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/domain/user"
+	"codematic/internal/infrastructure/cache"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/infrastructure/notifications"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type pinService struct {
+	DB           *db.DBConn
+	Repo         Repository
+	userService  user.Service
+	cacheManager cache.CacheManager
+	mailer       notifications.Mailer
+	cfg          *config.Config
+	logger       *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the transaction PIN service.
+func NewService(db *db.DBConn, userService user.Service, cacheManager cache.CacheManager,
+	mailer notifications.Mailer, cfg *config.Config, logger *zap.Logger) Service {
+	return &pinService{
+		DB:           db,
+		Repo:         NewRepository(db.Queries, db.Pool),
+		userService:  userService,
+		cacheManager: cacheManager,
+		mailer:       mailer,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// Wrong PINs share the login lockout store under their own key space
+func lockoutKey(userID string) string {
+	return "pin:" + userID
+}
+
+func (s *pinService) Status(ctx context.Context, userID string) (Status, error) {
+	var status Status
+
+	record, err := s.Repo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Status{}, err
+	}
+	if err == nil {
+		updatedAt := record.UpdatedAt.Time
+		status.IsSet = true
+		status.UpdatedAt = &updatedAt
+	}
+
+	if remaining, err := s.cacheManager.GetLockout(ctx, lockoutKey(userID)); err == nil && remaining > 0 {
+		status.Locked = true
+		status.LockedFor = int(remaining.Seconds())
+	}
+
+	return status, nil
+}
+
+// Set stores the user's first PIN. The account password is required so a
+// stolen access token alone cannot choose the PIN.
+func (s *pinService) Set(ctx context.Context, userID, password, pin string) error {
+	if err := validatePIN(pin); err != nil {
+		return err
+	}
+
+	u, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return model.ErrUserNotFound
+	}
+	if !utils.CheckPasswordHash(password, u.PasswordHash) {
+		return model.ErrIncorrectPassword
+	}
+
+	hash, err := utils.HashPassword(pin)
+	if err != nil {
+		return err
+	}
+
+	created, err := s.Repo.Create(ctx, userID, hash)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return model.ErrTransactionPINAlreadySet
+	}
+
+	s.logger.Info("Transaction PIN set", zap.String("userID", userID))
+	return nil
+}
+
+// Change replaces the PIN after checking the current one, which counts
+// towards the lockout like any other wrong PIN.
+func (s *pinService) Change(ctx context.Context, userID, currentPIN, newPIN string) error {
+	if err := validatePIN(newPIN); err != nil {
+		return err
+	}
+	if err := s.Verify(ctx, userID, currentPIN); err != nil {
+		return err
+	}
+
+	if err := s.update(ctx, s.Repo, userID, newPIN); err != nil {
+		return err
+	}
+
+	s.notifyChanged(ctx, userID)
+	return nil
+}
+
+// Verify checks the PIN for a money-moving request. Each attempt is counted
+// before the PIN is compared, so at most TransactionPINMaxAttempts guesses
+// are checked however many arrive at once; the last wrong one locks PIN
+// entry for TransactionPINLockout. Without the attempt counter no PIN is
+// accepted.
+func (s *pinService) Verify(ctx context.Context, userID, pin string) error {
+	record, err := s.Repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrTransactionPINNotSet
+		}
+		return err
+	}
+
+	key := lockoutKey(userID)
+	attempts, remaining, err := s.cacheManager.RecordAttempt(ctx, key, failureTTL)
+	if err != nil {
+		return fmt.Errorf("count PIN attempt: %w", err)
+	}
+	if remaining > 0 {
+		return &LockedError{RetryAfter: remaining}
+	}
+	if attempts > int64(s.cfg.TransactionPINMaxAttempts) {
+		// Raced past the attempt that is locking PIN entry
+		return &LockedError{RetryAfter: s.cfg.TransactionPINLockout}
+	}
+
+	if !utils.CheckPasswordHash(pin, record.PinHash) {
+		return s.failed(ctx, key, attempts)
+	}
+
+	_ = s.cacheManager.ResetLoginFailures(ctx, key)
+	return nil
+}
+
+func (s *pinService) failed(ctx context.Context, key string, attempts int64) error {
+	if attempts < int64(s.cfg.TransactionPINMaxAttempts) {
+		return model.ErrInvalidTransactionPIN
+	}
+
+	// The count is kept with the lock, so attempts racing this one stay
+	// rejected until it expires
+	if err := s.cacheManager.LockAttempts(ctx, key, s.cfg.TransactionPINLockout); err != nil {
+		s.logger.Error("Failed to lock transaction PIN", zap.String("key", key), zap.Error(err))
+	}
+
+	s.logger.Warn("Transaction PIN locked after repeated wrong PINs",
+		zap.String("key", key), zap.Int64("attempts", attempts))
+	return &LockedError{RetryAfter: s.cfg.TransactionPINLockout}
+}
+
+// RequestReset emails a single-use PIN reset link to the user's verified
+// email address.
+func (s *pinService) RequestReset(ctx context.Context, userID string) error {
+	u, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return model.ErrUserNotFound
+	}
+	if !u.EmailVerifiedAt.Valid {
+		return model.ErrEmailNotVerified
+	}
+	if _, err := s.Repo.Get(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrTransactionPINNotSet
+		}
+		return err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := s.Repo.InvalidateResetTokens(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.Repo.CreateResetToken(ctx, userID, utils.HashString(token),
+		time.Now().Add(pinResetTTL)); err != nil {
+		return err
+	}
+
+	link := s.cfg.AppBaseURL + "/reset-pin?token=" + token
+	if err := s.mailer.Send(ctx, notifications.Message{
+		To:      []string{u.Email},
+		Subject: "Reset your transaction PIN",
+		Text: "We received a request to reset your transaction PIN.\n\n" +
+			"Use the link below within 30 minutes to choose a new one:\n" + link + "\n\n" +
+			"If you did not request this, change your password now.",
+	}); err != nil {
+		s.logger.Error("Failed to send PIN reset email", zap.String("userID", userID), zap.Error(err))
+		return errors.New("failed to send reset email")
+	}
+
+	return nil
+}
+
+// Reset consumes an emailed reset token of the same user, sets the new PIN
+// and lifts any PIN lockout.
+func (s *pinService) Reset(ctx context.Context, userID, token, pin string) error {
+	if err := validatePIN(pin); err != nil {
+		return err
+	}
+
+	err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+
+		stored, err := repo.GetResetToken(ctx, utils.HashString(token))
+		if err != nil || stored.UserID.String() != userID {
+			return model.ErrInvalidUserToken
+		}
+		if stored.UsedAt.Valid || time.Now().After(stored.ExpiresAt.Time) {
+			return model.ErrInvalidUserToken
+		}
+
+		ok, err := repo.ConsumeResetToken(ctx, stored.ID.String())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return model.ErrInvalidUserToken
+		}
+
+		return s.update(ctx, repo, userID, pin)
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = s.cacheManager.UnlockAccount(ctx, lockoutKey(userID))
+
+	s.notifyChanged(ctx, userID)
+	return nil
+}
+
+func (s *pinService) update(ctx context.Context, repo Repository, userID, pin string) error {
+	hash, err := utils.HashPassword(pin)
+	if err != nil {
+		return err
+	}
+
+	updated, err := repo.Update(ctx, userID, hash)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return model.ErrTransactionPINNotSet
+	}
+
+	s.logger.Info("Transaction PIN changed", zap.String("userID", userID))
+	return nil
+}
+
+// notifyChanged tells the user their PIN changed, so an unexpected change is noticed
+func (s *pinService) notifyChanged(ctx context.Context, userID string) {
+	u, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	if err := s.mailer.Send(ctx, notifications.Message{
+		To:      []string{u.Email},
+		Subject: "Your transaction PIN was changed",
+		Text: "The transaction PIN on your account was just changed.\n\n" +
+			"If this was not you, reset your password and PIN now.",
+	}); err != nil {
+		s.logger.Error("Failed to send PIN change email", zap.String("userID", userID), zap.Error(err))
+	}
+}
+
+func validatePIN(pin string) error {
+	if len(pin) < minLength || len(pin) > maxLength {
+		return model.ErrInvalidTransactionPINFormat
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return model.ErrInvalidTransactionPINFormat
+		}
+	}
+	return nil
+}
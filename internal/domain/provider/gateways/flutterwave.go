@@ -1,7 +1,15 @@
 package gateways
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"codematic/internal/shared/utils"
 	"codematic/internal/thirdparty/flutterwave"
+
+	"github.com/shopspring/decimal"
 )
 
 type FlutterwaveProvider struct {
@@ -18,3 +26,84 @@ func NewFlutterwaveProvider(baseURL, apiKey string,
 		client:  client,
 	}
 }
+
+// VerifyTransaction looks a payment up by its tx_ref. Amounts are converted
+// to minor units to match Paystack.
+func (p *FlutterwaveProvider) VerifyTransaction(ctx context.Context,
+	reference string) (*VerifyResponse, error) {
+	resp, err := p.client.VerifyPaymentByReference(reference)
+	if err != nil {
+		return nil, fmt.Errorf("flutterwave verify error: %w", err)
+	}
+
+	return &VerifyResponse{
+		Provider:  flutterwave.ProviderFlutterwave,
+		Status:    flutterwaveStatus(resp.Data.Status),
+		Amount:    decimal.NewFromFloat(resp.Data.Amount).Round(utils.MinorUnits(resp.Data.Currency)),
+		Currency:  resp.Data.Currency,
+		Reference: resp.Data.TxRef,
+		Raw:       resp.Data,
+	}, nil
+}
+
+// ListTransactions fetches every transaction made on the days covered by
+// [from, to). Flutterwave filters by whole days only.
+func (p *FlutterwaveProvider) ListTransactions(ctx context.Context, from, to time.Time) ([]ReportEntry, error) {
+	var entries []ReportEntry
+	last := to.Add(-time.Nanosecond)
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := p.client.ListTransactions(from, last, page)
+		if err != nil {
+			return nil, fmt.Errorf("flutterwave list transactions error: %w", err)
+		}
+
+		for _, tx := range resp.Data {
+			entries = append(entries, ReportEntry{
+				Reference: tx.TxRef,
+				Amount:    decimal.NewFromFloat(tx.Amount).Round(utils.MinorUnits(tx.Currency)),
+				Currency:  tx.Currency,
+				Status:    flutterwaveStatus(tx.Status),
+			})
+		}
+
+		if page >= resp.Meta.PageInfo.TotalPages || len(resp.Data) == 0 {
+			return entries, nil
+		}
+	}
+}
+
+// Refund looks the payment up by its tx_ref, as Flutterwave refunds by its own
+// transaction ID
+func (p *FlutterwaveProvider) Refund(ctx context.Context, req RefundRequest) (*RefundResponse, error) {
+	payment, err := p.client.VerifyPaymentByReference(req.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("flutterwave refund lookup error: %w", err)
+	}
+
+	resp, err := p.client.RefundPayment(payment.Data.ID, req.Amount.InexactFloat64())
+	if err != nil {
+		return nil, fmt.Errorf("flutterwave refund error: %w", err)
+	}
+
+	return &RefundResponse{
+		Provider:  flutterwave.ProviderFlutterwave,
+		Reference: strconv.Itoa(resp.Data.ID),
+		Status:    resp.Data.Status,
+	}, nil
+}
+
+func flutterwaveStatus(status string) string {
+	switch status {
+	case "successful":
+		return VerifyStatusSuccess
+	case "failed":
+		return VerifyStatusFailed
+	case "cancelled":
+		return VerifyStatusAbandoned
+	}
+	return VerifyStatusPending
+}
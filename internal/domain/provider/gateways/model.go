@@ -2,6 +2,19 @@ package gateways
 
 import "github.com/shopspring/decimal"
 
+// Normalized VerifyResponse statuses. Anything a provider may still settle is
+// VerifyStatusPending; VerifyStatusAbandoned is a payment the customer never
+// completed.
+const (
+	VerifyStatusSuccess   = "success"
+	VerifyStatusFailed    = "failed"
+	VerifyStatusAbandoned = "abandoned"
+	VerifyStatusPending   = "pending"
+)
+
+// Transactions fetched per page of a provider report
+const reportPageSize = 100
+
 type (
 	CreateProviderParams struct {
 		Name   string
@@ -13,6 +26,7 @@ type (
 		ProviderID string
 		Email      string
 		Amount     decimal.Decimal
+		Currency   string
 		Metadata   map[string]interface{}
 	}
 
@@ -23,15 +37,43 @@ type (
 		ProviderID       string
 	}
 
+	// VerifyResponse is a provider's view of one payment. Amount is in major
+	// units of Currency.
 	VerifyResponse struct {
 		Provider  string
 		Status    string
-		Amount    int64
+		Amount    decimal.Decimal
 		Currency  string
 		Reference string
 		Raw       interface{}
 	}
 
+	// ReportEntry is one line of a provider's transaction report. Amount is in
+	// major units and Status is a normalized VerifyStatus* value.
+	ReportEntry struct {
+		Reference string          `json:"reference"`
+		Amount    decimal.Decimal `json:"amount"`
+		Currency  string          `json:"currency"`
+		Status    string          `json:"status"`
+	}
+
+	// RefundRequest returns Amount, in major units of Currency, of the payment
+	// with Reference to the customer
+	RefundRequest struct {
+		Reference string
+		Amount    decimal.Decimal
+		Currency  string
+		Reason    string
+	}
+
+	// RefundResponse is a provider's acknowledgement of a refund. Status is
+	// the provider's own, as refunds usually settle later.
+	RefundResponse struct {
+		Provider  string
+		Reference string
+		Status    string
+	}
+
 	WithdrawalRequest struct {
 		UserID   string
 		WalletID string
@@ -2,11 +2,14 @@ package gateways
 
 import (
 	"context"
+	"crypto/hmac"
 	"fmt"
+	"strconv"
+	"time"
 
+	"codematic/internal/shared/utils"
 	"codematic/internal/thirdparty/paystack"
 
-	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -26,10 +29,11 @@ func NewPaystackProvider(logger *zap.Logger, baseURL, apiSecret string) *Paystac
 
 func (p *PaystackProvider) InitDeposit(ctx context.Context,
 	req DepositRequest) (GatewayResponse, error) {
-	amountInKobo := req.Amount.Mul(decimal.NewFromInt(100))
+	amountInSubunits := utils.ToMinorUnits(req.Amount, req.Currency)
 
 	resp, err := p.client.InitializeTransaction(&paystack.InitializeTransactionRequest{
-		Amount:   amountInKobo.String(),
+		Amount:   amountInSubunits.String(),
+		Currency: req.Currency,
 		Email:    req.Email,
 		Metadata: req.Metadata,
 	})
@@ -37,7 +41,7 @@ func (p *PaystackProvider) InitDeposit(ctx context.Context,
 		return GatewayResponse{}, fmt.Errorf("paystack init deposit error: %w", err)
 	}
 
-	p.logger.Sugar().Infow("Paystack", "response", fmt.Sprintf("%+v", resp))
+	p.logger.Sugar().Infow("Paystack deposit initialized", "reference", resp.Data.Reference)
 
 	return GatewayResponse{
 		AuthorizationURL: resp.Data.AuthorizationURL,
@@ -54,15 +58,10 @@ func (p *PaystackProvider) VerifyTransaction(ctx context.Context,
 		return nil, fmt.Errorf("paystack verify error: %w", err)
 	}
 
-	status := "failed"
-	if resp.Data.Status == "success" {
-		status = "success"
-	}
-
 	return &VerifyResponse{
 		Provider:  paystack.ProviderPaystack,
-		Status:    status,
-		Amount:    resp.Data.Amount,
+		Status:    paystackStatus(resp.Data.Status),
+		Amount:    utils.FromMinorUnits(resp.Data.Amount, resp.Data.Currency),
 		Currency:  resp.Data.Currency,
 		Reference: resp.Data.Reference,
 		Raw:       resp.Data,
@@ -77,8 +76,68 @@ func (p *PaystackProvider) VerifyWebhookSignature(body []byte,
 		return false, fmt.Errorf("failed to generate signature: %w", err)
 	}
 
-	p.logger.Sugar().Debug("signatureHeader ", signatureHeader)
-	p.logger.Sugar().Debug("expectedSig ", expectedSig)
+	valid := hmac.Equal([]byte(expectedSig), []byte(signatureHeader))
+	if !valid {
+		p.logger.Warn("paystack webhook signature mismatch", zap.Int("payload_size", len(body)))
+	}
+
+	return valid, nil
+}
 
-	return expectedSig == signatureHeader, nil
+// ListTransactions fetches every transaction created in [from, to)
+func (p *PaystackProvider) ListTransactions(ctx context.Context, from, to time.Time) ([]ReportEntry, error) {
+	var entries []ReportEntry
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := p.client.ListTransactions(from, to, page, reportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("paystack list transactions error: %w", err)
+		}
+
+		for _, tx := range resp.Data {
+			entries = append(entries, ReportEntry{
+				Reference: tx.Reference,
+				Amount:    utils.FromMinorUnits(tx.Amount, tx.Currency),
+				Currency:  tx.Currency,
+				Status:    paystackStatus(tx.Status),
+			})
+		}
+
+		if page >= resp.Meta.PageCount || len(resp.Data) == 0 {
+			return entries, nil
+		}
+	}
+}
+
+func (p *PaystackProvider) Refund(ctx context.Context, req RefundRequest) (*RefundResponse, error) {
+	resp, err := p.client.CreateRefund(&paystack.CreateRefundRequest{
+		Transaction:  req.Reference,
+		Amount:       utils.ToMinorUnits(req.Amount, req.Currency).String(),
+		Currency:     req.Currency,
+		MerchantNote: req.Reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("paystack refund error: %w", err)
+	}
+
+	return &RefundResponse{
+		Provider:  paystack.ProviderPaystack,
+		Reference: strconv.FormatInt(resp.Data.ID, 10),
+		Status:    resp.Data.Status,
+	}, nil
+}
+
+func paystackStatus(status string) string {
+	switch status {
+	case "success":
+		return VerifyStatusSuccess
+	case "failed", "reversed":
+		return VerifyStatusFailed
+	case "abandoned":
+		return VerifyStatusAbandoned
+	}
+	return VerifyStatusPending
 }
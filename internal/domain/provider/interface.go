@@ -4,17 +4,28 @@ import (
 	"codematic/internal/domain/provider/gateways"
 	db "codematic/internal/infrastructure/db/sqlc"
 	"context"
+	"time"
 )
 
 type Service interface {
 	InitiateDeposit(ctx context.Context,
 		req DepositRequest) (gateways.GatewayResponse, error)
+	// SelectDepositProvider returns the ID of the provider a deposit in
+	// currency over channel would be routed to
+	SelectDepositProvider(ctx context.Context, currency, channel string) (string, error)
 	GetProviderByCode(ctx context.Context, code string) (*db.Provider, error)
 	GetProviderByID(ctx context.Context, id string) (*db.Provider, error)
 	VerifyWebhookSignature(ctx context.Context, providerCode, signatureHeader string,
 		body []byte) (bool, error)
 
 	VerifyPaystackTransaction(ctx context.Context, reference string) (*gateways.VerifyResponse, error)
+	VerifyFlutterwaveTransaction(ctx context.Context, reference string) (*gateways.VerifyResponse, error)
+	FetchTransactionReport(ctx context.Context, providerCode string,
+		from, to time.Time) ([]gateways.ReportEntry, error)
+	// RefundPayment asks the provider with providerID to return a payment to
+	// the customer
+	RefundPayment(ctx context.Context, providerID string,
+		req gateways.RefundRequest) (*gateways.RefundResponse, error)
 }
 
 type Repository interface {
@@ -13,11 +13,14 @@ type (
 		Config map[string]interface{}
 	}
 
+	// DepositRequest is routed to ProviderID when set, and otherwise to the
+	// best provider for its currency and channel
 	DepositRequest struct {
-		Amount   decimal.Decimal
-		Channel  string
-		Currency string
-		Metadata map[string]interface{}
+		Amount     decimal.Decimal
+		Channel    string
+		Currency   string
+		ProviderID string
+		Metadata   map[string]interface{}
 	}
 
 	InitDepositResponse struct {
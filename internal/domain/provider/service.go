@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"codematic/internal/domain/provider/gateways"
 	"codematic/internal/infrastructure/cache"
 	dbconn "codematic/internal/infrastructure/db"
 	db "codematic/internal/infrastructure/db/sqlc"
 	"codematic/internal/infrastructure/events/kafka"
+	"codematic/internal/shared/model"
 	"codematic/internal/thirdparty/flutterwave"
 	"codematic/internal/thirdparty/paystack"
 
@@ -46,13 +48,16 @@ func (s *providerService) InitiateDeposit(ctx context.Context,
 	req DepositRequest) (gateways.GatewayResponse, error) {
 	email, _ := req.Metadata["email"].(string)
 
-	providerRow, err := s.Repo.SelectBestProviderByCurrencyAndChannel(ctx, req.Currency, req.Channel)
-	if err != nil {
-		s.Logger.Error("No provider available", zap.Error(err))
-		return gateways.GatewayResponse{}, fmt.Errorf("no provider available for currency %s and channel %s", req.Currency, req.Channel)
+	providerID := req.ProviderID
+	if providerID == "" {
+		id, err := s.SelectDepositProvider(ctx, req.Currency, req.Channel)
+		if err != nil {
+			return gateways.GatewayResponse{}, err
+		}
+		providerID = id
 	}
 
-	provider, err := s.GetProviderByID(ctx, providerRow.ID.String())
+	provider, err := s.GetProviderByID(ctx, providerID)
 	if err != nil {
 		s.Logger.Error("Failed to retrieve provider details", zap.Error(err))
 		return gateways.GatewayResponse{}, err
@@ -71,6 +76,7 @@ func (s *providerService) InitiateDeposit(ctx context.Context,
 		return gateway.InitDeposit(ctx, gateways.DepositRequest{
 			Email:      email,
 			Amount:     req.Amount,
+			Currency:   req.Currency,
 			ProviderID: provider.ID.String(),
 		})
 	}
@@ -82,6 +88,16 @@ func (s *providerService) InitiateDeposit(ctx context.Context,
 	return gateways.GatewayResponse{}, fmt.Errorf("unsupported provider: %s", provider.Code)
 }
 
+func (s *providerService) SelectDepositProvider(ctx context.Context,
+	currency, channel string) (string, error) {
+	providerRow, err := s.Repo.SelectBestProviderByCurrencyAndChannel(ctx, currency, channel)
+	if err != nil {
+		s.Logger.Error("No provider available", zap.Error(err))
+		return "", fmt.Errorf("no provider available for currency %s and channel %s", currency, channel)
+	}
+	return providerRow.ID.String(), nil
+}
+
 func (s *providerService) GetProviderByCode(ctx context.Context,
 	code string) (*db.Provider, error) {
 	code = strings.ToLower(code)
@@ -194,3 +210,76 @@ func (s *providerService) VerifyPaystackTransaction(ctx context.Context, referen
 
 	return gateway.VerifyTransaction(ctx, reference)
 }
+
+func (s *providerService) VerifyFlutterwaveTransaction(ctx context.Context, reference string) (*gateways.VerifyResponse, error) {
+	provider, err := s.GetProviderByCode(ctx, flutterwave.ProviderFlutterwave)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg FlutterwaveConfig
+	if err := json.Unmarshal(provider.Config, &cfg); err != nil {
+		return nil, err
+	}
+
+	client := flutterwave.NewFlutterwaveClient(cfg.BaseURL, cfg.SecretKey, s.Logger)
+	gateway := gateways.NewFlutterwaveProvider(cfg.BaseURL, cfg.SecretKey, client)
+
+	return gateway.VerifyTransaction(ctx, reference)
+}
+
+// FetchTransactionReport pulls the provider's own record of the transactions
+// created in [from, to), for reconciliation
+func (s *providerService) FetchTransactionReport(ctx context.Context, providerCode string,
+	from, to time.Time) ([]gateways.ReportEntry, error) {
+	provider, err := s.GetProviderByCode(ctx, providerCode)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(provider.Code) {
+	case paystack.ProviderPaystack:
+		var cfg PaystackConfig
+		if err := json.Unmarshal(provider.Config, &cfg); err != nil {
+			return nil, err
+		}
+		return gateways.NewPaystackProvider(s.Logger, cfg.BaseURL, cfg.SecretKey).ListTransactions(ctx, from, to)
+
+	case flutterwave.ProviderFlutterwave:
+		var cfg FlutterwaveConfig
+		if err := json.Unmarshal(provider.Config, &cfg); err != nil {
+			return nil, err
+		}
+		client := flutterwave.NewFlutterwaveClient(cfg.BaseURL, cfg.SecretKey, s.Logger)
+		return gateways.NewFlutterwaveProvider(cfg.BaseURL, cfg.SecretKey, client).ListTransactions(ctx, from, to)
+	}
+
+	return nil, fmt.Errorf("%w: %s does not support report pulls", model.ErrUnsupportedProvider, providerCode)
+}
+
+func (s *providerService) RefundPayment(ctx context.Context, providerID string,
+	req gateways.RefundRequest) (*gateways.RefundResponse, error) {
+	provider, err := s.GetProviderByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(provider.Code) {
+	case paystack.ProviderPaystack:
+		var cfg PaystackConfig
+		if err := json.Unmarshal(provider.Config, &cfg); err != nil {
+			return nil, err
+		}
+		return gateways.NewPaystackProvider(s.Logger, cfg.BaseURL, cfg.SecretKey).Refund(ctx, req)
+
+	case flutterwave.ProviderFlutterwave:
+		var cfg FlutterwaveConfig
+		if err := json.Unmarshal(provider.Config, &cfg); err != nil {
+			return nil, err
+		}
+		client := flutterwave.NewFlutterwaveClient(cfg.BaseURL, cfg.SecretKey, s.Logger)
+		return gateways.NewFlutterwaveProvider(cfg.BaseURL, cfg.SecretKey, client).Refund(ctx, req)
+	}
+
+	return nil, fmt.Errorf("%w: %s does not support refunds", model.ErrUnsupportedProvider, provider.Code)
+}
@@ -0,0 +1,31 @@
+package rbac
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+)
+
+type Service interface {
+	ListRoles(ctx context.Context, tenantID string) ([]Role, error)
+	GetRole(ctx context.Context, tenantID, id string) (Role, error)
+	CreateRole(ctx context.Context, tenantID string, req RoleRequest) (Role, error)
+	UpdateRole(ctx context.Context, tenantID, id string, req RoleRequest) (Role, error)
+	DeleteRole(ctx context.Context, tenantID, id string) error
+	AssignRole(ctx context.Context, tenantID, roleID, userID string) error
+	UnassignRole(ctx context.Context, tenantID, roleID, userID string) error
+	ListUserRoles(ctx context.Context, userID string) ([]Role, error)
+	PermissionsFor(ctx context.Context, userID, role string) ([]string, error)
+	WithTx(q *db.Queries) Service
+}
+
+type Repository interface {
+	Create(ctx context.Context, tenantID string, req RoleRequest) (db.Role, error)
+	GetByID(ctx context.Context, tenantID, id string) (db.Role, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]db.Role, error)
+	Update(ctx context.Context, tenantID, id string, req RoleRequest) (db.Role, error)
+	Delete(ctx context.Context, tenantID, id string) (bool, error)
+	Assign(ctx context.Context, userID, roleID string) error
+	Unassign(ctx context.Context, userID, roleID string) (bool, error)
+	ListForUser(ctx context.Context, userID string) ([]db.Role, error)
+	WithTx(q *db.Queries) Repository
+}
@@ -0,0 +1,59 @@
+package rbac
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"time"
+)
+
+type (
+	RoleRequest struct {
+		Name        string   `json:"name" validate:"required,max=100"`
+		Description string   `json:"description" validate:"max=500"`
+		Permissions []string `json:"permissions" validate:"required,min=1"`
+	}
+
+	Role struct {
+		ID          string    `json:"id"`
+		TenantID    string    `json:"tenant_id,omitempty"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Permissions []string  `json:"permissions"`
+		BuiltIn     bool      `json:"built_in"`
+		CreatedAt   time.Time `json:"created_at,omitempty"`
+		UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	}
+)
+
+// builtInRoles describes the base roles alongside a tenant's custom roles;
+// they cannot be edited and are identified by name.
+func builtInRoles() []Role {
+	return []Role{
+		{
+			ID:          model.RoleTenantAdmin.String(),
+			Name:        model.RoleTenantAdmin.String(),
+			Description: "Full access to the tenant",
+			Permissions: model.DefaultPermissions(model.RoleTenantAdmin.String()),
+			BuiltIn:     true,
+		},
+		{
+			ID:          model.RoleUser.String(),
+			Name:        model.RoleUser.String(),
+			Description: "Access to the user's own wallet and transactions",
+			Permissions: []string{},
+			BuiltIn:     true,
+		},
+	}
+}
+
+func toDomainRole(r db.Role) Role {
+	return Role{
+		ID:          r.ID.String(),
+		TenantID:    r.TenantID.String(),
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: r.Permissions,
+		CreatedAt:   r.CreatedAt.Time,
+		UpdatedAt:   r.UpdatedAt.Time,
+	}
+}
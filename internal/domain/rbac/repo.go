@@ -0,0 +1,124 @@
+package rbac
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) Create(ctx context.Context, tenantID string, req RoleRequest) (db.Role, error) {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.Role{}, err
+	}
+	return r.q.CreateRole(ctx, db.CreateRoleParams{
+		ID:          utils.ToUUID(uuid.New()),
+		TenantID:    tid,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+}
+
+func (r *repository) GetByID(ctx context.Context, tenantID, id string) (db.Role, error) {
+	rid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.Role{}, err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.Role{}, err
+	}
+	return r.q.GetRoleByID(ctx, db.GetRoleByIDParams{ID: rid, TenantID: tid})
+}
+
+func (r *repository) ListByTenant(ctx context.Context, tenantID string) ([]db.Role, error) {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListRolesByTenant(ctx, tid)
+}
+
+func (r *repository) Update(ctx context.Context, tenantID, id string, req RoleRequest) (db.Role, error) {
+	rid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.Role{}, err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.Role{}, err
+	}
+	return r.q.UpdateRole(ctx, db.UpdateRoleParams{
+		ID:          rid,
+		TenantID:    tid,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, tenantID, id string) (bool, error) {
+	rid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.DeleteRole(ctx, db.DeleteRoleParams{ID: rid, TenantID: tid})
+	return rows > 0, err
+}
+
+func (r *repository) Assign(ctx context.Context, userID, roleID string) error {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	rid, err := utils.StringToPgUUID(roleID)
+	if err != nil {
+		return err
+	}
+	return r.q.AssignRoleToUser(ctx, db.AssignRoleToUserParams{UserID: uid, RoleID: rid})
+}
+
+func (r *repository) Unassign(ctx context.Context, userID, roleID string) (bool, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	rid, err := utils.StringToPgUUID(roleID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.UnassignRoleFromUser(ctx, db.UnassignRoleFromUserParams{UserID: uid, RoleID: rid})
+	return rows > 0, err
+}
+
+func (r *repository) ListForUser(ctx context.Context, userID string) ([]db.Role, error) {
+	uid, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListRolesForUser(ctx, uid)
+}
@@ -0,0 +1,221 @@
+package rbac
+
+import (
+	"codematic/internal/domain/user"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+type rbacService struct {
+	DB          *db.DBConn
+	Repo        Repository
+	userService user.Service
+	logger      *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the RBAC service.
+func NewService(db *db.DBConn, userService user.Service, logger *zap.Logger) Service {
+	return &rbacService{
+		DB:          db,
+		Repo:        NewRepository(db.Queries, db.Pool),
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+func (s *rbacService) WithTx(q *dbsqlc.Queries) Service {
+	return &rbacService{
+		DB:          s.DB,
+		Repo:        NewRepository(q, s.DB.Pool),
+		userService: s.userService.WithTx(q),
+		logger:      s.logger,
+	}
+}
+
+// ListRoles returns the built-in roles followed by the tenant's custom roles
+func (s *rbacService) ListRoles(ctx context.Context, tenantID string) ([]Role, error) {
+	custom, err := s.Repo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := builtInRoles()
+	for _, r := range custom {
+		roles = append(roles, toDomainRole(r))
+	}
+	return roles, nil
+}
+
+func (s *rbacService) GetRole(ctx context.Context, tenantID, id string) (Role, error) {
+	r, err := s.Repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return Role{}, model.ErrRoleNotFound
+	}
+	return toDomainRole(r), nil
+}
+
+func (s *rbacService) CreateRole(ctx context.Context, tenantID string, req RoleRequest) (Role, error) {
+	if err := normalizeRoleRequest(&req); err != nil {
+		return Role{}, err
+	}
+
+	r, err := s.Repo.Create(ctx, tenantID, req)
+	if err != nil {
+		return Role{}, mapRoleWriteError(err)
+	}
+
+	s.logger.Info("Role created", zap.String("tenantID", tenantID), zap.String("role", req.Name))
+	return toDomainRole(r), nil
+}
+
+func (s *rbacService) UpdateRole(ctx context.Context, tenantID, id string, req RoleRequest) (Role, error) {
+	if err := normalizeRoleRequest(&req); err != nil {
+		return Role{}, err
+	}
+
+	r, err := s.Repo.Update(ctx, tenantID, id, req)
+	if err != nil {
+		return Role{}, mapRoleWriteError(err)
+	}
+
+	s.logger.Info("Role updated", zap.String("tenantID", tenantID), zap.String("roleID", id))
+	return toDomainRole(r), nil
+}
+
+func (s *rbacService) DeleteRole(ctx context.Context, tenantID, id string) error {
+	deleted, err := s.Repo.Delete(ctx, tenantID, id)
+	if err != nil {
+		return model.ErrRoleNotFound
+	}
+	if !deleted {
+		return model.ErrRoleNotFound
+	}
+
+	s.logger.Info("Role deleted", zap.String("tenantID", tenantID), zap.String("roleID", id))
+	return nil
+}
+
+func (s *rbacService) AssignRole(ctx context.Context, tenantID, roleID, userID string) error {
+	if _, err := s.Repo.GetByID(ctx, tenantID, roleID); err != nil {
+		return model.ErrRoleNotFound
+	}
+	if err := s.checkUserInTenant(ctx, tenantID, userID); err != nil {
+		return err
+	}
+
+	if err := s.Repo.Assign(ctx, userID, roleID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Role assigned", zap.String("roleID", roleID), zap.String("userID", userID))
+	return nil
+}
+
+func (s *rbacService) UnassignRole(ctx context.Context, tenantID, roleID, userID string) error {
+	if _, err := s.Repo.GetByID(ctx, tenantID, roleID); err != nil {
+		return model.ErrRoleNotFound
+	}
+
+	removed, err := s.Repo.Unassign(ctx, userID, roleID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return model.ErrRoleNotFound
+	}
+
+	s.logger.Info("Role unassigned", zap.String("roleID", roleID), zap.String("userID", userID))
+	return nil
+}
+
+func (s *rbacService) ListUserRoles(ctx context.Context, userID string) ([]Role, error) {
+	assigned, err := s.Repo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]Role, 0, len(assigned))
+	for _, r := range assigned {
+		roles = append(roles, toDomainRole(r))
+	}
+	return roles, nil
+}
+
+// PermissionsFor resolves the permissions of a user: those of their base role
+// plus every custom role assigned to them.
+func (s *rbacService) PermissionsFor(ctx context.Context, userID, role string) ([]string, error) {
+	set := make(map[string]bool)
+	for _, p := range model.DefaultPermissions(role) {
+		set[p] = true
+	}
+
+	assigned, err := s.Repo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range assigned {
+		for _, p := range r.Permissions {
+			set[p] = true
+		}
+	}
+
+	permissions := make([]string, 0, len(set))
+	for p := range set {
+		permissions = append(permissions, p)
+	}
+	sort.Strings(permissions)
+	return permissions, nil
+}
+
+func (s *rbacService) checkUserInTenant(ctx context.Context, tenantID, userID string) error {
+	u, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil || u.TenantID.String() != tenantID {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+// normalizeRoleRequest trims the name, de-duplicates permissions and rejects
+// any permission a tenant cannot grant.
+func normalizeRoleRequest(req *RoleRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return model.ErrInvalidInputError
+	}
+
+	seen := make(map[string]bool)
+	permissions := make([]string, 0, len(req.Permissions))
+	for _, p := range req.Permissions {
+		p = strings.TrimSpace(p)
+		if !model.IsTenantPermission(p) {
+			return model.ErrInvalidPermission
+		}
+		if !seen[p] {
+			seen[p] = true
+			permissions = append(permissions, p)
+		}
+	}
+	sort.Strings(permissions)
+	req.Permissions = permissions
+	return nil
+}
+
+func mapRoleWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return model.ErrRoleNameTaken
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ErrRoleNotFound
+	}
+	return err
+}
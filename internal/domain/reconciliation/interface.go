@@ -0,0 +1,34 @@
+package reconciliation
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"time"
+)
+
+type Service interface {
+	Import(ctx context.Context, req ImportRequest) (*Run, error)
+	Pull(ctx context.Context, providerCode string, from, to time.Time, userID string) (*Run, error)
+	PullDaily(ctx context.Context, day time.Time) error
+	GetRun(ctx context.Context, id string) (*Run, error)
+	ListRuns(ctx context.Context, providerID string, limit, offset int) ([]*Run, error)
+	ListExceptions(ctx context.Context, filter ExceptionFilter) (ExceptionList, error)
+	ResolveException(ctx context.Context, id, userID string, req ResolveRequest) (*Exception, error)
+}
+
+type Repository interface {
+	CreateRun(ctx context.Context, arg db.CreateReconciliationRunParams) (db.ReconciliationRun, error)
+	CompleteRun(ctx context.Context, arg db.CompleteReconciliationRunParams) error
+	FailRun(ctx context.Context, id, reason string) error
+	GetRun(ctx context.Context, id string) (db.ReconciliationRun, error)
+	ListRuns(ctx context.Context, providerID string, limit, offset int) ([]db.ReconciliationRun, error)
+	ListTransactionsByReferences(ctx context.Context, refs []string) ([]db.Transaction, error)
+	ListCompletedDeposits(ctx context.Context, providerID string, from, to time.Time) ([]db.Transaction, error)
+	CreateException(ctx context.Context, arg db.CreateReconciliationExceptionParams) error
+	SupersedeExceptions(ctx context.Context, providerID, runID string, refs []string) error
+	GetException(ctx context.Context, id string) (db.ReconciliationException, error)
+	SearchExceptions(ctx context.Context, filter ExceptionFilter) ([]db.ReconciliationException, error)
+	CountExceptions(ctx context.Context, filter ExceptionFilter) (int64, error)
+	ResolveException(ctx context.Context, id, userID, status, note string) (bool, error)
+	WithTx(q *db.Queries) Repository
+}
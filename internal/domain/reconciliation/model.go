@@ -0,0 +1,139 @@
+package reconciliation
+
+import (
+	"codematic/internal/thirdparty/flutterwave"
+	"codematic/internal/thirdparty/paystack"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SourceUpload = "upload"
+	SourceAPI    = "api"
+
+	RunProcessing = "processing"
+	RunCompleted  = "completed"
+	RunFailed     = "failed"
+
+	// Matched items are only counted; the others become exceptions
+	Matched    = "matched"
+	Missing    = "missing"
+	Mismatched = "mismatched"
+	Extra      = "extra"
+
+	ExceptionOpen       = "open"
+	ExceptionResolved   = "resolved"
+	ExceptionIgnored    = "ignored"
+	ExceptionSuperseded = "superseded"
+
+	// Largest report accepted, in lines
+	maxReportEntries = 200000
+
+	// Longest period a single run may cover
+	maxPeriod = 31 * 24 * time.Hour
+
+	// References looked up per query
+	lookupBatchSize = 500
+
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// pullProviders are the providers whose reports the daily job pulls
+var pullProviders = []string{paystack.ProviderPaystack, flutterwave.ProviderFlutterwave}
+
+var (
+	validClassifications = map[string]bool{Missing: true, Mismatched: true, Extra: true}
+	validStatuses        = map[string]bool{
+		ExceptionOpen: true, ExceptionResolved: true, ExceptionIgnored: true, ExceptionSuperseded: true,
+	}
+)
+
+type (
+	// ImportRequest is an uploaded provider report covering [From, To)
+	ImportRequest struct {
+		ProviderCode string
+		FileName     string
+		Data         []byte
+		From         time.Time
+		To           time.Time
+		UserID       string
+	}
+
+	// Run is one reconciliation of a provider report against our transactions.
+	// TotalItems counts report lines, each of which is matched, mismatched or
+	// extra; Missing counts our completed deposits absent from the report.
+	Run struct {
+		ID          string     `json:"id"`
+		ProviderID  string     `json:"provider_id"`
+		Source      string     `json:"source"`
+		FileName    string     `json:"file_name,omitempty"`
+		PeriodStart time.Time  `json:"period_start"`
+		PeriodEnd   time.Time  `json:"period_end"`
+		Status      string     `json:"status"`
+		TotalItems  int        `json:"total_items"`
+		Matched     int        `json:"matched"`
+		Missing     int        `json:"missing"`
+		Mismatched  int        `json:"mismatched"`
+		Extra       int        `json:"extra"`
+		Error       string     `json:"error,omitempty"`
+		CreatedBy   string     `json:"created_by,omitempty"`
+		CreatedAt   time.Time  `json:"created_at"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+	}
+
+	// Exception is a report line or transaction that did not reconcile.
+	// Provider* fields come from the report, the others from our transaction.
+	Exception struct {
+		ID                string           `json:"id"`
+		RunID             string           `json:"run_id"`
+		ProviderID        string           `json:"provider_id"`
+		Reference         string           `json:"reference"`
+		Classification    string           `json:"classification"`
+		TransactionID     string           `json:"transaction_id,omitempty"`
+		ProviderAmount    *decimal.Decimal `json:"provider_amount,omitempty"`
+		ProviderCurrency  string           `json:"provider_currency,omitempty"`
+		ProviderStatus    string           `json:"provider_status,omitempty"`
+		Amount            *decimal.Decimal `json:"amount,omitempty"`
+		CurrencyCode      string           `json:"currency_code,omitempty"`
+		TransactionStatus string           `json:"transaction_status,omitempty"`
+		Detail            string           `json:"detail"`
+		Status            string           `json:"status"`
+		ResolvedBy        string           `json:"resolved_by,omitempty"`
+		ResolvedAt        *time.Time       `json:"resolved_at,omitempty"`
+		ResolutionNote    string           `json:"resolution_note,omitempty"`
+		CreatedAt         time.Time        `json:"created_at"`
+	}
+
+	// ExceptionFilter narrows the exception queue; empty fields match everything
+	ExceptionFilter struct {
+		RunID          string
+		ProviderID     string
+		Classification string
+		Status         string
+		Limit          int
+		Offset         int
+	}
+
+	ExceptionList struct {
+		Exceptions []*Exception `json:"exceptions"`
+		Total      int64        `json:"total"`
+		Limit      int          `json:"limit"`
+		Offset     int          `json:"offset"`
+	}
+
+	// PullRequest asks for a provider's report covering From to To, both
+	// inclusive UTC days
+	PullRequest struct {
+		Provider string `json:"provider" validate:"required"`
+		From     string `json:"from" validate:"required,datetime=2006-01-02"`
+		To       string `json:"to" validate:"required,datetime=2006-01-02"`
+	}
+
+	// ResolveRequest closes an open exception
+	ResolveRequest struct {
+		Status string `json:"status" validate:"required,oneof=resolved ignored"`
+		Note   string `json:"note" validate:"max=1000"`
+	}
+)
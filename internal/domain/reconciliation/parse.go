@@ -0,0 +1,191 @@
+package reconciliation
+
+import (
+	"bytes"
+	"codematic/internal/domain/provider/gateways"
+	"codematic/internal/shared/model"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Accepted header names for each report column, lower-cased
+var (
+	referenceColumns = []string{"reference", "ref", "tx_ref", "transaction_reference"}
+	amountColumns    = []string{"amount", "settled_amount"}
+	currencyColumns  = []string{"currency", "currency_code"}
+	statusColumns    = []string{"status", "transaction_status"}
+)
+
+// ParseReport reads a provider report uploaded as CSV or JSON. The format is
+// taken from the file extension, falling back to the content. Lines without a
+// status are treated as settled, since settlement reports only list those.
+func ParseReport(fileName string, data []byte) ([]gateways.ReportEntry, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var (
+		entries []gateways.ReportEntry
+		err     error
+	)
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		entries, err = parseJSON(data)
+	case ".csv":
+		entries, err = parseCSV(data)
+	default:
+		trimmed := bytes.TrimSpace(data)
+		if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+			entries, err = parseJSON(data)
+		} else {
+			entries, err = parseCSV(data)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidReport, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: report has no entries", model.ErrInvalidReport)
+	}
+	if len(entries) > maxReportEntries {
+		return nil, fmt.Errorf("%w: report has more than %d entries", model.ErrInvalidReport, maxReportEntries)
+	}
+	return entries, nil
+}
+
+func parseCSV(data []byte) ([]gateways.ReportEntry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	refCol, amountCol := findColumn(header, referenceColumns), findColumn(header, amountColumns)
+	currencyCol, statusCol := findColumn(header, currencyColumns), findColumn(header, statusColumns)
+	if refCol < 0 || amountCol < 0 || currencyCol < 0 {
+		return nil, errors.New("csv header must include reference, amount and currency columns")
+	}
+
+	var entries []gateways.ReportEntry
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		status := ""
+		if statusCol >= 0 {
+			status = record[statusCol]
+		}
+		entry, err := newEntry(record[refCol], record[amountCol], record[currencyCol], status)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		entries = append(entries, entry)
+		if len(entries) > maxReportEntries {
+			break
+		}
+	}
+	return entries, nil
+}
+
+func parseJSON(data []byte) ([]gateways.ReportEntry, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		// Provider exports wrap the list in {"data": [...]}
+		var wrapped struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, err
+		}
+		rows = wrapped.Data
+	}
+
+	entries := make([]gateways.ReportEntry, 0, len(rows))
+	for i, row := range rows {
+		fields := make(map[string]string, len(row))
+		for k, v := range row {
+			switch v := v.(type) {
+			case string:
+				fields[strings.ToLower(k)] = v
+			case float64:
+				fields[strings.ToLower(k)] = decimal.NewFromFloat(v).String()
+			}
+		}
+		entry, err := newEntry(pick(fields, referenceColumns), pick(fields, amountColumns),
+			pick(fields, currencyColumns), pick(fields, statusColumns))
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %v", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func newEntry(reference, amount, currency, status string) (gateways.ReportEntry, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return gateways.ReportEntry{}, errors.New("reference is empty")
+	}
+	value, err := decimal.NewFromString(strings.ReplaceAll(strings.TrimSpace(amount), ",", ""))
+	if err != nil {
+		return gateways.ReportEntry{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return gateways.ReportEntry{}, errors.New("currency is empty")
+	}
+	return gateways.ReportEntry{
+		Reference: reference,
+		Amount:    value,
+		Currency:  currency,
+		Status:    normalizeStatus(status),
+	}, nil
+}
+
+// normalizeStatus maps a report status onto the gateways.VerifyStatus* values
+func normalizeStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "", "success", "successful", "settled", "completed":
+		return gateways.VerifyStatusSuccess
+	case "failed", "reversed", "declined":
+		return gateways.VerifyStatusFailed
+	case "abandoned", "cancelled":
+		return gateways.VerifyStatusAbandoned
+	default:
+		return gateways.VerifyStatusPending
+	}
+}
+
+func findColumn(header []string, names []string) int {
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, name := range names {
+			if h == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func pick(fields map[string]string, names []string) string {
+	for _, name := range names {
+		if v, ok := fields[name]; ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+package reconciliation
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) CreateRun(ctx context.Context, arg db.CreateReconciliationRunParams) (db.ReconciliationRun, error) {
+	return r.q.CreateReconciliationRun(ctx, arg)
+}
+
+func (r *repository) CompleteRun(ctx context.Context, arg db.CompleteReconciliationRunParams) error {
+	return r.q.CompleteReconciliationRun(ctx, arg)
+}
+
+func (r *repository) FailRun(ctx context.Context, id, reason string) error {
+	runID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	return r.q.FailReconciliationRun(ctx, db.FailReconciliationRunParams{
+		Error: utils.ToPgxText(reason),
+		ID:    runID,
+	})
+}
+
+func (r *repository) GetRun(ctx context.Context, id string) (db.ReconciliationRun, error) {
+	runID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.ReconciliationRun{}, err
+	}
+	return r.q.GetReconciliationRun(ctx, runID)
+}
+
+func (r *repository) ListRuns(ctx context.Context, providerID string, limit, offset int) ([]db.ReconciliationRun, error) {
+	pid, err := optionalUUID(providerID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListReconciliationRuns(ctx, db.ListReconciliationRunsParams{
+		ProviderID: pid,
+		RowLimit:   int32(limit),
+		RowOffset:  int32(offset),
+	})
+}
+
+func (r *repository) ListTransactionsByReferences(ctx context.Context, refs []string) ([]db.Transaction, error) {
+	return r.q.ListTransactionsByReferences(ctx, refs)
+}
+
+func (r *repository) ListCompletedDeposits(ctx context.Context, providerID string,
+	from, to time.Time) ([]db.Transaction, error) {
+	pid, err := utils.StringToPgUUID(providerID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListCompletedProviderDeposits(ctx, db.ListCompletedProviderDepositsParams{
+		ProviderID:  pid,
+		PeriodStart: utils.ToPgTimestamptz(from),
+		PeriodEnd:   utils.ToPgTimestamptz(to),
+	})
+}
+
+func (r *repository) CreateException(ctx context.Context, arg db.CreateReconciliationExceptionParams) error {
+	return r.q.CreateReconciliationException(ctx, arg)
+}
+
+func (r *repository) SupersedeExceptions(ctx context.Context, providerID, runID string, refs []string) error {
+	pid, err := utils.StringToPgUUID(providerID)
+	if err != nil {
+		return err
+	}
+	rid, err := utils.StringToPgUUID(runID)
+	if err != nil {
+		return err
+	}
+	_, err = r.q.SupersedeReconciliationExceptions(ctx, db.SupersedeReconciliationExceptionsParams{
+		ResolutionNote: utils.ToPgxText("superseded by run " + runID),
+		ProviderID:     pid,
+		RunID:          rid,
+		Refs:           refs,
+	})
+	return err
+}
+
+func (r *repository) GetException(ctx context.Context, id string) (db.ReconciliationException, error) {
+	exceptionID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.ReconciliationException{}, err
+	}
+	return r.q.GetReconciliationException(ctx, exceptionID)
+}
+
+func (r *repository) SearchExceptions(ctx context.Context, filter ExceptionFilter) ([]db.ReconciliationException, error) {
+	runID, providerID, err := filterIDs(filter)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.SearchReconciliationExceptions(ctx, db.SearchReconciliationExceptionsParams{
+		RunID:          runID,
+		ProviderID:     providerID,
+		Classification: optionalText(filter.Classification),
+		Status:         optionalText(filter.Status),
+		RowLimit:       int32(filter.Limit),
+		RowOffset:      int32(filter.Offset),
+	})
+}
+
+func (r *repository) CountExceptions(ctx context.Context, filter ExceptionFilter) (int64, error) {
+	runID, providerID, err := filterIDs(filter)
+	if err != nil {
+		return 0, err
+	}
+	return r.q.CountReconciliationExceptions(ctx, db.CountReconciliationExceptionsParams{
+		RunID:          runID,
+		ProviderID:     providerID,
+		Classification: optionalText(filter.Classification),
+		Status:         optionalText(filter.Status),
+	})
+}
+
+// ResolveException closes an open exception, reporting false if it was not open
+func (r *repository) ResolveException(ctx context.Context, id, userID, status, note string) (bool, error) {
+	exceptionID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	resolvedBy, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := r.q.ResolveReconciliationException(ctx, db.ResolveReconciliationExceptionParams{
+		Status:         status,
+		ResolvedBy:     resolvedBy,
+		ResolutionNote: optionalText(note),
+		ID:             exceptionID,
+	})
+	return rows > 0, err
+}
+
+func filterIDs(filter ExceptionFilter) (pgtype.UUID, pgtype.UUID, error) {
+	runID, err := optionalUUID(filter.RunID)
+	if err != nil {
+		return pgtype.UUID{}, pgtype.UUID{}, err
+	}
+	providerID, err := optionalUUID(filter.ProviderID)
+	if err != nil {
+		return pgtype.UUID{}, pgtype.UUID{}, err
+	}
+	return runID, providerID, nil
+}
+
+// optionalUUID maps an empty id to NULL so the query ignores the filter
+func optionalUUID(id string) (pgtype.UUID, error) {
+	if id == "" {
+		return pgtype.UUID{}, nil
+	}
+	return utils.StringToPgUUID(id)
+}
+
+func optionalText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{}
+	}
+	return utils.ToPgxText(s)
+}
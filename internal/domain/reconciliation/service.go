@@ -0,0 +1,502 @@
+package reconciliation
+
+import (
+	"codematic/internal/domain/provider"
+	"codematic/internal/domain/provider/gateways"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const completedStatus = "completed"
+
+type reconciliationService struct {
+	DB       *db.DBConn
+	Repo     Repository
+	provider provider.Service
+	logger   *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the reconciliation service.
+func NewService(db *db.DBConn, providerService provider.Service, logger *zap.Logger) Service {
+	return &reconciliationService{
+		DB:       db,
+		Repo:     NewRepository(db.Queries, db.Pool),
+		provider: providerService,
+		logger:   logger,
+	}
+}
+
+// Import reconciles an uploaded provider report. A report that cannot be
+// parsed is rejected without recording a run.
+func (s *reconciliationService) Import(ctx context.Context, req ImportRequest) (*Run, error) {
+	p, err := s.lookupProvider(ctx, req.ProviderCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPeriod(req.From, req.To); err != nil {
+		return nil, err
+	}
+	entries, err := ParseReport(req.FileName, req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.startRun(ctx, p, SourceUpload, req.FileName, req.From, req.To, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reconcile(ctx, run, entries)
+}
+
+// Pull fetches the provider's transaction report for [from, to) through its
+// API and reconciles it. A failed pull is recorded on the run.
+func (s *reconciliationService) Pull(ctx context.Context, providerCode string,
+	from, to time.Time, userID string) (*Run, error) {
+	p, err := s.lookupProvider(ctx, providerCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPeriod(from, to); err != nil {
+		return nil, err
+	}
+
+	run, err := s.startRun(ctx, p, SourceAPI, "", from, to, userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.provider.FetchTransactionReport(ctx, p.Code, from, to)
+	if err != nil {
+		return s.failRun(ctx, run, fmt.Errorf("fetch report: %w", err))
+	}
+	return s.reconcile(ctx, run, entries)
+}
+
+// PullDaily reconciles the UTC day containing day for every active provider
+// whose report can be pulled, carrying on past failures.
+func (s *reconciliationService) PullDaily(ctx context.Context, day time.Time) error {
+	day = day.UTC()
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	var failed int
+	for _, code := range pullProviders {
+		p, err := s.provider.GetProviderByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return err
+		}
+		if !p.IsActive.Bool {
+			continue
+		}
+
+		run, err := s.Pull(ctx, code, from, to, "")
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.Error("Settlement reconciliation failed",
+				zap.String("provider", code), zap.Error(err))
+			failed++
+			continue
+		}
+		s.logger.Info("Settlement reconciliation completed",
+			zap.String("provider", code),
+			zap.String("runID", run.ID),
+			zap.Int("items", run.TotalItems),
+			zap.Int("matched", run.Matched),
+			zap.Int("missing", run.Missing),
+			zap.Int("mismatched", run.Mismatched),
+			zap.Int("extra", run.Extra))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d provider reconciliations failed", failed)
+	}
+	return nil
+}
+
+func (s *reconciliationService) GetRun(ctx context.Context, id string) (*Run, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrReconciliationRunNotFound
+	}
+	row, err := s.Repo.GetRun(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrReconciliationRunNotFound
+		}
+		return nil, err
+	}
+	return toRun(row), nil
+}
+
+// ListRuns returns runs newest first, optionally for one provider
+func (s *reconciliationService) ListRuns(ctx context.Context, providerID string, limit, offset int) ([]*Run, error) {
+	if providerID != "" {
+		if _, err := uuid.Parse(providerID); err != nil {
+			return nil, fmt.Errorf("%w: invalid provider_id", model.ErrInvalidInputError)
+		}
+	}
+	limit, offset = clampPage(limit, offset)
+
+	rows, err := s.Repo.ListRuns(ctx, providerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*Run, len(rows))
+	for i, row := range rows {
+		runs[i] = toRun(row)
+	}
+	return runs, nil
+}
+
+// ListExceptions returns the exception queue, oldest first
+func (s *reconciliationService) ListExceptions(ctx context.Context, filter ExceptionFilter) (ExceptionList, error) {
+	for name, id := range map[string]string{"run_id": filter.RunID, "provider_id": filter.ProviderID} {
+		if id == "" {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			return ExceptionList{}, fmt.Errorf("%w: invalid %s", model.ErrInvalidInputError, name)
+		}
+	}
+	if filter.Classification != "" && !validClassifications[filter.Classification] {
+		return ExceptionList{}, fmt.Errorf("%w: classification must be %s, %s or %s",
+			model.ErrInvalidInputError, Missing, Mismatched, Extra)
+	}
+	if filter.Status != "" && !validStatuses[filter.Status] {
+		return ExceptionList{}, fmt.Errorf("%w: invalid status", model.ErrInvalidInputError)
+	}
+	filter.Limit, filter.Offset = clampPage(filter.Limit, filter.Offset)
+
+	rows, err := s.Repo.SearchExceptions(ctx, filter)
+	if err != nil {
+		return ExceptionList{}, err
+	}
+	total, err := s.Repo.CountExceptions(ctx, filter)
+	if err != nil {
+		return ExceptionList{}, err
+	}
+
+	exceptions := make([]*Exception, len(rows))
+	for i, row := range rows {
+		exceptions[i] = toException(row)
+	}
+	return ExceptionList{
+		Exceptions: exceptions,
+		Total:      total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	}, nil
+}
+
+// ResolveException closes an open exception as resolved or ignored
+func (s *reconciliationService) ResolveException(ctx context.Context, id, userID string,
+	req ResolveRequest) (*Exception, error) {
+	if req.Status != ExceptionResolved && req.Status != ExceptionIgnored {
+		return nil, fmt.Errorf("%w: status must be %s or %s",
+			model.ErrInvalidInputError, ExceptionResolved, ExceptionIgnored)
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrReconciliationExceptionNotFound
+	}
+
+	ok, err := s.Repo.ResolveException(ctx, id, userID, req.Status, strings.TrimSpace(req.Note))
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.Repo.GetException(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrReconciliationExceptionNotFound
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, model.ErrExceptionAlreadyClosed
+	}
+	return toException(row), nil
+}
+
+func (s *reconciliationService) lookupProvider(ctx context.Context, code string) (*dbsqlc.Provider, error) {
+	p, err := s.provider.GetProviderByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: unknown provider %q", model.ErrInvalidInputError, code)
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *reconciliationService) startRun(ctx context.Context, p *dbsqlc.Provider, source, fileName string,
+	from, to time.Time, userID string) (dbsqlc.ReconciliationRun, error) {
+	arg := dbsqlc.CreateReconciliationRunParams{
+		ID:          utils.ToUUID(uuid.New()),
+		ProviderID:  p.ID,
+		Source:      source,
+		FileName:    optionalText(fileName),
+		PeriodStart: utils.ToPgTimestamptz(from),
+		PeriodEnd:   utils.ToPgTimestamptz(to),
+	}
+	if userID != "" {
+		createdBy, err := utils.StringToPgUUID(userID)
+		if err != nil {
+			return dbsqlc.ReconciliationRun{}, err
+		}
+		arg.CreatedBy = createdBy
+	}
+	return s.Repo.CreateRun(ctx, arg)
+}
+
+// failRun records why a run stopped and returns the cause
+func (s *reconciliationService) failRun(ctx context.Context, row dbsqlc.ReconciliationRun,
+	cause error) (*Run, error) {
+	runID := utils.FromPgUUID(row.ID)
+	if err := s.Repo.FailRun(context.WithoutCancel(ctx), runID, cause.Error()); err != nil {
+		s.logger.Error("Failed to mark reconciliation run failed",
+			zap.String("runID", runID), zap.Error(err))
+	}
+	return nil, cause
+}
+
+// reconcile classifies the report against our transactions and stores the
+// exceptions and counts. Open exceptions from earlier runs for references
+// this run covers are superseded, so the queue only holds the latest view.
+func (s *reconciliationService) reconcile(ctx context.Context, row dbsqlc.ReconciliationRun,
+	entries []gateways.ReportEntry) (*Run, error) {
+	runID := utils.FromPgUUID(row.ID)
+	providerID := utils.FromPgUUID(row.ProviderID)
+	from, to := utils.FromPgTimestamptz(row.PeriodStart), utils.FromPgTimestamptz(row.PeriodEnd)
+
+	ours, err := s.lookupTransactions(ctx, entries)
+	if err != nil {
+		return s.failRun(ctx, row, fmt.Errorf("look up transactions: %w", err))
+	}
+	deposits, err := s.Repo.ListCompletedDeposits(ctx, providerID, from, to)
+	if err != nil {
+		return s.failRun(ctx, row, fmt.Errorf("list deposits: %w", err))
+	}
+
+	counts := dbsqlc.CompleteReconciliationRunParams{ID: row.ID, TotalItems: int32(len(entries))}
+	var exceptions []dbsqlc.CreateReconciliationExceptionParams
+	seen := make(map[string]bool, len(entries))
+	refs := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if seen[entry.Reference] {
+			exceptions = append(exceptions, newException(row, Extra, entry, nil,
+				"reference appears more than once in the report"))
+			counts.Extra++
+			continue
+		}
+		seen[entry.Reference] = true
+		refs = append(refs, entry.Reference)
+
+		tx, ok := ours[entry.Reference]
+		if !ok {
+			exceptions = append(exceptions, newException(row, Extra, entry, nil,
+				"no transaction with this reference"))
+			counts.Extra++
+			continue
+		}
+		if problems := compare(entry, tx, row.ProviderID); len(problems) > 0 {
+			exceptions = append(exceptions, newException(row, Mismatched, entry, &tx,
+				strings.Join(problems, "; ")))
+			counts.Mismatched++
+			continue
+		}
+		counts.Matched++
+	}
+
+	for i := range deposits {
+		tx := deposits[i]
+		if seen[tx.Reference] {
+			continue
+		}
+		exceptions = append(exceptions, newException(row, Missing, gateways.ReportEntry{}, &tx,
+			"completed deposit is absent from the provider report"))
+		refs = append(refs, tx.Reference)
+		counts.Missing++
+	}
+
+	err = utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+		for _, arg := range exceptions {
+			if err := repo.CreateException(ctx, arg); err != nil {
+				return err
+			}
+		}
+		if len(refs) > 0 {
+			if err := repo.SupersedeExceptions(ctx, providerID, runID, refs); err != nil {
+				return err
+			}
+		}
+		return repo.CompleteRun(ctx, counts)
+	})
+	if err != nil {
+		return s.failRun(ctx, row, fmt.Errorf("store results: %w", err))
+	}
+
+	return s.GetRun(ctx, runID)
+}
+
+// lookupTransactions loads our transactions for the report's references
+func (s *reconciliationService) lookupTransactions(ctx context.Context,
+	entries []gateways.ReportEntry) (map[string]dbsqlc.Transaction, error) {
+	ours := make(map[string]dbsqlc.Transaction, len(entries))
+	for start := 0; start < len(entries); start += lookupBatchSize {
+		end := min(start+lookupBatchSize, len(entries))
+		refs := make([]string, 0, end-start)
+		for _, entry := range entries[start:end] {
+			refs = append(refs, entry.Reference)
+		}
+
+		rows, err := s.Repo.ListTransactionsByReferences(ctx, refs)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			ours[row.Reference] = row
+		}
+	}
+	return ours, nil
+}
+
+// compare lists the ways a report entry disagrees with our transaction
+func compare(entry gateways.ReportEntry, tx dbsqlc.Transaction, providerID pgtype.UUID) []string {
+	var problems []string
+	if tx.ProviderID != providerID {
+		problems = append(problems, "transaction belongs to another provider")
+	}
+	if !entry.Amount.Equal(tx.Amount) {
+		problems = append(problems, fmt.Sprintf("amount differs: provider %s, ours %s",
+			entry.Amount.String(), tx.Amount.String()))
+	}
+	if !strings.EqualFold(entry.Currency, tx.CurrencyCode) {
+		problems = append(problems, fmt.Sprintf("currency differs: provider %s, ours %s",
+			entry.Currency, tx.CurrencyCode))
+	}
+
+	settled := entry.Status == gateways.VerifyStatusSuccess
+	credited := tx.Status == completedStatus
+	if settled != credited {
+		problems = append(problems, fmt.Sprintf("status differs: provider %s, ours %s",
+			entry.Status, tx.Status))
+	}
+	return problems
+}
+
+func newException(row dbsqlc.ReconciliationRun, classification string, entry gateways.ReportEntry,
+	tx *dbsqlc.Transaction, detail string) dbsqlc.CreateReconciliationExceptionParams {
+	arg := dbsqlc.CreateReconciliationExceptionParams{
+		ID:             utils.ToUUID(uuid.New()),
+		RunID:          row.ID,
+		ProviderID:     row.ProviderID,
+		Reference:      entry.Reference,
+		Classification: classification,
+		Detail:         detail,
+	}
+	if entry.Reference != "" {
+		amount := entry.Amount
+		arg.ProviderAmount = &amount
+		arg.ProviderCurrency = optionalText(entry.Currency)
+		arg.ProviderStatus = optionalText(entry.Status)
+	}
+	if tx != nil {
+		amount := tx.Amount
+		arg.Reference = tx.Reference
+		arg.TransactionID = tx.ID
+		arg.Amount = &amount
+		arg.CurrencyCode = optionalText(tx.CurrencyCode)
+		arg.TransactionStatus = optionalText(tx.Status)
+	}
+	return arg
+}
+
+func checkPeriod(from, to time.Time) error {
+	if !to.After(from) || to.Sub(from) > maxPeriod {
+		return fmt.Errorf("%w: from must be before to and the period at most %d days",
+			model.ErrInvalidInputError, int(maxPeriod.Hours()/24))
+	}
+	return nil
+}
+
+func clampPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func toRun(row dbsqlc.ReconciliationRun) *Run {
+	run := &Run{
+		ID:          utils.FromPgUUID(row.ID),
+		ProviderID:  utils.FromPgUUID(row.ProviderID),
+		Source:      row.Source,
+		FileName:    row.FileName.String,
+		PeriodStart: utils.FromPgTimestamptz(row.PeriodStart),
+		PeriodEnd:   utils.FromPgTimestamptz(row.PeriodEnd),
+		Status:      row.Status,
+		TotalItems:  int(row.TotalItems),
+		Matched:     int(row.Matched),
+		Missing:     int(row.Missing),
+		Mismatched:  int(row.Mismatched),
+		Extra:       int(row.Extra),
+		Error:       row.Error.String,
+		CreatedBy:   utils.FromPgUUID(row.CreatedBy),
+		CreatedAt:   utils.FromPgTimestamptz(row.CreatedAt),
+	}
+	if row.CompletedAt.Valid {
+		t := row.CompletedAt.Time
+		run.CompletedAt = &t
+	}
+	return run
+}
+
+func toException(row dbsqlc.ReconciliationException) *Exception {
+	e := &Exception{
+		ID:                utils.FromPgUUID(row.ID),
+		RunID:             utils.FromPgUUID(row.RunID),
+		ProviderID:        utils.FromPgUUID(row.ProviderID),
+		Reference:         row.Reference,
+		Classification:    row.Classification,
+		TransactionID:     utils.FromPgUUID(row.TransactionID),
+		ProviderAmount:    row.ProviderAmount,
+		ProviderCurrency:  row.ProviderCurrency.String,
+		ProviderStatus:    row.ProviderStatus.String,
+		Amount:            row.Amount,
+		CurrencyCode:      row.CurrencyCode.String,
+		TransactionStatus: row.TransactionStatus.String,
+		Detail:            row.Detail,
+		Status:            row.Status,
+		ResolvedBy:        utils.FromPgUUID(row.ResolvedBy),
+		ResolutionNote:    row.ResolutionNote.String,
+		CreatedAt:         utils.FromPgTimestamptz(row.CreatedAt),
+	}
+	if row.ResolvedAt.Valid {
+		t := row.ResolvedAt.Time
+		e.ResolvedAt = &t
+	}
+	return e
+}
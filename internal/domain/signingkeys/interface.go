@@ -0,0 +1,22 @@
+package signingkeys
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"time"
+)
+
+type Service interface {
+	Load(ctx context.Context) error
+	Rotate(ctx context.Context) error
+}
+
+type Repository interface {
+	Lock(ctx context.Context) error
+	Create(ctx context.Context, kid, algorithm, privateKeyEncrypted string,
+		activatesAt time.Time) (db.JwtSigningKey, error)
+	ListLive(ctx context.Context) ([]db.JwtSigningKey, error)
+	ExpireOthers(ctx context.Context, kid string, expiresAt time.Time) error
+	DeleteExpired(ctx context.Context) error
+	WithTx(q *db.Queries) Repository
+}
@@ -0,0 +1,57 @@
+package signingkeys
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+// Lock serializes key rotation across instances until the transaction ends
+func (r *repository) Lock(ctx context.Context) error {
+	return r.q.LockJWTSigningKeys(ctx)
+}
+
+func (r *repository) Create(ctx context.Context, kid, algorithm, privateKeyEncrypted string,
+	activatesAt time.Time) (db.JwtSigningKey, error) {
+	return r.q.CreateJWTSigningKey(ctx, db.CreateJWTSigningKeyParams{
+		Kid:                 kid,
+		Algorithm:           algorithm,
+		PrivateKeyEncrypted: privateKeyEncrypted,
+		ActivatesAt:         utils.ToPgTimestamptz(activatesAt),
+	})
+}
+
+func (r *repository) ListLive(ctx context.Context) ([]db.JwtSigningKey, error) {
+	return r.q.ListLiveJWTSigningKeys(ctx)
+}
+
+// ExpireOthers schedules the expiry of every current key except kid
+func (r *repository) ExpireOthers(ctx context.Context, kid string, expiresAt time.Time) error {
+	return r.q.ExpireJWTSigningKeys(ctx, db.ExpireJWTSigningKeysParams{
+		Kid:       kid,
+		ExpiresAt: utils.ToPgTimestamptz(expiresAt),
+	})
+}
+
+func (r *repository) DeleteExpired(ctx context.Context) error {
+	return r.q.DeleteExpiredJWTSigningKeys(ctx)
+}
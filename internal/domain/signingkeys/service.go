@@ -0,0 +1,163 @@
+package signingkeys
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// RefreshInterval is how often every instance reloads the key set and
+	// checks whether a rotation is due
+	RefreshInterval = 5 * time.Minute
+
+	// A new key is published this long before it signs anything, so every
+	// instance can verify its tokens by the time any instance issues them
+	publishDelay = 2 * RefreshInterval
+
+	// Allowance for clock skew between instances when retiring a key
+	expiryLeeway = time.Minute
+)
+
+type signingKeyService struct {
+	DB         *db.DBConn
+	Repo       Repository
+	jwtManager *utils.JWTManager
+	cfg        *config.Config
+	logger     *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the signing key service.
+func NewService(db *db.DBConn, jwtManager *utils.JWTManager, cfg *config.Config,
+	logger *zap.Logger) Service {
+	return &signingKeyService{
+		DB:         db,
+		Repo:       NewRepository(db.Queries, db.Pool),
+		jwtManager: jwtManager,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// Load installs every unexpired key into the JWT manager, creating the first
+// key if there is none yet.
+func (s *signingKeyService) Load(ctx context.Context) error {
+	rows, err := s.Repo.ListLive(ctx)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return s.Rotate(ctx)
+	}
+
+	keys := make([]*utils.SigningKey, 0, len(rows))
+	for _, row := range rows {
+		key, err := s.decode(row)
+		if err != nil {
+			// Skip the key rather than lock every user out
+			s.logger.Error("Failed to load JWT signing key", zap.String("kid", row.Kid), zap.Error(err))
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	s.jwtManager.SetKeys(keys)
+	return nil
+}
+
+// Rotate creates a new signing key once the current one is older than the
+// rotation interval, then reloads the key set. The new key is published
+// before it activates, and the keys it replaces keep verifying until the
+// last access token they signed has expired. Instances coordinate through a
+// database lock so only one of them rotates.
+func (s *signingKeyService) Rotate(ctx context.Context) error {
+	err := utils.WithTX(ctx, s.DB.Pool, func(q *dbsqlc.Queries) error {
+		repo := s.Repo.WithTx(q)
+		if err := repo.Lock(ctx); err != nil {
+			return err
+		}
+
+		rows, err := repo.ListLive(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		activatesAt := now
+		if len(rows) > 0 {
+			newest := utils.FromPgTimestamptz(rows[0].ActivatesAt)
+			if now.Sub(newest) < s.cfg.JwtKeyRotationInterval {
+				return nil
+			}
+			activatesAt = now.Add(publishDelay)
+		}
+
+		created, err := s.create(ctx, repo, activatesAt)
+		if err != nil {
+			return err
+		}
+
+		expiresAt := activatesAt.Add(s.jwtManager.AccessTokenExpiry + expiryLeeway)
+		if err := repo.ExpireOthers(ctx, created.Kid, expiresAt); err != nil {
+			return err
+		}
+		if err := repo.DeleteExpired(ctx); err != nil {
+			return err
+		}
+
+		s.logger.Info("JWT signing key created", zap.String("kid", created.Kid),
+			zap.String("algorithm", created.Algorithm), zap.Time("activatesAt", activatesAt))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.Load(ctx)
+}
+
+func (s *signingKeyService) create(ctx context.Context, repo Repository,
+	activatesAt time.Time) (dbsqlc.JwtSigningKey, error) {
+	key, err := utils.GenerateSigningKey(s.cfg.JwtSigningAlgorithm)
+	if err != nil {
+		return dbsqlc.JwtSigningKey{}, err
+	}
+
+	pem, err := utils.MarshalPrivateKeyPEM(key.PrivateKey)
+	if err != nil {
+		return dbsqlc.JwtSigningKey{}, err
+	}
+
+	encrypted, err := utils.EncryptString(s.cfg.JwtKeyEncryptionKey, pem)
+	if err != nil {
+		return dbsqlc.JwtSigningKey{}, err
+	}
+
+	return repo.Create(ctx, key.ID, key.Algorithm, encrypted, activatesAt)
+}
+
+func (s *signingKeyService) decode(row dbsqlc.JwtSigningKey) (*utils.SigningKey, error) {
+	pem, err := utils.DecryptString(s.cfg.JwtKeyEncryptionKey, row.PrivateKeyEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := utils.ParsePrivateKeyPEM(pem)
+	if err != nil {
+		return nil, err
+	}
+
+	return &utils.SigningKey{
+		ID:          row.Kid,
+		Algorithm:   row.Algorithm,
+		PrivateKey:  signer,
+		PublicKey:   signer.Public(),
+		ActivatesAt: utils.FromPgTimestamptz(row.ActivatesAt),
+		ExpiresAt:   utils.FromPgTimestamptz(row.ExpiresAt),
+	}, nil
+}
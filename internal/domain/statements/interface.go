@@ -0,0 +1,30 @@
+package statements
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"context"
+	"io"
+	"time"
+)
+
+type Service interface {
+	Generate(ctx context.Context, walletID, userID string, from, to time.Time) (*Statement, error)
+	Render(statement *Statement, format string, w io.Writer) error
+	List(ctx context.Context, walletID, userID string) ([]*Summary, error)
+	Open(ctx context.Context, id, walletID, userID, format string) (*Download, error)
+	GenerateMonthly(ctx context.Context, month time.Time) error
+}
+
+type Repository interface {
+	GetWalletOwner(ctx context.Context, walletID string) (string, error)
+	GetBalances(ctx context.Context, walletID string, from, to time.Time) (db.GetStatementBalancesRow, error)
+	ListTransactions(ctx context.Context, walletID string, from, to time.Time) ([]db.Transaction, error)
+	ListWallets(ctx context.Context, periodEnd time.Time, afterID string, limit int) ([]db.ListStatementWalletsRow, error)
+	Create(ctx context.Context, arg db.CreateWalletStatementParams) (bool, error)
+	Get(ctx context.Context, id string) (db.WalletStatement, error)
+	GetByPeriod(ctx context.Context, walletID string, from, to time.Time) (db.WalletStatement, error)
+	List(ctx context.Context, walletID string, limit int) ([]db.WalletStatement, error)
+	ClaimEmail(ctx context.Context, id string) (bool, error)
+	ReleaseEmail(ctx context.Context, id string) error
+	WithTx(q *db.Queries) Repository
+}
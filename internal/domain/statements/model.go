@@ -0,0 +1,82 @@
+package statements
+
+import (
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatPDF  = "pdf"
+
+	// Longest period a single statement may cover
+	maxPeriod = 366 * 24 * time.Hour
+
+	// Wallets handled per page by the monthly run
+	walletBatchSize = 200
+
+	listLimit = 24
+)
+
+var contentTypes = map[string]string{
+	FormatCSV: "text/csv",
+	FormatPDF: "application/pdf",
+}
+
+// Statement covers a wallet's completed transactions from PeriodStart up to,
+// but excluding, PeriodEnd. Balances are in the wallet's currency.
+type Statement struct {
+	ID             string          `json:"id,omitempty"`
+	WalletID       string          `json:"wallet_id"`
+	CurrencyCode   string          `json:"currency_code"`
+	PeriodStart    time.Time       `json:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+	TotalCredits   decimal.Decimal `json:"total_credits"`
+	TotalDebits    decimal.Decimal `json:"total_debits"`
+	TotalFees      decimal.Decimal `json:"total_fees"`
+	ClosingBalance decimal.Decimal `json:"closing_balance"`
+	Entries        []Entry         `json:"entries"`
+	GeneratedAt    time.Time       `json:"generated_at"`
+}
+
+// Entry is one transaction on a statement. Exactly one of Credit and Debit is
+// non-zero; Fee is charged on top and Balance is the running balance after it.
+type Entry struct {
+	TransactionID string          `json:"transaction_id"`
+	Date          time.Time       `json:"date"`
+	Reference     string          `json:"reference"`
+	Type          string          `json:"type"`
+	Description   string          `json:"description"`
+	Credit        decimal.Decimal `json:"credit"`
+	Debit         decimal.Decimal `json:"debit"`
+	Fee           decimal.Decimal `json:"fee"`
+	Balance       decimal.Decimal `json:"balance"`
+}
+
+// Summary describes a pre-generated monthly statement
+type Summary struct {
+	ID             string          `json:"id"`
+	WalletID       string          `json:"wallet_id"`
+	CurrencyCode   string          `json:"currency_code"`
+	PeriodStart    time.Time       `json:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+	TotalCredits   decimal.Decimal `json:"total_credits"`
+	TotalDebits    decimal.Decimal `json:"total_debits"`
+	TotalFees      decimal.Decimal `json:"total_fees"`
+	ClosingBalance decimal.Decimal `json:"closing_balance"`
+	EntryCount     int             `json:"entry_count"`
+	EmailedAt      *time.Time      `json:"emailed_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Download is an open statement file. The caller must close Body.
+type Download struct {
+	FileName    string
+	ContentType string
+	Body        io.ReadCloser
+}
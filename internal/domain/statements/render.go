@@ -0,0 +1,129 @@
+package statements
+
+import (
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var csvHeader = []string{"date", "transaction_id", "reference", "type", "description", "credit", "debit", "fee", "balance"}
+
+// Render writes the statement as CSV or PDF
+func (s *statementService) Render(st *Statement, format string, w io.Writer) error {
+	switch format {
+	case FormatCSV:
+		return renderCSV(st, w)
+	case FormatPDF:
+		_, err := renderPDF(st).WriteTo(w)
+		return err
+	}
+	return fmt.Errorf("%w: format must be %s, %s or %s", model.ErrInvalidInputError, FormatJSON, FormatCSV, FormatPDF)
+}
+
+// renderCSV writes the entries framed by opening and closing balance rows, so
+// the file reads top to bottom like the PDF
+func renderCSV(st *Statement, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		csvHeader,
+		{st.PeriodStart.UTC().Format(time.RFC3339), "", "", "", "Opening balance", "", "", "", st.OpeningBalance.String()},
+	}
+	for _, e := range st.Entries {
+		rows = append(rows, []string{
+			e.Date.UTC().Format(time.RFC3339),
+			e.TransactionID,
+			utils.SpreadsheetText(e.Reference),
+			e.Type,
+			utils.SpreadsheetText(e.Description),
+			e.Credit.String(),
+			e.Debit.String(),
+			e.Fee.String(),
+			e.Balance.String(),
+		})
+	}
+	rows = append(rows, []string{
+		st.PeriodEnd.UTC().Format(time.RFC3339), "", "", "", "Closing balance",
+		st.TotalCredits.String(), st.TotalDebits.String(), st.TotalFees.String(), st.ClosingBalance.String(),
+	})
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// PDF column widths; with single space separators they fill utils.PDFLineWidth
+var pdfColumns = []int{10, 16, 20, 11, 11, 9, 12}
+
+func renderPDF(st *Statement) *utils.TextPDF {
+	pdf := utils.NewTextPDF()
+	last := st.PeriodEnd.Add(-time.Nanosecond)
+
+	pdf.Line("WALLET STATEMENT")
+	pdf.Line("")
+	pdf.Line("Wallet:    " + st.WalletID)
+	pdf.Line("Currency:  " + st.CurrencyCode)
+	pdf.Line(fmt.Sprintf("Period:    %s to %s (UTC)", st.PeriodStart.UTC().Format("02 Jan 2006"), last.UTC().Format("02 Jan 2006")))
+	pdf.Line("Generated: " + st.GeneratedAt.UTC().Format("02 Jan 2006 15:04 MST"))
+	pdf.Line("")
+	pdf.Line("Opening balance: " + money(st.OpeningBalance))
+	pdf.Line("Total credits:   " + money(st.TotalCredits))
+	pdf.Line("Total debits:    " + money(st.TotalDebits))
+	pdf.Line("Total fees:      " + money(st.TotalFees))
+	pdf.Line("Closing balance: " + money(st.ClosingBalance))
+	pdf.Line("")
+
+	header := pdfRow("Date", "Reference", "Description", "Credit", "Debit", "Fee", "Balance")
+	rule := strings.Repeat("-", len(header))
+	pdf.Line(header)
+	pdf.Line(rule)
+	pdf.Line(pdfRow(st.PeriodStart.UTC().Format("2006-01-02"), "", "Opening balance", "", "", "", money(st.OpeningBalance)))
+	for _, e := range st.Entries {
+		pdf.Line(pdfRow(e.Date.UTC().Format("2006-01-02"), e.Reference, e.Description,
+			moneyOrBlank(e.Credit), moneyOrBlank(e.Debit), moneyOrBlank(e.Fee), money(e.Balance)))
+	}
+	pdf.Line(rule)
+	pdf.Line(pdfRow(last.UTC().Format("2006-01-02"), "", "Closing balance",
+		money(st.TotalCredits), money(st.TotalDebits), money(st.TotalFees), money(st.ClosingBalance)))
+
+	if len(st.Entries) == 0 {
+		pdf.Line("")
+		pdf.Line("No transactions in this period.")
+	}
+	return pdf
+}
+
+// pdfRow lays out cells in pdfColumns: text is left aligned and cut to fit,
+// amounts (the last four columns) are right aligned
+func pdfRow(cells ...string) string {
+	parts := make([]string, len(cells))
+	for i, cell := range cells {
+		width := pdfColumns[i]
+		if len(cell) > width {
+			cell = cell[:width]
+		}
+		if i >= len(cells)-4 {
+			parts[i] = fmt.Sprintf("%*s", width, cell)
+		} else {
+			parts[i] = fmt.Sprintf("%-*s", width, cell)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func money(d decimal.Decimal) string {
+	return d.StringFixed(2)
+}
+
+func moneyOrBlank(d decimal.Decimal) string {
+	if d.IsZero() {
+		return ""
+	}
+	return money(d)
+}
@@ -0,0 +1,136 @@
+package statements
+
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	q *db.Queries
+	p *pgxpool.Pool
+}
+
+func NewRepository(q *db.Queries, pool *pgxpool.Pool) Repository {
+	return &repository{
+		q: q,
+		p: pool,
+	}
+}
+
+func (r *repository) WithTx(q *db.Queries) Repository {
+	return NewRepository(q, r.p)
+}
+
+func (r *repository) GetWalletOwner(ctx context.Context, walletID string) (string, error) {
+	id, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return "", err
+	}
+	wallet, err := r.q.GetWalletByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return utils.FromPgUUID(wallet.UserID), nil
+}
+
+func (r *repository) GetBalances(ctx context.Context, walletID string,
+	from, to time.Time) (db.GetStatementBalancesRow, error) {
+	id, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return db.GetStatementBalancesRow{}, err
+	}
+	return r.q.GetStatementBalances(ctx, db.GetStatementBalancesParams{
+		PeriodEnd:   utils.ToPgTimestamptz(to),
+		PeriodStart: utils.ToPgTimestamptz(from),
+		WalletID:    id,
+	})
+}
+
+func (r *repository) ListTransactions(ctx context.Context, walletID string,
+	from, to time.Time) ([]db.Transaction, error) {
+	id, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListStatementTransactions(ctx, db.ListStatementTransactionsParams{
+		WalletID:    id,
+		PeriodStart: utils.ToPgTimestamptz(from),
+		PeriodEnd:   utils.ToPgTimestamptz(to),
+	})
+}
+
+func (r *repository) ListWallets(ctx context.Context, periodEnd time.Time, afterID string,
+	limit int) ([]db.ListStatementWalletsRow, error) {
+	// The nil UUID sorts before every wallet
+	after := pgtype.UUID{Valid: true}
+	if afterID != "" {
+		var err error
+		if after, err = utils.StringToPgUUID(afterID); err != nil {
+			return nil, err
+		}
+	}
+	return r.q.ListStatementWallets(ctx, db.ListStatementWalletsParams{
+		PeriodEnd: utils.ToPgTimestamptz(periodEnd),
+		AfterID:   after,
+		RowLimit:  int32(limit),
+	})
+}
+
+func (r *repository) Create(ctx context.Context, arg db.CreateWalletStatementParams) (bool, error) {
+	n, err := r.q.CreateWalletStatement(ctx, arg)
+	return n > 0, err
+}
+
+func (r *repository) Get(ctx context.Context, id string) (db.WalletStatement, error) {
+	statementID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.WalletStatement{}, err
+	}
+	return r.q.GetWalletStatement(ctx, statementID)
+}
+
+func (r *repository) GetByPeriod(ctx context.Context, walletID string,
+	from, to time.Time) (db.WalletStatement, error) {
+	id, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return db.WalletStatement{}, err
+	}
+	return r.q.GetWalletStatementByPeriod(ctx, db.GetWalletStatementByPeriodParams{
+		WalletID:    id,
+		PeriodStart: utils.ToPgTimestamptz(from),
+		PeriodEnd:   utils.ToPgTimestamptz(to),
+	})
+}
+
+func (r *repository) List(ctx context.Context, walletID string, limit int) ([]db.WalletStatement, error) {
+	id, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListWalletStatements(ctx, db.ListWalletStatementsParams{
+		WalletID: id,
+		RowLimit: int32(limit),
+	})
+}
+
+func (r *repository) ClaimEmail(ctx context.Context, id string) (bool, error) {
+	statementID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.ClaimWalletStatementEmail(ctx, statementID)
+	return n > 0, err
+}
+
+func (r *repository) ReleaseEmail(ctx context.Context, id string) error {
+	statementID, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return err
+	}
+	return r.q.ReleaseWalletStatementEmail(ctx, statementID)
+}
@@ -0,0 +1,422 @@
+package statements
+
+import (
+	"bytes"
+	"codematic/internal/config"
+	"codematic/internal/infrastructure/db"
+	dbsqlc "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/infrastructure/notifications"
+	"codematic/internal/infrastructure/storage"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type statementService struct {
+	DB     *db.DBConn
+	Repo   Repository
+	store  storage.BlobStore
+	mailer notifications.Mailer
+	cfg    *config.Config
+	logger *zap.Logger
+}
+
+// NewService initializes and returns a new instance of the statement service.
+func NewService(db *db.DBConn, store storage.BlobStore, mailer notifications.Mailer, cfg *config.Config,
+	logger *zap.Logger) Service {
+	return &statementService{
+		DB:     db,
+		Repo:   NewRepository(db.Queries, db.Pool),
+		store:  store,
+		mailer: mailer,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Generate builds a statement of the user's wallet for [from, to) from its
+// completed transactions.
+func (s *statementService) Generate(ctx context.Context, walletID, userID string,
+	from, to time.Time) (*Statement, error) {
+	if !to.After(from) || to.Sub(from) > maxPeriod {
+		return nil, fmt.Errorf("%w: from must be before to and the period at most %d days",
+			model.ErrInvalidStatementPeriod, int(maxPeriod.Hours()/24))
+	}
+	if _, err := uuid.Parse(walletID); err != nil {
+		return nil, model.ErrWalletNotFound
+	}
+
+	st, owner, err := s.build(ctx, walletID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if owner != userID {
+		return nil, model.ErrWalletNotFound
+	}
+	return st, nil
+}
+
+// List returns the pre-generated monthly statements of the user's wallet,
+// newest first.
+func (s *statementService) List(ctx context.Context, walletID, userID string) ([]*Summary, error) {
+	if err := s.checkOwner(ctx, walletID, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Repo.List(ctx, walletID, listLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*Summary, len(rows))
+	for i, row := range rows {
+		summaries[i] = toSummary(row)
+	}
+	return summaries, nil
+}
+
+// Open opens the stored PDF or CSV file of a pre-generated statement.
+func (s *statementService) Open(ctx context.Context, id, walletID, userID, format string) (*Download, error) {
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: format must be %s or %s", model.ErrInvalidInputError, FormatPDF, FormatCSV)
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrStatementNotFound
+	}
+
+	row, err := s.Repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrStatementNotFound
+		}
+		return nil, err
+	}
+	if utils.FromPgUUID(row.WalletID) != walletID || utils.FromPgUUID(row.UserID) != userID {
+		return nil, model.ErrStatementNotFound
+	}
+
+	key := row.PdfKey
+	if format == FormatCSV {
+		key = row.CsvKey
+	}
+	body, err := s.store.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, model.ErrStatementNotFound
+		}
+		return nil, err
+	}
+
+	return &Download{
+		FileName:    fileName(utils.FromPgTimestamptz(row.PeriodStart), utils.FromPgTimestamptz(row.PeriodEnd), format),
+		ContentType: contentType,
+		Body:        body,
+	}, nil
+}
+
+// GenerateMonthly stores statements for the calendar month containing month
+// for every open wallet, skipping wallets that were dormant all month and ones
+// already done, so a rerun picks up where a failed one stopped.
+func (s *statementService) GenerateMonthly(ctx context.Context, month time.Time) error {
+	month = month.UTC()
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	var generated, failed int
+	afterID := ""
+	for {
+		wallets, err := s.Repo.ListWallets(ctx, to, afterID, walletBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, wallet := range wallets {
+			walletID := utils.FromPgUUID(wallet.ID)
+			ok, err := s.generateMonthly(ctx, wallet, from, to)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// One bad wallet must not hold up everyone else's statement
+				s.logger.Error("Failed to generate wallet statement",
+					zap.String("walletID", walletID), zap.Error(err))
+				failed++
+				continue
+			}
+			if ok {
+				generated++
+			}
+		}
+
+		if len(wallets) < walletBatchSize {
+			break
+		}
+		afterID = utils.FromPgUUID(wallets[len(wallets)-1].ID)
+	}
+
+	s.logger.Info("Generated monthly wallet statements",
+		zap.String("period", from.Format("2006-01")),
+		zap.Int("generated", generated),
+		zap.Int("failed", failed))
+	return nil
+}
+
+// generateMonthly stores and, if enabled, emails one wallet's statement. It
+// reports whether a new statement was stored.
+func (s *statementService) generateMonthly(ctx context.Context, wallet dbsqlc.ListStatementWalletsRow,
+	from, to time.Time) (bool, error) {
+	walletID := utils.FromPgUUID(wallet.ID)
+
+	row, err := s.Repo.GetByPeriod(ctx, walletID, from, to)
+	if err == nil {
+		// Stored by an earlier run; it may still owe the email
+		return false, s.email(ctx, row, wallet, nil)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, err
+	}
+
+	st, _, err := s.build(ctx, walletID, from, to)
+	if err != nil {
+		return false, err
+	}
+	if len(st.Entries) == 0 && st.OpeningBalance.IsZero() && st.ClosingBalance.IsZero() {
+		return false, nil
+	}
+
+	period := from.Format("2006-01")
+	var pdf, csv bytes.Buffer
+	if err := s.Render(st, FormatPDF, &pdf); err != nil {
+		return false, err
+	}
+	if err := s.Render(st, FormatCSV, &csv); err != nil {
+		return false, err
+	}
+	pdfKey := fmt.Sprintf("statements/%s/%s.pdf", walletID, period)
+	csvKey := fmt.Sprintf("statements/%s/%s.csv", walletID, period)
+	if _, err := s.store.Put(ctx, pdfKey, bytes.NewReader(pdf.Bytes())); err != nil {
+		return false, err
+	}
+	if _, err := s.store.Put(ctx, csvKey, bytes.NewReader(csv.Bytes())); err != nil {
+		return false, err
+	}
+
+	params := dbsqlc.CreateWalletStatementParams{
+		ID:             utils.ToUUID(uuid.New()),
+		WalletID:       wallet.ID,
+		UserID:         wallet.UserID,
+		CurrencyCode:   st.CurrencyCode,
+		PeriodStart:    utils.ToPgTimestamptz(from),
+		PeriodEnd:      utils.ToPgTimestamptz(to),
+		OpeningBalance: st.OpeningBalance,
+		TotalCredits:   st.TotalCredits,
+		TotalDebits:    st.TotalDebits,
+		TotalFees:      st.TotalFees,
+		ClosingBalance: st.ClosingBalance,
+		EntryCount:     int32(len(st.Entries)),
+		PdfKey:         pdfKey,
+		CsvKey:         csvKey,
+	}
+	created, err := s.Repo.Create(ctx, params)
+	if err != nil || !created {
+		// Another instance got there first; its row points at the same keys
+		return false, err
+	}
+
+	row, err = s.Repo.Get(ctx, utils.FromPgUUID(params.ID))
+	if err != nil {
+		return true, err
+	}
+	return true, s.email(ctx, row, wallet, pdf.Bytes())
+}
+
+// email sends a stored statement to its owner once, when statement emails are
+// enabled and the owner's address is verified. A nil pdf is read from the store.
+func (s *statementService) email(ctx context.Context, row dbsqlc.WalletStatement,
+	wallet dbsqlc.ListStatementWalletsRow, pdf []byte) error {
+	if !s.cfg.StatementEmailEnabled || !wallet.EmailVerifiedAt.Valid || row.EmailedAt.Valid {
+		return nil
+	}
+
+	id := utils.FromPgUUID(row.ID)
+	claimed, err := s.Repo.ClaimEmail(ctx, id)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := s.sendEmail(ctx, row, wallet.Email, pdf); err != nil {
+		// Let the next run retry
+		if releaseErr := s.Repo.ReleaseEmail(ctx, id); releaseErr != nil {
+			s.logger.Error("Failed to release statement email claim",
+				zap.String("statementID", id), zap.Error(releaseErr))
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *statementService) sendEmail(ctx context.Context, row dbsqlc.WalletStatement, to string,
+	pdf []byte) error {
+	if pdf == nil {
+		body, err := s.store.Open(ctx, row.PdfKey)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		if pdf, err = io.ReadAll(body); err != nil {
+			return err
+		}
+	}
+
+	from := utils.FromPgTimestamptz(row.PeriodStart).UTC()
+	month := from.Format("January 2006")
+	return s.mailer.Send(ctx, notifications.Message{
+		To:      []string{to},
+		Subject: fmt.Sprintf("Your %s wallet statement for %s", row.CurrencyCode, month),
+		Text: fmt.Sprintf("Hello,\n\nYour %s wallet statement for %s is attached.\n\n"+
+			"Opening balance: %s %s\nClosing balance: %s %s\n",
+			row.CurrencyCode, month,
+			row.OpeningBalance.StringFixed(2), row.CurrencyCode,
+			row.ClosingBalance.StringFixed(2), row.CurrencyCode),
+		Attachments: []notifications.Attachment{{
+			Name:        fileName(from, utils.FromPgTimestamptz(row.PeriodEnd), FormatPDF),
+			ContentType: contentTypes[FormatPDF],
+			Data:        pdf,
+		}},
+	})
+}
+
+// build derives the statement from the wallet's current balance: the opening
+// and closing balances are it less everything completed since the period's
+// start and end. It also returns the wallet's owner.
+func (s *statementService) build(ctx context.Context, walletID string, from, to time.Time) (*Statement, string, error) {
+	balances, err := s.Repo.GetBalances(ctx, walletID, from, to)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", model.ErrWalletNotFound
+		}
+		return nil, "", err
+	}
+
+	txs, err := s.Repo.ListTransactions(ctx, walletID, from, to)
+	if err != nil {
+		return nil, "", err
+	}
+
+	st := &Statement{
+		WalletID:       walletID,
+		CurrencyCode:   balances.CurrencyCode,
+		PeriodStart:    from,
+		PeriodEnd:      to,
+		OpeningBalance: balances.Balance.Sub(balances.NetSinceStart),
+		ClosingBalance: balances.Balance.Sub(balances.NetSinceEnd),
+		Entries:        make([]Entry, 0, len(txs)),
+		GeneratedAt:    time.Now().UTC(),
+	}
+
+	balance := st.OpeningBalance
+	for _, tx := range txs {
+		entry := Entry{
+			TransactionID: utils.FromPgUUID(tx.ID),
+			Date:          utils.FromPgTimestamptz(tx.CreatedAt),
+			Reference:     tx.Reference,
+			Type:          tx.Type,
+			Description:   describe(tx),
+			Fee:           tx.Fee,
+		}
+		if isCredit(tx) {
+			entry.Credit = tx.Amount
+			st.TotalCredits = st.TotalCredits.Add(tx.Amount)
+			balance = balance.Add(tx.Amount)
+		} else {
+			entry.Debit = tx.Amount
+			st.TotalDebits = st.TotalDebits.Add(tx.Amount)
+			balance = balance.Sub(tx.Amount)
+		}
+		st.TotalFees = st.TotalFees.Add(tx.Fee)
+		balance = balance.Sub(tx.Fee)
+		entry.Balance = balance
+		st.Entries = append(st.Entries, entry)
+	}
+
+	return st, utils.FromPgUUID(balances.UserID), nil
+}
+
+func (s *statementService) checkOwner(ctx context.Context, walletID, userID string) error {
+	if _, err := uuid.Parse(walletID); err != nil {
+		return model.ErrWalletNotFound
+	}
+	owner, err := s.Repo.GetWalletOwner(ctx, walletID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrWalletNotFound
+		}
+		return err
+	}
+	if owner != userID {
+		return model.ErrWalletNotFound
+	}
+	return nil
+}
+
+// isCredit matches the sign used by the statement balance queries
+func isCredit(tx dbsqlc.Transaction) bool {
+	if tx.Type == "deposit" {
+		return true
+	}
+	var metadata struct {
+		Direction string `json:"direction"`
+	}
+	_ = json.Unmarshal(tx.Metadata, &metadata)
+	return metadata.Direction == "credit"
+}
+
+// describe uses the transaction's own description when it has one
+func describe(tx dbsqlc.Transaction) string {
+	var metadata struct {
+		Description string `json:"description"`
+	}
+	_ = json.Unmarshal(tx.Metadata, &metadata)
+	if metadata.Description != "" {
+		return metadata.Description
+	}
+	return strings.ToUpper(tx.Type[:1]) + tx.Type[1:]
+}
+
+func fileName(from, to time.Time, format string) string {
+	return fmt.Sprintf("statement-%s-%s.%s", from.UTC().Format("20060102"),
+		to.UTC().Add(-time.Nanosecond).Format("20060102"), format)
+}
+
+func toSummary(row dbsqlc.WalletStatement) *Summary {
+	summary := &Summary{
+		ID:             utils.FromPgUUID(row.ID),
+		WalletID:       utils.FromPgUUID(row.WalletID),
+		CurrencyCode:   row.CurrencyCode,
+		PeriodStart:    utils.FromPgTimestamptz(row.PeriodStart),
+		PeriodEnd:      utils.FromPgTimestamptz(row.PeriodEnd),
+		OpeningBalance: row.OpeningBalance,
+		TotalCredits:   row.TotalCredits,
+		TotalDebits:    row.TotalDebits,
+		TotalFees:      row.TotalFees,
+		ClosingBalance: row.ClosingBalance,
+		EntryCount:     int(row.EntryCount),
+		CreatedAt:      utils.FromPgTimestamptz(row.CreatedAt),
+	}
+	if row.EmailedAt.Valid {
+		t := row.EmailedAt.Time
+		summary.EmailedAt = &t
+	}
+	return summary
+}
@@ -12,6 +12,8 @@ type Service interface {
 	GetTenantBySlug(ctx context.Context, slug string) (Tenant, error)
 	UpdateTenant(ctx context.Context, id, name, slug, webhookURL string) (Tenant, error)
 	DeleteTenant(ctx context.Context, id string) error
+	UpdateTenantSettings(ctx context.Context, id string, req TenantSettingsRequest) (Tenant, error)
+	UpdateWebhookURL(ctx context.Context, id, webhookURL string) (Tenant, error)
 	WithTx(q *db.Queries) Service
 }
 
@@ -22,5 +24,8 @@ type Repository interface {
 	GetTenantBySlug(ctx context.Context, slug string) (db.Tenant, error)
 	UpdateTenant(ctx context.Context, id, name, slug, webhookURL string) (db.Tenant, error)
 	DeleteTenant(ctx context.Context, id string) error
+	UpdateTenantSettings(ctx context.Context, id string, requireEmailVerification bool,
+		depositAmountPolicy string, allowP2PTransfers bool) (db.Tenant, error)
+	UpdateWebhookURL(ctx context.Context, id, webhookURL string) (db.Tenant, error)
 	WithTx(q *db.Queries) Repository
 }
@@ -2,6 +2,20 @@ package tenants
 
 import db "codematic/internal/infrastructure/db/sqlc"
 
+// Deposit amount policies decide what happens when a customer pays a
+// different amount than the deposit was initiated for. Anything the policy
+// does not allow, and any currency mismatch, is flagged for review.
+const (
+	// DepositPolicyExact credits only payments of exactly the initiated amount
+	DepositPolicyExact = "exact"
+	// DepositPolicyAllowOverpayment also credits payments above the initiated
+	// amount, crediting what was paid
+	DepositPolicyAllowOverpayment = "allow_overpayment"
+	// DepositPolicyAllowPartial also credits payments below the initiated
+	// amount, crediting what was paid
+	DepositPolicyAllowPartial = "allow_partial"
+)
+
 type (
 	CreateTenantRequest struct {
 		Name       string `json:"id" validate:"required"`
@@ -14,20 +28,40 @@ type (
 		WebhookURL string `json:"webhook_url"`
 	}
 
+	TenantSettingsRequest struct {
+		RequireEmailVerification bool   `json:"require_email_verification"`
+		DepositAmountPolicy      string `json:"deposit_amount_policy" validate:"omitempty,oneof=exact allow_overpayment allow_partial"`
+		// AllowP2PTransfers lets users send money to each other by email,
+		// phone or username; it is kept when not set
+		AllowP2PTransfers *bool `json:"allow_p2p_transfers"`
+	}
+
+	// WebhookConfig is where a tenant's outgoing webhooks are sent; an empty
+	// URL turns them off
+	WebhookConfig struct {
+		WebhookURL string `json:"webhook_url" validate:"omitempty,url,startswith=https://"`
+	}
+
 	Tenant struct {
-		ID         string `json:"id"`
-		Name       string `json:"name"`
-		Slug       string `json:"slug" `
-		WebhookURL string `json:"webhook_url"`
+		ID                       string `json:"id"`
+		Name                     string `json:"name"`
+		Slug                     string `json:"slug" `
+		WebhookURL               string `json:"webhook_url"`
+		RequireEmailVerification bool   `json:"require_email_verification"`
+		DepositAmountPolicy      string `json:"deposit_amount_policy"`
+		AllowP2PTransfers        bool   `json:"allow_p2p_transfers"`
 	}
 )
 
 func toDomainTenant(dbTenant db.Tenant) Tenant {
 	return Tenant{
-		ID:         dbTenant.ID.String(),
-		Name:       dbTenant.Name,
-		Slug:       dbTenant.Slug,
-		WebhookURL: dbTenant.WebhookUrl,
+		ID:                       dbTenant.ID.String(),
+		Name:                     dbTenant.Name,
+		Slug:                     dbTenant.Slug,
+		WebhookURL:               dbTenant.WebhookUrl,
+		RequireEmailVerification: dbTenant.RequireEmailVerification,
+		DepositAmountPolicy:      dbTenant.DepositAmountPolicy,
+		AllowP2PTransfers:        dbTenant.AllowP2pTransfers,
 	}
 }
 
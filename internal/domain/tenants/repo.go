@@ -81,3 +81,28 @@ func (r *repository) DeleteTenant(ctx context.Context, id string) error {
 	}
 	return r.q.DeleteTenant(ctx, uuid)
 }
+
+func (r *repository) UpdateTenantSettings(ctx context.Context, id string,
+	requireEmailVerification bool, depositAmountPolicy string, allowP2PTransfers bool) (db.Tenant, error) {
+	uuid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.Tenant{}, err
+	}
+	return r.q.UpdateTenantSettings(ctx, db.UpdateTenantSettingsParams{
+		ID:                       uuid,
+		RequireEmailVerification: requireEmailVerification,
+		DepositAmountPolicy:      depositAmountPolicy,
+		AllowP2pTransfers:        allowP2PTransfers,
+	})
+}
+
+func (r *repository) UpdateWebhookURL(ctx context.Context, id, webhookURL string) (db.Tenant, error) {
+	uuid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return db.Tenant{}, err
+	}
+	return r.q.UpdateTenantWebhookURL(ctx, db.UpdateTenantWebhookURLParams{
+		ID:         uuid,
+		WebhookUrl: webhookURL,
+	})
+}
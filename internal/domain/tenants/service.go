@@ -82,6 +82,42 @@ func (s *tenantService) UpdateTenant(ctx context.Context,
 	return toDomainTenant(dbTenant), nil
 }
 
+// UpdateWebhookURL sets where the tenant's outgoing webhooks are sent; an
+// empty URL stops them
+func (s *tenantService) UpdateWebhookURL(ctx context.Context, id, webhookURL string) (Tenant, error) {
+	dbTenant, err := s.Repo.UpdateWebhookURL(ctx, id, webhookURL)
+	if err != nil {
+		return Tenant{}, err
+	}
+	return toDomainTenant(dbTenant), nil
+}
+
 func (s *tenantService) DeleteTenant(ctx context.Context, id string) error {
 	return s.Repo.DeleteTenant(ctx, id)
 }
+
+func (s *tenantService) UpdateTenantSettings(ctx context.Context, id string,
+	req TenantSettingsRequest) (Tenant, error) {
+	// The deposit policy and P2P setting are kept unless the request sets them
+	policy := req.DepositAmountPolicy
+	var allowP2P bool
+	if policy == "" || req.AllowP2PTransfers == nil {
+		current, err := s.Repo.GetTenantByID(ctx, id)
+		if err != nil {
+			return Tenant{}, err
+		}
+		if policy == "" {
+			policy = current.DepositAmountPolicy
+		}
+		allowP2P = current.AllowP2pTransfers
+	}
+	if req.AllowP2PTransfers != nil {
+		allowP2P = *req.AllowP2PTransfers
+	}
+
+	dbTenant, err := s.Repo.UpdateTenantSettings(ctx, id, req.RequireEmailVerification, policy, allowP2P)
+	if err != nil {
+		return Tenant{}, err
+	}
+	return toDomainTenant(dbTenant), nil
+}
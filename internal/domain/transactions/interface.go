@@ -10,6 +10,8 @@ type Repository interface {
 	ListTransactionsByTenantID(ctx context.Context, tenantID string, limit, offset int) ([]*Transaction, error)
 	ListAllTransactions(ctx context.Context, limit, offset int) ([]*Transaction, error)
 	ListTransactionsByStatus(ctx context.Context, status string, limit, offset int) ([]*Transaction, error)
+	Search(ctx context.Context, filter SearchFilter, after *cursor, limit int) ([]*Transaction, error)
+	Count(ctx context.Context, filter SearchFilter) (int64, error)
 }
 
 type Service interface {
@@ -18,4 +20,6 @@ type Service interface {
 	ListTransactionsByTenantID(ctx context.Context, tenantID string, limit, offset int) ([]*Transaction, error)
 	ListAllTransactions(ctx context.Context, limit, offset int) ([]*Transaction, error)
 	ListTransactionsByStatus(ctx context.Context, status string, limit, offset int) ([]*Transaction, error)
+	Search(ctx context.Context, filter SearchFilter) (SearchResult, error)
+	Each(ctx context.Context, filter SearchFilter, fn func(*Transaction) error) error
 }
@@ -22,3 +22,72 @@ type Transaction struct {
 	CreatedAt    time.Time              `json:"created_at"`
 	UpdatedAt    time.Time              `json:"updated_at"`
 }
+
+const (
+	SortCreatedAt = "created_at"
+	SortAmount    = "amount"
+
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// Page size used by Each when walking every match
+	eachPageSize = 500
+)
+
+var (
+	validTypes    = map[string]bool{"deposit": true, "withdrawal": true, "transfer": true, "reversal": true}
+	validStatuses = map[string]bool{"pending": true, "completed": true, "failed": true, "expired": true, "flagged": true}
+)
+
+// SearchFilter narrows a transaction search. TenantID and UserID scope the
+// search to what the caller may see and must come from their credentials,
+// not the request. Empty fields do not filter.
+type SearchFilter struct {
+	TenantID   string
+	UserID     string
+	WalletID   string
+	ProviderID string
+	Reference  string
+
+	Types      []string
+	Statuses   []string
+	Currencies []string
+
+	MinAmount *decimal.Decimal
+	MaxAmount *decimal.Decimal
+	From      *time.Time
+	To        *time.Time
+
+	// Metadata matches transactions whose metadata contains every key with
+	// the given string value; MetadataKeys only requires the keys to exist
+	Metadata     map[string]string
+	MetadataKeys []string
+
+	// SortBy is SortCreatedAt (default) or SortAmount, newest or largest first
+	// unless Ascending
+	SortBy    string
+	Ascending bool
+
+	// Cursor is the NextCursor of the previous page, searched with the same
+	// filter and sort
+	Cursor string
+	Limit  int
+}
+
+type SearchResult struct {
+	Transactions []*Transaction `json:"transactions"`
+	Total        int64          `json:"total"`
+	Limit        int            `json:"limit"`
+
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// cursor is the position after the last row of a page. It is handed out
+// base64 encoded so clients treat it as opaque.
+type cursor struct {
+	SortBy    string `json:"s"`
+	Ascending bool   `json:"a,omitempty"`
+	Value     string `json:"v"`
+	ID        string `json:"id"`
+}
@@ -2,11 +2,16 @@ package transactions
 
 import (
 	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
 	"codematic/internal/shared/utils"
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 )
 
 type repository struct {
@@ -109,6 +114,140 @@ func (r *repository) ListTransactionsByStatus(ctx context.Context, status string
 	return dbToDomainTransactions(txs), nil
 }
 
+// Search returns up to limit transactions matching filter, in the filter's
+// sort order, starting after the given cursor position when it is non-nil.
+func (r *repository) Search(ctx context.Context, filter SearchFilter, after *cursor,
+	limit int) ([]*Transaction, error) {
+
+	f, err := countParams(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursorID pgtype.UUID
+	if after != nil {
+		if cursorID, err = utils.StringToPgUUID(after.ID); err != nil {
+			return nil, model.ErrInvalidCursor
+		}
+	}
+
+	var txs []db.Transaction
+	switch filter.SortBy {
+	case SortAmount:
+		params := db.SearchTransactionsByAmountParams{
+			TenantID: f.TenantID, UserID: f.UserID, WalletID: f.WalletID, ProviderID: f.ProviderID,
+			Reference: f.Reference, Types: f.Types, Statuses: f.Statuses, Currencies: f.Currencies,
+			MinAmount: f.MinAmount, MaxAmount: f.MaxAmount, CreatedFrom: f.CreatedFrom, CreatedTo: f.CreatedTo,
+			Metadata: f.Metadata, MetadataKeys: f.MetadataKeys,
+			CursorID: cursorID,
+			SortAsc:  filter.Ascending,
+			RowLimit: int32(limit),
+		}
+		if after != nil {
+			amount, err := decimal.NewFromString(after.Value)
+			if err != nil {
+				return nil, model.ErrInvalidCursor
+			}
+			params.CursorAmount = &amount
+		}
+		txs, err = r.q.SearchTransactionsByAmount(ctx, params)
+	default:
+		params := db.SearchTransactionsByCreatedAtParams{
+			TenantID: f.TenantID, UserID: f.UserID, WalletID: f.WalletID, ProviderID: f.ProviderID,
+			Reference: f.Reference, Types: f.Types, Statuses: f.Statuses, Currencies: f.Currencies,
+			MinAmount: f.MinAmount, MaxAmount: f.MaxAmount, CreatedFrom: f.CreatedFrom, CreatedTo: f.CreatedTo,
+			Metadata: f.Metadata, MetadataKeys: f.MetadataKeys,
+			CursorID: cursorID,
+			SortAsc:  filter.Ascending,
+			RowLimit: int32(limit),
+		}
+		if after != nil {
+			createdAt, err := time.Parse(time.RFC3339Nano, after.Value)
+			if err != nil {
+				return nil, model.ErrInvalidCursor
+			}
+			params.CursorCreatedAt = utils.ToPgTimestamptz(createdAt)
+		}
+		txs, err = r.q.SearchTransactionsByCreatedAt(ctx, params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+
+	return dbToDomainTransactions(txs), nil
+}
+
+func (r *repository) Count(ctx context.Context, filter SearchFilter) (int64, error) {
+	params, err := countParams(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := r.q.CountTransactions(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+	return total, nil
+}
+
+// countParams converts the filter shared by the search and count queries. The
+// list filters are never nil: a NULL array would match nothing.
+func countParams(filter SearchFilter) (db.CountTransactionsParams, error) {
+	params := db.CountTransactionsParams{
+		Types:        nonNil(filter.Types),
+		Statuses:     nonNil(filter.Statuses),
+		Currencies:   nonNil(filter.Currencies),
+		MinAmount:    filter.MinAmount,
+		MaxAmount:    filter.MaxAmount,
+		MetadataKeys: nonNil(filter.MetadataKeys),
+	}
+
+	for _, id := range []struct {
+		value string
+		dst   *pgtype.UUID
+	}{
+		{filter.TenantID, &params.TenantID},
+		{filter.UserID, &params.UserID},
+		{filter.WalletID, &params.WalletID},
+		{filter.ProviderID, &params.ProviderID},
+	} {
+		if id.value == "" {
+			continue
+		}
+		u, err := utils.StringToPgUUID(id.value)
+		if err != nil {
+			return db.CountTransactionsParams{}, fmt.Errorf("failed to convert id to uuid: %w", err)
+		}
+		*id.dst = u
+	}
+
+	if filter.Reference != "" {
+		params.Reference = utils.ToPgxText(filter.Reference)
+	}
+	if filter.From != nil {
+		params.CreatedFrom = utils.ToPgTimestamptz(*filter.From)
+	}
+	if filter.To != nil {
+		params.CreatedTo = utils.ToPgTimestamptz(*filter.To)
+	}
+	if len(filter.Metadata) > 0 {
+		metadata, err := json.Marshal(filter.Metadata)
+		if err != nil {
+			return db.CountTransactionsParams{}, err
+		}
+		params.Metadata = metadata
+	}
+
+	return params, nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
 func dbToDomainTransaction(tx *db.Transaction) *Transaction {
 	return &Transaction{
 		ID:           utils.FromPgUUID(tx.ID),
@@ -3,9 +3,15 @@ package transactions
 import (
 	"codematic/internal/infrastructure/cache"
 	"codematic/internal/infrastructure/db"
+	"codematic/internal/shared/model"
 
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // service implements the business logic for transaction-related operations.
@@ -99,3 +105,149 @@ func (s *service) ListTransactionsByStatus(ctx context.Context, status string,
 	limit, offset int) ([]*Transaction, error) {
 	return s.Repo.ListTransactionsByStatus(ctx, status, limit, offset)
 }
+
+// Search returns one page of the transactions matching filter along with the
+// total number of matches. Pages are keyset paginated: pass NextCursor back
+// with the same filter to get the next one.
+func (s *service) Search(ctx context.Context, filter SearchFilter) (SearchResult, error) {
+	if err := ValidateSearchFilter(&filter); err != nil {
+		return SearchResult{}, err
+	}
+
+	var after *cursor
+	if filter.Cursor != "" {
+		c, err := decodeCursor(filter.Cursor)
+		if err != nil || c.SortBy != filter.SortBy || c.Ascending != filter.Ascending {
+			return SearchResult{}, model.ErrInvalidCursor
+		}
+		after = &c
+	}
+
+	// One extra row tells whether there is a next page
+	txns, err := s.Repo.Search(ctx, filter, after, filter.Limit+1)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	result := SearchResult{Transactions: txns, Limit: filter.Limit}
+	if len(txns) > filter.Limit {
+		result.Transactions = txns[:filter.Limit]
+		result.NextCursor = encodeCursor(filter, result.Transactions[filter.Limit-1])
+	}
+
+	result.Total, err = s.Repo.Count(ctx, filter)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	return result, nil
+}
+
+// Each calls fn for every transaction matching filter, in the filter's sort
+// order, stopping at the first error. Rows are read a page at a time so
+// memory stays flat however many match; Cursor and Limit are ignored.
+func (s *service) Each(ctx context.Context, filter SearchFilter, fn func(*Transaction) error) error {
+	filter.Cursor, filter.Limit = "", 0
+	if err := ValidateSearchFilter(&filter); err != nil {
+		return err
+	}
+
+	var after *cursor
+	for {
+		txns, err := s.Repo.Search(ctx, filter, after, eachPageSize)
+		if err != nil {
+			return err
+		}
+		for _, tx := range txns {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		if len(txns) < eachPageSize {
+			return nil
+		}
+
+		last := txns[len(txns)-1]
+		after = &cursor{SortBy: filter.SortBy, Ascending: filter.Ascending, ID: last.ID,
+			Value: cursorValue(filter.SortBy, last)}
+	}
+}
+
+// ValidateSearchFilter rejects unknown values and malformed IDs, which would
+// otherwise silently match nothing, and applies the sort and limit defaults.
+// Exported so filters stored for later, such as exports, can be checked up front.
+func ValidateSearchFilter(filter *SearchFilter) error {
+	for _, t := range filter.Types {
+		if !validTypes[t] {
+			return fmt.Errorf("%w: unknown type %q", model.ErrInvalidInputError, t)
+		}
+	}
+	for _, st := range filter.Statuses {
+		if !validStatuses[st] {
+			return fmt.Errorf("%w: unknown status %q", model.ErrInvalidInputError, st)
+		}
+	}
+	for name, id := range map[string]string{
+		"tenant_id":   filter.TenantID,
+		"user_id":     filter.UserID,
+		"wallet_id":   filter.WalletID,
+		"provider_id": filter.ProviderID,
+	} {
+		if id == "" {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("%w: %s must be a UUID", model.ErrInvalidInputError, name)
+		}
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MinAmount.GreaterThan(*filter.MaxAmount) {
+		return fmt.Errorf("%w: min_amount is greater than max_amount", model.ErrInvalidInputError)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return fmt.Errorf("%w: from must be before to", model.ErrInvalidInputError)
+	}
+
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = SortCreatedAt
+	case SortCreatedAt, SortAmount:
+	default:
+		return fmt.Errorf("%w: sort must be %s or %s", model.ErrInvalidInputError, SortCreatedAt, SortAmount)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchLimit
+	}
+	if filter.Limit > maxSearchLimit {
+		filter.Limit = maxSearchLimit
+	}
+	return nil
+}
+
+func encodeCursor(filter SearchFilter, last *Transaction) string {
+	c := cursor{SortBy: filter.SortBy, Ascending: filter.Ascending, ID: last.ID,
+		Value: cursorValue(filter.SortBy, last)}
+
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func cursorValue(sortBy string, tx *Transaction) string {
+	if sortBy == SortAmount {
+		return tx.Amount.String()
+	}
+	return tx.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, err
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return cursor{}, err
+	}
+	return c, nil
+}
@@ -10,6 +10,17 @@ type Service interface {
 	GetUserByEmail(ctx context.Context, email string) (db.User, error)
 	GetUserByEmailAndTenantID(ctx context.Context, email string, tenantID string) (db.User, error)
 	GetUserByID(ctx context.Context, userID string) (db.User, error)
+	UpdatePassword(ctx context.Context, userID, password string) error
+	MarkEmailVerified(ctx context.Context, userID string) error
+	ListTenantUsers(ctx context.Context, tenantID string, filter ListUsersFilter) (UserList, error)
+	GetTenantUser(ctx context.Context, tenantID, userID string) (User, error)
+	SetTenantUserActive(ctx context.Context, tenantID, userID string, active bool) (User, error)
+	UpdateTenantUserRole(ctx context.Context, tenantID, userID, role string) (User, error)
+	GetTenantUserByUsername(ctx context.Context, tenantID, username string) (db.User, error)
+	// ListTenantUsersByPhone returns up to two users with the phone number,
+	// enough to tell whether it identifies one user
+	ListTenantUsersByPhone(ctx context.Context, tenantID, phone string) ([]db.User, error)
+	SetUsername(ctx context.Context, userID, username string) (User, error)
 	WithTx(q *db.Queries) Service
 }
 
@@ -18,5 +29,15 @@ type Repository interface {
 	CreateUser(ctx context.Context, params db.CreateUserParams) (db.User, error)
 	GetUserByEmailAndTenantID(ctx context.Context, email string, tenantID string) (db.User, error)
 	GetUserByID(ctx context.Context, userID string) (db.User, error)
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, userID string) error
+	SearchTenantUsers(ctx context.Context, tenantID string, filter ListUsersFilter) ([]db.User, error)
+	CountTenantUsers(ctx context.Context, tenantID string, filter ListUsersFilter) (int64, error)
+	GetTenantUserByID(ctx context.Context, tenantID, userID string) (db.User, error)
+	SetTenantUserActive(ctx context.Context, tenantID, userID string, active bool) (db.User, error)
+	UpdateTenantUserRole(ctx context.Context, tenantID, userID, role string) (db.User, error)
+	GetTenantUserByUsername(ctx context.Context, tenantID, username string) (db.User, error)
+	ListTenantUsersByPhone(ctx context.Context, tenantID, phone string) ([]db.User, error)
+	SetUsername(ctx context.Context, userID, username string) (db.User, error)
 	WithTx(q *db.Queries) Repository
 }
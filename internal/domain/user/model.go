@@ -1,12 +1,91 @@
 package user
 
-import "codematic/internal/shared/model"
+import (
+	db "codematic/internal/infrastructure/db/sqlc"
+	"codematic/internal/shared/model"
+	"time"
+)
 
 type CreateUserRequest struct {
-	TenantID string
-	Email    string
-	Phone    string
-	Password string
-	IsActive bool
-	Role     model.UserRole // PLATFORM_ADMIN, TENANT_ADMIN, USER
+	TenantID  string
+	Email     string
+	Phone     string
+	Password  string
+	FirstName string
+	LastName  string
+	IsActive  bool
+	Role      model.UserRole // PLATFORM_ADMIN, TENANT_ADMIN, USER
+}
+
+type (
+	// ListUsersFilter narrows a tenant's user list. Nil fields are not filtered on.
+	ListUsersFilter struct {
+		Search   string
+		Role     string
+		IsActive *bool
+		Limit    int
+		Offset   int
+	}
+
+	// User is the public view of a user for tenant admin APIs
+	User struct {
+		ID              string     `json:"id"`
+		TenantID        string     `json:"tenant_id"`
+		Email           string     `json:"email"`
+		Phone           string     `json:"phone"`
+		FirstName       string     `json:"first_name"`
+		LastName        string     `json:"last_name"`
+		Username        string     `json:"username,omitempty"`
+		Role            string     `json:"role"`
+		IsActive        bool       `json:"is_active"`
+		EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+		CreatedAt       time.Time  `json:"created_at"`
+		UpdatedAt       time.Time  `json:"updated_at"`
+	}
+
+	UserList struct {
+		Users  []User `json:"users"`
+		Total  int64  `json:"total"`
+		Limit  int    `json:"limit"`
+		Offset int    `json:"offset"`
+	}
+
+	CreateTenantUserRequest struct {
+		FirstName string `json:"first_name" validate:"required"`
+		LastName  string `json:"last_name" validate:"required"`
+		Email     string `json:"email" validate:"required,email"`
+		Phone     string `json:"phone" validate:"required"`
+		Password  string `json:"password" validate:"required,min=8"`
+		Role      string `json:"role" validate:"omitempty,oneof=USER TENANT_ADMIN"`
+	}
+
+	// SetUsernameRequest picks the username others can send money to
+	SetUsernameRequest struct {
+		Username string `json:"username" validate:"required"`
+	}
+
+	UpdateRoleRequest struct {
+		Role string `json:"role" validate:"required,oneof=USER TENANT_ADMIN"`
+	}
+)
+
+func ToUser(u db.User) User {
+	result := User{
+		ID:        u.ID.String(),
+		TenantID:  u.TenantID.String(),
+		Email:     u.Email,
+		Phone:     u.Phone.String,
+		FirstName: u.FirstName.String,
+		LastName:  u.LastName.String,
+		Username:  u.Username.String,
+		Role:      u.Role.String,
+		IsActive:  u.IsActive.Bool,
+		CreatedAt: u.CreatedAt.Time,
+		UpdatedAt: u.UpdatedAt.Time,
+	}
+	if u.EmailVerifiedAt.Valid {
+		verifiedAt := u.EmailVerifiedAt.Time
+		result.EmailVerifiedAt = &verifiedAt
+	}
+	return result
 }
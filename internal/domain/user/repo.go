@@ -5,6 +5,7 @@ import (
 	"codematic/internal/shared/utils"
 	"context"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -31,15 +32,19 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (db.U
 		return db.User{}, err
 	}
 	user := db.User{
-		ID:           data.ID,
-		TenantID:     data.TenantID,
-		Email:        data.Email,
-		Phone:        data.Phone,
-		PasswordHash: data.PasswordHash,
-		Role:         data.Role,
-		IsActive:     data.IsActive,
-		CreatedAt:    data.CreatedAt,
-		UpdatedAt:    data.UpdatedAt,
+		ID:              data.ID,
+		TenantID:        data.TenantID,
+		Email:           data.Email,
+		Phone:           data.Phone,
+		PasswordHash:    data.PasswordHash,
+		Role:            data.Role,
+		IsActive:        data.IsActive,
+		CreatedAt:       data.CreatedAt,
+		UpdatedAt:       data.UpdatedAt,
+		EmailVerifiedAt: data.EmailVerifiedAt,
+		FirstName:       data.FirstName,
+		LastName:        data.LastName,
+		Username:        data.Username,
 	}
 
 	return user, nil
@@ -63,15 +68,19 @@ func (r *userRepository) GetUserByEmailAndTenantID(ctx context.Context,
 		return db.User{}, err
 	}
 	user := db.User{
-		ID:           data.ID,
-		TenantID:     data.TenantID,
-		Email:        data.Email,
-		Phone:        data.Phone,
-		PasswordHash: data.PasswordHash,
-		Role:         data.Role,
-		IsActive:     data.IsActive,
-		CreatedAt:    data.CreatedAt,
-		UpdatedAt:    data.UpdatedAt,
+		ID:              data.ID,
+		TenantID:        data.TenantID,
+		Email:           data.Email,
+		Phone:           data.Phone,
+		PasswordHash:    data.PasswordHash,
+		Role:            data.Role,
+		IsActive:        data.IsActive,
+		CreatedAt:       data.CreatedAt,
+		UpdatedAt:       data.UpdatedAt,
+		EmailVerifiedAt: data.EmailVerifiedAt,
+		FirstName:       data.FirstName,
+		LastName:        data.LastName,
+		Username:        data.Username,
 	}
 	return user, nil
 
@@ -89,15 +98,171 @@ func (r *userRepository) GetUserByID(ctx context.Context, userID string) (db.Use
 	}
 
 	user := db.User{
-		ID:           data.ID,
-		TenantID:     data.TenantID,
-		Email:        data.Email,
-		Phone:        data.Phone,
-		PasswordHash: data.PasswordHash,
-		Role:         data.Role,
-		IsActive:     data.IsActive,
-		CreatedAt:    data.CreatedAt,
-		UpdatedAt:    data.UpdatedAt,
+		ID:              data.ID,
+		TenantID:        data.TenantID,
+		Email:           data.Email,
+		Phone:           data.Phone,
+		PasswordHash:    data.PasswordHash,
+		Role:            data.Role,
+		IsActive:        data.IsActive,
+		CreatedAt:       data.CreatedAt,
+		UpdatedAt:       data.UpdatedAt,
+		EmailVerifiedAt: data.EmailVerifiedAt,
+		FirstName:       data.FirstName,
+		LastName:        data.LastName,
+		Username:        data.Username,
 	}
 	return user, nil
 }
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	uuidUser, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	return r.q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+		ID:           uuidUser,
+		PasswordHash: passwordHash,
+	})
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, userID string) error {
+	uuidUser, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return err
+	}
+	return r.q.MarkUserEmailVerified(ctx, uuidUser)
+}
+
+// tenantFilterParams converts a filter into the optional query arguments shared
+// by SearchTenantUsers and CountTenantUsers.
+func tenantFilterParams(filter ListUsersFilter) (search, role pgtype.Text, isActive pgtype.Bool) {
+	if filter.Search != "" {
+		search = utils.ToPgxText(filter.Search)
+	}
+	if filter.Role != "" {
+		role = utils.ToPgxText(filter.Role)
+	}
+	if filter.IsActive != nil {
+		isActive = utils.ToPgxBool(*filter.IsActive)
+	}
+	return search, role, isActive
+}
+
+func (r *userRepository) SearchTenantUsers(ctx context.Context, tenantID string,
+	filter ListUsersFilter) ([]db.User, error) {
+	uuidTenant, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	search, role, isActive := tenantFilterParams(filter)
+	return r.q.SearchTenantUsers(ctx, db.SearchTenantUsersParams{
+		TenantID:  uuidTenant,
+		Search:    search,
+		Role:      role,
+		IsActive:  isActive,
+		RowLimit:  int32(filter.Limit),
+		RowOffset: int32(filter.Offset),
+	})
+}
+
+func (r *userRepository) CountTenantUsers(ctx context.Context, tenantID string,
+	filter ListUsersFilter) (int64, error) {
+	uuidTenant, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	search, role, isActive := tenantFilterParams(filter)
+	return r.q.CountTenantUsers(ctx, db.CountTenantUsersParams{
+		TenantID: uuidTenant,
+		Search:   search,
+		Role:     role,
+		IsActive: isActive,
+	})
+}
+
+func (r *userRepository) GetTenantUserByID(ctx context.Context, tenantID, userID string) (db.User, error) {
+	uuidUser, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.User{}, err
+	}
+	uuidTenant, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.User{}, err
+	}
+	return r.q.GetTenantUserByID(ctx, db.GetTenantUserByIDParams{
+		ID:       uuidUser,
+		TenantID: uuidTenant,
+	})
+}
+
+func (r *userRepository) SetTenantUserActive(ctx context.Context, tenantID, userID string,
+	active bool) (db.User, error) {
+	uuidUser, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.User{}, err
+	}
+	uuidTenant, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.User{}, err
+	}
+	return r.q.SetTenantUserActive(ctx, db.SetTenantUserActiveParams{
+		ID:       uuidUser,
+		TenantID: uuidTenant,
+		IsActive: utils.ToPgxBool(active),
+	})
+}
+
+func (r *userRepository) UpdateTenantUserRole(ctx context.Context, tenantID, userID,
+	role string) (db.User, error) {
+	uuidUser, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.User{}, err
+	}
+	uuidTenant, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.User{}, err
+	}
+	return r.q.UpdateTenantUserRole(ctx, db.UpdateTenantUserRoleParams{
+		ID:       uuidUser,
+		TenantID: uuidTenant,
+		Role:     utils.ToPgxText(role),
+	})
+}
+
+func (r *userRepository) GetTenantUserByUsername(ctx context.Context, tenantID,
+	username string) (db.User, error) {
+	uuidTenant, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return db.User{}, err
+	}
+	return r.q.GetTenantUserByUsername(ctx, db.GetTenantUserByUsernameParams{
+		TenantID: uuidTenant,
+		Username: username,
+	})
+}
+
+func (r *userRepository) ListTenantUsersByPhone(ctx context.Context, tenantID,
+	phone string) ([]db.User, error) {
+	uuidTenant, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListTenantUsersByPhone(ctx, db.ListTenantUsersByPhoneParams{
+		TenantID: uuidTenant,
+		Phone:    utils.ToPgxText(phone),
+	})
+}
+
+func (r *userRepository) SetUsername(ctx context.Context, userID, username string) (db.User, error) {
+	uuidUser, err := utils.StringToPgUUID(userID)
+	if err != nil {
+		return db.User{}, err
+	}
+	return r.q.SetUsername(ctx, db.SetUsernameParams{
+		Username: utils.ToPgxText(username),
+		ID:       uuidUser,
+	})
+}
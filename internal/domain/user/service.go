@@ -9,8 +9,11 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
@@ -90,6 +93,8 @@ func (s *userService) CreateUser(ctx context.Context, req *CreateUserRequest) (d
 		PasswordHash: hash,
 		IsActive:     pgtype.Bool{Bool: req.IsActive, Valid: true},
 		Role:         utils.ToDBString(&role),
+		FirstName:    optionalText(req.FirstName),
+		LastName:     optionalText(req.LastName),
 	}
 	created, err := s.Repo.CreateUser(ctx, params)
 	if err != nil {
@@ -97,3 +102,143 @@ func (s *userService) CreateUser(ctx context.Context, req *CreateUserRequest) (d
 	}
 	return created, nil
 }
+
+func (s *userService) UpdatePassword(ctx context.Context, userID, password string) error {
+	if len(password) < 8 {
+		return model.ErrPasswordTooShort
+	}
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+	return s.Repo.UpdatePassword(ctx, userID, hash)
+}
+
+func (s *userService) MarkEmailVerified(ctx context.Context, userID string) error {
+	return s.Repo.MarkEmailVerified(ctx, userID)
+}
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListTenantUsers returns one page of a tenant's users, newest first, with the
+// total number matching the filter.
+func (s *userService) ListTenantUsers(ctx context.Context, tenantID string,
+	filter ListUsersFilter) (UserList, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	rows, err := s.Repo.SearchTenantUsers(ctx, tenantID, filter)
+	if err != nil {
+		return UserList{}, err
+	}
+
+	total, err := s.Repo.CountTenantUsers(ctx, tenantID, filter)
+	if err != nil {
+		return UserList{}, err
+	}
+
+	users := make([]User, 0, len(rows))
+	for _, u := range rows {
+		users = append(users, ToUser(u))
+	}
+
+	return UserList{
+		Users:  users,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// GetTenantUser returns a user only if they belong to tenantID
+func (s *userService) GetTenantUser(ctx context.Context, tenantID, userID string) (User, error) {
+	u, err := s.Repo.GetTenantUserByID(ctx, tenantID, userID)
+	if err != nil {
+		return User{}, model.ErrUserNotFound
+	}
+	return ToUser(u), nil
+}
+
+func (s *userService) SetTenantUserActive(ctx context.Context, tenantID, userID string,
+	active bool) (User, error) {
+	u, err := s.Repo.SetTenantUserActive(ctx, tenantID, userID, active)
+	if err != nil {
+		return User{}, model.ErrUserNotFound
+	}
+
+	s.logger.Info("User active state changed", zap.String("userID", userID), zap.Bool("active", active))
+	return ToUser(u), nil
+}
+
+// UpdateTenantUserRole changes a user's base role. Only tenant roles can be set;
+// PLATFORM_ADMIN is never assignable from within a tenant.
+func (s *userService) UpdateTenantUserRole(ctx context.Context, tenantID, userID,
+	role string) (User, error) {
+	if role != model.RoleUser.String() && role != model.RoleTenantAdmin.String() {
+		return User{}, model.ErrInvalidInputError
+	}
+
+	u, err := s.Repo.UpdateTenantUserRole(ctx, tenantID, userID, role)
+	if err != nil {
+		return User{}, model.ErrUserNotFound
+	}
+
+	s.logger.Info("User role changed", zap.String("userID", userID), zap.String("role", role))
+	return ToUser(u), nil
+}
+
+func (s *userService) GetTenantUserByUsername(ctx context.Context, tenantID,
+	username string) (dbsqlc.User, error) {
+	return s.Repo.GetTenantUserByUsername(ctx, tenantID, username)
+}
+
+func (s *userService) ListTenantUsersByPhone(ctx context.Context, tenantID,
+	phone string) ([]dbsqlc.User, error) {
+	return s.Repo.ListTenantUsersByPhone(ctx, tenantID, phone)
+}
+
+// usernamePattern allows 3 to 30 lowercase letters, digits, dots and
+// underscores, starting with a letter
+var usernamePattern = regexp.MustCompile(`^[a-z][a-z0-9._]{2,29}$`)
+
+// SetUsername sets the username a user can be sent money by. Usernames are
+// stored in lower case and are unique within a tenant.
+func (s *userService) SetUsername(ctx context.Context, userID, username string) (User, error) {
+	username = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+	if !usernamePattern.MatchString(username) {
+		return User{}, model.ErrInvalidUsername
+	}
+
+	u, err := s.Repo.SetUsername(ctx, userID, username)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return User{}, model.ErrUsernameTaken
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, model.ErrUserNotFound
+		}
+		return User{}, err
+	}
+	return ToUser(u), nil
+}
+
+func optionalText(s string) pgtype.Text {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return pgtype.Text{}
+	}
+	return utils.ToPgxText(s)
+}
@@ -4,6 +4,7 @@ import (
 	"codematic/internal/domain/provider/gateways"
 	db "codematic/internal/infrastructure/db/sqlc"
 	"context"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -11,7 +12,11 @@ import (
 type Service interface {
 	InitiateDeposit(ctx context.Context, data DepositForm) (gateways.GatewayResponse, error)
 	Withdraw(ctx context.Context, data WithdrawalForm) error
-	Transfer(ctx context.Context, data TransferForm) error
+	Transfer(ctx context.Context, data TransferForm) (*TransferDetails, error)
+	GetTransfer(ctx context.Context, id string) (*TransferDetails, error)
+	ResolveRecipient(ctx context.Context, tenantID, senderID string,
+		req ResolveRecipientRequest) (*Recipient, error)
+	SendToUser(ctx context.Context, data SendToUserForm) (*TransferDetails, error)
 	GetBalance(ctx context.Context, walletID string) (decimal.Decimal, error)
 	GetTransactions(ctx context.Context, walletID string,
 		limit, offset int) ([]Transaction, error)
@@ -24,13 +29,24 @@ type Service interface {
 
 	// Kafka event handler
 	HandlePaystackKafkaEvent(ctx context.Context, key, value []byte)
+
+	ReconcileDeposits(ctx context.Context, verifyAfter, expireAfter time.Duration) (*ReconcileReport, error)
+	RetryPendingReversals(ctx context.Context, retryAfter time.Duration) (*ReversalRetryReport, error)
+	ReviewDeposit(ctx context.Context, id, tenantID string, req ReviewDepositRequest) (*Transaction, error)
+	ReverseTransaction(ctx context.Context, form ReversalForm) (*Reversal, error)
+	ListReversals(ctx context.Context, transactionID, tenantID string) ([]Reversal, error)
+	GetWallet(ctx context.Context, id, tenantID string) (*Wallet, error)
+	UpdateWalletStatus(ctx context.Context, id, tenantID string, req WalletStatusRequest) (*WalletStatusChange, error)
 }
 
 type Repository interface {
 	CreateWalletsForNewUserFromAvailableWallets(ctx context.Context,
 		userID string) ([]*Wallet, error)
 	GetWallet(ctx context.Context, walletID string) (*Wallet, error)
+	LockWallet(ctx context.Context, walletID string) (*Wallet, error)
+	UpdateWalletStatus(ctx context.Context, walletID, status string) error
 	GetWalletByUserAndCurrency(ctx context.Context, userID string, currency string) (*Wallet, error)
+	GetWalletCurrency(ctx context.Context, walletID string) (string, error)
 	UpdateWalletBalance(ctx context.Context, walletID string,
 		amount decimal.Decimal) error
 	CreateTransaction(ctx context.Context, tx *Transaction) error
@@ -43,12 +59,50 @@ type Repository interface {
 	WithTx(q *db.Queries) Repository
 
 	GetTransactionByReference(ctx context.Context, reference string) (*Transaction, error)
+	GetTransactionByID(ctx context.Context, id string) (*Transaction, error)
 	UpdateTransactionStatusAndAmount(ctx context.Context, id, status string, amount decimal.Decimal) error
+	// TransitionTransaction moves a transaction to status only if it is in one
+	// of from, reporting whether it did
+	TransitionTransaction(ctx context.Context, id, status string, amount decimal.Decimal, reason string,
+		from ...string) (bool, error)
+	// FlagTransaction holds a transaction for review if it is in one of from,
+	// merging review into its metadata, and reports whether it did
+	FlagTransaction(ctx context.Context, id, reason string, review map[string]interface{},
+		from ...string) (bool, error)
+	ListPendingDeposits(ctx context.Context, createdBefore time.Time, after *Transaction,
+		limit int) ([]Transaction, error)
+	GetDepositAmountPolicy(ctx context.Context, tenantID string) (string, error)
+	AllowsP2PTransfers(ctx context.Context, tenantID string) (bool, error)
+	// PostFee records a fee charged on a transaction and credits it to the
+	// platform's revenue wallet for its currency
+	PostFee(ctx context.Context, transactionID, tenantID, currency string, amount decimal.Decimal) error
+
+	// Reversal operations. LockTransaction holds the original until the
+	// surrounding transaction ends so concurrent reversals cannot overshoot it.
+	LockTransaction(ctx context.Context, id string) (*Transaction, error)
+	SumReversals(ctx context.Context, transactionID string) (decimal.Decimal, error)
+	CreateReversal(ctx context.Context, reversal *Reversal) error
+	// FinishReversal settles a pending reversal, returning pgx.ErrNoRows if
+	// it was already settled
+	FinishReversal(ctx context.Context, reversal *Reversal) error
+	ListReversals(ctx context.Context, transactionID string) ([]Reversal, error)
+	// RecordReversalRefund stores the outcome of a pending reversal's refund
+	// before the reversal is settled
+	RecordReversalRefund(ctx context.Context, reversal *Reversal) error
+	// ListStaleReversals returns reversals left pending since before
+	// updatedBefore; ClaimReversal takes one for a retry, reporting false if
+	// another run got to it first
+	ListStaleReversals(ctx context.Context, updatedBefore time.Time, limit int) ([]Reversal, error)
+	ClaimReversal(ctx context.Context, id string, updatedBefore time.Time) (bool, error)
 
 	// Deposit operations
 	CreateDeposit(ctx context.Context, deposit *Deposit) error
 	GetDepositByID(ctx context.Context, id int) (*Deposit, error)
 
+	// Transfer operations
+	CreateTransfer(ctx context.Context, transfer *TransferDetails) error
+	GetTransfer(ctx context.Context, id string) (*TransferDetails, error)
+
 	// Withdrawal operations
 	CreateWithdrawal(ctx context.Context, withdrawal *Withdrawal) error
 	GetWithdrawalByID(ctx context.Context, id int) (*Withdrawal, error)
@@ -9,11 +9,57 @@ import (
 const (
 	TransactionDeposit    = "deposit"
 	TransactionWithdrawal = "withdrawal"
-	TransactionTransfer   = "withdrawal"
+	TransactionTransfer   = "transfer"
+	// TransactionReversal is a compensating leg posted by a reversal
+	TransactionReversal = "reversal"
 
 	StatusPending   = "pending"
 	StatusCompleted = "completed"
 	StatusFailed    = "failed"
+	// StatusExpired marks a deposit the customer never paid for
+	StatusExpired = "expired"
+	// StatusFlagged holds a deposit paid with an amount or currency its
+	// tenant's policy does not accept until someone reviews it
+	StatusFlagged = "flagged"
+
+	// Decisions on a flagged deposit
+	ReviewCredit = "credit"
+	ReviewReject = "reject"
+
+	// Metadata keys recording what the customer actually paid
+	metaPaidAmount   = "paid_amount"
+	metaPaidCurrency = "paid_currency"
+
+	// Metadata keys linking a reversal leg to what it reverses, and recording
+	// which wallet a transfer paid
+	metaReversalID  = "reversal_id"
+	metaReversalOf  = "reversal_of"
+	metaDirection   = "direction"
+	metaDescription = "description"
+	metaToWalletID  = "to_wallet_id"
+	metaTransferID  = "transfer_id"
+	metaFromWallet  = "from_wallet_id"
+	// metaRecipientType records how a P2P transfer's recipient was identified
+	metaRecipientType = "recipient_type"
+
+	// How a reversed deposit goes back to the customer: submitted to the
+	// provider's refund API, or left to be paid out by hand
+	RefundSubmitted = "submitted"
+	RefundManual    = "manual"
+
+	// Wallet statuses. Only active wallets move money; a closed wallet
+	// cannot be reopened
+	WalletActive = "active"
+	WalletFrozen = "frozen"
+	WalletClosed = "closed"
+
+	// Ways a P2P recipient can be identified
+	RecipientByEmail    = "email"
+	RecipientByPhone    = "phone"
+	RecipientByUsername = "username"
+
+	// Pending deposits reconciled per page
+	reconcileBatchSize = 100
 
 	ChannelBankTransfer Channel = "bank_transfer"
 	ChannelCard         Channel = "card"
@@ -73,6 +119,78 @@ type (
 		Metadata     map[string]interface{} `json:"metadata"`
 	}
 
+	// ReviewDepositRequest credits a flagged deposit with the amount paid, or
+	// rejects it so the payment can be refunded outside the wallet
+	ReviewDepositRequest struct {
+		Decision string `json:"decision" validate:"required,oneof=credit reject"`
+		Note     string `json:"note" validate:"max=1000"`
+	}
+
+	// WalletStatusRequest freezes, unfreezes or closes a wallet
+	WalletStatusRequest struct {
+		Status string `json:"status" validate:"required,oneof=active frozen closed"`
+		Reason string `json:"reason" validate:"max=500"`
+	}
+
+	// WalletStatusChange records a wallet moving from one status to another
+	WalletStatusChange struct {
+		WalletID string `json:"wallet_id"`
+		TenantID string `json:"tenant_id"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+		Reason   string `json:"reason,omitempty"`
+	}
+
+	// ReverseTransactionRequest reverses part or, with no amount, the rest of
+	// a completed transaction
+	ReverseTransactionRequest struct {
+		Amount string `json:"amount" validate:"omitempty,numeric"`
+		Reason string `json:"reason" validate:"required,max=500"`
+	}
+
+	ReversalForm struct {
+		TransactionID string          `json:"transaction_id"`
+		TenantID      string          `json:"tenant_id"`
+		UserID        string          `json:"user_id"`
+		Amount        decimal.Decimal `json:"amount"`
+		Reason        string          `json:"reason"`
+	}
+
+	// Reversal undoes all or part of a completed transaction through
+	// compensating transactions, leaving the original untouched
+	Reversal struct {
+		ID                  string          `json:"id"`
+		TenantID            string          `json:"tenant_id"`
+		TransactionID       string          `json:"transaction_id"`
+		CurrencyCode        string          `json:"currency_code"`
+		Amount              decimal.Decimal `json:"amount"`
+		Reason              string          `json:"reason"`
+		Status              string          `json:"status"`
+		DebitTransactionID  string          `json:"debit_transaction_id,omitempty"`
+		CreditTransactionID string          `json:"credit_transaction_id,omitempty"`
+		RefundStatus        string          `json:"refund_status,omitempty"`
+		RefundReference     string          `json:"refund_reference,omitempty"`
+		Error               string          `json:"error,omitempty"`
+		CreatedBy           string          `json:"created_by,omitempty"`
+		CreatedAt           time.Time       `json:"created_at"`
+		UpdatedAt           time.Time       `json:"updated_at"`
+	}
+
+	// ReversalEvent is published when a reversal completes or fails
+	ReversalEvent struct {
+		Event           string    `json:"event"`
+		TenantID        string    `json:"tenant_id"`
+		ReversalID      string    `json:"reversal_id"`
+		TransactionID   string    `json:"transaction_id"`
+		TransactionType string    `json:"transaction_type"`
+		Amount          string    `json:"amount"`
+		Currency        string    `json:"currency"`
+		Status          string    `json:"status"`
+		RefundStatus    string    `json:"refund_status,omitempty"`
+		Reason          string    `json:"reason"`
+		Timestamp       time.Time `json:"timestamp"`
+	}
+
 	TransferForm struct {
 		UserID       string                 `json:"user_id"`
 		TenantID     string                 `json:"tenant_id"`
@@ -82,10 +200,69 @@ type (
 		Metadata     map[string]interface{} `json:"metadata"`
 	}
 
+	// ResolveRecipientRequest looks up who a P2P transfer would go to. The
+	// recipient is an email, a phone number or a username, which may start
+	// with @.
+	ResolveRecipientRequest struct {
+		Recipient string `json:"recipient" validate:"required,max=320"`
+		Currency  string `json:"currency" validate:"required,uppercase,len=3"`
+	}
+
+	// Recipient is shown to a sender to confirm who they are paying without
+	// revealing the recipient's details
+	Recipient struct {
+		Recipient  string `json:"recipient"`
+		Type       string `json:"type"`
+		MaskedName string `json:"masked_name"`
+		Currency   string `json:"currency"`
+	}
+
+	// SendToUserRequest transfers money to another user of the same tenant,
+	// identified as in ResolveRecipientRequest
+	SendToUserRequest struct {
+		FromWalletID string                 `json:"from_wallet_id" validate:"required,uuid"`
+		Recipient    string                 `json:"recipient" validate:"required,max=320"`
+		Amount       string                 `json:"amount" validate:"required,numeric"`
+		Metadata     map[string]interface{} `json:"metadata"`
+	}
+
+	SendToUserForm struct {
+		UserID       string                 `json:"user_id"`
+		TenantID     string                 `json:"tenant_id"`
+		FromWalletID string                 `json:"from_wallet_id"`
+		Recipient    string                 `json:"recipient"`
+		Amount       decimal.Decimal        `json:"amount"`
+		Metadata     map[string]interface{} `json:"metadata"`
+	}
+
+	// TransferDetails is an internal transfer with both of its parties
+	TransferDetails struct {
+		ID           string          `json:"id"`
+		TenantID     string          `json:"tenant_id"`
+		CurrencyCode string          `json:"currency_code"`
+		Amount       decimal.Decimal `json:"amount"`
+		Fee          decimal.Decimal `json:"fee"`
+		Status       string          `json:"status"`
+		Sender       TransferParty   `json:"sender"`
+		Receiver     TransferParty   `json:"receiver"`
+		CreatedAt    time.Time       `json:"created_at"`
+		UpdatedAt    time.Time       `json:"updated_at"`
+	}
+
+	// TransferParty is one side of a transfer and the transaction posted to
+	// its wallet
+	TransferParty struct {
+		UserID        string `json:"user_id"`
+		WalletID      string `json:"wallet_id"`
+		TransactionID string `json:"transaction_id"`
+	}
+
 	Wallet struct {
 		ID        string          `json:"id"`
 		UserID    string          `json:"user_id"`
+		TenantID  string          `json:"tenant_id"`
 		Balance   decimal.Decimal `json:"balance"`
+		Status    string          `json:"status"`
 		CreatedAt time.Time       `json:"created_at"`
 		UpdatedAt time.Time       `json:"updated_at"`
 	}
@@ -125,6 +302,31 @@ type (
 		UpdatedAt     time.Time `json:"updated_at"`
 	}
 
+	// ReconcileReport summarises one reconciliation run over pending deposits
+	ReconcileReport struct {
+		StartedAt time.Time     `json:"started_at"`
+		Duration  time.Duration `json:"duration"`
+		Checked   int           `json:"checked"`
+		Completed int           `json:"completed"`
+		Failed    int           `json:"failed"`
+		Expired   int           `json:"expired"`
+		Flagged   int           `json:"flagged"`
+		StillOpen int           `json:"still_open"`
+		Errors    int           `json:"errors"`
+		ErrorRefs []string      `json:"error_references,omitempty"`
+	}
+
+	// ReversalRetryReport summarises one run over reversals left pending
+	ReversalRetryReport struct {
+		StartedAt time.Time     `json:"started_at"`
+		Duration  time.Duration `json:"duration"`
+		Checked   int           `json:"checked"`
+		Completed int           `json:"completed"`
+		Failed    int           `json:"failed"`
+		Errors    int           `json:"errors"`
+		ErrorIDs  []string      `json:"error_ids,omitempty"`
+	}
+
 	// Withdrawal represents a wallet withdrawal record
 	Withdrawal struct {
 		ID            int       `json:"id"`
@@ -6,8 +6,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
@@ -35,19 +37,59 @@ func (r *walletRepository) GetWallet(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	w, err := r.q.GetWalletByID(ctx, uid)
+	w, err := r.q.GetWalletWithTenant(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
 	return &Wallet{
 		ID:        w.ID.String(),
 		UserID:    w.UserID.String(),
+		TenantID:  w.TenantID.String(),
 		Balance:   w.Balance,
+		Status:    w.Status,
 		CreatedAt: w.CreatedAt.Time,
 		UpdatedAt: w.UpdatedAt.Time,
 	}, nil
 }
 
+// LockWallet reads a wallet and locks it until the transaction ends, so its
+// balance can be checked and updated without racing other updates
+func (r *walletRepository) LockWallet(ctx context.Context, walletID string) (*Wallet, error) {
+	uid, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return nil, err
+	}
+	w, err := r.q.GetWalletForUpdate(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		ID:        w.ID.String(),
+		UserID:    w.UserID.String(),
+		TenantID:  w.TenantID.String(),
+		Balance:   w.Balance,
+		Status:    w.Status,
+		CreatedAt: w.CreatedAt.Time,
+		UpdatedAt: w.UpdatedAt.Time,
+	}, nil
+}
+
+func (r *walletRepository) UpdateWalletStatus(ctx context.Context, walletID, status string) error {
+	uid, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return err
+	}
+	return r.q.UpdateWalletStatus(ctx, db.UpdateWalletStatusParams{Status: status, ID: uid})
+}
+
+func (r *walletRepository) GetWalletCurrency(ctx context.Context, walletID string) (string, error) {
+	uid, err := utils.StringToPgUUID(walletID)
+	if err != nil {
+		return "", err
+	}
+	return r.q.GetWalletCurrency(ctx, uid)
+}
+
 func (r *walletRepository) UpdateWalletBalance(ctx context.Context,
 	walletID string, amount decimal.Decimal) error {
 	uid, err := utils.StringToPgUUID(walletID)
@@ -134,15 +176,13 @@ func (r *walletRepository) ListTransactions(ctx context.Context,
 		var meta map[string]interface{}
 		_ = json.Unmarshal(row.Metadata, &meta)
 
-		fee := decimal.Zero
-
 		txs = append(txs, Transaction{
 			ID:        row.ID.String(),
 			WalletID:  row.WalletID.String(),
 			Type:      row.Type,
 			Status:    row.Status,
 			Amount:    row.Amount,
-			Fee:       fee,
+			Fee:       row.Fee,
 			Provider:  row.ProviderID.String(),
 			Reference: row.Reference,
 			Metadata:  meta,
@@ -240,24 +280,19 @@ func (r *walletRepository) GetTransactionByReference(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	var meta map[string]interface{}
-	_ = json.Unmarshal(tx.Metadata, &meta)
-	return &Transaction{
-		ID:           tx.ID.String(),
-		WalletID:     tx.WalletID.String(),
-		Type:         tx.Type,
-		TenantID:     tx.TenantID.String(),
-		Status:       tx.Status,
-		CurrencyCode: tx.CurrencyCode,
-		Amount:       tx.Amount,
-		Fee:          tx.Fee,
-		Provider:     tx.ProviderID.String(),
-		Reference:    tx.Reference,
-		Metadata:     meta,
-		Error:        tx.ErrorReason.String,
-		CreatedAt:    tx.CreatedAt.Time,
-		UpdatedAt:    tx.UpdatedAt.Time,
-	}, nil
+	return toDomainTransaction(tx), nil
+}
+
+func (r *walletRepository) GetTransactionByID(ctx context.Context, id string) (*Transaction, error) {
+	uid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.q.GetTransactionByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainTransaction(tx), nil
 }
 
 func (r *walletRepository) UpdateTransactionStatusAndAmount(
@@ -377,3 +412,375 @@ func (r *walletRepository) UpdateDepositStatus(ctx context.Context, transactionI
 			TransactionID: tid,
 		})
 }
+
+func (r *walletRepository) TransitionTransaction(ctx context.Context, id, status string,
+	amount decimal.Decimal, reason string, from ...string) (bool, error) {
+	uid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	var errorReason pgtype.Text
+	if reason != "" {
+		errorReason = utils.ToPgxText(reason)
+	}
+
+	n, err := r.q.TransitionTransactionStatus(ctx, db.TransitionTransactionStatusParams{
+		Status:       status,
+		Amount:       amount,
+		ErrorReason:  errorReason,
+		ID:           uid,
+		FromStatuses: from,
+	})
+	return n > 0, err
+}
+
+func (r *walletRepository) FlagTransaction(ctx context.Context, id, reason string,
+	review map[string]interface{}, from ...string) (bool, error) {
+	uid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	payload, err := json.Marshal(review)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.q.FlagTransaction(ctx, db.FlagTransactionParams{
+		ErrorReason:  utils.ToPgxText(reason),
+		Review:       payload,
+		ID:           uid,
+		FromStatuses: from,
+	})
+	return n > 0, err
+}
+
+func (r *walletRepository) GetDepositAmountPolicy(ctx context.Context, tenantID string) (string, error) {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return "", err
+	}
+	tenant, err := r.q.GetTenantByID(ctx, tid)
+	if err != nil {
+		return "", err
+	}
+	return tenant.DepositAmountPolicy, nil
+}
+
+func (r *walletRepository) AllowsP2PTransfers(ctx context.Context, tenantID string) (bool, error) {
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return false, err
+	}
+	tenant, err := r.q.GetTenantByID(ctx, tid)
+	if err != nil {
+		return false, err
+	}
+	return tenant.AllowP2pTransfers, nil
+}
+
+// PostFee credits the revenue wallet inside the caller's transaction, so every
+// fee-bearing transfer, withdrawal or settled deposit in a currency takes the
+// same revenue_wallets row lock until it commits. That serialises fee
+// charging per currency, which we accept for a balance that is always exact.
+// If it becomes a bottleneck, drop the CreditRevenueWallet call and have
+// ListRevenue sum fee_entries instead, as the entries already hold every fee.
+func (r *walletRepository) PostFee(ctx context.Context, transactionID, tenantID, currency string,
+	amount decimal.Decimal) error {
+	txID, err := utils.StringToPgUUID(transactionID)
+	if err != nil {
+		return err
+	}
+	tid, err := utils.StringToPgUUID(tenantID)
+	if err != nil {
+		return err
+	}
+	if err := r.q.CreateFeeEntry(ctx, db.CreateFeeEntryParams{
+		ID:            utils.ToUUID(uuid.New()),
+		TransactionID: txID,
+		TenantID:      tid,
+		CurrencyCode:  currency,
+		Amount:        amount,
+	}); err != nil {
+		return err
+	}
+	return r.q.CreditRevenueWallet(ctx, db.CreditRevenueWalletParams{
+		CurrencyCode: currency,
+		Amount:       amount,
+	})
+}
+
+func (r *walletRepository) ListPendingDeposits(ctx context.Context, createdBefore time.Time,
+	after *Transaction, limit int) ([]Transaction, error) {
+	params := db.ListPendingDepositsParams{
+		CreatedBefore: utils.ToPgTimestamptz(createdBefore),
+		// The nil UUID sorts before every transaction created at the same time
+		AfterCreatedAt: utils.ToPgTimestamptz(time.Time{}),
+		AfterID:        pgtype.UUID{Valid: true},
+		RowLimit:       int32(limit),
+	}
+	if after != nil {
+		afterID, err := utils.StringToPgUUID(after.ID)
+		if err != nil {
+			return nil, err
+		}
+		params.AfterCreatedAt = utils.ToPgTimestamptz(after.CreatedAt)
+		params.AfterID = afterID
+	}
+
+	rows, err := r.q.ListPendingDeposits(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]Transaction, len(rows))
+	for i, row := range rows {
+		txs[i] = *toDomainTransaction(row)
+	}
+	return txs, nil
+}
+
+func (r *walletRepository) CreateTransfer(ctx context.Context, transfer *TransferDetails) error {
+	uid, err := utils.StringToPgUUID(transfer.ID)
+	if err != nil {
+		return err
+	}
+	tid, _ := utils.StringToPgUUID(transfer.TenantID)
+	senderID, _ := utils.StringToPgUUID(transfer.Sender.WalletID)
+	receiverID, _ := utils.StringToPgUUID(transfer.Receiver.WalletID)
+	debitID, _ := utils.StringToPgUUID(transfer.Sender.TransactionID)
+	creditID, _ := utils.StringToPgUUID(transfer.Receiver.TransactionID)
+
+	return r.q.CreateTransfer(ctx, db.CreateTransferParams{
+		ID:                  uid,
+		TenantID:            tid,
+		SenderWalletID:      senderID,
+		ReceiverWalletID:    receiverID,
+		TransactionID:       debitID,
+		CreditTransactionID: creditID,
+		CurrencyCode:        transfer.CurrencyCode,
+		Amount:              transfer.Amount,
+		Fee:                 transfer.Fee,
+		Status:              transfer.Status,
+	})
+}
+
+func (r *walletRepository) GetTransfer(ctx context.Context, id string) (*TransferDetails, error) {
+	uid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := r.q.GetTransferDetails(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferDetails{
+		ID:           t.ID.String(),
+		TenantID:     t.TenantID.String(),
+		CurrencyCode: t.CurrencyCode,
+		Amount:       t.Amount,
+		Fee:          t.Fee,
+		Status:       t.Status,
+		Sender: TransferParty{
+			UserID:        t.SenderUserID.String(),
+			WalletID:      t.SenderWalletID.String(),
+			TransactionID: t.TransactionID.String(),
+		},
+		Receiver: TransferParty{
+			UserID:        t.ReceiverUserID.String(),
+			WalletID:      t.ReceiverWalletID.String(),
+			TransactionID: t.CreditTransactionID.String(),
+		},
+		CreatedAt: t.CreatedAt.Time,
+		UpdatedAt: t.UpdatedAt.Time,
+	}, nil
+}
+
+func (r *walletRepository) LockTransaction(ctx context.Context, id string) (*Transaction, error) {
+	uid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.q.LockTransaction(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainTransaction(tx), nil
+}
+
+func (r *walletRepository) SumReversals(ctx context.Context, transactionID string) (decimal.Decimal, error) {
+	uid, err := utils.StringToPgUUID(transactionID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return r.q.SumReversals(ctx, uid)
+}
+
+func (r *walletRepository) CreateReversal(ctx context.Context, reversal *Reversal) error {
+	uid, err := utils.StringToPgUUID(reversal.ID)
+	if err != nil {
+		return err
+	}
+	tid, err := utils.StringToPgUUID(reversal.TenantID)
+	if err != nil {
+		return err
+	}
+	txID, err := utils.StringToPgUUID(reversal.TransactionID)
+	if err != nil {
+		return err
+	}
+	debitID, _ := utils.StringToPgUUID(reversal.DebitTransactionID)
+	creditID, _ := utils.StringToPgUUID(reversal.CreditTransactionID)
+	createdBy, _ := utils.StringToPgUUID(reversal.CreatedBy)
+
+	row, err := r.q.CreateReversal(ctx, db.CreateReversalParams{
+		ID:                    uid,
+		TenantID:              tid,
+		OriginalTransactionID: txID,
+		CurrencyCode:          reversal.CurrencyCode,
+		Amount:                reversal.Amount,
+		Reason:                reversal.Reason,
+		Status:                reversal.Status,
+		DebitTransactionID:    debitID,
+		CreditTransactionID:   creditID,
+		CreatedBy:             createdBy,
+	})
+	if err != nil {
+		return err
+	}
+	*reversal = toDomainReversal(row)
+	return nil
+}
+
+func (r *walletRepository) FinishReversal(ctx context.Context, reversal *Reversal) error {
+	uid, err := utils.StringToPgUUID(reversal.ID)
+	if err != nil {
+		return err
+	}
+	row, err := r.q.FinishReversal(ctx, db.FinishReversalParams{
+		Status:          reversal.Status,
+		RefundStatus:    optionalText(reversal.RefundStatus),
+		RefundReference: optionalText(reversal.RefundReference),
+		Error:           optionalText(reversal.Error),
+		ID:              uid,
+	})
+	if err != nil {
+		return err
+	}
+	*reversal = toDomainReversal(row)
+	return nil
+}
+
+func (r *walletRepository) ListReversals(ctx context.Context, transactionID string) ([]Reversal, error) {
+	uid, err := utils.StringToPgUUID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListReversalsByTransaction(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	reversals := make([]Reversal, len(rows))
+	for i, row := range rows {
+		reversals[i] = toDomainReversal(row)
+	}
+	return reversals, nil
+}
+
+func (r *walletRepository) RecordReversalRefund(ctx context.Context, reversal *Reversal) error {
+	uid, err := utils.StringToPgUUID(reversal.ID)
+	if err != nil {
+		return err
+	}
+	n, err := r.q.RecordReversalRefund(ctx, db.RecordReversalRefundParams{
+		RefundStatus:    reversal.RefundStatus,
+		RefundReference: optionalText(reversal.RefundReference),
+		ID:              uid,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *walletRepository) ListStaleReversals(ctx context.Context, updatedBefore time.Time,
+	limit int) ([]Reversal, error) {
+	rows, err := r.q.ListStaleReversals(ctx, db.ListStaleReversalsParams{
+		UpdatedBefore: utils.ToPgTimestamptz(updatedBefore),
+		RowLimit:      int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	reversals := make([]Reversal, len(rows))
+	for i, row := range rows {
+		reversals[i] = toDomainReversal(row)
+	}
+	return reversals, nil
+}
+
+func (r *walletRepository) ClaimReversal(ctx context.Context, id string, updatedBefore time.Time) (bool, error) {
+	uid, err := utils.StringToPgUUID(id)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.ClaimReversal(ctx, db.ClaimReversalParams{
+		ID:            uid,
+		UpdatedBefore: utils.ToPgTimestamptz(updatedBefore),
+	})
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func optionalText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{}
+	}
+	return utils.ToPgxText(s)
+}
+
+func toDomainReversal(r db.TransactionReversal) Reversal {
+	return Reversal{
+		ID:                  r.ID.String(),
+		TenantID:            r.TenantID.String(),
+		TransactionID:       r.OriginalTransactionID.String(),
+		CurrencyCode:        r.CurrencyCode,
+		Amount:              r.Amount,
+		Reason:              r.Reason,
+		Status:              r.Status,
+		DebitTransactionID:  utils.FromPgUUID(r.DebitTransactionID),
+		CreditTransactionID: utils.FromPgUUID(r.CreditTransactionID),
+		RefundStatus:        r.RefundStatus.String,
+		RefundReference:     r.RefundReference.String,
+		Error:               r.Error.String,
+		CreatedBy:           utils.FromPgUUID(r.CreatedBy),
+		CreatedAt:           r.CreatedAt.Time,
+		UpdatedAt:           r.UpdatedAt.Time,
+	}
+}
+
+func toDomainTransaction(tx db.Transaction) *Transaction {
+	var meta map[string]interface{}
+	_ = json.Unmarshal(tx.Metadata, &meta)
+	return &Transaction{
+		ID:           tx.ID.String(),
+		WalletID:     tx.WalletID.String(),
+		Type:         tx.Type,
+		TenantID:     tx.TenantID.String(),
+		Status:       tx.Status,
+		CurrencyCode: tx.CurrencyCode,
+		Amount:       tx.Amount,
+		Fee:          tx.Fee,
+		Provider:     tx.ProviderID.String(),
+		Reference:    tx.Reference,
+		Metadata:     meta,
+		Error:        tx.ErrorReason.String,
+		CreatedAt:    tx.CreatedAt.Time,
+		UpdatedAt:    tx.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,508 @@
+package wallet
+
+import (
+	"codematic/internal/domain/provider"
+	"codematic/internal/domain/provider/gateways"
+	"codematic/internal/shared/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// memRepo keeps wallets, transactions and reversals in memory. calls records
+// the reversal writes in the order they happen.
+type memRepo struct {
+	Repository
+	wallets    map[string]Wallet
+	currencies map[string]string
+	txs        map[string]Transaction
+	reversals  []Reversal
+	transfers  []TransferDetails
+	calls      []string
+
+	// finishFailures makes that many FinishReversal calls fail
+	finishFailures int
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{wallets: map[string]Wallet{}, currencies: map[string]string{}, txs: map[string]Transaction{}}
+}
+
+func (r *memRepo) snapshot() *memRepo {
+	c := *r
+	c.wallets = make(map[string]Wallet, len(r.wallets))
+	for id, w := range r.wallets {
+		c.wallets[id] = w
+	}
+	c.txs = make(map[string]Transaction, len(r.txs))
+	for id, tx := range r.txs {
+		c.txs[id] = tx
+	}
+	c.reversals = append([]Reversal(nil), r.reversals...)
+	c.transfers = append([]TransferDetails(nil), r.transfers...)
+	return &c
+}
+
+// runTx runs fn against the repository and undoes its writes if it fails,
+// like a database transaction
+func (r *memRepo) runTx(ctx context.Context, fn func(repo Repository) error) error {
+	saved := r.snapshot()
+	if err := fn(r); err != nil {
+		r.wallets, r.txs, r.reversals, r.transfers = saved.wallets, saved.txs, saved.reversals, saved.transfers
+		return err
+	}
+	return nil
+}
+
+func (r *memRepo) GetTransactionByID(ctx context.Context, id string) (*Transaction, error) {
+	tx, ok := r.txs[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &tx, nil
+}
+
+func (r *memRepo) LockTransaction(ctx context.Context, id string) (*Transaction, error) {
+	return r.GetTransactionByID(ctx, id)
+}
+
+func (r *memRepo) LockWallet(ctx context.Context, id string) (*Wallet, error) {
+	w, ok := r.wallets[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &w, nil
+}
+
+func (r *memRepo) UpdateWalletBalance(ctx context.Context, id string, balance decimal.Decimal) error {
+	w := r.wallets[id]
+	w.Balance = balance
+	r.wallets[id] = w
+	return nil
+}
+
+func (r *memRepo) CreateTransaction(ctx context.Context, tx *Transaction) error {
+	r.txs[tx.ID] = *tx
+	return nil
+}
+
+func (r *memRepo) TransitionTransaction(ctx context.Context, id, status string, amount decimal.Decimal,
+	reason string, from ...string) (bool, error) {
+	tx, ok := r.txs[id]
+	if !ok {
+		return false, nil
+	}
+	for _, f := range from {
+		if tx.Status == f {
+			tx.Status, tx.Amount, tx.Error = status, amount, reason
+			r.txs[id] = tx
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memRepo) SumReversals(ctx context.Context, transactionID string) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for _, rv := range r.reversals {
+		if rv.TransactionID == transactionID && rv.Status != StatusFailed {
+			total = total.Add(rv.Amount)
+		}
+	}
+	return total, nil
+}
+
+func (r *memRepo) CreateReversal(ctx context.Context, reversal *Reversal) error {
+	reversal.CreatedAt, reversal.UpdatedAt = time.Now(), time.Now()
+	r.reversals = append(r.reversals, *reversal)
+	r.calls = append(r.calls, "CreateReversal")
+	return nil
+}
+
+func (r *memRepo) reversal(id string) *Reversal {
+	for i := range r.reversals {
+		if r.reversals[i].ID == id {
+			return &r.reversals[i]
+		}
+	}
+	return nil
+}
+
+func (r *memRepo) RecordReversalRefund(ctx context.Context, reversal *Reversal) error {
+	r.calls = append(r.calls, "RecordReversalRefund")
+	if rv := r.reversal(reversal.ID); rv != nil && rv.Status == StatusPending {
+		rv.RefundStatus, rv.RefundReference = reversal.RefundStatus, reversal.RefundReference
+		rv.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (r *memRepo) FinishReversal(ctx context.Context, reversal *Reversal) error {
+	r.calls = append(r.calls, "FinishReversal")
+	if r.finishFailures > 0 {
+		r.finishFailures--
+		return errors.New("connection reset")
+	}
+	rv := r.reversal(reversal.ID)
+	if rv == nil || rv.Status != StatusPending {
+		return pgx.ErrNoRows
+	}
+	rv.Status, rv.RefundStatus, rv.RefundReference = reversal.Status, reversal.RefundStatus, reversal.RefundReference
+	rv.Error, rv.UpdatedAt = reversal.Error, time.Now()
+	return nil
+}
+
+func (r *memRepo) ListStaleReversals(ctx context.Context, updatedBefore time.Time, limit int) ([]Reversal, error) {
+	var stale []Reversal
+	for _, rv := range r.reversals {
+		if rv.Status == StatusPending && rv.UpdatedAt.Before(updatedBefore) && len(stale) < limit {
+			stale = append(stale, rv)
+		}
+	}
+	return stale, nil
+}
+
+func (r *memRepo) ClaimReversal(ctx context.Context, id string, updatedBefore time.Time) (bool, error) {
+	rv := r.reversal(id)
+	if rv == nil || rv.Status != StatusPending || !rv.UpdatedAt.Before(updatedBefore) {
+		return false, nil
+	}
+	rv.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// stubRefunds answers refunds with errs in turn, succeeding once they run out
+type stubRefunds struct {
+	provider.Service
+	errs  []error
+	calls int
+}
+
+func (p *stubRefunds) RefundPayment(ctx context.Context, providerID string,
+	req gateways.RefundRequest) (*gateways.RefundResponse, error) {
+	p.calls++
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &gateways.RefundResponse{Reference: "refund-" + req.Reference}, nil
+}
+
+const testTenant = "tenant-1"
+
+func newReversalTestService(repo *memRepo, refunds *stubRefunds) *WalletService {
+	return &WalletService{
+		Repo:     repo,
+		Provider: refunds,
+		logger:   zap.NewNop(),
+		runTx:    repo.runTx,
+	}
+}
+
+func (r *memRepo) addWallet(balance string) string {
+	return r.addUserWallet(uuid.NewString(), testTenant, "NGN", balance, WalletActive)
+}
+
+func (r *memRepo) addUserWallet(userID, tenantID, currency, balance, status string) string {
+	id := uuid.NewString()
+	r.wallets[id] = Wallet{
+		ID:       id,
+		UserID:   userID,
+		TenantID: tenantID,
+		Balance:  decimal.RequireFromString(balance),
+		Status:   status,
+	}
+	r.currencies[id] = currency
+	return id
+}
+
+func (r *memRepo) addTransaction(tx Transaction) string {
+	tx.ID = uuid.NewString()
+	tx.TenantID = testTenant
+	tx.Status = StatusCompleted
+	tx.CurrencyCode = "NGN"
+	tx.Reference = "ref-" + tx.ID
+	r.txs[tx.ID] = tx
+	return tx.ID
+}
+
+func (r *memRepo) balance(t *testing.T, walletID, want string) {
+	t.Helper()
+	if got := r.wallets[walletID].Balance; !got.Equal(decimal.RequireFromString(want)) {
+		t.Errorf("wallet balance = %s, want %s", got, want)
+	}
+}
+
+func TestReverseTransactionCapsPartialReversals(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	sender, receiver := repo.addWallet("0"), repo.addWallet("1000")
+	transferID := repo.addTransaction(Transaction{
+		WalletID: sender,
+		Type:     TransactionTransfer,
+		Amount:   decimal.RequireFromString("1000"),
+		Fee:      decimal.RequireFromString("25"),
+		Metadata: map[string]interface{}{metaDirection: "debit", metaToWalletID: receiver},
+	})
+	s := newReversalTestService(repo, &stubRefunds{})
+
+	reverse := func(amount string) (*Reversal, error) {
+		return s.ReverseTransaction(ctx, ReversalForm{
+			TransactionID: transferID,
+			TenantID:      testTenant,
+			Amount:        decimal.RequireFromString(amount),
+		})
+	}
+
+	if _, err := reverse("600"); err != nil {
+		t.Fatalf("first partial reversal: %v", err)
+	}
+	repo.balance(t, sender, "600")
+	repo.balance(t, receiver, "400")
+
+	// Only 400 is left, and the fee is never given back
+	if _, err := reverse("400.01"); !errors.Is(err, model.ErrReversalExceedsOriginal) {
+		t.Fatalf("over-reversal error = %v, want %v", err, model.ErrReversalExceedsOriginal)
+	}
+	repo.balance(t, sender, "600")
+	repo.balance(t, receiver, "400")
+
+	if _, err := reverse("0.001"); !errors.Is(err, model.ErrInvalidInputError) {
+		t.Errorf("sub-kobo reversal error = %v, want %v", err, model.ErrInvalidInputError)
+	}
+
+	// A zero amount reverses whatever is left
+	rest, err := reverse("0")
+	if err != nil {
+		t.Fatalf("reversal of the rest: %v", err)
+	}
+	if !rest.Amount.Equal(decimal.RequireFromString("400")) || rest.Status != StatusCompleted {
+		t.Errorf("reversal of the rest = %s %s, want 400 completed", rest.Amount, rest.Status)
+	}
+	repo.balance(t, sender, "1000")
+	repo.balance(t, receiver, "0")
+
+	if _, err := reverse("0"); !errors.Is(err, model.ErrReversalExceedsOriginal) {
+		t.Errorf("reversal of a fully reversed transfer error = %v, want %v", err, model.ErrReversalExceedsOriginal)
+	}
+	if len(repo.reversals) != 2 {
+		t.Errorf("reversals recorded = %d, want 2", len(repo.reversals))
+	}
+}
+
+func TestReverseDepositExcludesFee(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	walletID := repo.addWallet("5000")
+	depositID := repo.addTransaction(Transaction{
+		WalletID: walletID,
+		Type:     TransactionDeposit,
+		Amount:   decimal.RequireFromString("1000"),
+		Fee:      decimal.RequireFromString("15"),
+	})
+	s := newReversalTestService(repo, &stubRefunds{})
+
+	_, err := s.ReverseTransaction(ctx, ReversalForm{
+		TransactionID: depositID,
+		TenantID:      testTenant,
+		Amount:        decimal.RequireFromString("1000"),
+	})
+	if !errors.Is(err, model.ErrReversalExceedsOriginal) {
+		t.Fatalf("reversal including the fee error = %v, want %v", err, model.ErrReversalExceedsOriginal)
+	}
+
+	reversal, err := s.ReverseTransaction(ctx, ReversalForm{TransactionID: depositID, TenantID: testTenant})
+	if err != nil {
+		t.Fatalf("full reversal: %v", err)
+	}
+	if !reversal.Amount.Equal(decimal.RequireFromString("985")) || reversal.Status != StatusCompleted {
+		t.Errorf("full reversal = %s %s, want 985 completed", reversal.Amount, reversal.Status)
+	}
+	repo.balance(t, walletID, "4015")
+}
+
+func TestReverseDepositRecordsRefundBeforeSettling(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	walletID := repo.addWallet("1000")
+	depositID := repo.addTransaction(Transaction{
+		WalletID: walletID,
+		Type:     TransactionDeposit,
+		Amount:   decimal.RequireFromString("1000"),
+		Fee:      decimal.Zero,
+		Provider: "provider-1",
+	})
+	refunds := &stubRefunds{}
+	s := newReversalTestService(repo, refunds)
+
+	// The refund goes out but the reversal cannot be settled
+	repo.finishFailures = 1
+	reversal, err := s.ReverseTransaction(ctx, ReversalForm{
+		TransactionID: depositID,
+		TenantID:      testTenant,
+		Amount:        decimal.RequireFromString("300"),
+	})
+	if err != nil {
+		t.Fatalf("ReverseTransaction: %v", err)
+	}
+	if reversal.Status != StatusPending {
+		t.Fatalf("reversal status = %s, want %s", reversal.Status, StatusPending)
+	}
+
+	want := []string{"CreateReversal", "RecordReversalRefund", "FinishReversal"}
+	if len(repo.calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", repo.calls, want)
+	}
+	for i := range want {
+		if repo.calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", repo.calls, want)
+		}
+	}
+
+	stored := repo.reversal(reversal.ID)
+	if stored.Status != StatusPending || stored.RefundStatus != RefundSubmitted || stored.RefundReference == "" {
+		t.Errorf("stored reversal = %s, refund %q %q, want pending with the refund recorded",
+			stored.Status, stored.RefundStatus, stored.RefundReference)
+	}
+	if debit := repo.txs[reversal.DebitTransactionID]; debit.Status != StatusPending {
+		t.Errorf("debit leg status = %s, want %s", debit.Status, StatusPending)
+	}
+	repo.balance(t, walletID, "700")
+
+	report, err := s.RetryPendingReversals(ctx, 0)
+	if err != nil {
+		t.Fatalf("RetryPendingReversals: %v", err)
+	}
+	if report.Checked != 1 || report.Completed != 1 || report.Errors != 0 {
+		t.Errorf("report = %+v, want 1 checked and completed", report)
+	}
+	if refunds.calls != 1 {
+		t.Errorf("refunds sent = %d, want 1", refunds.calls)
+	}
+	if stored := repo.reversal(reversal.ID); stored.Status != StatusCompleted || stored.RefundStatus != RefundSubmitted {
+		t.Errorf("retried reversal = %s, refund %q, want completed and submitted", stored.Status, stored.RefundStatus)
+	}
+	if debit := repo.txs[reversal.DebitTransactionID]; debit.Status != StatusCompleted {
+		t.Errorf("debit leg status after retry = %s, want %s", debit.Status, StatusCompleted)
+	}
+	repo.balance(t, walletID, "700")
+
+	// Nothing is left pending, so another run does nothing
+	report, err = s.RetryPendingReversals(ctx, 0)
+	if err != nil || report.Checked != 0 {
+		t.Errorf("second retry = %+v, %v, want nothing checked", report, err)
+	}
+}
+
+func TestRetryPendingReversals(t *testing.T) {
+	tests := []struct {
+		name string
+		// errs answers the refund during the reversal and then the retry
+		errs        []error
+		wantStatus  string
+		wantRefunds int
+		wantBalance string
+	}{
+		{
+			name:        "refund that never went out is sent on retry",
+			errs:        []error{errors.New("gateway timeout"), nil},
+			wantStatus:  StatusCompleted,
+			wantRefunds: 2,
+			wantBalance: "700",
+		},
+		{
+			name:        "refund refused on retry credits the wallet back",
+			errs:        []error{errors.New("gateway timeout"), errors.New("refund declined")},
+			wantStatus:  StatusFailed,
+			wantRefunds: 2,
+			wantBalance: "1000",
+		},
+		{
+			name:        "provider without refunds is left for a manual payout",
+			errs:        []error{errors.New("gateway timeout"), model.ErrUnsupportedProvider},
+			wantStatus:  StatusCompleted,
+			wantRefunds: 2,
+			wantBalance: "700",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemRepo()
+			walletID := repo.addWallet("1000")
+			depositID := repo.addTransaction(Transaction{
+				WalletID: walletID,
+				Type:     TransactionDeposit,
+				Amount:   decimal.RequireFromString("1000"),
+				Fee:      decimal.Zero,
+				Provider: "provider-1",
+			})
+			refunds := &stubRefunds{errs: tt.errs}
+			s := newReversalTestService(repo, refunds)
+
+			// The failed refund cannot be settled either, so it stays pending
+			repo.finishFailures = 1
+			reversal, err := s.ReverseTransaction(ctx, ReversalForm{
+				TransactionID: depositID,
+				TenantID:      testTenant,
+				Amount:        decimal.RequireFromString("300"),
+			})
+			if err != nil {
+				t.Fatalf("ReverseTransaction: %v", err)
+			}
+			if reversal.Status != StatusPending || repo.reversal(reversal.ID).RefundStatus != "" {
+				t.Fatalf("reversal = %s with refund %q, want pending with no refund",
+					reversal.Status, repo.reversal(reversal.ID).RefundStatus)
+			}
+
+			report, err := s.RetryPendingReversals(ctx, 0)
+			if err != nil {
+				t.Fatalf("RetryPendingReversals: %v", err)
+			}
+			if report.Checked != 1 || report.Errors != 0 {
+				t.Errorf("report = %+v, want 1 checked without errors", report)
+			}
+			if stored := repo.reversal(reversal.ID); stored.Status != tt.wantStatus {
+				t.Errorf("reversal status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+			if refunds.calls != tt.wantRefunds {
+				t.Errorf("refunds sent = %d, want %d", refunds.calls, tt.wantRefunds)
+			}
+			repo.balance(t, walletID, tt.wantBalance)
+		})
+	}
+}
+
+func TestRetryPendingReversalsSkipsClaimedReversals(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	repo.reversals = []Reversal{{
+		ID:            uuid.NewString(),
+		TransactionID: uuid.NewString(),
+		Status:        StatusPending,
+		UpdatedAt:     time.Now().Add(-time.Hour),
+	}}
+	refunds := &stubRefunds{}
+	s := newReversalTestService(repo, refunds)
+
+	// Another run claimed it a moment ago, so it is no longer stale
+	if _, err := repo.ClaimReversal(ctx, repo.reversals[0].ID, time.Now()); err != nil {
+		t.Fatalf("ClaimReversal: %v", err)
+	}
+	report, err := s.RetryPendingReversals(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("RetryPendingReversals: %v", err)
+	}
+	if report.Checked != 0 || refunds.calls != 0 {
+		t.Errorf("checked %d and sent %d refunds, want neither", report.Checked, refunds.calls)
+	}
+}
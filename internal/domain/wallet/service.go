@@ -5,15 +5,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/fees"
 	"codematic/internal/domain/provider"
 	"codematic/internal/domain/provider/gateways"
+	"codematic/internal/domain/tenants"
 	"codematic/internal/domain/user"
 	"codematic/internal/infrastructure/cache"
 	"codematic/internal/infrastructure/db"
 	dbsqlc "codematic/internal/infrastructure/db/sqlc"
 	"codematic/internal/infrastructure/events/kafka"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"codematic/internal/thirdparty/flutterwave"
+	"codematic/internal/thirdparty/paystack"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -28,11 +38,17 @@ type WalletService struct {
 
 	Provider provider.Service
 	User     user.Service
+	Fees     fees.Service
 
 	logger   *zap.Logger
 	Producer *kafka.KafkaProducer
 
 	Cache cache.WalletCacheStore
+	Audit audit.Service
+
+	// runTx replaces the database transaction of withTx when set, so tests
+	// can run the service against an in-memory Repository
+	runTx func(ctx context.Context, fn func(repo Repository) error) error
 }
 
 // NewService initializes and returns a new instance of the wallet service.
@@ -40,18 +56,22 @@ func NewService(
 	logger *zap.Logger,
 	Provider provider.Service,
 	User user.Service,
+	feeService fees.Service,
 	db *db.DBConn,
 	producer *kafka.KafkaProducer,
 	cacheStore cache.WalletCacheStore,
+	auditService audit.Service,
 ) Service {
 	return &WalletService{
 		DB:       db,
 		Repo:     NewRepository(db.Queries, db.Pool),
 		Provider: Provider,
 		User:     User,
+		Fees:     feeService,
 		logger:   logger,
 		Producer: producer,
 		Cache:    cacheStore,
+		Audit:    auditService,
 	}
 }
 
@@ -60,12 +80,16 @@ func (s *WalletService) WithTx(q *dbsqlc.Queries) Service {
 		DB:     s.DB,
 		Repo:   NewRepository(q, s.DB.Pool),
 		User:   s.User,
+		Fees:   s.Fees,
 		logger: s.logger,
 	}
 }
 
 // Transactional wrapper
 func (s *WalletService) withTx(ctx context.Context, fn func(repo Repository) error) error {
+	if s.runTx != nil {
+		return s.runTx(ctx, fn)
+	}
 	tx, err := s.DB.Pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return err
@@ -97,7 +121,24 @@ func (s *WalletService) InitiateDeposit(ctx context.Context, data DepositForm) (
 		return response, errors.New("amount must be positive")
 	}
 
-	err := s.withTx(ctx, func(repo Repository) error {
+	// Price the deposit on the provider it will be routed to
+	providerID, err := s.Provider.SelectDepositProvider(ctx, data.Currency, data.Channel)
+	if err != nil {
+		return response, err
+	}
+	quote, err := s.Fees.Quote(ctx, fees.QuoteInput{
+		TenantID:        data.TenantID,
+		TransactionType: fees.TypeDeposit,
+		Amount:          data.Amount,
+		Currency:        data.Currency,
+		Channel:         data.Channel,
+		ProviderID:      providerID,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	err = s.withTx(ctx, func(repo Repository) error {
 		// Check wallet existence
 		wallet, err := repo.GetWalletByUserAndCurrency(ctx, data.UserID, data.Currency)
 		if err != nil {
@@ -107,10 +148,11 @@ func (s *WalletService) InitiateDeposit(ctx context.Context, data DepositForm) (
 
 		// Call the provider service to initiate the payment first
 		providerReq := provider.DepositRequest{
-			Amount:   data.Amount,
-			Channel:  data.Channel,
-			Currency: data.Currency,
-			Metadata: data.Metadata,
+			Amount:     quote.Debit,
+			Channel:    data.Channel,
+			Currency:   data.Currency,
+			ProviderID: providerID,
+			Metadata:   data.Metadata,
 		}
 
 		gateway, err := s.Provider.InitiateDeposit(ctx, providerReq)
@@ -119,7 +161,7 @@ func (s *WalletService) InitiateDeposit(ctx context.Context, data DepositForm) (
 			return err
 		}
 
-		s.logger.Sugar().Infow("Gateway response", "response", fmt.Sprintf("%+v", gateway))
+		s.logger.Sugar().Infow("Gateway response", "reference", gateway.Reference)
 
 		transaction := &Transaction{
 			ID:           uuid.NewString(),
@@ -127,8 +169,8 @@ func (s *WalletService) InitiateDeposit(ctx context.Context, data DepositForm) (
 			Type:         TransactionDeposit,
 			TenantID:     data.TenantID,
 			Status:       StatusPending,
-			Amount:       data.Amount,
-			Fee:          decimal.Zero,
+			Amount:       quote.Debit,
+			Fee:          quote.Fee,
 			Provider:     gateway.ProviderID,
 			CurrencyCode: data.Currency,
 			Reference:    gateway.Reference,
@@ -145,7 +187,7 @@ func (s *WalletService) InitiateDeposit(ctx context.Context, data DepositForm) (
 			UserID:        data.UserID,
 			TransactionID: transaction.ID,
 			ExternalTxID:  gateway.Reference, // or use gateway.ProviderID if that's the txid
-			Amount:        data.Amount.InexactFloat64(),
+			Amount:        quote.Debit.InexactFloat64(),
 			Status:        StatusPending,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
@@ -168,15 +210,41 @@ func (s *WalletService) Withdraw(ctx context.Context, data WithdrawalForm) error
 	}
 
 	return s.withTx(ctx, func(repo Repository) error {
-		wallet, err := repo.GetWallet(ctx, data.WalletID)
+		// Nothing about the wallet is looked up before it is known to be the
+		// caller's, so other users' wallets are indistinguishable from missing
+		// ones
+		wallet, err := repo.LockWallet(ctx, data.WalletID)
 		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.ErrWalletNotFound
+			}
 			return err
 		}
-		if wallet.Balance.LessThan(data.Amount) {
+		if wallet.UserID != data.UserID || wallet.TenantID != data.TenantID {
+			return model.ErrWalletNotFound
+		}
+		if err := checkActive(wallet); err != nil {
+			return err
+		}
+
+		currency, err := repo.GetWalletCurrency(ctx, wallet.ID)
+		if err != nil {
+			return err
+		}
+		quote, err := s.Fees.Quote(ctx, fees.QuoteInput{
+			TenantID:        data.TenantID,
+			TransactionType: fees.TypeWithdrawal,
+			Amount:          data.Amount,
+			Currency:        currency,
+		})
+		if err != nil {
+			return err
+		}
+		if wallet.Balance.LessThan(quote.Debit) {
 			return errors.New("insufficient balance")
 		}
 
-		wallet.Balance = wallet.Balance.Sub(data.Amount)
+		wallet.Balance = wallet.Balance.Sub(quote.Debit)
 
 		if err := repo.UpdateWalletBalance(ctx, wallet.ID, wallet.Balance); err != nil {
 			return err
@@ -186,27 +254,35 @@ func (s *WalletService) Withdraw(ctx context.Context, data WithdrawalForm) error
 			_ = s.Cache.DeleteWalletTransactions(ctx, wallet.ID)
 		}
 
+		// Amount is what is paid out; the wallet is debited it plus Fee
 		tx := &Transaction{
-			ID:        uuid.NewString(),
-			WalletID:  wallet.ID,
-			Type:      TransactionWithdrawal,
-			Status:    StatusCompleted,
-			Amount:    data.Amount,
-			Fee:       decimal.Zero,
-			Provider:  data.Provider,
-			Reference: uuid.NewString(),
-			Metadata:  data.Metadata,
+			ID:           uuid.NewString(),
+			WalletID:     wallet.ID,
+			Type:         TransactionWithdrawal,
+			TenantID:     data.TenantID,
+			Status:       StatusCompleted,
+			CurrencyCode: currency,
+			Amount:       quote.Credit,
+			Fee:          quote.Fee,
+			Provider:     data.Provider,
+			Reference:    uuid.NewString(),
+			Metadata:     data.Metadata,
 		}
 		if err := repo.CreateTransaction(ctx, tx); err != nil {
 			return err
 		}
+		if quote.Fee.IsPositive() {
+			if err := repo.PostFee(ctx, tx.ID, tx.TenantID, currency, quote.Fee); err != nil {
+				return err
+			}
+		}
 
 		// Create withdrawal record
 		withdrawal := &Withdrawal{
 			UserID:        data.UserID,
 			TransactionID: tx.ID,
 			ExternalTxID:  tx.Reference,
-			Amount:        data.Amount.InexactFloat64(),
+			Amount:        quote.Credit.InexactFloat64(),
 			Status:        StatusCompleted,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
@@ -219,27 +295,72 @@ func (s *WalletService) Withdraw(ctx context.Context, data WithdrawalForm) error
 	})
 }
 
-func (s *WalletService) Transfer(ctx context.Context, data TransferForm) error {
+// Transfer moves money between two wallets of the same currency. The sender's
+// wallet is debited the amount plus the fee and the receiver's credited the
+// amount, each with its own transaction, and both are tied together by a
+// transfer record.
+func (s *WalletService) Transfer(ctx context.Context, data TransferForm) (*TransferDetails, error) {
 	if data.Amount.LessThanOrEqual(decimal.Zero) {
-		return errors.New("amount must be positive")
+		return nil, errors.New("amount must be positive")
+	}
+	if data.FromWalletID == data.ToWalletID {
+		return nil, errors.New("cannot transfer to the same wallet")
 	}
 
-	return s.withTx(ctx, func(repo Repository) error {
-		from, err := repo.GetWallet(ctx, data.FromWalletID)
+	transferID := uuid.NewString()
+	err := s.withTx(ctx, func(repo Repository) error {
+		// Both wallets are checked before anything else is looked up, so
+		// wallets the caller may not use are indistinguishable from missing
+		// ones, whatever their currency
+		wallets, err := lockWallets(ctx, repo, data.FromWalletID, data.ToWalletID)
 		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.ErrWalletNotFound
+			}
 			return err
 		}
-		if from.Balance.LessThan(data.Amount) {
-			return errors.New("insufficient balance")
+		from, to := wallets[data.FromWalletID], wallets[data.ToWalletID]
+		if from.UserID != data.UserID || from.TenantID != data.TenantID {
+			return model.ErrWalletNotFound
+		}
+		// Money never leaves the tenant
+		if to.TenantID != data.TenantID {
+			return model.ErrWalletNotFound
+		}
+		if err := checkActive(from); err != nil {
+			return err
+		}
+		if err := checkActive(to); err != nil {
+			return err
 		}
 
-		to, err := repo.GetWallet(ctx, data.ToWalletID)
+		currency, err := repo.GetWalletCurrency(ctx, from.ID)
 		if err != nil {
 			return err
 		}
+		toCurrency, err := repo.GetWalletCurrency(ctx, to.ID)
+		if err != nil {
+			return err
+		}
+		if toCurrency != currency {
+			return fmt.Errorf("%w: cannot transfer from a %s wallet to a %s wallet", model.ErrInvalidInputError,
+				currency, toCurrency)
+		}
+		quote, err := s.Fees.Quote(ctx, fees.QuoteInput{
+			TenantID:        data.TenantID,
+			TransactionType: fees.TypeTransfer,
+			Amount:          data.Amount,
+			Currency:        currency,
+		})
+		if err != nil {
+			return err
+		}
+		if from.Balance.LessThan(quote.Debit) {
+			return model.ErrInsufficientBalance
+		}
 
-		from.Balance = from.Balance.Sub(data.Amount)
-		to.Balance = to.Balance.Add(data.Amount)
+		from.Balance = from.Balance.Sub(quote.Debit)
+		to.Balance = to.Balance.Add(quote.Credit)
 
 		if err := repo.UpdateWalletBalance(ctx, from.ID, from.Balance); err != nil {
 			return err
@@ -254,21 +375,261 @@ func (s *WalletService) Transfer(ctx context.Context, data TransferForm) error {
 			_ = s.Cache.DeleteWalletTransactions(ctx, data.ToWalletID)
 		}
 
-		tx := &Transaction{
-			ID:        uuid.NewString(),
-			WalletID:  from.ID,
-			Type:      "transfer",
-			Status:    "success",
-			Amount:    data.Amount,
-			Fee:       decimal.Zero,
-			Provider:  "internal",
-			Reference: uuid.NewString(),
-			Metadata:  data.Metadata,
-		}
-		return repo.CreateTransaction(ctx, tx)
+		// Amount is what the receiver gets; the sender is debited it plus Fee
+		debit := &Transaction{
+			ID:           uuid.NewString(),
+			WalletID:     from.ID,
+			Type:         TransactionTransfer,
+			TenantID:     data.TenantID,
+			Status:       StatusCompleted,
+			CurrencyCode: currency,
+			Amount:       quote.Credit,
+			Fee:          quote.Fee,
+			Reference:    uuid.NewString(),
+			Metadata: transferMetadata(data.Metadata, transferID, "debit", map[string]interface{}{
+				metaToWalletID: to.ID,
+			}),
+		}
+		credit := &Transaction{
+			ID:           uuid.NewString(),
+			WalletID:     to.ID,
+			Type:         TransactionTransfer,
+			TenantID:     data.TenantID,
+			Status:       StatusCompleted,
+			CurrencyCode: currency,
+			Amount:       quote.Credit,
+			Fee:          decimal.Zero,
+			Reference:    uuid.NewString(),
+			Metadata: transferMetadata(data.Metadata, transferID, "credit", map[string]interface{}{
+				metaFromWallet: from.ID,
+			}),
+		}
+		if err := repo.CreateTransaction(ctx, debit); err != nil {
+			return err
+		}
+		if err := repo.CreateTransaction(ctx, credit); err != nil {
+			return err
+		}
+		if err := repo.CreateTransfer(ctx, &TransferDetails{
+			ID:           transferID,
+			TenantID:     data.TenantID,
+			CurrencyCode: currency,
+			Amount:       quote.Credit,
+			Fee:          quote.Fee,
+			Status:       StatusCompleted,
+			Sender:       TransferParty{WalletID: from.ID, TransactionID: debit.ID},
+			Receiver:     TransferParty{WalletID: to.ID, TransactionID: credit.ID},
+		}); err != nil {
+			return err
+		}
+		if quote.Fee.IsPositive() {
+			return repo.PostFee(ctx, debit.ID, debit.TenantID, currency, quote.Fee)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetTransfer(ctx, transferID)
+}
+
+// transferMetadata builds one leg's metadata: the caller's metadata, the
+// transfer it belongs to, which way it moves money and its counterparty
+func transferMetadata(metadata map[string]interface{}, transferID, direction string,
+	counterparty map[string]interface{}) map[string]interface{} {
+	leg := map[string]interface{}{}
+	for k, v := range metadata {
+		leg[k] = v
+	}
+	for k, v := range counterparty {
+		leg[k] = v
+	}
+	leg[metaTransferID] = transferID
+	leg[metaDirection] = direction
+	return leg
+}
+
+// GetTransfer returns a transfer with both of its parties
+func (s *WalletService) GetTransfer(ctx context.Context, id string) (*TransferDetails, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrTransferNotFound
+	}
+	transfer, err := s.Repo.GetTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrTransferNotFound
+		}
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// ResolveRecipient finds the user of the sender's tenant a P2P transfer would
+// be paid to and returns their masked name for the sender to confirm. The
+// recipient needs a wallet in the currency.
+func (s *WalletService) ResolveRecipient(ctx context.Context, tenantID, senderID string,
+	req ResolveRecipientRequest) (*Recipient, error) {
+	recipient, by, err := s.findRecipient(ctx, tenantID, senderID, req.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.recipientWallet(ctx, recipient, req.Currency); err != nil {
+		return nil, err
+	}
+
+	return &Recipient{
+		Recipient:  strings.TrimSpace(req.Recipient),
+		Type:       by,
+		MaskedName: maskName(recipient),
+		Currency:   req.Currency,
+	}, nil
+}
+
+// SendToUser transfers money from one of the sender's wallets to the
+// recipient's wallet in the same currency
+func (s *WalletService) SendToUser(ctx context.Context, data SendToUserForm) (*TransferDetails, error) {
+	recipient, by, err := s.findRecipient(ctx, data.TenantID, data.UserID, data.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	// Transfer checks the sender's wallet again under lock; this keeps other
+	// users' wallets from being probed for their currency
+	from, err := s.Repo.GetWallet(ctx, data.FromWalletID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrWalletNotFound
+		}
+		return nil, err
+	}
+	if from.UserID != data.UserID || from.TenantID != data.TenantID {
+		return nil, model.ErrWalletNotFound
+	}
+	currency, err := s.Repo.GetWalletCurrency(ctx, from.ID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.recipientWallet(ctx, recipient, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{}
+	for k, v := range data.Metadata {
+		metadata[k] = v
+	}
+	metadata[metaRecipientType] = by
+
+	return s.Transfer(ctx, TransferForm{
+		UserID:       data.UserID,
+		TenantID:     data.TenantID,
+		FromWalletID: data.FromWalletID,
+		ToWalletID:   to.ID,
+		Amount:       data.Amount,
+		Metadata:     metadata,
 	})
 }
 
+// phonePattern matches what looks like a phone number rather than a username
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 -]{5,}$`)
+
+// recipientType tells whether a recipient is an email, a phone number or a
+// username
+func recipientType(recipient string) string {
+	switch {
+	case strings.HasPrefix(recipient, "@"):
+		return RecipientByUsername
+	case strings.Contains(recipient, "@"):
+		return RecipientByEmail
+	case phonePattern.MatchString(recipient):
+		return RecipientByPhone
+	}
+	return RecipientByUsername
+}
+
+// findRecipient looks up the active user of the tenant a P2P transfer is
+// addressed to, reporting how they were identified
+func (s *WalletService) findRecipient(ctx context.Context, tenantID, senderID,
+	recipient string) (*dbsqlc.User, string, error) {
+	allowed, err := s.Repo.AllowsP2PTransfers(ctx, tenantID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !allowed {
+		return nil, "", model.ErrP2PTransfersDisabled
+	}
+
+	recipient = strings.TrimSpace(recipient)
+	by := recipientType(recipient)
+
+	var users []dbsqlc.User
+	switch by {
+	case RecipientByEmail:
+		u, err := s.User.GetUserByEmailAndTenantID(ctx, recipient, tenantID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", err
+		}
+		if err == nil {
+			users = append(users, u)
+		}
+	case RecipientByPhone:
+		users, err = s.User.ListTenantUsersByPhone(ctx, tenantID, recipient)
+		if err != nil {
+			return nil, "", err
+		}
+	default:
+		u, err := s.User.GetTenantUserByUsername(ctx, tenantID, strings.TrimPrefix(recipient, "@"))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", err
+		}
+		if err == nil {
+			users = append(users, u)
+		}
+	}
+
+	if len(users) > 1 {
+		return nil, "", fmt.Errorf("%w: more than one user has this phone number; use their email or username",
+			model.ErrInvalidInputError)
+	}
+	if len(users) == 0 || !users[0].IsActive.Bool {
+		return nil, "", model.ErrRecipientNotFound
+	}
+	if users[0].ID.String() == senderID {
+		return nil, "", fmt.Errorf("%w: you cannot send money to yourself", model.ErrInvalidInputError)
+	}
+	return &users[0], by, nil
+}
+
+func (s *WalletService) recipientWallet(ctx context.Context, recipient *dbsqlc.User,
+	currency string) (*Wallet, error) {
+	wallet, err := s.Repo.GetWalletByUserAndCurrency(ctx, recipient.ID.String(), currency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: they have no %s wallet", model.ErrRecipientNotFound, currency)
+		}
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// maskName shows only the first letter of each of a user's names, such as
+// "J*** D**", falling back to their username or the start of their email
+func maskName(u *dbsqlc.User) string {
+	parts := strings.Fields(u.FirstName.String + " " + u.LastName.String)
+	if len(parts) == 0 {
+		name := u.Username.String
+		if name == "" {
+			name, _, _ = strings.Cut(u.Email, "@")
+		}
+		parts = []string{name}
+	}
+	for i, part := range parts {
+		runes := []rune(part)
+		parts[i] = string(runes[:1]) + strings.Repeat("*", max(len(runes)-1, 2))
+	}
+	return strings.Join(parts, " ")
+}
+
 func (s *WalletService) CreateWalletForNewUser(ctx context.Context,
 	userID string) ([]*Wallet, error) {
 
@@ -341,7 +702,7 @@ func (s *WalletService) GetWalletTypeIDByCurrency(ctx context.Context,
 }
 
 func (s *WalletService) HandlePaystackKafkaEvent(ctx context.Context, key, value []byte) {
-	s.logger.Sugar().Infof("Received Paystack wallet event from Kafka. Key: %s, Value: %s", string(key), string(value))
+	s.logger.Sugar().Infof("Received Paystack wallet event from Kafka. Key: %s, Size: %d bytes", string(key), len(value))
 
 	// Parse the Paystack event payload
 	var event map[string]interface{}
@@ -371,55 +732,147 @@ func (s *WalletService) HandlePaystackKafkaEvent(ctx context.Context, key, value
 		return
 	}
 
-	if verifyResp.Status != "success" {
-		s.logger.Sugar().Errorf("Paystack transaction not successful: status=%s", verifyResp.Status)
-		return
-	}
-
 	// Find the transaction in our DB by reference
 	tx, err := s.Repo.GetTransactionByReference(ctx, reference)
 	if err != nil {
 		s.logger.Sugar().Errorf("No matching transaction for reference %s: %v", reference, err)
 		return
 	}
-	if tx.Status == StatusCompleted {
-		s.logger.Sugar().Infof("Transaction %s already completed", tx.ID)
-		return // idempotent
+
+	outcome, err := s.settleDeposit(ctx, tx, verifyResp)
+	if err != nil {
+		s.logger.Sugar().Errorf("Failed to settle deposit for reference %s: %v", reference, err)
+		return
 	}
+	s.logger.Sugar().Infof("Paystack event for reference %s: deposit %s", reference, outcome)
+}
+
+// Outcomes of settleDeposit
+const (
+	settleCompleted = "completed"
+	settleFailed    = "failed"
+	settleExpired   = "expired"
+	settleFlagged   = "flagged for review"
+	settleUnchanged = "unchanged"
+	settleOpen      = "still open"
+)
 
-	// Update wallet balance and mark transaction as completed
-	amount := decimal.NewFromInt(verifyResp.Amount).Div(decimal.NewFromInt(100))
+// settleDeposit applies a provider's verification result to a deposit. It is
+// the one code path that completes or fails deposits, whether triggered by a
+// webhook or by reconciliation, and is safe to call any number of times: the
+// status change is conditional, so only one caller ever credits the wallet.
+func (s *WalletService) settleDeposit(ctx context.Context, tx *Transaction,
+	verify *gateways.VerifyResponse) (string, error) {
+	switch verify.Status {
+	case gateways.VerifyStatusSuccess:
+		return s.completeDeposit(ctx, tx, verify)
+	case gateways.VerifyStatusFailed:
+		changed, err := s.closeDeposit(ctx, tx, StatusFailed, "payment failed at provider", StatusPending)
+		if err != nil || !changed {
+			return settleUnchanged, err
+		}
+		return settleFailed, nil
+	}
+	return settleOpen, nil
+}
 
-	err = s.withTx(ctx, func(repo Repository) error {
-		wallet, err := repo.GetWallet(ctx, tx.WalletID)
-		if err != nil {
+// completeDeposit credits a paid deposit if its tenant's policy accepts the
+// amount and currency paid, and otherwise flags it for review.
+func (s *WalletService) completeDeposit(ctx context.Context, tx *Transaction,
+	verify *gateways.VerifyResponse) (string, error) {
+	amount, problem, err := s.checkDepositAmount(ctx, tx, verify)
+	if err != nil {
+		return settleUnchanged, err
+	}
+	if problem != "" {
+		return s.flagDeposit(ctx, tx, verify, problem)
+	}
+
+	// A deposit expired by reconciliation is still credited if the customer
+	// paid after all
+	return s.creditDeposit(ctx, tx, amount, StatusPending, StatusExpired)
+}
+
+// checkDepositAmount returns the amount paid, or why the tenant's deposit
+// amount policy does not accept it. The amount must also cover the deposit's
+// fee, which is taken out of it.
+func (s *WalletService) checkDepositAmount(ctx context.Context, tx *Transaction,
+	verify *gateways.VerifyResponse) (decimal.Decimal, string, error) {
+	if !strings.EqualFold(verify.Currency, tx.CurrencyCode) {
+		return decimal.Zero, fmt.Sprintf("paid in %q, expected %s", verify.Currency, tx.CurrencyCode), nil
+	}
+
+	paid := verify.Amount.Round(utils.MinorUnits(tx.CurrencyCode))
+	if !paid.IsPositive() {
+		return decimal.Zero, fmt.Sprintf("paid %s %s", paid.String(), tx.CurrencyCode), nil
+	}
+	if !paid.GreaterThan(tx.Fee) {
+		return decimal.Zero, fmt.Sprintf("paid %s %s, which does not cover the %s fee", paid.String(),
+			tx.CurrencyCode, tx.Fee.String()), nil
+	}
+	if paid.Equal(tx.Amount) {
+		return paid, "", nil
+	}
+
+	policy, err := s.Repo.GetDepositAmountPolicy(ctx, tx.TenantID)
+	if err != nil {
+		return decimal.Zero, "", err
+	}
+	if paid.GreaterThan(tx.Amount) && policy == tenants.DepositPolicyAllowOverpayment ||
+		paid.LessThan(tx.Amount) && policy == tenants.DepositPolicyAllowPartial {
+		return paid, "", nil
+	}
+	return decimal.Zero, fmt.Sprintf("paid %s %s, expected %s", paid.String(), tx.CurrencyCode,
+		tx.Amount.String()), nil
+}
+
+// creditDeposit completes a deposit in one of from, paid with amount, and
+// credits its wallet with amount less the deposit's fee. Only the caller that
+// moves it out of from credits the wallet.
+func (s *WalletService) creditDeposit(ctx context.Context, tx *Transaction, amount decimal.Decimal,
+	from ...string) (string, error) {
+	credit := amount.Sub(tx.Fee)
+	completed := false
+	err := s.withTx(ctx, func(repo Repository) error {
+		changed, err := repo.TransitionTransaction(ctx, tx.ID, StatusCompleted, amount, "", from...)
+		if err != nil || !changed {
 			return err
 		}
 
-		wallet.Balance = wallet.Balance.Add(amount)
-		if err := repo.UpdateWalletBalance(ctx, wallet.ID, wallet.Balance); err != nil {
+		wallet, err := repo.LockWallet(ctx, tx.WalletID)
+		if err != nil {
 			return err
 		}
 
-		if err := repo.UpdateTransactionStatusAndAmount(ctx, tx.ID, StatusCompleted, amount); err != nil {
+		wallet.Balance = wallet.Balance.Add(credit)
+		if err := repo.UpdateWalletBalance(ctx, wallet.ID, wallet.Balance); err != nil {
 			return err
 		}
+		if tx.Fee.IsPositive() {
+			if err := repo.PostFee(ctx, tx.ID, tx.TenantID, tx.CurrencyCode, tx.Fee); err != nil {
+				return err
+			}
+		}
 
 		// Update deposit status to completed
 		if err := repo.UpdateDepositStatus(ctx, tx.ID, StatusCompleted); err != nil {
 			s.logger.Sugar().Errorf("Failed to update deposit status for transaction %s: %v", tx.ID, err)
 		}
+		completed = true
 		return nil
 	})
-	if err != nil {
-		s.logger.Sugar().Errorf("Failed to complete deposit for reference %s: %v", reference, err)
-		return
+	if err != nil || !completed {
+		return settleUnchanged, err
+	}
+
+	if s.Cache != nil {
+		_ = s.Cache.DeleteWalletTransactions(ctx, tx.WalletID)
 	}
 
 	eventt := DepositEvent{
 		TenantID:  tx.TenantID,
 		WalletID:  tx.WalletID,
-		Amount:    amount.String(),
+		Amount:    credit.String(),
 		Provider:  tx.Provider,
 		Metadata:  tx.Metadata,
 		Timestamp: time.Now(),
@@ -427,5 +880,749 @@ func (s *WalletService) HandlePaystackKafkaEvent(ctx context.Context, key, value
 	payload, _ := json.Marshal(eventt)
 	s.Producer.Publish(ctx, kafka.WalletDepositSuccessTopic, tx.TenantID, payload)
 
-	s.logger.Sugar().Infof("Deposit completed for reference %s, wallet %s, amount %s", reference, tx.WalletID, amount.String())
+	s.Audit.Record(ctx, audit.Entry{
+		TenantID:   tx.TenantID,
+		ActorRole:  audit.ActorSystem,
+		Action:     audit.ActionDepositComplete,
+		TargetType: audit.TargetTransaction,
+		TargetID:   tx.ID,
+		Metadata: map[string]any{
+			"wallet_id": tx.WalletID,
+			"amount":    amount.String(),
+			"fee":       tx.Fee.String(),
+			"credited":  credit.String(),
+			"reference": tx.Reference,
+			"provider":  tx.Provider,
+		},
+	})
+
+	s.logger.Sugar().Infof("Deposit completed for reference %s, wallet %s, amount %s, fee %s", tx.Reference,
+		tx.WalletID, amount.String(), tx.Fee.String())
+	return settleCompleted, nil
+}
+
+// flagDeposit holds a paid deposit for review instead of crediting it,
+// recording what was actually paid in its metadata.
+func (s *WalletService) flagDeposit(ctx context.Context, tx *Transaction,
+	verify *gateways.VerifyResponse, problem string) (string, error) {
+	review := map[string]interface{}{
+		metaPaidAmount:   verify.Amount.String(),
+		metaPaidCurrency: strings.ToUpper(verify.Currency),
+	}
+
+	flagged := false
+	err := s.withTx(ctx, func(repo Repository) error {
+		changed, err := repo.FlagTransaction(ctx, tx.ID, problem, review, StatusPending, StatusExpired)
+		if err != nil || !changed {
+			return err
+		}
+		flagged = true
+		return repo.UpdateDepositStatus(ctx, tx.ID, StatusFlagged)
+	})
+	if err != nil || !flagged {
+		return settleUnchanged, err
+	}
+
+	s.Audit.Record(ctx, audit.Entry{
+		TenantID:   tx.TenantID,
+		ActorRole:  audit.ActorSystem,
+		Action:     audit.ActionDepositFlag,
+		TargetType: audit.TargetTransaction,
+		TargetID:   tx.ID,
+		Metadata: map[string]any{
+			"wallet_id":     tx.WalletID,
+			"reference":     tx.Reference,
+			"reason":        problem,
+			"amount":        tx.Amount.String(),
+			"paid_amount":   verify.Amount.String(),
+			"paid_currency": verify.Currency,
+		},
+	})
+
+	s.logger.Sugar().Warnf("Deposit flagged for review for reference %s: %s", tx.Reference, problem)
+	return settleFlagged, nil
+}
+
+// ReviewDeposit settles a flagged deposit. Crediting adds what the customer
+// actually paid, so it needs the payment to be in the wallet's currency;
+// rejecting fails the deposit and leaves any refund to the caller. An empty
+// tenantID allows deposits of any tenant.
+func (s *WalletService) ReviewDeposit(ctx context.Context, id, tenantID string,
+	req ReviewDepositRequest) (*Transaction, error) {
+	tx, err := s.tenantTransaction(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Status != StatusFlagged {
+		return nil, model.ErrDepositNotFlagged
+	}
+
+	var outcome string
+	switch req.Decision {
+	case ReviewCredit:
+		paidCurrency, _ := tx.Metadata[metaPaidCurrency].(string)
+		if !strings.EqualFold(paidCurrency, tx.CurrencyCode) {
+			return nil, fmt.Errorf("%w: paid in %q but the wallet is %s; reject the deposit instead",
+				model.ErrInvalidInputError, paidCurrency, tx.CurrencyCode)
+		}
+		paidAmount, _ := tx.Metadata[metaPaidAmount].(string)
+		amount, err := decimal.NewFromString(paidAmount)
+		if err != nil || !amount.IsPositive() {
+			return nil, fmt.Errorf("%w: no paid amount to credit; reject the deposit instead",
+				model.ErrInvalidInputError)
+		}
+		amount = amount.Round(utils.MinorUnits(tx.CurrencyCode))
+		if !amount.GreaterThan(tx.Fee) {
+			return nil, fmt.Errorf("%w: paid amount does not cover the %s fee; reject the deposit instead",
+				model.ErrInvalidInputError, tx.Fee.String())
+		}
+		outcome, err = s.creditDeposit(ctx, tx, amount, StatusFlagged)
+		if err != nil {
+			return nil, err
+		}
+
+	case ReviewReject:
+		reason := "rejected on review"
+		if note := strings.TrimSpace(req.Note); note != "" {
+			reason += ": " + note
+		}
+		changed, err := s.closeDeposit(ctx, tx, StatusFailed, reason, StatusFlagged)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			outcome = settleFailed
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: decision must be %s or %s", model.ErrInvalidInputError,
+			ReviewCredit, ReviewReject)
+	}
+	if outcome != settleCompleted && outcome != settleFailed {
+		// Someone else reviewed it first
+		return nil, model.ErrDepositNotFlagged
+	}
+
+	return s.Repo.GetTransactionByID(ctx, id)
+}
+
+// tenantTransaction loads a transaction, treating one outside tenantID as
+// missing. An empty tenantID allows any tenant.
+func (s *WalletService) tenantTransaction(ctx context.Context, id, tenantID string) (*Transaction, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrTransactionNotFound
+	}
+	tx, err := s.Repo.GetTransactionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	if tenantID != "" && tx.TenantID != tenantID {
+		return nil, model.ErrTransactionNotFound
+	}
+	return tx, nil
+}
+
+// closeDeposit fails or expires a deposit in one of from, reporting whether it did
+func (s *WalletService) closeDeposit(ctx context.Context, tx *Transaction, status, reason string,
+	from ...string) (bool, error) {
+	closed := false
+	err := s.withTx(ctx, func(repo Repository) error {
+		changed, err := repo.TransitionTransaction(ctx, tx.ID, status, tx.Amount, reason, from...)
+		if err != nil || !changed {
+			return err
+		}
+		closed = true
+		return repo.UpdateDepositStatus(ctx, tx.ID, status)
+	})
+	if err != nil || !closed {
+		return false, err
+	}
+
+	action := audit.ActionDepositFail
+	if status == StatusExpired {
+		action = audit.ActionDepositExpire
+	}
+	s.Audit.Record(ctx, audit.Entry{
+		TenantID:   tx.TenantID,
+		ActorRole:  audit.ActorSystem,
+		Action:     action,
+		TargetType: audit.TargetTransaction,
+		TargetID:   tx.ID,
+		Metadata: map[string]any{
+			"wallet_id": tx.WalletID,
+			"reference": tx.Reference,
+			"reason":    reason,
+		},
+	})
+	return true, nil
+}
+
+// ReconcileDeposits settles deposits that have been pending for longer than
+// verifyAfter, in case their webhook never arrived: each is verified with its
+// provider and completed or failed as the webhook would have. Deposits the
+// customer abandoned are expired once older than expireAfter; ones the
+// provider is still processing are left alone.
+func (s *WalletService) ReconcileDeposits(ctx context.Context, verifyAfter,
+	expireAfter time.Duration) (*ReconcileReport, error) {
+	report := &ReconcileReport{StartedAt: time.Now()}
+	providerCodes := map[string]string{}
+
+	var after *Transaction
+	for {
+		txs, err := s.Repo.ListPendingDeposits(ctx, report.StartedAt.Add(-verifyAfter), after, reconcileBatchSize)
+		if err != nil {
+			return report, err
+		}
+
+		for i := range txs {
+			tx := &txs[i]
+			report.Checked++
+
+			outcome, err := s.reconcileDeposit(ctx, tx, providerCodes, report.StartedAt.Add(-expireAfter))
+			if err != nil {
+				if ctx.Err() != nil {
+					return report, ctx.Err()
+				}
+				s.logger.Error("Failed to reconcile deposit",
+					zap.String("reference", tx.Reference), zap.Error(err))
+				report.Errors++
+				report.ErrorRefs = append(report.ErrorRefs, tx.Reference)
+				continue
+			}
+
+			switch outcome {
+			case settleCompleted:
+				report.Completed++
+			case settleFailed:
+				report.Failed++
+			case settleExpired:
+				report.Expired++
+			case settleFlagged:
+				report.Flagged++
+			case settleOpen:
+				report.StillOpen++
+			}
+		}
+
+		if len(txs) < reconcileBatchSize {
+			break
+		}
+		after = &txs[len(txs)-1]
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	return report, nil
+}
+
+func (s *WalletService) reconcileDeposit(ctx context.Context, tx *Transaction,
+	providerCodes map[string]string, expireBefore time.Time) (string, error) {
+	code, ok := providerCodes[tx.Provider]
+	if !ok {
+		p, err := s.Provider.GetProviderByID(ctx, tx.Provider)
+		if err != nil {
+			return "", err
+		}
+		code = strings.ToLower(p.Code)
+		providerCodes[tx.Provider] = code
+	}
+
+	var verify *gateways.VerifyResponse
+	var err error
+	switch code {
+	case paystack.ProviderPaystack:
+		verify, err = s.Provider.VerifyPaystackTransaction(ctx, tx.Reference)
+	case flutterwave.ProviderFlutterwave:
+		verify, err = s.Provider.VerifyFlutterwaveTransaction(ctx, tx.Reference)
+	default:
+		return "", fmt.Errorf("provider %q cannot verify deposits", code)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if verify.Status == gateways.VerifyStatusAbandoned && tx.CreatedAt.Before(expireBefore) {
+		changed, err := s.closeDeposit(ctx, tx, StatusExpired, "abandoned by customer", StatusPending)
+		if err != nil || !changed {
+			return settleUnchanged, err
+		}
+		return settleExpired, nil
+	}
+
+	return s.settleDeposit(ctx, tx, verify)
+}
+
+// ReverseTransaction reverses form.Amount of a completed deposit, withdrawal
+// or transfer, or all that is left of it when Amount is zero, by posting
+// compensating transactions linked to the original, which is never changed.
+// Reversals never add up to more than the original moved between wallets, so
+// fees are not given back. A reversed deposit is refunded through its
+// provider when the provider supports refunds and is otherwise left for a
+// manual payout. An empty form.TenantID allows transactions of any tenant.
+func (s *WalletService) ReverseTransaction(ctx context.Context, form ReversalForm) (*Reversal, error) {
+	original, err := s.tenantTransaction(ctx, form.TransactionID, form.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if form.Amount.IsNegative() {
+		return nil, fmt.Errorf("%w: amount must be positive", model.ErrInvalidInputError)
+	}
+	units := utils.MinorUnits(original.CurrencyCode)
+	if !form.Amount.Equal(form.Amount.Round(units)) {
+		return nil, fmt.Errorf("%w: %s amounts have at most %d decimal places", model.ErrInvalidInputError,
+			original.CurrencyCode, units)
+	}
+
+	reversal := &Reversal{
+		ID:            uuid.NewString(),
+		TenantID:      original.TenantID,
+		TransactionID: original.ID,
+		CurrencyCode:  original.CurrencyCode,
+		Reason:        strings.TrimSpace(form.Reason),
+		CreatedBy:     form.UserID,
+	}
+	var wallets []string
+	err = s.withTx(ctx, func(repo Repository) error {
+		tx, err := repo.LockTransaction(ctx, original.ID)
+		if err != nil {
+			return err
+		}
+		if tx.Status != StatusCompleted {
+			return fmt.Errorf("%w: only completed transactions can be reversed, this one is %s",
+				model.ErrTransactionNotReversible, tx.Status)
+		}
+
+		reversed, err := repo.SumReversals(ctx, tx.ID)
+		if err != nil {
+			return err
+		}
+		remaining := reversibleAmount(tx).Sub(reversed)
+		if !remaining.IsPositive() {
+			return fmt.Errorf("%w: it has already been fully reversed", model.ErrReversalExceedsOriginal)
+		}
+		reversal.Amount = form.Amount
+		if reversal.Amount.IsZero() {
+			reversal.Amount = remaining
+		}
+		if reversal.Amount.GreaterThan(remaining) {
+			return fmt.Errorf("%w: only %s %s can still be reversed", model.ErrReversalExceedsOriginal,
+				remaining.String(), tx.CurrencyCode)
+		}
+
+		switch tx.Type {
+		case TransactionDeposit:
+			// The refund goes out once the wallet has given the money back
+			reversal.Status = StatusPending
+			wallets = []string{tx.WalletID}
+			reversal.DebitTransactionID, err = s.postReversalLeg(ctx, repo, tx, reversal, tx.WalletID,
+				false, StatusPending)
+		case TransactionWithdrawal:
+			reversal.Status = StatusCompleted
+			wallets = []string{tx.WalletID}
+			reversal.CreditTransactionID, err = s.postReversalLeg(ctx, repo, tx, reversal, tx.WalletID,
+				true, StatusCompleted)
+		case TransactionTransfer:
+			if direction, _ := tx.Metadata[metaDirection].(string); direction == "credit" {
+				return fmt.Errorf("%w: reverse the sender's transaction of the transfer instead",
+					model.ErrTransactionNotReversible)
+			}
+			toWalletID, _ := tx.Metadata[metaToWalletID].(string)
+			if toWalletID == "" {
+				return fmt.Errorf("%w: the transfer does not record who received it",
+					model.ErrTransactionNotReversible)
+			}
+			reversal.Status = StatusCompleted
+			wallets = []string{toWalletID, tx.WalletID}
+			if _, err := lockWallets(ctx, repo, wallets...); err != nil {
+				return err
+			}
+			reversal.DebitTransactionID, err = s.postReversalLeg(ctx, repo, tx, reversal, toWalletID,
+				false, StatusCompleted)
+			if err != nil {
+				return err
+			}
+			reversal.CreditTransactionID, err = s.postReversalLeg(ctx, repo, tx, reversal, tx.WalletID,
+				true, StatusCompleted)
+		default:
+			return fmt.Errorf("%w: %s transactions cannot be reversed", model.ErrTransactionNotReversible,
+				tx.Type)
+		}
+		if err != nil {
+			return err
+		}
+		return repo.CreateReversal(ctx, reversal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateWallets(ctx, wallets...)
+
+	var refundErr error
+	if original.Type == TransactionDeposit {
+		refundErr = s.refundDeposit(ctx, original, reversal)
+		if reversal.Status == StatusPending {
+			// The wallet has been debited; RetryPendingReversals settles it
+			s.logger.Sugar().Errorf("Reversal %s left pending for retry: %v", reversal.ID, refundErr)
+			return reversal, nil
+		}
+	}
+	s.publishReversal(ctx, original, reversal)
+
+	s.logger.Sugar().Infof("Reversal %s of transaction %s for %s %s is %s", reversal.ID, original.ID,
+		reversal.Amount.String(), reversal.CurrencyCode, reversal.Status)
+	return reversal, refundErr
+}
+
+// ListReversals returns the reversals of a transaction, oldest first. An
+// empty tenantID allows transactions of any tenant.
+func (s *WalletService) ListReversals(ctx context.Context, transactionID, tenantID string) ([]Reversal, error) {
+	tx, err := s.tenantTransaction(ctx, transactionID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.ListReversals(ctx, tx.ID)
+}
+
+// GetWallet returns a wallet, treating one outside tenantID as missing. An
+// empty tenantID allows any tenant.
+func (s *WalletService) GetWallet(ctx context.Context, id, tenantID string) (*Wallet, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrWalletNotFound
+	}
+	wallet, err := s.Repo.GetWallet(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrWalletNotFound
+		}
+		return nil, err
+	}
+	if tenantID != "" && wallet.TenantID != tenantID {
+		return nil, model.ErrWalletNotFound
+	}
+	return wallet, nil
+}
+
+// UpdateWalletStatus freezes, unfreezes or closes a wallet. Closing needs an
+// empty wallet and is final. An empty tenantID allows wallets of any tenant.
+func (s *WalletService) UpdateWalletStatus(ctx context.Context, id, tenantID string,
+	req WalletStatusRequest) (*WalletStatusChange, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, model.ErrWalletNotFound
+	}
+
+	var change *WalletStatusChange
+	err := s.withTx(ctx, func(repo Repository) error {
+		wallet, err := repo.LockWallet(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.ErrWalletNotFound
+			}
+			return err
+		}
+		if tenantID != "" && wallet.TenantID != tenantID {
+			return model.ErrWalletNotFound
+		}
+
+		switch {
+		case wallet.Status == WalletClosed && req.Status != WalletClosed:
+			return fmt.Errorf("%w: wallet is closed", model.ErrInvalidInputError)
+		case req.Status == WalletClosed && !wallet.Balance.IsZero():
+			return fmt.Errorf("%w: wallet still holds %s; empty it before closing",
+				model.ErrInvalidInputError, wallet.Balance.String())
+		}
+
+		if wallet.Status != req.Status {
+			if err := repo.UpdateWalletStatus(ctx, wallet.ID, req.Status); err != nil {
+				return err
+			}
+		}
+		change = &WalletStatusChange{
+			WalletID: wallet.ID,
+			TenantID: wallet.TenantID,
+			From:     wallet.Status,
+			To:       req.Status,
+			Reason:   req.Reason,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Sugar().Infof("Wallet %s status changed from %s to %s", change.WalletID, change.From, change.To)
+	return change, nil
+}
+
+// reversibleAmount is how much of a transaction moved between wallets and so
+// can be reversed: what a deposit credited, what a withdrawal paid out and
+// what a transfer's receiver got
+func reversibleAmount(tx *Transaction) decimal.Decimal {
+	if tx.Type == TransactionDeposit {
+		return tx.Amount.Sub(tx.Fee)
+	}
+	return tx.Amount
+}
+
+// checkActive rejects moving money in or out of a frozen or closed wallet
+func checkActive(w *Wallet) error {
+	if w.Status != WalletActive {
+		return fmt.Errorf("%w: wallet %s is %s", model.ErrWalletNotActive, w.ID, w.Status)
+	}
+	return nil
+}
+
+// lockWallets locks wallets in ascending ID order, so transactions locking
+// the same pair of wallets cannot deadlock
+func lockWallets(ctx context.Context, repo Repository, walletIDs ...string) (map[string]*Wallet, error) {
+	ids := append([]string(nil), walletIDs...)
+	sort.Strings(ids)
+
+	wallets := make(map[string]*Wallet, len(ids))
+	for _, id := range ids {
+		if _, ok := wallets[id]; ok {
+			continue
+		}
+		wallet, err := repo.LockWallet(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		wallets[id] = wallet
+	}
+	return wallets, nil
+}
+
+// postReversalLeg credits or debits a wallet with a reversal's amount and
+// records the compensating transaction, returning its ID
+func (s *WalletService) postReversalLeg(ctx context.Context, repo Repository, original *Transaction,
+	reversal *Reversal, walletID string, credit bool, status string) (string, error) {
+	wallet, err := repo.LockWallet(ctx, walletID)
+	if err != nil {
+		return "", err
+	}
+
+	metadata := map[string]interface{}{
+		metaReversalID:  reversal.ID,
+		metaReversalOf:  original.ID,
+		metaDescription: "Reversal of " + original.Reference,
+	}
+	if credit {
+		wallet.Balance = wallet.Balance.Add(reversal.Amount)
+		metadata[metaDirection] = "credit"
+	} else {
+		if wallet.Balance.LessThan(reversal.Amount) {
+			return "", fmt.Errorf("%w: wallet %s holds %s of the %s to reverse", model.ErrInsufficientBalance,
+				wallet.ID, wallet.Balance.String(), reversal.Amount.String())
+		}
+		wallet.Balance = wallet.Balance.Sub(reversal.Amount)
+		metadata[metaDirection] = "debit"
+	}
+	if err := repo.UpdateWalletBalance(ctx, wallet.ID, wallet.Balance); err != nil {
+		return "", err
+	}
+
+	leg := &Transaction{
+		ID:           uuid.NewString(),
+		WalletID:     wallet.ID,
+		Type:         TransactionReversal,
+		TenantID:     original.TenantID,
+		Status:       status,
+		CurrencyCode: original.CurrencyCode,
+		Amount:       reversal.Amount,
+		Fee:          decimal.Zero,
+		Provider:     original.Provider,
+		Reference:    uuid.NewString(),
+		Metadata:     metadata,
+	}
+	if err := repo.CreateTransaction(ctx, leg); err != nil {
+		return "", err
+	}
+	return leg.ID, nil
+}
+
+// refundDeposit sends a reversed deposit back through its provider and
+// settles the pending reversal: completed once the refund is submitted, or
+// marked for a manual payout when the provider cannot refund, and failed with
+// the wallet credited back when the provider refuses it. An accepted refund is
+// recorded before settling, so a reversal left pending because it could not be
+// settled is completed on retry without being refunded twice.
+func (s *WalletService) refundDeposit(ctx context.Context, original *Transaction, reversal *Reversal) error {
+	settled := *reversal
+	var refundErr error
+	if settled.RefundStatus == "" {
+		refund, err := s.Provider.RefundPayment(ctx, original.Provider, gateways.RefundRequest{
+			Reference: original.Reference,
+			Amount:    reversal.Amount,
+			Currency:  reversal.CurrencyCode,
+			Reason:    reversal.Reason,
+		})
+		switch {
+		case err == nil:
+			settled.RefundStatus = RefundSubmitted
+			settled.RefundReference = refund.Reference
+		case errors.Is(err, model.ErrUnsupportedProvider):
+			settled.RefundStatus = RefundManual
+		default:
+			s.logger.Sugar().Errorf("Refund of deposit %s failed: %v", original.Reference, err)
+			refundErr = err
+		}
+
+		if settled.RefundStatus != "" {
+			if err := s.Repo.RecordReversalRefund(ctx, &settled); err != nil {
+				s.logger.Sugar().Errorf("Failed to record refund %q of reversal %s: %v",
+					settled.RefundReference, settled.ID, err)
+				return err
+			}
+		}
+	}
+
+	if refundErr == nil {
+		settled.Status = StatusCompleted
+	} else {
+		settled.Status = StatusFailed
+		settled.Error = refundErr.Error()
+	}
+
+	err := s.withTx(ctx, func(repo Repository) error {
+		changed, err := repo.TransitionTransaction(ctx, settled.DebitTransactionID, settled.Status,
+			settled.Amount, settled.Error, StatusPending)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return fmt.Errorf("reversal %s debit is no longer pending", settled.ID)
+		}
+
+		if settled.Status == StatusFailed {
+			wallet, err := repo.LockWallet(ctx, original.WalletID)
+			if err != nil {
+				return err
+			}
+			wallet.Balance = wallet.Balance.Add(settled.Amount)
+			if err := repo.UpdateWalletBalance(ctx, wallet.ID, wallet.Balance); err != nil {
+				return err
+			}
+		}
+		return repo.FinishReversal(ctx, &settled)
+	})
+	if err != nil {
+		s.logger.Sugar().Errorf("Failed to settle reversal %s: %v", reversal.ID, err)
+		return err
+	}
+	*reversal = settled
+	s.invalidateWallets(ctx, original.WalletID)
+
+	if refundErr != nil {
+		return fmt.Errorf("%w: %v", model.ErrRefundFailed, refundErr)
+	}
+	return nil
+}
+
+// RetryPendingReversals settles deposit reversals left pending for longer
+// than retryAfter, such as when the refund went out but the reversal could not
+// be settled. A refund already recorded as accepted is not sent again. Each
+// reversal is claimed before it is retried, so overlapping runs never refund
+// it twice.
+func (s *WalletService) RetryPendingReversals(ctx context.Context,
+	retryAfter time.Duration) (*ReversalRetryReport, error) {
+	report := &ReversalRetryReport{StartedAt: time.Now()}
+	before := report.StartedAt.Add(-retryAfter)
+
+	for {
+		// Claimed reversals are no longer stale, so each batch is new
+		reversals, err := s.Repo.ListStaleReversals(ctx, before, reconcileBatchSize)
+		if err != nil {
+			return report, err
+		}
+
+		for i := range reversals {
+			reversal := &reversals[i]
+			claimed, err := s.Repo.ClaimReversal(ctx, reversal.ID, before)
+			if err != nil {
+				return report, err
+			}
+			if !claimed {
+				continue
+			}
+			report.Checked++
+
+			if err := s.retryReversal(ctx, reversal); err != nil && reversal.Status == StatusPending {
+				if ctx.Err() != nil {
+					return report, ctx.Err()
+				}
+				s.logger.Error("Failed to retry reversal", zap.String("reversal_id", reversal.ID), zap.Error(err))
+				report.Errors++
+				report.ErrorIDs = append(report.ErrorIDs, reversal.ID)
+				continue
+			}
+
+			switch reversal.Status {
+			case StatusCompleted:
+				report.Completed++
+			case StatusFailed:
+				report.Failed++
+			}
+		}
+
+		if len(reversals) < reconcileBatchSize {
+			break
+		}
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	return report, nil
+}
+
+// retryReversal settles one pending reversal. Only deposit reversals wait on
+// a refund; the others are settled when they are created.
+func (s *WalletService) retryReversal(ctx context.Context, reversal *Reversal) error {
+	original, err := s.Repo.GetTransactionByID(ctx, reversal.TransactionID)
+	if err != nil {
+		return err
+	}
+	if original.Type != TransactionDeposit {
+		return fmt.Errorf("%s reversals are never pending", original.Type)
+	}
+
+	err = s.refundDeposit(ctx, original, reversal)
+	if reversal.Status != StatusPending {
+		s.publishReversal(ctx, original, reversal)
+		s.logger.Sugar().Infof("Retried reversal %s of transaction %s is %s", reversal.ID, original.ID,
+			reversal.Status)
+	}
+	return err
+}
+
+func (s *WalletService) invalidateWallets(ctx context.Context, walletIDs ...string) {
+	if s.Cache == nil {
+		return
+	}
+	for _, id := range walletIDs {
+		_ = s.Cache.DeleteWalletBalance(ctx, id)
+		_ = s.Cache.DeleteWalletTransactions(ctx, id)
+	}
+}
+
+func (s *WalletService) publishReversal(ctx context.Context, original *Transaction, reversal *Reversal) {
+	if s.Producer == nil {
+		return
+	}
+	event := ReversalEvent{
+		Event:           "transaction.reversal",
+		TenantID:        reversal.TenantID,
+		ReversalID:      reversal.ID,
+		TransactionID:   original.ID,
+		TransactionType: original.Type,
+		Amount:          reversal.Amount.String(),
+		Currency:        reversal.CurrencyCode,
+		Status:          reversal.Status,
+		RefundStatus:    reversal.RefundStatus,
+		Reason:          reversal.Reason,
+		Timestamp:       time.Now(),
+	}
+	payload, _ := json.Marshal(event)
+	if err := s.Producer.Publish(ctx, kafka.WalletReversalTopic, reversal.TenantID, payload); err != nil {
+		s.logger.Sugar().Errorf("Failed to publish reversal %s: %v", reversal.ID, err)
+	}
 }
@@ -0,0 +1,204 @@
+package wallet
+
+import (
+	"codematic/internal/domain/fees"
+	"codematic/internal/shared/model"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func (r *memRepo) GetWalletCurrency(ctx context.Context, walletID string) (string, error) {
+	r.calls = append(r.calls, "GetWalletCurrency")
+	currency, ok := r.currencies[walletID]
+	if !ok {
+		return "", pgx.ErrNoRows
+	}
+	return currency, nil
+}
+
+func (r *memRepo) CreateTransfer(ctx context.Context, transfer *TransferDetails) error {
+	r.transfers = append(r.transfers, *transfer)
+	return nil
+}
+
+func (r *memRepo) GetTransfer(ctx context.Context, id string) (*TransferDetails, error) {
+	for i := range r.transfers {
+		if r.transfers[i].ID == id {
+			return &r.transfers[i], nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *memRepo) CreateWithdrawal(ctx context.Context, withdrawal *Withdrawal) error {
+	return nil
+}
+
+func (r *memRepo) PostFee(ctx context.Context, transactionID, tenantID, currency string, amount decimal.Decimal) error {
+	return nil
+}
+
+// flatFee charges the payer a flat fee and counts the quotes it gives
+type flatFee struct {
+	fees.Service
+	fee    decimal.Decimal
+	quotes int
+}
+
+func (f *flatFee) Quote(ctx context.Context, in fees.QuoteInput) (*fees.Quote, error) {
+	f.quotes++
+	return &fees.Quote{
+		TransactionType: in.TransactionType,
+		Currency:        in.Currency,
+		Amount:          in.Amount,
+		Fee:             f.fee,
+		Bearer:          fees.BearerPayer,
+		Debit:           in.Amount.Add(f.fee),
+		Credit:          in.Amount,
+	}, nil
+}
+
+func newTransferTestService(repo *memRepo, pricing *flatFee) *WalletService {
+	return &WalletService{
+		Repo:   repo,
+		Fees:   pricing,
+		logger: zap.NewNop(),
+		runTx:  repo.runTx,
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	const (
+		sender      = "user-1"
+		otherUser   = "user-2"
+		otherTenant = "tenant-2"
+	)
+	tests := []struct {
+		name string
+		// The sender owns an active NGN wallet in testTenant and sends to an
+		// active NGN wallet there unless these say otherwise
+		fromOwner, fromStatus          string
+		toTenant, toCurrency, toStatus string
+		wantErr                        error
+	}{
+		{name: "moves the amount and charges the fee"},
+		{name: "someone else's wallet is missing", fromOwner: otherUser, toCurrency: "USD", wantErr: model.ErrWalletNotFound},
+		{name: "another tenant's recipient is missing", toTenant: otherTenant, toCurrency: "USD", wantErr: model.ErrWalletNotFound},
+		{name: "frozen sender", fromStatus: WalletFrozen, wantErr: model.ErrWalletNotActive},
+		{name: "closed sender", fromStatus: WalletClosed, wantErr: model.ErrWalletNotActive},
+		{name: "frozen recipient", toStatus: WalletFrozen, wantErr: model.ErrWalletNotActive},
+		{name: "closed recipient", toStatus: WalletClosed, wantErr: model.ErrWalletNotActive},
+		{name: "different currencies", toCurrency: "USD", wantErr: model.ErrInvalidInputError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			or := func(v, fallback string) string {
+				if v == "" {
+					return fallback
+				}
+				return v
+			}
+			repo := newMemRepo()
+			from := repo.addUserWallet(or(tt.fromOwner, sender), testTenant, "NGN", "1000",
+				or(tt.fromStatus, WalletActive))
+			to := repo.addUserWallet(otherUser, or(tt.toTenant, testTenant), or(tt.toCurrency, "NGN"), "0",
+				or(tt.toStatus, WalletActive))
+			pricing := &flatFee{fee: decimal.RequireFromString("10")}
+			s := newTransferTestService(repo, pricing)
+
+			transfer, err := s.Transfer(context.Background(), TransferForm{
+				UserID:       sender,
+				TenantID:     testTenant,
+				FromWalletID: from,
+				ToWalletID:   to,
+				Amount:       decimal.RequireFromString("500"),
+			})
+
+			// Wallets the sender may not use are never priced or looked up
+			lookups := len(repo.calls) > 0 || pricing.quotes > 0
+			if tt.wantErr == nil || tt.wantErr == model.ErrInvalidInputError {
+				if !lookups {
+					t.Errorf("currencies were not looked up")
+				}
+			} else if lookups {
+				t.Errorf("looked up %v and priced %d quotes before rejecting the transfer", repo.calls, pricing.quotes)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Transfer error = %v, want %v", err, tt.wantErr)
+				}
+				repo.balance(t, from, "1000")
+				repo.balance(t, to, "0")
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transfer: %v", err)
+			}
+			if !transfer.Amount.Equal(decimal.RequireFromString("500")) || !transfer.Fee.Equal(decimal.RequireFromString("10")) {
+				t.Errorf("transfer = %s with fee %s, want 500 with fee 10", transfer.Amount, transfer.Fee)
+			}
+			repo.balance(t, from, "490")
+			repo.balance(t, to, "500")
+		})
+	}
+}
+
+func TestWithdrawRejectsUnusableWallets(t *testing.T) {
+	tests := []struct {
+		name    string
+		owner   string
+		tenant  string
+		status  string
+		wantErr error
+	}{
+		{"someone else's wallet", "user-2", testTenant, WalletActive, model.ErrWalletNotFound},
+		{"another tenant's wallet", "user-1", "tenant-2", WalletActive, model.ErrWalletNotFound},
+		{"frozen wallet", "user-1", testTenant, WalletFrozen, model.ErrWalletNotActive},
+		{"closed wallet", "user-1", testTenant, WalletClosed, model.ErrWalletNotActive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemRepo()
+			walletID := repo.addUserWallet(tt.owner, tt.tenant, "NGN", "1000", tt.status)
+			pricing := &flatFee{}
+			s := newTransferTestService(repo, pricing)
+
+			err := s.Withdraw(context.Background(), WithdrawalForm{
+				UserID:   "user-1",
+				TenantID: testTenant,
+				WalletID: walletID,
+				Amount:   decimal.RequireFromString("100"),
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Withdraw error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.calls) > 0 || pricing.quotes > 0 {
+				t.Errorf("looked up %v and priced %d quotes before rejecting the withdrawal", repo.calls, pricing.quotes)
+			}
+			repo.balance(t, walletID, "1000")
+		})
+	}
+}
+
+func TestWithdraw(t *testing.T) {
+	repo := newMemRepo()
+	walletID := repo.addUserWallet("user-1", testTenant, "NGN", "1000", WalletActive)
+	s := newTransferTestService(repo, &flatFee{fee: decimal.RequireFromString("25")})
+
+	err := s.Withdraw(context.Background(), WithdrawalForm{
+		UserID:   "user-1",
+		TenantID: testTenant,
+		WalletID: walletID,
+		Amount:   decimal.RequireFromString("100"),
+	})
+	if err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	repo.balance(t, walletID, "875")
+}
@@ -17,6 +17,8 @@ type Service interface {
 		headers map[string]string,
 		payload []byte,
 	) error
+	// DeliverTenantEvent posts an event to the tenant's webhook URL
+	DeliverTenantEvent(ctx context.Context, tenantID, eventType string, payload []byte) error
 }
 
 type Repository interface {
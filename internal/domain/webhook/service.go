@@ -91,8 +91,6 @@ func (s *service) handlePaystackEvent(
 	event map[string]interface{},
 	rawPayload []byte,
 ) error {
-	s.logger.Sugar().Infof("Paystack event received: %+v", event)
-
 	key := ""
 	if evt, ok := event["event"]; ok {
 		if str, ok := evt.(string); ok {
@@ -100,6 +98,8 @@ func (s *service) handlePaystackEvent(
 		}
 	}
 
+	s.logger.Sugar().Infof("Paystack event received: %s", key)
+
 	// Emit event to Kafka for wallet service to process
 	return s.Producer.Publish(ctx, kafka.PaystackWalletEventTopic, key, rawPayload)
 }
@@ -109,7 +109,7 @@ func (s *service) handleFlutterwaveEvent(
 	event map[string]interface{},
 	rawPayload []byte,
 ) error {
-	s.logger.Sugar().Infof("Flutterwave event received: %+v", event)
+	s.logger.Sugar().Infof("Flutterwave event received: %v", event["event"])
 
 	return nil
 }
@@ -164,6 +164,56 @@ func getHeader(headers map[string]string, key string) string {
 	return ""
 }
 
+// DeliverTenantEvent posts an event to the tenant's webhook URL, recording
+// the outgoing delivery. Tenants without a webhook URL are skipped.
+func (s *service) DeliverTenantEvent(ctx context.Context, tenantID, eventType string,
+	payload []byte) error {
+	tenant, err := s.tenantService.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("get tenant for webhook: %w", err)
+	}
+	if tenant.WebhookURL == "" {
+		s.logger.Sugar().Warnf("No webhook URL for tenant %s", tenantID)
+		return nil
+	}
+
+	// Save outgoing webhook event to DB
+	webhookEvent := &WebhookEvent{
+		ID:         uuid.NewString(),
+		TenantID:   tenantID,
+		EventType:  eventType,
+		Payload:    payload,
+		Status:     "pending",
+		Attempts:   0,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		IsOutgoing: true,
+	}
+	if err := s.Repo.Create(ctx, webhookEvent); err != nil {
+		return fmt.Errorf("save outgoing webhook event: %w", err)
+	}
+
+	// Send HTTP POST to tenant webhook URL
+	resp, err := http.Post(tenant.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		webhookEvent.Status = "failed"
+		webhookEvent.Attempts++
+		webhookEvent.UpdatedAt = time.Now()
+		s.Repo.Create(ctx, webhookEvent)
+		return fmt.Errorf("send webhook to tenant: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		webhookEvent.Status = "success"
+	} else {
+		webhookEvent.Status = "failed"
+	}
+	webhookEvent.Attempts++
+	webhookEvent.UpdatedAt = time.Now()
+	s.Repo.Create(ctx, webhookEvent)
+	return nil
+}
+
 func (s *service) StartWalletDepositSuccessConsumer(ctx context.Context,
 	broker string) {
 	go func() {
@@ -171,7 +221,7 @@ func (s *service) StartWalletDepositSuccessConsumer(ctx context.Context,
 		groupID := "webhook-wallet-deposit-success-group"
 
 		err := kafka.Subscribe(ctx, broker, kafka.WalletDepositSuccessTopic, groupID, func(key, value []byte) {
-			s.logger.Sugar().Infof("Received wallet deposit success event: %s", string(value))
+			s.logger.Sugar().Infof("Received wallet deposit success event (%d bytes)", len(value))
 
 			// Parse event
 			var event event
@@ -180,54 +230,9 @@ func (s *service) StartWalletDepositSuccessConsumer(ctx context.Context,
 				return
 			}
 
-			// Get tenant webhook URL
-			tenant, err := s.tenantService.GetTenantByID(ctx, event.TenantID)
-			if err != nil {
-				s.logger.Sugar().Errorf("Failed to get tenant for webhook: %v", err)
-				return
-			}
-			if tenant.WebhookURL == "" {
-				s.logger.Sugar().Warnf("No webhook URL for tenant %s", event.TenantID)
-				return
-			}
-
-			// Save outgoing webhook event to DB
-			webhookEvent := &WebhookEvent{
-				ID:         uuid.NewString(),
-				TenantID:   event.TenantID,
-				EventType:  "wallet.deposit.success",
-				Payload:    value,
-				Status:     "pending",
-				Attempts:   0,
-				CreatedAt:  time.Now(),
-				UpdatedAt:  time.Now(),
-				IsOutgoing: true,
-			}
-			err = s.Repo.Create(ctx, webhookEvent)
-			if err != nil {
-				s.logger.Sugar().Errorf("Failed to save outgoing webhook event: %v", err)
-				return
-			}
-
-			// Send HTTP POST to tenant webhook URL
-			resp, err := http.Post(tenant.WebhookURL, "application/json", bytes.NewReader(value))
-			if err != nil {
-				s.logger.Sugar().Errorf("Failed to send webhook to tenant: %v", err)
-				webhookEvent.Status = "failed"
-				webhookEvent.Attempts++
-				webhookEvent.UpdatedAt = time.Now()
-				s.Repo.Create(ctx, webhookEvent)
-				return
-			}
-			defer resp.Body.Close()
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				webhookEvent.Status = "success"
-			} else {
-				webhookEvent.Status = "failed"
+			if err := s.DeliverTenantEvent(ctx, event.TenantID, "wallet.deposit.success", value); err != nil {
+				s.logger.Sugar().Errorf("Failed to deliver deposit webhook: %v", err)
 			}
-			webhookEvent.Attempts++
-			webhookEvent.UpdatedAt = time.Now()
-			s.Repo.Create(ctx, webhookEvent)
 		})
 		if err != nil {
 			s.logger.Sugar().Errorf("Failed to subscribe to wallet deposit success events: %v", err)
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"codematic/internal/domain/apikeys"
+	"codematic/internal/domain/audit"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type APIKeys struct {
+	service apikeys.Service
+	env     *Environment
+}
+
+func (h *APIKeys) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.APIKeys
+
+	// Key management is JWT only; an API key cannot mint or revoke other keys
+	group := env.Fiber.Group(basePath + "/api-keys")
+	protected := group.Use(
+		middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
+		middleware.RequireTenant(),
+		middleware.RequirePermission(model.PermAPIKeysManage),
+	)
+
+	protected.Post("/", h.Create)
+	protected.Get("/", h.List)
+	protected.Post("/:id/roll", h.Roll)
+	protected.Delete("/:id", h.Revoke)
+
+	return nil
+}
+
+// Create godoc
+// @Summary      Create an API key
+// @Description  Creates a publishable or secret API key for the caller's tenant. Callers can only grant scopes they hold as permissions. The plaintext key is only returned once.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        body  body      apikeys.CreateAPIKeyRequest  true  "API key payload"
+// @Success      201   {object}  apikeys.CreatedAPIKey
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Router       /api-keys [post]
+func (h *APIKeys) Create(c *fiber.Ctx) error {
+	var req apikeys.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if !canGrant(c, req.Scopes) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+	userID := utils.ExtractUserIDFromJWT(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	key, err := h.service.CreateKey(ctx, tenantID, userID, req)
+	if err != nil {
+		h.env.Logger.Error("Failed to create api key", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionAPIKeyCreate,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   key.ID,
+		After:      key.APIKey,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, key)
+}
+
+// List godoc
+// @Summary      List API keys
+// @Description  Lists API keys for the caller's tenant. Secrets are never returned.
+// @Tags         api-keys
+// @Produce      json
+// @Success      200  {array}   apikeys.APIKey
+// @Failure      500  {object}  model.ErrorResponse
+// @Router       /api-keys [get]
+func (h *APIKeys) List(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	keys, err := h.service.ListKeys(ctx, utils.ExtractTenantFromJWT(c))
+	if err != nil {
+		h.env.Logger.Error("Failed to list api keys", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, keys)
+}
+
+// Roll godoc
+// @Summary      Roll an API key
+// @Description  Issues a replacement key with the same settings and revokes the old one, optionally after a grace period.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                     true   "API key ID"
+// @Param        body  body      apikeys.RollAPIKeyRequest  false  "Roll options"
+// @Success      201   {object}  apikeys.CreatedAPIKey
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Router       /api-keys/{id}/roll [post]
+func (h *APIKeys) Roll(c *fiber.Ctx) error {
+	var req apikeys.RollAPIKeyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+				model.ErrInvalidInputError.Error())
+		}
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// The replacement keeps the old key's scopes, so the caller must hold them
+	current, err := h.service.GetKey(ctx, utils.ExtractTenantFromJWT(c), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to get api key", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get api key")
+	}
+	if !canGrant(c, current.Scopes) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	key, err := h.service.RollKey(ctx, utils.ExtractTenantFromJWT(c), c.Params("id"),
+		utils.ExtractUserIDFromJWT(c), req)
+	if err != nil {
+		h.env.Logger.Error("Failed to roll api key", zap.Error(err))
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionAPIKeyRoll,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   c.Params("id"),
+		After:      key.APIKey,
+		Metadata:   map[string]any{"replacement_id": key.ID},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, key)
+}
+
+// Revoke godoc
+// @Summary      Revoke an API key
+// @Description  Revokes an API key immediately
+// @Tags         api-keys
+// @Produce      json
+// @Param        id   path      string  true  "API key ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /api-keys/{id} [delete]
+func (h *APIKeys) Revoke(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.RevokeKey(ctx, utils.ExtractTenantFromJWT(c), c.Params("id")); err != nil {
+		h.env.Logger.Error("Failed to revoke api key", zap.Error(err))
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionAPIKeyRevoke,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   c.Params("id"),
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
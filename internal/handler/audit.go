@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"bufio"
+	"codematic/internal/domain/audit"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Audit struct {
+	service audit.Service
+	env     *Environment
+}
+
+func (h *Audit) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.Audit
+
+	group := env.Fiber.Group(basePath + "/audit-logs")
+	protected := group.Use(
+		middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
+		middleware.RequirePermission(model.PermAuditRead),
+	)
+
+	protected.Get("/", h.List)
+	protected.Get("/export", h.Export)
+
+	return nil
+}
+
+// List godoc
+// @Summary      Search audit logs
+// @Description  Lists audit logs newest first. Tenant staff see their own tenant; platform admins see every tenant and may filter by tenant_id. action matches exactly or by area (e.g. "tenant" matches "tenant.update").
+// @Tags         audit
+// @Produce      json
+// @Param        tenant_id    query     string  false  "Tenant ID (platform admins only)"
+// @Param        actor_id     query     string  false  "Acting user ID"
+// @Param        action       query     string  false  "Action or action area"
+// @Param        target_type  query     string  false  "Target type, e.g. user or wallet"
+// @Param        target_id    query     string  false  "Target ID"
+// @Param        from         query     string  false  "From (RFC3339, inclusive)"
+// @Param        to           query     string  false  "To (RFC3339, exclusive)"
+// @Param        limit        query     int     false  "Limit (default 50, max 200)"
+// @Param        offset       query     int     false  "Offset"
+// @Success      200          {object}  audit.LogList
+// @Failure      400          {object}  model.ErrorResponse
+// @Failure      403          {object}  model.ErrorResponse
+// @Router       /audit-logs [get]
+func (h *Audit) List(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return sendAuditFilterError(c, err)
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.Query("limit", "50"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset", "0"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	logs, err := h.service.List(ctx, filter)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInputError) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to list audit logs", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list audit logs")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, logs)
+}
+
+// Export godoc
+// @Summary      Export audit logs as CSV
+// @Description  Streams the audit logs matching the same filters as the search, newest first, up to 50,000 rows
+// @Tags         audit
+// @Produce      text/csv
+// @Param        tenant_id    query     string  false  "Tenant ID (platform admins only)"
+// @Param        actor_id     query     string  false  "Acting user ID"
+// @Param        action       query     string  false  "Action or action area"
+// @Param        target_type  query     string  false  "Target type"
+// @Param        target_id    query     string  false  "Target ID"
+// @Param        from         query     string  false  "From (RFC3339, inclusive)"
+// @Param        to           query     string  false  "To (RFC3339, exclusive)"
+// @Success      200          {string}  string  "CSV file"
+// @Failure      400          {object}  model.ErrorResponse
+// @Failure      403          {object}  model.ErrorResponse
+// @Router       /audit-logs/export [get]
+func (h *Audit) Export(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return sendAuditFilterError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition,
+		`attachment; filename="audit-logs-`+time.Now().UTC().Format("20060102-150405")+`.csv"`)
+
+	service, logger := h.service, h.env.Logger
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		if err := service.ExportCSV(ctx, filter, w); err != nil {
+			logger.Error("Failed to export audit logs", zap.Error(err))
+		}
+		_ = w.Flush()
+	})
+
+	return nil
+}
+
+// auditFilter reads the search filters, pinning tenant staff to their tenant
+func auditFilter(c *fiber.Ctx) (audit.Filter, error) {
+	filter := audit.Filter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+
+	if utils.ExtractUserRoleFromJWT(c) == model.RolePlatformAdmin.String() {
+		filter.TenantID = c.Query("tenant_id")
+	} else {
+		filter.TenantID = utils.ExtractTenantFromJWT(c)
+		if filter.TenantID == "" {
+			return audit.Filter{}, model.ErrInsufficientPermission
+		}
+	}
+
+	for param, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return audit.Filter{}, errors.New(param + " must be an RFC3339 timestamp")
+		}
+		*dst = &t
+	}
+
+	return filter, nil
+}
+
+func sendAuditFilterError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, model.ErrInsufficientPermission) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+	}
+	return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+}
+
+// recordAudit records an action performed by the caller of c, filling in the
+// actor, tenant and client details the entry leaves empty. Actions taken with
+// an impersonation token also note the impersonating admin.
+func recordAudit(env *Environment, c *fiber.Ctx, entry audit.Entry) {
+	if entry.ActorID == "" {
+		entry.ActorID = utils.ExtractUserIDFromJWT(c)
+		if entry.ActorRole == "" {
+			entry.ActorRole = utils.ExtractUserRoleFromJWT(c)
+		}
+	}
+	if entry.TenantID == "" {
+		entry.TenantID = utils.ExtractTenantFromJWT(c)
+	}
+	entry.IPAddress = c.IP()
+	entry.UserAgent = c.Get(fiber.HeaderUserAgent)
+
+	if impersonatorID := utils.ExtractImpersonatorFromJWT(c); impersonatorID != "" {
+		metadata := make(map[string]any, len(entry.Metadata)+1)
+		for k, v := range entry.Metadata {
+			metadata[k] = v
+		}
+		metadata["impersonator_id"] = impersonatorID
+		entry.Metadata = metadata
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	env.Services.Audit.Record(ctx, entry)
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"codematic/internal/domain/audit"
 	"codematic/internal/domain/auth"
 	"codematic/internal/middleware"
 	"codematic/internal/shared/model"
 	"codematic/internal/shared/utils"
 	"context"
+	"errors"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,22 +27,62 @@ func (h *Auth) Init(basePath string, env *Environment) error {
 
 	h.service = env.Services.Auth
 
+	cfg := env.Config
+	ipRule := middleware.RateLimitRule{Name: "login_ip", Limit: cfg.RateLimitLoginIP, Key: middleware.KeyByIP}
+	emailRule := middleware.RateLimitRule{Name: "login_email", Limit: cfg.RateLimitLoginEmail,
+		Key: middleware.KeyByBodyField("email")}
+	tenantRule := middleware.RateLimitRule{Name: "login_tenant", Limit: cfg.RateLimitLoginTenant,
+		Key: middleware.KeyByBodyField("tenant_id")}
+
 	// Public auth routes
 	authGroup := env.Fiber.Group(basePath + "/auth")
-	authGroup.Post("/login", h.Login)
-	authGroup.Post("/admin", h.AdminLogin)
-
-	authGroup.Post("/signup", middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
-		middleware.RoleMiddleware("TENANT_ADMIN"), h.Signup)
+	authGroup.Post("/login",
+		middleware.RateLimitMiddleware(env.CacheManager, ipRule, emailRule, tenantRule),
+		h.Login)
+	authGroup.Post("/admin",
+		middleware.RateLimitMiddleware(env.CacheManager, ipRule, emailRule),
+		h.AdminLogin)
+
+	// Refresh must work once the access token has expired, so it is not behind JWTMiddleware
+	authGroup.Post("/refresh", h.RefreshToken)
+
+	authGroup.Post("/password/forgot", h.ForgotPassword)
+	authGroup.Post("/password/reset", h.ResetPassword)
+	authGroup.Post("/email/verify", h.VerifyEmail)
+
+	// Second step of a login that returned an MFA challenge
+	authGroup.Post("/mfa/challenge/enroll", h.EnrollMFAChallenge)
+	authGroup.Post("/mfa/challenge/verify",
+		middleware.RateLimitMiddleware(env.CacheManager, ipRule),
+		h.VerifyMFAChallenge)
+
+	authGroup.Post("/signup",
+		middleware.AuthMiddleware(env.JWTManager, env.CacheManager, env.Services.APIKeys),
+		middleware.RequireTenant(),
+		middleware.RequirePermission(model.PermUsersWrite),
+		h.Signup)
 
 	// Protected routes group with JWT middleware
 	protected := authGroup.Use(middleware.JWTMiddleware(
 		env.JWTManager,
 		env.CacheManager,
 	))
-	// protected.Get("/users", middleware.RoleMiddleware("TENANT_ADMIN"), h.ListUsers)
 	protected.Post("/logout", h.Logout)
-	protected.Post("/refresh", h.RefreshToken)
+
+	protected.Get("/sessions", h.ListSessions)
+	protected.Delete("/sessions", h.RevokeOtherSessions)
+	protected.Delete("/sessions/:id", h.RevokeSession)
+
+	protected.Post("/email/verification", h.ResendEmailVerification)
+
+	protected.Delete("/lockouts/:user_id",
+		middleware.RequirePermission(model.PermUsersWrite),
+		h.UnlockAccount)
+
+	protected.Post("/impersonation",
+		utils.RequireAnyRole(model.RolePlatformAdmin, model.RoleTenantAdmin),
+		h.Impersonate)
+	protected.Delete("/impersonation", h.EndImpersonation)
 
 	// Add /auth/me endpoint
 	protected.Get("/me", h.Me)
@@ -91,7 +135,7 @@ func (h *Auth) Signup(c *fiber.Ctx) error {
 
 // Login godoc
 // @Summary      Login a tenant user (regular or tenant admin)
-// @Description  Authenticates a tenant user and returns tokens. TenantID must be provided in the request body.
+// @Description  Authenticates a tenant user and returns tokens. TenantID must be provided in the request body. Users with 2FA (mandatory for tenant admins) get an MFA challenge instead, completed via /auth/mfa/challenge/verify.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -125,16 +169,31 @@ func (h *Auth) Login(c *fiber.Ctx) error {
 
 	authResp, err := h.service.Login(ctx, &req, sessionInfo)
 	if err != nil {
+		h.auditLoginFailure(c, req.Email, req.TenantID, err)
+		if errors.Is(err, model.ErrEmailNotVerified) {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+		}
+		var locked *auth.AccountLockedError
+		if errors.As(err, &locked) {
+			return sendLocked(c, locked)
+		}
 		h.env.Logger.Error("Failed to login", zap.Error(err))
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	// A second factor is still needed; the role was checked before the challenge was issued
+	if _, ok := authResp.(auth.MFAChallengeResponse); ok {
+		return utils.SendSuccessResponse(c, 200, authResp)
+	}
+
 	// Only allow login for tenant admin or user roles
 	user, ok := authResp.(auth.LoginResponse)
 	if !ok || (user.User.Role != string(model.RoleTenantAdmin) && user.User.Role != string(model.RoleUser)) {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Only tenant admin or user can login here")
 	}
 
+	h.auditLogin(c, user.User)
+
 	return utils.SendSuccessResponse(c, 200, authResp)
 }
 
@@ -155,6 +214,16 @@ func (h *Auth) Logout(c *fiber.Ctx) error {
 	return utils.SendSuccessResponse(c, 200, "Logged out successfully")
 }
 
+// RefreshToken godoc
+// @Summary      Rotate refresh token
+// @Description  Exchanges a refresh token for a new access/refresh pair. Refresh tokens are single use; replaying one revokes all of the user's sessions.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      auth.RefreshTokenRequest  true  "Refresh token"
+// @Success      200   {object}  auth.JwtAuthData
+// @Failure      401   {object}  model.ErrorResponse
+// @Router       /auth/refresh [post]
 func (h *Auth) RefreshToken(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
@@ -164,8 +233,15 @@ func (h *Auth) RefreshToken(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	auth, err := h.service.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
+		if errors.Is(err, model.ErrInvalidRefreshToken) || errors.Is(err, model.ErrRefreshTokenReused) {
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+		}
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
@@ -174,7 +250,7 @@ func (h *Auth) RefreshToken(c *fiber.Ctx) error {
 
 // AdminLogin godoc
 // @Summary      Login a platform admin
-// @Description  Authenticates a platform admin and returns tokens
+// @Description  Authenticates a platform admin and returns an MFA challenge, completed via /auth/mfa/challenge/verify
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -204,16 +280,27 @@ func (h *Auth) AdminLogin(c *fiber.Ctx) error {
 
 	authResp, err := h.service.AdminLogin(ctx, &req, sessionInfo)
 	if err != nil {
+		h.auditLoginFailure(c, req.Email, "", err)
+		var locked *auth.AccountLockedError
+		if errors.As(err, &locked) {
+			return sendLocked(c, locked)
+		}
 		h.env.Logger.Error("Failed to login", zap.Error(err))
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	if _, ok := authResp.(auth.MFAChallengeResponse); ok {
+		return utils.SendSuccessResponse(c, 200, authResp)
+	}
+
 	// Check if user is PLATFORM_ADMIN
 	user, ok := authResp.(auth.LoginResponse)
 	if !ok || user.User.Role != string(model.RolePlatformAdmin) {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Not an admin user")
 	}
 
+	h.auditLogin(c, user.User)
+
 	return utils.SendSuccessResponse(c, 200, authResp)
 }
 
@@ -237,3 +324,440 @@ func (h *Auth) Me(c *fiber.Ctx) error {
 		"role":      claims.Role,
 	})
 }
+
+// ListSessions godoc
+// @Summary      List active sessions
+// @Description  Lists the current user's active sessions across devices
+// @Tags         auth
+// @Produce      json
+// @Success      200  {array}   auth.Session
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /auth/sessions [get]
+func (h *Auth) ListSessions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tokenID, _ := c.Locals("token_id").(string)
+
+	sessions, err := h.service.ListSessions(ctx, utils.ExtractUserIDFromJWT(c), tokenID)
+	if err != nil {
+		h.env.Logger.Error("Failed to list sessions", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, sessions)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke a session
+// @Description  Signs the current user out of one of their sessions
+// @Tags         auth
+// @Produce      json
+// @Param        id   path      string  true  "Session ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /auth/sessions/{id} [delete]
+func (h *Auth) RevokeSession(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := h.service.RevokeSession(ctx, utils.ExtractUserIDFromJWT(c), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, model.ErrSessionNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to revoke session", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeOtherSessions godoc
+// @Summary      Revoke all other sessions
+// @Description  Signs the current user out of every session except the current one
+// @Tags         auth
+// @Produce      json
+// @Success      204  {object}  nil
+// @Failure      500  {object}  model.ErrorResponse
+// @Router       /auth/sessions [delete]
+func (h *Auth) RevokeOtherSessions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tokenID, _ := c.Locals("token_id").(string)
+
+	if err := h.service.RevokeOtherSessions(ctx, utils.ExtractUserIDFromJWT(c), tokenID); err != nil {
+		h.env.Logger.Error("Failed to revoke other sessions", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Emails a single-use reset link. Always succeeds so account existence is not revealed.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      auth.ForgotPasswordRequest  true  "Email and tenant"
+// @Success      202   {object}  interface{}
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /auth/password/forgot [post]
+func (h *Auth) ForgotPassword(c *fiber.Ctx) error {
+	var req auth.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.RequestPasswordReset(ctx, &req); err != nil {
+		h.env.Logger.Error("Failed to request password reset", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to request password reset")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusAccepted,
+		"If the account exists, a reset link has been sent")
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Sets a new password using a reset token and revokes all sessions
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      auth.ResetPasswordRequest  true  "Reset token and new password"
+// @Success      200   {object}  interface{}
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /auth/password/reset [post]
+func (h *Auth) ResetPassword(c *fiber.Ctx) error {
+	var req auth.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.ResetPassword(ctx, &req); err != nil {
+		if errors.Is(err, model.ErrInvalidUserToken) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to reset password", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to reset password")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, "Password reset successfully")
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Confirms the user's email address using the emailed token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      auth.VerifyEmailRequest  true  "Verification token"
+// @Success      200   {object}  interface{}
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /auth/email/verify [post]
+func (h *Auth) VerifyEmail(c *fiber.Ctx) error {
+	var req auth.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.VerifyEmail(ctx, req.Token); err != nil {
+		if errors.Is(err, model.ErrInvalidUserToken) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to verify email", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to verify email")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, "Email verified successfully")
+}
+
+// ResendEmailVerification godoc
+// @Summary      Resend verification email
+// @Description  Sends a new verification link to the current user's email address
+// @Tags         auth
+// @Produce      json
+// @Success      202  {object}  interface{}
+// @Failure      409  {object}  model.ErrorResponse
+// @Router       /auth/email/verification [post]
+func (h *Auth) ResendEmailVerification(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.SendEmailVerification(ctx, utils.ExtractUserIDFromJWT(c)); err != nil {
+		if errors.Is(err, model.ErrEmailAlreadyVerified) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
+		h.env.Logger.Error("Failed to send verification email", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to send verification email")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusAccepted, "Verification email sent")
+}
+
+// EnrollMFAChallenge godoc
+// @Summary      Set up 2FA during login
+// @Description  For roles where 2FA is mandatory, returns a TOTP secret and otpauth URI for a user who has not enrolled yet. Finish with /auth/mfa/challenge/verify.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      auth.MFAChallengeRequest  true  "Challenge token from login"
+// @Success      200   {object}  mfa.Enrollment
+// @Failure      401   {object}  model.ErrorResponse
+// @Router       /auth/mfa/challenge/enroll [post]
+func (h *Auth) EnrollMFAChallenge(c *fiber.Ctx) error {
+	var req auth.MFAChallengeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	enrollment, err := h.service.StartMFAEnrollment(ctx, req.ChallengeToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidMFAChallenge):
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+		case errors.Is(err, model.ErrMFAAlreadyEnabled):
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
+		h.env.Logger.Error("Failed to start MFA enrollment", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to start enrollment")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, enrollment)
+}
+
+// VerifyMFAChallenge godoc
+// @Summary      Complete a 2FA login
+// @Description  Exchanges a challenge token and TOTP or recovery code for tokens. When enrolling during login, recovery codes are returned once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      auth.MFAVerifyRequest  true  "Challenge token and code"
+// @Success      200   {object}  auth.LoginResponse
+// @Failure      401   {object}  model.ErrorResponse
+// @Router       /auth/mfa/challenge/verify [post]
+func (h *Auth) VerifyMFAChallenge(c *fiber.Ctx) error {
+	var req auth.MFAVerifyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	resp, err := h.service.VerifyMFAChallenge(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidMFAChallenge), errors.Is(err, model.ErrInvalidMFACode):
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+		case errors.Is(err, model.ErrMFAEnrollmentNotStarted):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to verify MFA challenge", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to verify code")
+	}
+
+	h.auditLogin(c, resp.User)
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, resp)
+}
+
+// UnlockAccount godoc
+// @Summary      Unlock a locked account
+// @Description  Clears a lockout caused by repeated failed logins. Tenant admins can unlock users of their own tenant.
+// @Tags         auth
+// @Produce      json
+// @Param        user_id  path      string  true  "User ID"
+// @Success      204      {object}  nil
+// @Failure      404      {object}  model.ErrorResponse
+// @Router       /auth/lockouts/{user_id} [delete]
+func (h *Auth) UnlockAccount(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := ""
+	if utils.ExtractUserRoleFromJWT(c) != model.RolePlatformAdmin.String() {
+		tenantID = utils.ExtractTenantFromJWT(c)
+	}
+
+	if err := h.service.UnlockAccount(ctx, tenantID, c.Params("user_id")); err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to unlock account", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to unlock account")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionAccountUnlock,
+		TargetType: audit.TargetUser,
+		TargetID:   c.Params("user_id"),
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Impersonate godoc
+// @Summary      Impersonate a user
+// @Description  Issues a short-lived access token to act as a user for support. The token is read-only unless allow_writes is set, cannot move money unless allow_money_movement is set (platform admins only), and has no refresh token. Platform admins may impersonate any user but other platform admins; tenant admins, when enabled, only USERs of their tenant. Every request made with the token is audited and its responses carry X-Impersonated-By.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      auth.ImpersonateRequest  true  "Impersonation request"
+// @Success      201   {object}  auth.ImpersonationResponse
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Router       /auth/impersonation [post]
+func (h *Auth) Impersonate(c *fiber.Ctx) error {
+	var req auth.ImpersonateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := validate.Struct(req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	claims, ok := c.Locals("claims").(*model.Claims)
+	if !ok || claims == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	sessionInfo := model.UserSessionInfo{
+		UserAgent: c.Get("User-Agent"),
+		IPAddress: c.IP(),
+	}
+
+	resp, err := h.service.Impersonate(ctx, claims, &req, sessionInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrUserNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		case errors.Is(err, model.ErrImpersonationNotAllowed):
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+		case errors.Is(err, model.ErrInvalidInputError):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+				"allow_money_movement requires allow_writes")
+		}
+		h.env.Logger.Error("Failed to start impersonation", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to start impersonation")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   resp.User.TenantID,
+		Action:     audit.ActionImpersonationStart,
+		TargetType: audit.TargetUser,
+		TargetID:   resp.User.ID,
+		Metadata: map[string]any{
+			"reason":               req.Reason,
+			"allow_writes":         req.AllowWrites,
+			"allow_money_movement": req.AllowMoneyMovement,
+			"expires_at":           resp.ExpiresAt,
+		},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, resp)
+}
+
+// EndImpersonation godoc
+// @Summary      End impersonation
+// @Description  Revokes the impersonation token used to call it
+// @Tags         auth
+// @Produce      json
+// @Success      204   {object}  nil
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /auth/impersonation [delete]
+func (h *Auth) EndImpersonation(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*model.Claims)
+	if !ok || claims == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.EndImpersonation(ctx, claims); err != nil {
+		if errors.Is(err, model.ErrNotImpersonating) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to end impersonation", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to end impersonation")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		ActorID:    claims.ImpersonatorID,
+		Action:     audit.ActionImpersonationEnd,
+		TargetType: audit.TargetUser,
+		TargetID:   claims.UserID,
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// auditLogin records a login that issued tokens. The caller is not
+// authenticated yet, so the actor comes from the logged-in user.
+func (h *Auth) auditLogin(c *fiber.Ctx, user auth.User) {
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   user.TenantID,
+		ActorID:    user.ID,
+		ActorRole:  user.Role,
+		Action:     audit.ActionLogin,
+		TargetType: audit.TargetUser,
+		TargetID:   user.ID,
+	})
+}
+
+func (h *Auth) auditLoginFailure(c *fiber.Ctx, email, tenantID string, err error) {
+	recordAudit(h.env, c, audit.Entry{
+		TenantID: tenantID,
+		Action:   audit.ActionLoginFailed,
+		Metadata: map[string]any{"email": email, "reason": err.Error()},
+	})
+}
+
+func sendLocked(c *fiber.Ctx, locked *auth.AccountLockedError) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	return utils.SendErrorResponse(c, fiber.StatusTooManyRequests, locked.Error())
+}
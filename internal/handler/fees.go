@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/fees"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type Fees struct {
+	service fees.Service
+	env     *Environment
+}
+
+func (h *Fees) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.Fees
+
+	group := env.Fiber.Group(basePath + "/fees")
+	protected := group.Use(middleware.JWTMiddleware(env.JWTManager, env.CacheManager))
+	manage := middleware.RequirePermission(model.PermPricingManage)
+
+	protected.Post("/quote", h.Quote)
+	protected.Get("/plans", manage, h.ListPlans)
+	protected.Post("/plans", manage, h.CreatePlan)
+	protected.Get("/plans/:id", manage, h.GetPlan)
+	protected.Put("/plans/:id", manage, h.UpdatePlan)
+	protected.Delete("/plans/:id", manage, h.DeletePlan)
+	protected.Get("/tenants/:tenant_id/plan", manage, h.GetTenantPlan)
+	protected.Put("/tenants/:tenant_id/plan", manage, h.AssignPlan)
+	protected.Get("/revenue", manage, h.ListRevenue)
+
+	return nil
+}
+
+// Quote godoc
+// @Summary      Quote the fee on a transaction
+// @Description  Prices a deposit, withdrawal or transfer under the caller's tenant's pricing plan without executing it. debit is what the payer is charged and credit what the receiver gets; the bearer of the fee decides which of them it comes out of. Deposits with a channel are priced on the provider they would be routed to now.
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Param        body  body      fees.QuoteRequest  true  "Transaction to price"
+// @Success      200   {object}  fees.Quote
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /fees/quote [post]
+func (h *Fees) Quote(c *fiber.Ctx) error {
+	var req fees.QuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "invalid amount")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	quote, err := h.service.Quote(ctx, fees.QuoteInput{
+		TenantID:        utils.ExtractTenantFromJWT(c),
+		TransactionType: req.TransactionType,
+		Amount:          amount,
+		Currency:        strings.ToUpper(req.Currency),
+		Channel:         req.Channel,
+	})
+	if err != nil {
+		return h.sendError(c, err, "Failed to quote fee")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, quote)
+}
+
+// ListPlans godoc
+// @Summary      List pricing plans
+// @Tags         fees
+// @Produce      json
+// @Success      200  {array}   fees.Plan
+// @Failure      403  {object}  model.ErrorResponse
+// @Router       /fees/plans [get]
+func (h *Fees) ListPlans(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	plans, err := h.service.ListPlans(ctx)
+	if err != nil {
+		return h.sendError(c, err, "Failed to list pricing plans")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, plans)
+}
+
+// CreatePlan godoc
+// @Summary      Create a pricing plan
+// @Description  Creates a plan of fee rules. Each rule prices one transaction type as a flat fee, a percentage (plus an optional flat part) or by amount tiers, optionally capped by min_fee and max_fee, and may be narrowed to a channel, currency or provider. The most specific matching rule applies, the first listed on a tie. bearer is payer (fee added on top, the default) or receiver (fee taken out). Making a plan the default unsets the previous default.
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Param        body  body      fees.PlanRequest  true  "Plan"
+// @Success      201   {object}  fees.Plan
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /fees/plans [post]
+func (h *Fees) CreatePlan(c *fiber.Ctx) error {
+	var req fees.PlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	plan, err := h.service.CreatePlan(ctx, req)
+	if err != nil {
+		return h.sendError(c, err, "Failed to create pricing plan")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionPricingPlanCreate,
+		TargetType: audit.TargetPricingPlan,
+		TargetID:   plan.ID,
+		After:      plan,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, plan)
+}
+
+// GetPlan godoc
+// @Summary      Get a pricing plan
+// @Tags         fees
+// @Produce      json
+// @Param        id   path      string  true  "Plan ID"
+// @Success      200  {object}  fees.Plan
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /fees/plans/{id} [get]
+func (h *Fees) GetPlan(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	plan, err := h.service.GetPlan(ctx, c.Params("id"))
+	if err != nil {
+		return h.sendError(c, err, "Failed to get pricing plan")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, plan)
+}
+
+// UpdatePlan godoc
+// @Summary      Replace a pricing plan
+// @Description  Replaces a plan's details and all of its rules. Tenants on the plan are priced on the new rules from then on.
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string            true  "Plan ID"
+// @Param        body  body      fees.PlanRequest  true  "Plan"
+// @Success      200   {object}  fees.Plan
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /fees/plans/{id} [put]
+func (h *Fees) UpdatePlan(c *fiber.Ctx) error {
+	var req fees.PlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	before, err := h.service.GetPlan(ctx, c.Params("id"))
+	if err != nil {
+		return h.sendError(c, err, "Failed to update pricing plan")
+	}
+
+	plan, err := h.service.UpdatePlan(ctx, before.ID, req)
+	if err != nil {
+		return h.sendError(c, err, "Failed to update pricing plan")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionPricingPlanUpdate,
+		TargetType: audit.TargetPricingPlan,
+		TargetID:   plan.ID,
+		Before:     before,
+		After:      plan,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, plan)
+}
+
+// DeletePlan godoc
+// @Summary      Delete a pricing plan
+// @Description  Deletes a plan. Tenants assigned to it fall back to the default plan.
+// @Tags         fees
+// @Produce      json
+// @Param        id   path      string  true  "Plan ID"
+// @Success      200  {object}  map[string]string
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /fees/plans/{id} [delete]
+func (h *Fees) DeletePlan(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	id := c.Params("id")
+	if err := h.service.DeletePlan(ctx, id); err != nil {
+		return h.sendError(c, err, "Failed to delete pricing plan")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionPricingPlanDelete,
+		TargetType: audit.TargetPricingPlan,
+		TargetID:   id,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, fiber.Map{"status": "deleted"})
+}
+
+// GetTenantPlan godoc
+// @Summary      Get a tenant's pricing plan
+// @Description  Returns the plan the tenant is priced on: its own plan, or else the default plan. Returns null when neither exists and the tenant's transactions are free.
+// @Tags         fees
+// @Produce      json
+// @Param        tenant_id  path      string  true  "Tenant ID"
+// @Success      200        {object}  fees.Plan
+// @Failure      400        {object}  model.ErrorResponse
+// @Failure      403        {object}  model.ErrorResponse
+// @Router       /fees/tenants/{tenant_id}/plan [get]
+func (h *Fees) GetTenantPlan(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	plan, err := h.service.GetTenantPlan(ctx, c.Params("tenant_id"))
+	if err != nil {
+		return h.sendError(c, err, "Failed to get tenant pricing plan")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, plan)
+}
+
+// AssignPlan godoc
+// @Summary      Assign a pricing plan to a tenant
+// @Description  Sets the plan a tenant is priced on. An empty plan_id puts the tenant back on the default plan. Returns the plan that now applies.
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Param        tenant_id  path      string                  true  "Tenant ID"
+// @Param        body       body      fees.AssignPlanRequest  true  "Plan"
+// @Success      200        {object}  fees.Plan
+// @Failure      400        {object}  model.ErrorResponse
+// @Failure      403        {object}  model.ErrorResponse
+// @Failure      404        {object}  model.ErrorResponse
+// @Router       /fees/tenants/{tenant_id}/plan [put]
+func (h *Fees) AssignPlan(c *fiber.Ctx) error {
+	var req fees.AssignPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := c.Params("tenant_id")
+	plan, err := h.service.AssignPlan(ctx, tenantID, req)
+	if err != nil {
+		return h.sendError(c, err, "Failed to assign pricing plan")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   tenantID,
+		Action:     audit.ActionPricingPlanAssign,
+		TargetType: audit.TargetTenant,
+		TargetID:   tenantID,
+		Metadata:   map[string]any{"plan_id": req.PlanID},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, plan)
+}
+
+// ListRevenue godoc
+// @Summary      List fee revenue
+// @Description  Returns the platform revenue wallet balance of fees collected in each currency.
+// @Tags         fees
+// @Produce      json
+// @Success      200  {array}   fees.RevenueBalance
+// @Failure      403  {object}  model.ErrorResponse
+// @Router       /fees/revenue [get]
+func (h *Fees) ListRevenue(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	balances, err := h.service.ListRevenue(ctx)
+	if err != nil {
+		return h.sendError(c, err, "Failed to list fee revenue")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, balances)
+}
+
+func (h *Fees) sendError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidInputError), errors.Is(err, model.ErrInvalidTenantIDFormat),
+		errors.Is(err, model.ErrFeeExceedsAmount):
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, model.ErrPricingPlanNotFound):
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrPricingPlanNameTaken):
+		return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
+	h.env.Logger.Error(message, zap.Error(err))
+	return utils.SendErrorResponse(c, fiber.StatusInternalServerError, message)
+}
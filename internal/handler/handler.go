@@ -6,6 +6,7 @@ import (
 	"codematic/internal/infrastructure/cache"
 	"codematic/internal/infrastructure/db"
 	"codematic/internal/infrastructure/events/kafka"
+	"codematic/internal/scheduler"
 	"codematic/internal/shared/utils"
 
 	"github.com/go-playground/validator/v10"
@@ -23,12 +24,14 @@ type IHandler interface {
 type Environment struct {
 	Config        *config.Config
 	Fiber         *fiber.App
+	OpsFiber      *fiber.App
 	DB            *db.DBConn
 	Logger        *zap.Logger
 	Cache         *redis.Client
 	JWTManager    *utils.JWTManager
 	CacheManager  cache.CacheManager
 	KafkaProducer *kafka.KafkaProducer
+	Scheduler     *scheduler.Scheduler
 
 	Services *app.Services
 }
@@ -36,23 +39,27 @@ type Environment struct {
 func NewEnvironment(
 	config *config.Config,
 	fiber *fiber.App,
+	opsFiber *fiber.App,
 	db *db.DBConn,
 	Cache *redis.Client,
 	logger *zap.Logger,
 	jwtManager *utils.JWTManager,
 	cacheManager cache.CacheManager,
 	kafkaProducer *kafka.KafkaProducer,
+	sched *scheduler.Scheduler,
 	services *app.Services,
 ) *Environment {
 	return &Environment{
 		Config:        config,
 		Fiber:         fiber,
+		OpsFiber:      opsFiber,
 		DB:            db,
 		Cache:         Cache,
 		Logger:        logger,
 		JWTManager:    jwtManager,
 		CacheManager:  cacheManager,
 		KafkaProducer: kafkaProducer,
+		Scheduler:     sched,
 		Services:      services,
 	}
 }
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/invites"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Invites struct {
+	service invites.Service
+	env     *Environment
+}
+
+func (h *Invites) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.Invites
+
+	ipRule := middleware.RateLimitRule{Name: "invite_ip", Limit: env.Config.RateLimitLoginIP,
+		Key: middleware.KeyByIP}
+
+	// Public routes used by the invitee, authorized by the invite token
+	group := env.Fiber.Group(basePath + "/invites")
+	group.Get("/accept", middleware.RateLimitMiddleware(env.CacheManager, ipRule), h.Lookup)
+	group.Post("/accept", middleware.RateLimitMiddleware(env.CacheManager, ipRule), h.Accept)
+
+	protected := group.Use(
+		middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
+		middleware.RequireTenant(),
+		middleware.RequirePermission(model.PermUsersWrite),
+	)
+
+	protected.Post("/", h.Create)
+	protected.Get("/", h.List)
+	protected.Post("/:id/resend", h.Resend)
+	protected.Delete("/:id", h.Revoke)
+
+	return nil
+}
+
+// Create godoc
+// @Summary      Invite a user
+// @Description  Emails a signed invite link to join the caller's tenant with a role. Any open invite to the same email is replaced. Only tenant admins can invite tenant admins.
+// @Tags         invites
+// @Accept       json
+// @Produce      json
+// @Param        body  body      invites.CreateInviteRequest  true  "Invite payload"
+// @Success      201   {object}  invites.Invite
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /invites [post]
+func (h *Invites) Create(c *fiber.Ctx) error {
+	var req invites.CreateInviteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if !canManageRole(c, req.Role) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	invite, err := h.service.CreateInvite(ctx, utils.ExtractTenantFromJWT(c),
+		utils.ExtractUserIDFromJWT(c), req)
+	if err != nil {
+		h.env.Logger.Error("Failed to create invite", zap.Error(err))
+		if errors.Is(err, model.ErrUserAlreadyExists) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionInviteCreate,
+		TargetType: audit.TargetInvite,
+		TargetID:   invite.ID,
+		After:      invite,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, invite)
+}
+
+// List godoc
+// @Summary      List invites
+// @Description  Lists invites of the caller's tenant, newest first, with their status (pending, accepted, revoked or expired)
+// @Tags         invites
+// @Produce      json
+// @Param        limit   query     int  false  "Limit (max 100)"
+// @Param        offset  query     int  false  "Offset"
+// @Success      200     {array}   invites.Invite
+// @Failure      500     {object}  model.ErrorResponse
+// @Router       /invites [get]
+func (h *Invites) List(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	list, err := h.service.ListInvites(ctx, utils.ExtractTenantFromJWT(c), limit, offset)
+	if err != nil {
+		h.env.Logger.Error("Failed to list invites", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list invites")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, list)
+}
+
+// Resend godoc
+// @Summary      Resend an invite
+// @Description  Emails a new link for an open invite and extends its expiry. Earlier links stop working.
+// @Tags         invites
+// @Produce      json
+// @Param        id   path      string  true  "Invite ID"
+// @Success      200  {object}  invites.Invite
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /invites/{id}/resend [post]
+func (h *Invites) Resend(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	invite, err := h.service.ResendInvite(ctx, utils.ExtractTenantFromJWT(c), c.Params("id"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, invite)
+}
+
+// Revoke godoc
+// @Summary      Revoke an invite
+// @Description  Revokes an open invite so its link can no longer be used
+// @Tags         invites
+// @Produce      json
+// @Param        id   path      string  true  "Invite ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /invites/{id} [delete]
+func (h *Invites) Revoke(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.RevokeInvite(ctx, utils.ExtractTenantFromJWT(c), c.Params("id")); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionInviteRevoke,
+		TargetType: audit.TargetInvite,
+		TargetID:   c.Params("id"),
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Lookup godoc
+// @Summary      Look up an invite
+// @Description  Returns the email, role and expiry of an open invite for the accept page
+// @Tags         invites
+// @Produce      json
+// @Param        token  query     string  true  "Invite token"
+// @Success      200    {object}  invites.Invite
+// @Failure      400    {object}  model.ErrorResponse
+// @Router       /invites/accept [get]
+func (h *Invites) Lookup(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	invite, err := h.service.LookupInvite(ctx, c.Query("token"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, invite)
+}
+
+// Accept godoc
+// @Summary      Accept an invite
+// @Description  Sets the invitee's password and creates their account and wallet in the inviting tenant
+// @Tags         invites
+// @Accept       json
+// @Produce      json
+// @Param        body  body      invites.AcceptInviteRequest  true  "Accept payload"
+// @Success      201   {object}  auth.User
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /invites/accept [post]
+func (h *Invites) Accept(c *fiber.Ctx) error {
+	var req invites.AcceptInviteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	user, err := h.service.AcceptInvite(ctx, req)
+	if err != nil {
+		h.env.Logger.Error("Failed to accept invite", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, user)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"codematic/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWKS publishes the public keys access tokens are signed with
+type JWKS struct {
+	jwtManager *utils.JWTManager
+}
+
+func (h *JWKS) Init(basePath string, env *Environment) error {
+	h.jwtManager = env.JWTManager
+
+	// Served at the well-known root path rather than under basePath
+	env.Fiber.Get("/.well-known/jwks.json", h.Keys)
+
+	return nil
+}
+
+// Keys godoc
+// @Summary      JSON Web Key Set
+// @Description  Lists the public keys that verify access tokens, matched by the token's kid header. Includes keys published ahead of activation and retired keys whose tokens may still be valid. The response is a bare RFC 7517 key set, not the usual envelope.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  utils.JWKSet
+// @Router       /.well-known/jwks.json [get]
+func (h *JWKS) Keys(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.jwtManager.JWKS())
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"codematic/internal/domain/mfa"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type MFA struct {
+	service mfa.Service
+	env     *Environment
+}
+
+func (h *MFA) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.MFA
+
+	group := env.Fiber.Group(basePath + "/mfa")
+	protected := group.Use(middleware.JWTMiddleware(env.JWTManager, env.CacheManager))
+
+	protected.Get("/", h.Status)
+	protected.Post("/totp", h.Enroll)
+	protected.Post("/totp/confirm", h.Confirm)
+	protected.Delete("/totp", h.Disable)
+	protected.Post("/recovery-codes", h.RegenerateRecoveryCodes)
+
+	return nil
+}
+
+// Status godoc
+// @Summary      Get 2FA status
+// @Description  Returns whether TOTP is enabled or required for the current user and how many recovery codes remain
+// @Tags         mfa
+// @Produce      json
+// @Success      200  {object}  mfa.Status
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /mfa [get]
+func (h *MFA) Status(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	status, err := h.service.Status(ctx, utils.ExtractUserIDFromJWT(c), utils.ExtractUserRoleFromJWT(c))
+	if err != nil {
+		h.env.Logger.Error("Failed to get MFA status", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get 2FA status")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, status)
+}
+
+// Enroll godoc
+// @Summary      Start TOTP enrollment
+// @Description  Generates a TOTP secret and otpauth URI to scan into an authenticator app. 2FA is not active until confirmed.
+// @Tags         mfa
+// @Produce      json
+// @Success      200  {object}  mfa.Enrollment
+// @Failure      409  {object}  model.ErrorResponse
+// @Router       /mfa/totp [post]
+func (h *MFA) Enroll(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	enrollment, err := h.service.Enroll(ctx, utils.ExtractUserIDFromJWT(c), utils.ExtractUserEmailFromJWT(c))
+	if err != nil {
+		if errors.Is(err, model.ErrMFAAlreadyEnabled) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
+		h.env.Logger.Error("Failed to start MFA enrollment", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to start enrollment")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, enrollment)
+}
+
+// Confirm godoc
+// @Summary      Confirm TOTP enrollment
+// @Description  Activates 2FA with a code from the authenticator app and returns recovery codes (shown once)
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param        body  body      mfa.CodeRequest  true  "TOTP code"
+// @Success      200   {object}  mfa.RecoveryCodes
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /mfa/totp/confirm [post]
+func (h *MFA) Confirm(c *fiber.Ctx) error {
+	var req mfa.CodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	codes, err := h.service.Confirm(ctx, utils.ExtractUserIDFromJWT(c), req.Code)
+	if err != nil {
+		return h.sendError(c, err, "Failed to confirm 2FA")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, mfa.RecoveryCodes{Codes: codes})
+}
+
+// Disable godoc
+// @Summary      Disable TOTP
+// @Description  Turns off 2FA after checking a TOTP or recovery code. Not allowed for roles where 2FA is mandatory.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param        body  body      mfa.CodeRequest  true  "TOTP or recovery code"
+// @Success      204   {object}  nil
+// @Failure      403   {object}  model.ErrorResponse
+// @Router       /mfa/totp [delete]
+func (h *MFA) Disable(c *fiber.Ctx) error {
+	var req mfa.CodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := h.service.Disable(ctx, utils.ExtractUserIDFromJWT(c), utils.ExtractUserRoleFromJWT(c), req.Code)
+	if err != nil {
+		return h.sendError(c, err, "Failed to disable 2FA")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replaces all recovery codes after checking a TOTP or recovery code
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param        body  body      mfa.CodeRequest  true  "TOTP or recovery code"
+// @Success      200   {object}  mfa.RecoveryCodes
+// @Failure      401   {object}  model.ErrorResponse
+// @Router       /mfa/recovery-codes [post]
+func (h *MFA) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var req mfa.CodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	codes, err := h.service.RegenerateRecoveryCodes(ctx, utils.ExtractUserIDFromJWT(c), req.Code)
+	if err != nil {
+		return h.sendError(c, err, "Failed to regenerate recovery codes")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, mfa.RecoveryCodes{Codes: codes})
+}
+
+func (h *MFA) sendError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidMFACode):
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, model.ErrMFARequired):
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, model.ErrMFAAlreadyEnabled):
+		return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, model.ErrMFANotEnabled), errors.Is(err, model.ErrMFAEnrollmentNotStarted):
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	h.env.Logger.Error(fallback, zap.Error(err))
+	return utils.SendErrorResponse(c, fiber.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/consumers"
+	"codematic/internal/middleware"
+	"codematic/internal/scheduler"
+	"codematic/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Ops struct {
+	env *Environment
+}
+
+func (h *Ops) Init(basePath string, env *Environment) error {
+	h.env = env
+
+	app := env.OpsFiber
+	if app == nil {
+		app = env.Fiber
+	}
+
+	group := app.Group(basePath+"/ops", middleware.OpsMiddleware(
+		env.Config,
+		env.JWTManager,
+		env.CacheManager,
+	))
+
+	group.Get("/build", h.Build)
+	group.Get("/config", h.Config)
+	group.Get("/consumers", h.Consumers)
+	group.Get("/scheduler", h.Scheduler)
+
+	return nil
+}
+
+// Build godoc
+// @Summary      Get build info
+// @Description  Returns version, git hash, build time and Go version of the running binary
+// @Tags         ops
+// @Produce      json
+// @Success      200  {object}  config.BuildInfo
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /ops/build [get]
+func (h *Ops) Build(c *fiber.Ctx) error {
+	return utils.SendSuccessResponse(c, fiber.StatusOK, config.GetBuildInfo())
+}
+
+// Config godoc
+// @Summary      Get running config
+// @Description  Returns the loaded configuration with secret values redacted
+// @Tags         ops
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /ops/config [get]
+func (h *Ops) Config(c *fiber.Ctx) error {
+	return utils.SendSuccessResponse(c, fiber.StatusOK, h.env.Config.Redacted())
+}
+
+// Consumers godoc
+// @Summary      Get consumer status
+// @Description  Returns the status of every Kafka consumer started by this instance
+// @Tags         ops
+// @Produce      json
+// @Success      200  {array}   consumers.ConsumerStatus
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /ops/consumers [get]
+func (h *Ops) Consumers(c *fiber.Ctx) error {
+	return utils.SendSuccessResponse(c, fiber.StatusOK, consumers.Statuses())
+}
+
+// Scheduler godoc
+// @Summary      Get scheduler job state
+// @Description  Returns last and next run times of every scheduled job
+// @Tags         ops
+// @Produce      json
+// @Success      200  {array}   scheduler.JobState
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /ops/scheduler [get]
+func (h *Ops) Scheduler(c *fiber.Ctx) error {
+	if h.env.Scheduler == nil {
+		return utils.SendSuccessResponse(c, fiber.StatusOK, []scheduler.JobState{})
+	}
+	return utils.SendSuccessResponse(c, fiber.StatusOK, h.env.Scheduler.JobStates())
+}
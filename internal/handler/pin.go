@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/pin"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// HeaderTransactionPIN carries the transaction PIN on withdrawals and transfers
+const HeaderTransactionPIN = "X-Transaction-PIN"
+
+type PIN struct {
+	service pin.Service
+	env     *Environment
+}
+
+func (h *PIN) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.PIN
+
+	group := env.Fiber.Group(basePath + "/pin")
+	protected := group.Use(
+		middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
+		middleware.RateLimitMiddleware(env.CacheManager, middleware.RateLimitRule{
+			Name: "pin", Limit: env.Config.RateLimitPIN, Key: middleware.KeyByUser,
+		}),
+	)
+
+	protected.Get("/", h.Status)
+	protected.Post("/", h.Set)
+	protected.Put("/", h.Change)
+	protected.Post("/reset/request", h.RequestReset)
+	protected.Post("/reset", h.Reset)
+
+	return nil
+}
+
+// Status godoc
+// @Summary      Get transaction PIN status
+// @Description  Returns whether the current user has set a transaction PIN and whether PIN entry is locked
+// @Tags         pin
+// @Produce      json
+// @Success      200  {object}  pin.Status
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /pin [get]
+func (h *PIN) Status(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	status, err := h.service.Status(ctx, utils.ExtractUserIDFromJWT(c))
+	if err != nil {
+		h.env.Logger.Error("Failed to get PIN status", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get PIN status")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, status)
+}
+
+// Set godoc
+// @Summary      Set transaction PIN
+// @Description  Sets the 4-6 digit PIN required for withdrawals and transfers. Needs the account password; use change or reset once a PIN is set.
+// @Tags         pin
+// @Accept       json
+// @Produce      json
+// @Param        body  body      pin.SetRequest  true  "PIN and password"
+// @Success      204   {object}  nil
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      401   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /pin [post]
+func (h *PIN) Set(c *fiber.Ctx) error {
+	var req pin.SetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, model.ErrInvalidInputError.Error())
+	}
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.Set(ctx, utils.ExtractUserIDFromJWT(c), req.Password, req.PIN); err != nil {
+		if errors.Is(err, model.ErrIncorrectPassword) {
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+		}
+		return h.sendError(c, err, "Failed to set PIN")
+	}
+
+	h.audit(c, audit.ActionPINSet)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Change godoc
+// @Summary      Change transaction PIN
+// @Description  Replaces the PIN after checking the current one. Wrong current PINs count towards the lockout.
+// @Tags         pin
+// @Accept       json
+// @Produce      json
+// @Param        body  body      pin.ChangeRequest  true  "Current and new PIN"
+// @Success      204   {object}  nil
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      401   {object}  model.ErrorResponse
+// @Failure      429   {object}  model.ErrorResponse
+// @Router       /pin [put]
+func (h *PIN) Change(c *fiber.Ctx) error {
+	var req pin.ChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, model.ErrInvalidInputError.Error())
+	}
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.Change(ctx, utils.ExtractUserIDFromJWT(c), req.CurrentPIN, req.NewPIN); err != nil {
+		return h.sendError(c, err, "Failed to change PIN")
+	}
+
+	h.audit(c, audit.ActionPINChange)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RequestReset godoc
+// @Summary      Request a transaction PIN reset
+// @Description  Emails a reset link, valid for 30 minutes, to the user's verified email address
+// @Tags         pin
+// @Produce      json
+// @Success      202  {object}  nil
+// @Failure      400  {object}  model.ErrorResponse
+// @Router       /pin/reset/request [post]
+func (h *PIN) RequestReset(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.RequestReset(ctx, utils.ExtractUserIDFromJWT(c)); err != nil {
+		if errors.Is(err, model.ErrEmailNotVerified) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+				"verify your email address before resetting your PIN")
+		}
+		return h.sendError(c, err, "Failed to request PIN reset")
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// Reset godoc
+// @Summary      Reset transaction PIN
+// @Description  Sets a new PIN with the token from the reset email and lifts any PIN lockout
+// @Tags         pin
+// @Accept       json
+// @Produce      json
+// @Param        body  body      pin.ResetRequest  true  "Reset token and new PIN"
+// @Success      204   {object}  nil
+// @Failure      400   {object}  model.ErrorResponse
+// @Router       /pin/reset [post]
+func (h *PIN) Reset(c *fiber.Ctx) error {
+	var req pin.ResetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, model.ErrInvalidInputError.Error())
+	}
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.Reset(ctx, utils.ExtractUserIDFromJWT(c), req.Token, req.PIN); err != nil {
+		if errors.Is(err, model.ErrInvalidUserToken) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		return h.sendError(c, err, "Failed to reset PIN")
+	}
+
+	h.audit(c, audit.ActionPINReset)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *PIN) audit(c *fiber.Ctx, action string) {
+	recordAudit(h.env, c, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   utils.ExtractUserIDFromJWT(c),
+	})
+}
+
+func (h *PIN) sendError(c *fiber.Ctx, err error, fallback string) error {
+	if sendPINError(c, err) {
+		return nil
+	}
+	switch {
+	case errors.Is(err, model.ErrTransactionPINAlreadySet):
+		return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, model.ErrUserNotFound):
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	h.env.Logger.Error(fallback, zap.Error(err))
+	return utils.SendErrorResponse(c, fiber.StatusInternalServerError, fallback)
+}
+
+// sendPINError responds to transaction PIN errors, reporting whether err was
+// one of them. Shared by the PIN and wallet handlers.
+func sendPINError(c *fiber.Ctx, err error) bool {
+	var locked *pin.LockedError
+	switch {
+	case errors.As(err, &locked):
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		_ = utils.SendErrorResponse(c, fiber.StatusTooManyRequests, locked.Error())
+	case errors.Is(err, model.ErrInvalidTransactionPIN):
+		_ = utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, model.ErrTransactionPINRequired), errors.Is(err, model.ErrTransactionPINNotSet):
+		_ = utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, model.ErrInvalidTransactionPINFormat):
+		_ = utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	default:
+		return false
+	}
+	return true
+}
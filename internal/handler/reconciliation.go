@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/reconciliation"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Reconciliation struct {
+	service reconciliation.Service
+	env     *Environment
+}
+
+func (h *Reconciliation) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.Reconciliation
+
+	group := env.Fiber.Group(basePath + "/reconciliation")
+	protected := group.Use(
+		middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
+		middleware.RequirePermission(model.PermReconciliationManage),
+	)
+
+	protected.Post("/runs", h.Upload)
+	protected.Post("/runs/pull", h.Pull)
+	protected.Get("/runs", h.ListRuns)
+	protected.Get("/runs/:id", h.GetRun)
+	protected.Get("/exceptions", h.ListExceptions)
+	protected.Post("/exceptions/:id/resolve", h.Resolve)
+
+	return nil
+}
+
+// Upload godoc
+// @Summary      Reconcile an uploaded provider report
+// @Description  Matches a provider settlement or transaction report (CSV with reference, amount, currency and optional status columns, or a JSON array of the same fields) against transactions by reference and amount. Completed deposits for the provider in the period that the report lacks are flagged missing. Dates are UTC and both ends are inclusive.
+// @Tags         reconciliation
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        provider  formData  string  true  "Provider code, e.g. paystack"
+// @Param        from      formData  string  true  "First day (YYYY-MM-DD)"
+// @Param        to        formData  string  true  "Last day (YYYY-MM-DD)"
+// @Param        file      formData  file    true  "Report file (.csv or .json)"
+// @Success      201       {object}  reconciliation.Run
+// @Failure      400       {object}  model.ErrorResponse
+// @Failure      403       {object}  model.ErrorResponse
+// @Router       /reconciliation/runs [post]
+func (h *Reconciliation) Upload(c *fiber.Ctx) error {
+	from, to, err := reconciliationPeriod(c.FormValue("from"), c.FormValue("to"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "file is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "file could not be read")
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "file could not be read")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	run, err := h.service.Import(ctx, reconciliation.ImportRequest{
+		ProviderCode: c.FormValue("provider"),
+		FileName:     header.Filename,
+		Data:         data,
+		From:         from,
+		To:           to,
+		UserID:       utils.ExtractUserIDFromJWT(c),
+	})
+	if err != nil {
+		return h.sendRunError(c, err)
+	}
+
+	h.recordRun(c, run)
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, run)
+}
+
+// Pull godoc
+// @Summary      Reconcile a report pulled from the provider
+// @Description  Fetches the provider's transaction report for the period through its API and reconciles it like an uploaded report. A failed fetch is recorded on the run. Dates are UTC and both ends are inclusive.
+// @Tags         reconciliation
+// @Accept       json
+// @Produce      json
+// @Param        body  body      reconciliation.PullRequest  true  "Provider and period"
+// @Success      201   {object}  reconciliation.Run
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      500   {object}  model.ErrorResponse
+// @Router       /reconciliation/runs/pull [post]
+func (h *Reconciliation) Pull(c *fiber.Ctx) error {
+	var req reconciliation.PullRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	from, to, err := reconciliationPeriod(req.From, req.To)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	run, err := h.service.Pull(ctx, req.Provider, from, to, utils.ExtractUserIDFromJWT(c))
+	if err != nil {
+		return h.sendRunError(c, err)
+	}
+
+	h.recordRun(c, run)
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, run)
+}
+
+// ListRuns godoc
+// @Summary      List reconciliation runs
+// @Description  Lists reconciliation runs newest first with their matched, missing, mismatched and extra counts.
+// @Tags         reconciliation
+// @Produce      json
+// @Param        provider_id  query     string  false  "Provider ID"
+// @Param        limit        query     int     false  "Limit (default 50, max 200)"
+// @Param        offset       query     int     false  "Offset"
+// @Success      200          {array}   reconciliation.Run
+// @Failure      400          {object}  model.ErrorResponse
+// @Failure      403          {object}  model.ErrorResponse
+// @Router       /reconciliation/runs [get]
+func (h *Reconciliation) ListRuns(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	runs, err := h.service.ListRuns(ctx, c.Query("provider_id"), limit, offset)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInputError) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to list reconciliation runs", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list reconciliation runs")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, runs)
+}
+
+// GetRun godoc
+// @Summary      Get a reconciliation run
+// @Tags         reconciliation
+// @Produce      json
+// @Param        id   path      string  true  "Run ID"
+// @Success      200  {object}  reconciliation.Run
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /reconciliation/runs/{id} [get]
+func (h *Reconciliation) GetRun(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	run, err := h.service.GetRun(ctx, c.Params("id"))
+	if err != nil {
+		if errors.Is(err, model.ErrReconciliationRunNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to get reconciliation run", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get reconciliation run")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, run)
+}
+
+// ListExceptions godoc
+// @Summary      List reconciliation exceptions
+// @Description  Lists the exception queue oldest first. Defaults to open exceptions; pass status=all for every status.
+// @Tags         reconciliation
+// @Produce      json
+// @Param        status          query     string  false  "open (default), resolved, ignored, superseded or all"
+// @Param        classification  query     string  false  "missing, mismatched or extra"
+// @Param        run_id          query     string  false  "Run ID"
+// @Param        provider_id     query     string  false  "Provider ID"
+// @Param        limit           query     int     false  "Limit (default 50, max 200)"
+// @Param        offset          query     int     false  "Offset"
+// @Success      200             {object}  reconciliation.ExceptionList
+// @Failure      400             {object}  model.ErrorResponse
+// @Failure      403             {object}  model.ErrorResponse
+// @Router       /reconciliation/exceptions [get]
+func (h *Reconciliation) ListExceptions(c *fiber.Ctx) error {
+	filter := reconciliation.ExceptionFilter{
+		RunID:          c.Query("run_id"),
+		ProviderID:     c.Query("provider_id"),
+		Classification: c.Query("classification"),
+		Status:         c.Query("status", reconciliation.ExceptionOpen),
+	}
+	if filter.Status == "all" {
+		filter.Status = ""
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit", "50"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset", "0"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	list, err := h.service.ListExceptions(ctx, filter)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInputError) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to list reconciliation exceptions", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list reconciliation exceptions")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, list)
+}
+
+// Resolve godoc
+// @Summary      Resolve a reconciliation exception
+// @Description  Closes an open exception as resolved (the discrepancy was dealt with) or ignored (it needs no action), with an optional note.
+// @Tags         reconciliation
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                         true  "Exception ID"
+// @Param        body  body      reconciliation.ResolveRequest  true  "Resolution"
+// @Success      200   {object}  reconciliation.Exception
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /reconciliation/exceptions/{id}/resolve [post]
+func (h *Reconciliation) Resolve(c *fiber.Ctx) error {
+	var req reconciliation.ResolveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	exception, err := h.service.ResolveException(ctx, c.Params("id"), utils.ExtractUserIDFromJWT(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidInputError):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrReconciliationExceptionNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		case errors.Is(err, model.ErrExceptionAlreadyClosed):
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
+		h.env.Logger.Error("Failed to resolve reconciliation exception", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to resolve exception")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionReconciliationResolve,
+		TargetType: audit.TargetReconciliationException,
+		TargetID:   exception.ID,
+		After:      exception,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, exception)
+}
+
+func (h *Reconciliation) sendRunError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, model.ErrInvalidInputError) || errors.Is(err, model.ErrInvalidReport) ||
+		errors.Is(err, model.ErrUnsupportedProvider) {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	h.env.Logger.Error("Failed to reconcile provider report", zap.Error(err))
+	return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to reconcile provider report")
+}
+
+func (h *Reconciliation) recordRun(c *fiber.Ctx, run *reconciliation.Run) {
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionReconciliationRun,
+		TargetType: audit.TargetReconciliationRun,
+		TargetID:   run.ID,
+		After:      run,
+	})
+}
+
+// reconciliationPeriod turns inclusive YYYY-MM-DD days into a [from, to) range
+func reconciliationPeriod(fromDay, toDay string) (time.Time, time.Time, error) {
+	from, err := time.Parse(time.DateOnly, fromDay)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("from must be a date (YYYY-MM-DD)")
+	}
+	to, err := time.Parse(time.DateOnly, toDay)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("to must be a date (YYYY-MM-DD)")
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
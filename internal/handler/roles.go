@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/rbac"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Roles struct {
+	service rbac.Service
+	env     *Environment
+}
+
+func (h *Roles) Init(basePath string, env *Environment) error {
+	h.env = env
+	h.service = env.Services.RBAC
+
+	group := env.Fiber.Group(basePath + "/roles")
+	protected := group.Use(
+		middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
+		middleware.RequireTenant(),
+		middleware.RequirePermission(model.PermRolesManage),
+	)
+
+	protected.Get("/", h.List)
+	protected.Get("/permissions", h.ListPermissions)
+	protected.Get("/users/:user_id", h.ListUserRoles)
+	protected.Post("/", h.Create)
+	protected.Get("/:id", h.GetByID)
+	protected.Put("/:id", h.Update)
+	protected.Delete("/:id", h.Delete)
+	protected.Post("/:id/users/:user_id", h.Assign)
+	protected.Delete("/:id/users/:user_id", h.Unassign)
+
+	return nil
+}
+
+// List godoc
+// @Summary      List roles
+// @Description  Lists the built-in roles and the custom roles of the caller's tenant
+// @Tags         roles
+// @Produce      json
+// @Success      200  {array}   rbac.Role
+// @Failure      500  {object}  model.ErrorResponse
+// @Router       /roles [get]
+func (h *Roles) List(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	roles, err := h.service.ListRoles(ctx, utils.ExtractTenantFromJWT(c))
+	if err != nil {
+		h.env.Logger.Error("Failed to list roles", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list roles")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, roles)
+}
+
+// ListPermissions godoc
+// @Summary      List grantable permissions
+// @Description  Lists the permissions custom roles can be built from
+// @Tags         roles
+// @Produce      json
+// @Success      200  {array}  string
+// @Router       /roles/permissions [get]
+func (h *Roles) ListPermissions(c *fiber.Ctx) error {
+	return utils.SendSuccessResponse(c, fiber.StatusOK, model.TenantPermissions)
+}
+
+// GetByID godoc
+// @Summary      Get a role
+// @Description  Gets a custom role of the caller's tenant
+// @Tags         roles
+// @Produce      json
+// @Param        id   path      string  true  "Role ID"
+// @Success      200  {object}  rbac.Role
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /roles/{id} [get]
+func (h *Roles) GetByID(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	role, err := h.service.GetRole(ctx, utils.ExtractTenantFromJWT(c), c.Params("id"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, role)
+}
+
+// Create godoc
+// @Summary      Create a role
+// @Description  Creates a custom role from a set of permissions. Callers can only grant permissions they hold.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        body  body      rbac.RoleRequest  true  "Role payload"
+// @Success      201   {object}  rbac.Role
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /roles [post]
+func (h *Roles) Create(c *fiber.Ctx) error {
+	var req rbac.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if !canGrant(c, req.Permissions) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	role, err := h.service.CreateRole(ctx, utils.ExtractTenantFromJWT(c), req)
+	if err != nil {
+		h.env.Logger.Error("Failed to create role", zap.Error(err))
+		return h.sendRoleError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionRoleCreate,
+		TargetType: audit.TargetRole,
+		TargetID:   role.ID,
+		After:      role,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, role)
+}
+
+// Update godoc
+// @Summary      Update a role
+// @Description  Replaces the name, description and permissions of a custom role. Holders pick up the change on their next login or token refresh.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string            true  "Role ID"
+// @Param        body  body      rbac.RoleRequest  true  "Role payload"
+// @Success      200   {object}  rbac.Role
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Router       /roles/{id} [put]
+func (h *Roles) Update(c *fiber.Ctx) error {
+	var req rbac.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if !canGrant(c, req.Permissions) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+
+	before, err := h.service.GetRole(ctx, tenantID, c.Params("id"))
+	if err != nil {
+		return h.sendRoleError(c, err)
+	}
+
+	role, err := h.service.UpdateRole(ctx, tenantID, c.Params("id"), req)
+	if err != nil {
+		h.env.Logger.Error("Failed to update role", zap.Error(err))
+		return h.sendRoleError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionRoleUpdate,
+		TargetType: audit.TargetRole,
+		TargetID:   role.ID,
+		Before:     before,
+		After:      role,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, role)
+}
+
+// Delete godoc
+// @Summary      Delete a role
+// @Description  Deletes a custom role and removes it from every user holding it
+// @Tags         roles
+// @Produce      json
+// @Param        id   path      string  true  "Role ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /roles/{id} [delete]
+func (h *Roles) Delete(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+
+	before, err := h.service.GetRole(ctx, tenantID, c.Params("id"))
+	if err != nil {
+		return h.sendRoleError(c, err)
+	}
+
+	if err := h.service.DeleteRole(ctx, tenantID, before.ID); err != nil {
+		h.env.Logger.Error("Failed to delete role", zap.Error(err))
+		return h.sendRoleError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionRoleDelete,
+		TargetType: audit.TargetRole,
+		TargetID:   before.ID,
+		Before:     before,
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Assign godoc
+// @Summary      Assign a role to a user
+// @Description  Grants a custom role to a user of the caller's tenant
+// @Tags         roles
+// @Produce      json
+// @Param        id       path      string  true  "Role ID"
+// @Param        user_id  path      string  true  "User ID"
+// @Success      204      {object}  nil
+// @Failure      404      {object}  model.ErrorResponse
+// @Router       /roles/{id}/users/{user_id} [post]
+func (h *Roles) Assign(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+
+	role, err := h.service.GetRole(ctx, tenantID, c.Params("id"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if !canGrant(c, role.Permissions) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	if err := h.service.AssignRole(ctx, tenantID, role.ID, c.Params("user_id")); err != nil {
+		h.env.Logger.Error("Failed to assign role", zap.Error(err))
+		return h.sendRoleError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionRoleAssign,
+		TargetType: audit.TargetUser,
+		TargetID:   c.Params("user_id"),
+		Metadata:   map[string]any{"role_id": role.ID, "role_name": role.Name},
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Unassign godoc
+// @Summary      Remove a role from a user
+// @Description  Revokes a custom role from a user of the caller's tenant
+// @Tags         roles
+// @Produce      json
+// @Param        id       path      string  true  "Role ID"
+// @Param        user_id  path      string  true  "User ID"
+// @Success      204      {object}  nil
+// @Failure      404      {object}  model.ErrorResponse
+// @Router       /roles/{id}/users/{user_id} [delete]
+func (h *Roles) Unassign(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := h.service.UnassignRole(ctx, utils.ExtractTenantFromJWT(c),
+		c.Params("id"), c.Params("user_id")); err != nil {
+		h.env.Logger.Error("Failed to unassign role", zap.Error(err))
+		return h.sendRoleError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionRoleUnassign,
+		TargetType: audit.TargetUser,
+		TargetID:   c.Params("user_id"),
+		Metadata:   map[string]any{"role_id": c.Params("id")},
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListUserRoles godoc
+// @Summary      List a user's roles
+// @Description  Lists the custom roles assigned to a user of the caller's tenant
+// @Tags         roles
+// @Produce      json
+// @Param        user_id  path      string  true  "User ID"
+// @Success      200      {array}   rbac.Role
+// @Failure      500      {object}  model.ErrorResponse
+// @Router       /roles/users/{user_id} [get]
+func (h *Roles) ListUserRoles(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+
+	assigned, err := h.service.ListUserRoles(ctx, c.Params("user_id"))
+	if err != nil {
+		h.env.Logger.Error("Failed to list user roles", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list user roles")
+	}
+
+	roles := make([]rbac.Role, 0, len(assigned))
+	for _, r := range assigned {
+		if r.TenantID == tenantID {
+			roles = append(roles, r)
+		}
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, roles)
+}
+
+func (h *Roles) sendRoleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrRoleNotFound), errors.Is(err, model.ErrUserNotFound):
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrRoleNameTaken):
+		return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, model.ErrInvalidPermission), errors.Is(err, model.ErrInvalidInputError):
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	default:
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to update roles")
+	}
+}
+
+// canGrant stops callers from handing out permissions they do not hold
+// themselves, so roles:manage or api_keys:manage cannot be used to escalate.
+// API key scopes use the same names as permissions.
+func canGrant(c *fiber.Ctx, permissions []string) bool {
+	for _, p := range permissions {
+		if !utils.HasPermission(c, p) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"codematic/internal/domain/audit"
 	"codematic/internal/domain/tenants"
 	"codematic/internal/middleware"
 	"codematic/internal/shared/model"
@@ -30,12 +31,13 @@ func (h *Tenants) Init(basePath string, env *Environment) error {
 		env.CacheManager,
 	))
 
-	protected.Post("/create", middleware.RoleMiddleware("PLATFORM_ADMIN"), h.Create)
-	protected.Get("/:id", middleware.RoleMiddleware("PLATFORM_ADMIN"), h.GetByID)
-	protected.Get("/slug/:slug", middleware.RoleMiddleware("PLATFORM_ADMIN"), h.GetBySlug)
-	protected.Get("/", middleware.RoleMiddleware("PLATFORM_ADMIN"), h.List)
-	protected.Put("/:id", middleware.RoleMiddleware("PLATFORM_ADMIN"), h.Update)
-	protected.Delete("/:id", middleware.RoleMiddleware("PLATFORM_ADMIN"), h.Delete)
+	protected.Post("/create", middleware.RequirePermission(model.PermTenantsManage), h.Create)
+	protected.Get("/:id", middleware.RequirePermission(model.PermTenantsManage), h.GetByID)
+	protected.Get("/slug/:slug", middleware.RequirePermission(model.PermTenantsManage), h.GetBySlug)
+	protected.Get("/", middleware.RequirePermission(model.PermTenantsManage), h.List)
+	protected.Put("/:id", middleware.RequirePermission(model.PermTenantsManage), h.Update)
+	protected.Put("/:id/settings", middleware.RequirePermission(model.PermTenantsManage), h.UpdateSettings)
+	protected.Delete("/:id", middleware.RequirePermission(model.PermTenantsManage), h.Delete)
 
 	return nil
 
@@ -72,6 +74,14 @@ func (h *Tenants) Create(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   tenant.ID,
+		Action:     audit.ActionTenantCreate,
+		TargetType: audit.TargetTenant,
+		TargetID:   tenant.ID,
+		After:      tenant,
+	})
+
 	return utils.SendSuccessResponse(c, fiber.StatusCreated, tenant)
 }
 
@@ -162,12 +172,76 @@ func (h *Tenants) Update(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 
+	before, err := h.service.GetTenantByID(ctx, id)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
 	tenant, err := h.service.UpdateTenant(ctx, id, req.Name, req.Slug, req.WebhookURL)
 	if err != nil {
 		h.env.Logger.Error("Failed to update tenant", zap.Error(err))
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   tenant.ID,
+		Action:     audit.ActionTenantUpdate,
+		TargetType: audit.TargetTenant,
+		TargetID:   tenant.ID,
+		Before:     before,
+		After:      tenant,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, tenant)
+}
+
+// UpdateSettings godoc
+// @Summary      Update tenant settings
+// @Description  Update tenant policy settings such as requiring verified emails to log in and the deposit amount policy (exact, allow_overpayment or allow_partial; unchanged when omitted)
+// @Tags         tenants
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Tenant ID"
+// @Param        body body      tenants.TenantSettingsRequest true "Tenant settings payload"
+// @Success      200  {object}  tenants.Tenant
+// @Failure      400  {object}  model.ErrorResponse
+// @Router       /tenant/{id}/settings [put]
+func (h *Tenants) UpdateSettings(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req tenants.TenantSettingsRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	before, err := h.service.GetTenantByID(ctx, id)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	tenant, err := h.service.UpdateTenantSettings(ctx, id, req)
+	if err != nil {
+		h.env.Logger.Error("Failed to update tenant settings", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   tenant.ID,
+		Action:     audit.ActionTenantSettingsUpdate,
+		TargetType: audit.TargetTenant,
+		TargetID:   tenant.ID,
+		Before:     before,
+		After:      tenant,
+	})
+
 	return utils.SendSuccessResponse(c, fiber.StatusOK, tenant)
 }
 
@@ -187,10 +261,23 @@ func (h *Tenants) Delete(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 
+	before, err := h.service.GetTenantByID(ctx, id)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
 	if err := h.service.DeleteTenant(ctx, id); err != nil {
 		h.env.Logger.Error("Failed to delete tenant", zap.Error(err))
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	// Logs of the tenant go with it, so the deletion is kept as a platform entry
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionTenantDelete,
+		TargetType: audit.TargetTenant,
+		TargetID:   id,
+		Before:     before,
+	})
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -1,40 +1,78 @@
 package handler
 
 import (
+	"codematic/internal/domain/apikeys"
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/exports"
 	"codematic/internal/domain/transactions"
 	"codematic/internal/domain/user"
+	"codematic/internal/domain/wallet"
 	"codematic/internal/middleware"
 	"codematic/internal/shared/model"
 	"codematic/internal/shared/utils"
 	"context"
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
 )
 
 type Transactions struct {
-	service     transactions.Service
-	userService user.Service
-	env         *Environment
+	service       transactions.Service
+	userService   user.Service
+	exportService exports.Service
+	env           *Environment
 }
 
 func (h *Transactions) Init(basePath string, env *Environment) error {
 	h.env = env
 	h.service = env.Services.Transactions
 	h.userService = env.Services.User
+	h.exportService = env.Services.Exports
 
 	group := env.Fiber.Group(basePath + "/transactions")
-	protected := group.Use(middleware.JWTMiddleware(
-		env.JWTManager,
-		env.CacheManager,
-	))
 
+	// Authorized by the signed link rather than a token, so files can be
+	// handed to a browser or spreadsheet tool
+	group.Get("/exports/:id/download", h.DownloadExport)
+
+	protected := group.Use(
+		middleware.AuthMiddleware(env.JWTManager, env.CacheManager, env.Services.APIKeys),
+		middleware.RequireScope(apikeys.ScopeTransactionsRead),
+	)
+
+	protected.Post("/exports", h.RequestExport)
+	protected.Get("/exports", h.ListExports)
+	protected.Get("/exports/:id", h.GetExport)
+	protected.Get("/transfers/:id", h.GetTransfer)
 	protected.Get("/:id", h.GetTransactionByID)
-	protected.Get("/", h.ListTransactions)
+	noImpersonation := middleware.BlockImpersonatedMoneyMovement()
+	protected.Post("/:id/review", middleware.RequirePermission(model.PermDepositsReview), noImpersonation,
+		h.ReviewDeposit)
+	protected.Post("/:id/reversals", middleware.RequirePermission(model.PermTransactionsRefund), noImpersonation,
+		h.Reverse)
+	protected.Get("/:id/reversals", middleware.RequirePermission(model.PermTransactionsRefund), h.ListReversals)
+	protected.Get("/", h.Search)
 
 	return nil
 }
 
+// accessRole returns the role whose transaction visibility applies. Staff with
+// transactions:read from a custom role, and API keys with that scope, see their
+// whole tenant like a tenant admin. API keys without it see nothing.
+func accessRole(c *fiber.Ctx) string {
+	role := utils.ExtractUserRoleFromJWT(c)
+	if (role == model.RoleUser.String() || role == model.RoleAPIKey.String()) &&
+		utils.HasPermission(c, model.PermTransactionsRead) {
+		return model.RoleTenantAdmin.String()
+	}
+	return role
+}
+
 func (h *Transactions) validateUserActive(c *fiber.Ctx) error {
 
 	userID := utils.ExtractUserIDFromJWT(c)
@@ -71,7 +109,7 @@ func (h *Transactions) GetTransactionByID(c *fiber.Ctx) error {
 	}
 	id := c.Params("id")
 	userID := utils.ExtractUserIDFromJWT(c)
-	role := utils.ExtractUserRoleFromJWT(c)
+	role := accessRole(c)
 	tenantID := utils.ExtractTenantFromJWT(c)
 
 	tx, err := h.service.GetTransactionByID(c.Context(), id)
@@ -100,72 +138,543 @@ func (h *Transactions) GetTransactionByID(c *fiber.Ctx) error {
 	return utils.SendSuccessResponse(c, fiber.StatusOK, tx)
 }
 
-// ListTransactions godoc
-// @Summary      List transactions
-// @Description  Lists transactions with access control and optional filters
+// GetTransfer godoc
+// @Summary      Get an internal transfer
+// @Description  Retrieves a transfer between wallets with both parties: the sender's wallet and debit transaction, and the receiver's wallet and credit transaction. Users see transfers they sent or received, tenant staff their tenant's and platform admins any.
 // @Tags         transactions
-// @Accept       json
 // @Produce      json
-// @Param        status   query   string  false  "Transaction status filter"
-// @Param        limit    query   int     false  "Limit"
-// @Param        offset   query   int     false  "Offset"
-// @Success      200   {object}  map[string][]transactions.Transaction
-// @Failure      400   {object}  map[string]string
-// @Failure      401   {object}  map[string]string
-// @Failure      403   {object}  map[string]string
-// @Router       /transactions [get]
-func (h *Transactions) ListTransactions(c *fiber.Ctx) error {
-
+// @Param        id   path      string  true  "Transfer ID"
+// @Success      200  {object}  wallet.TransferDetails
+// @Failure      401  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /transactions/transfers/{id} [get]
+func (h *Transactions) GetTransfer(c *fiber.Ctx) error {
 	if err := h.validateUserActive(c); err != nil {
 		return err
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	transfer, err := h.env.Services.Wallet.GetTransfer(ctx, c.Params("id"))
+	if err != nil {
+		if errors.Is(err, model.ErrTransferNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to get transfer", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get transfer")
+	}
+
+	// Transfers the caller may not see are reported as missing
 	userID := utils.ExtractUserIDFromJWT(c)
+	visible := false
+	switch accessRole(c) {
+	case model.RoleUser.String():
+		visible = transfer.Sender.UserID == userID || transfer.Receiver.UserID == userID
+	case model.RoleTenantAdmin.String():
+		visible = transfer.TenantID == utils.ExtractTenantFromJWT(c)
+	case model.RolePlatformAdmin.String():
+		visible = true
+	}
+	if !visible {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, model.ErrTransferNotFound.Error())
+	}
 
-	role := utils.ExtractUserRoleFromJWT(c)
+	return utils.SendSuccessResponse(c, fiber.StatusOK, transfer)
+}
+
+// ReviewDeposit godoc
+// @Summary      Review a flagged deposit
+// @Description  Settles a deposit that was flagged because the amount or currency paid did not meet the tenant's deposit amount policy. credit adds the amount actually paid to the wallet and needs the payment to be in the wallet's currency; reject fails the deposit so the payment can be refunded. Tenant staff review their own tenant's deposits.
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                       true  "Transaction ID"
+// @Param        body  body      wallet.ReviewDepositRequest  true  "Decision"
+// @Success      200   {object}  wallet.Transaction
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /transactions/{id}/review [post]
+func (h *Transactions) ReviewDeposit(c *fiber.Ctx) error {
+	var req wallet.ReviewDepositRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
 
 	tenantID := utils.ExtractTenantFromJWT(c)
+	if utils.ExtractUserRoleFromJWT(c) == model.RolePlatformAdmin.String() {
+		tenantID = ""
+	}
 
-	status := c.Query("status")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
 
-	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	tx, err := h.env.Services.Wallet.ReviewDeposit(ctx, c.Params("id"), tenantID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidInputError):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrTransactionNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		case errors.Is(err, model.ErrDepositNotFlagged):
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
+		h.env.Logger.Error("Failed to review deposit", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to review deposit")
+	}
 
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   tx.TenantID,
+		Action:     audit.ActionDepositReview,
+		TargetType: audit.TargetTransaction,
+		TargetID:   tx.ID,
+		After:      tx,
+		Metadata: map[string]any{
+			"decision": req.Decision,
+			"note":     req.Note,
+		},
+	})
 
-	var txs []*transactions.Transaction
+	return utils.SendSuccessResponse(c, fiber.StatusOK, tx)
+}
 
-	var err error
+// Reverse godoc
+// @Summary      Reverse a transaction
+// @Description  Reverses all or part of a completed deposit, withdrawal or transfer by posting compensating transactions linked to the original, which is left unchanged. Without an amount whatever is left to reverse is reversed. Reversals of a transaction never add up to more than it moved; fees are not given back. A reversed deposit is refunded to the customer through the provider when it supports refunds (refund_status submitted) and is otherwise left for a manual payout (refund_status manual); if the provider refuses the refund the reversal fails, the wallet is credited back and 502 is returned. A reversal whose refund could not be settled yet is returned pending with 202 and finished by a background job. Tenant staff reverse their own tenant's transactions.
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                             true  "Transaction ID"
+// @Param        body  body      wallet.ReverseTransactionRequest  true  "Reversal"
+// @Success      201   {object}  wallet.Reversal
+// @Success      202   {object}  wallet.Reversal
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Failure      502   {object}  model.ErrorResponse
+// @Router       /transactions/{id}/reversals [post]
+func (h *Transactions) Reverse(c *fiber.Ctx) error {
+	var req wallet.ReverseTransactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
 
-	h.env.Logger.Sugar().Debug("ListTransactions role is ", role)
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
 
-	switch role {
+	form := wallet.ReversalForm{
+		TransactionID: c.Params("id"),
+		TenantID:      utils.ExtractTenantFromJWT(c),
+		UserID:        utils.ExtractUserIDFromJWT(c),
+		Reason:        req.Reason,
+	}
+	if utils.ExtractUserRoleFromJWT(c) == model.RolePlatformAdmin.String() {
+		form.TenantID = ""
+	}
+	if req.Amount != "" {
+		amount, err := decimal.NewFromString(req.Amount)
+		if err != nil || !amount.IsPositive() {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, "amount must be positive")
+		}
+		form.Amount = amount
+	}
 
-	case model.RoleUser.String():
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	reversal, err := h.env.Services.Wallet.ReverseTransaction(ctx, form)
+	if reversal != nil {
+		recordAudit(h.env, c, audit.Entry{
+			TenantID:   reversal.TenantID,
+			Action:     audit.ActionTransactionReverse,
+			TargetType: audit.TargetTransaction,
+			TargetID:   reversal.TransactionID,
+			After:      reversal,
+		})
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidInputError):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrTransactionNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		case errors.Is(err, model.ErrTransactionNotReversible),
+			errors.Is(err, model.ErrReversalExceedsOriginal),
+			errors.Is(err, model.ErrInsufficientBalance):
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		case errors.Is(err, model.ErrRefundFailed):
+			return utils.SendErrorResponse(c, fiber.StatusBadGateway, err.Error())
+		}
+		h.env.Logger.Error("Failed to reverse transaction", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to reverse transaction")
+	}
+
+	// A refund that could not be settled yet is finished by a background job
+	if reversal.Status == wallet.StatusPending {
+		return utils.SendSuccessResponse(c, fiber.StatusAccepted, reversal)
+	}
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, reversal)
+}
+
+// ListReversals godoc
+// @Summary      List a transaction's reversals
+// @Description  Lists the reversals of a transaction, oldest first, including failed ones. Tenant staff see their own tenant's transactions.
+// @Tags         transactions
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {array}   wallet.Reversal
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /transactions/{id}/reversals [get]
+func (h *Transactions) ListReversals(c *fiber.Ctx) error {
+	tenantID := utils.ExtractTenantFromJWT(c)
+	if utils.ExtractUserRoleFromJWT(c) == model.RolePlatformAdmin.String() {
+		tenantID = ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	reversals, err := h.env.Services.Wallet.ListReversals(ctx, c.Params("id"), tenantID)
+	if err != nil {
+		if errors.Is(err, model.ErrTransactionNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to list reversals", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list reversals")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, reversals)
+}
+
+// Search godoc
+// @Summary      Search transactions
+// @Description  Searches the transactions the caller may see, newest first by default, one page at a time. Users see their own wallets, tenant staff their tenant and platform admins every tenant. type, status, currency and metadata may be repeated or comma-separated; metadata takes key:value to match a value or key to require the key. Pass next_cursor back as cursor, with the same filters, for the next page.
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        from         query     string  false  "Created from (RFC3339, inclusive)"
+// @Param        to           query     string  false  "Created to (RFC3339, exclusive)"
+// @Param        type         query     string  false  "deposit, withdrawal or transfer"
+// @Param        status       query     string  false  "pending, completed or failed"
+// @Param        currency     query     string  false  "Currency code"
+// @Param        min_amount   query     string  false  "Minimum amount (inclusive)"
+// @Param        max_amount   query     string  false  "Maximum amount (inclusive)"
+// @Param        provider_id  query     string  false  "Provider ID"
+// @Param        wallet_id    query     string  false  "Wallet ID"
+// @Param        reference    query     string  false  "Exact reference"
+// @Param        metadata     query     string  false  "Metadata key or key:value"
+// @Param        tenant_id    query     string  false  "Tenant ID (platform admins only)"
+// @Param        sort         query     string  false  "created_at (default) or amount"
+// @Param        order        query     string  false  "desc (default) or asc"
+// @Param        cursor       query     string  false  "next_cursor of the previous page"
+// @Param        limit        query     int     false  "Limit (default 20, max 100)"
+// @Success      200          {object}  transactions.SearchResult
+// @Failure      400          {object}  model.ErrorResponse
+// @Failure      401          {object}  model.ErrorResponse
+// @Failure      403          {object}  model.ErrorResponse
+// @Router       /transactions [get]
+func (h *Transactions) Search(c *fiber.Ctx) error {
+	if err := h.validateUserActive(c); err != nil {
+		return err
+	}
+
+	filter, err := transactionFilter(c)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	if !scopeFilter(c, &filter) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, "forbidden")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	result, err := h.service.Search(ctx, filter)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInputError) || errors.Is(err, model.ErrInvalidCursor) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to search transactions", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to search transactions")
+	}
 
-		txs, err = h.service.ListTransactionsByUserID(c.Context(), userID, limit, offset)
+	return utils.SendSuccessResponse(c, fiber.StatusOK, result)
+}
+
+// scopeFilter limits filter to the transactions the caller may see, reporting
+// false when they may see none
+func scopeFilter(c *fiber.Ctx, filter *transactions.SearchFilter) bool {
+	switch accessRole(c) {
+	case model.RoleUser.String():
+		filter.UserID = utils.ExtractUserIDFromJWT(c)
 
 	case model.RoleTenantAdmin.String():
-		if status != "" {
-			txs, err = h.service.ListTransactionsByStatus(c.Context(), status, limit, offset)
-		} else {
-			txs, err = h.service.ListTransactionsByTenantID(c.Context(), tenantID, limit, offset)
+		filter.TenantID = utils.ExtractTenantFromJWT(c)
+		if filter.TenantID == "" {
+			return false
 		}
 
 	case model.RolePlatformAdmin.String():
-		if status != "" {
-			txs, err = h.service.ListTransactionsByStatus(c.Context(), status, limit, offset)
+		filter.TenantID = c.Query("tenant_id")
+
+	default:
+		return false
+	}
+	return true
+}
+
+// transactionFilter reads the search filters and paging from the query string.
+// The access scope is left to the caller.
+func transactionFilter(c *fiber.Ctx) (transactions.SearchFilter, error) {
+	filter := transactions.SearchFilter{
+		WalletID:   c.Query("wallet_id"),
+		ProviderID: c.Query("provider_id"),
+		Reference:  c.Query("reference"),
+		Types:      queryList(c, "type"),
+		Statuses:   queryList(c, "status"),
+		Currencies: queryList(c, "currency"),
+		SortBy:     c.Query("sort"),
+		Cursor:     c.Query("cursor"),
+	}
+	for i, currency := range filter.Currencies {
+		filter.Currencies[i] = strings.ToUpper(currency)
+	}
+
+	switch c.Query("order", "desc") {
+	case "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return transactions.SearchFilter{}, errors.New("order must be asc or desc")
+	}
+
+	for param, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return transactions.SearchFilter{}, errors.New(param + " must be an RFC3339 timestamp")
 		}
+		*dst = &t
+	}
 
-		if status == "" {
-			txs, err = h.service.ListAllTransactions(c.Context(), limit, offset)
+	for param, dst := range map[string]**decimal.Decimal{
+		"min_amount": &filter.MinAmount,
+		"max_amount": &filter.MaxAmount,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
 		}
-	default:
+		amount, err := decimal.NewFromString(value)
+		if err != nil {
+			return transactions.SearchFilter{}, errors.New(param + " must be a number")
+		}
+		*dst = &amount
+	}
+
+	for _, entry := range queryList(c, "metadata") {
+		key, value, hasValue := strings.Cut(entry, ":")
+		if key == "" {
+			return transactions.SearchFilter{}, errors.New("metadata must be key or key:value")
+		}
+		if !hasValue {
+			filter.MetadataKeys = append(filter.MetadataKeys, key)
+			continue
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = make(map[string]string)
+		}
+		filter.Metadata[key] = value
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return transactions.SearchFilter{}, errors.New("limit must be a number")
+		}
+		filter.Limit = n
+	}
+
+	return filter, nil
+}
+
+// queryList collects a query parameter given repeatedly, comma-separated, or
+// both
+func queryList(c *fiber.Ctx, param string) []string {
+	var values []string
+	for _, raw := range c.Context().QueryArgs().PeekMulti(param) {
+		for _, v := range strings.Split(string(raw), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// RequestExport godoc
+// @Summary      Export transactions
+// @Description  Queues a CSV or XLSX export of the transactions matching the same filters and sort as the search, with the same access control. The file is generated in the background; poll the export until it is completed to get a download link.
+// @Tags         transactions
+// @Produce      json
+// @Param        format       query     string  true   "csv or xlsx"
+// @Param        from         query     string  false  "Created from (RFC3339, inclusive)"
+// @Param        to           query     string  false  "Created to (RFC3339, exclusive)"
+// @Param        type         query     string  false  "deposit, withdrawal or transfer"
+// @Param        status       query     string  false  "pending, completed or failed"
+// @Param        currency     query     string  false  "Currency code"
+// @Param        min_amount   query     string  false  "Minimum amount (inclusive)"
+// @Param        max_amount   query     string  false  "Maximum amount (inclusive)"
+// @Param        provider_id  query     string  false  "Provider ID"
+// @Param        wallet_id    query     string  false  "Wallet ID"
+// @Param        reference    query     string  false  "Exact reference"
+// @Param        metadata     query     string  false  "Metadata key or key:value"
+// @Param        tenant_id    query     string  false  "Tenant ID (platform admins only)"
+// @Param        sort         query     string  false  "created_at (default) or amount"
+// @Param        order        query     string  false  "desc (default) or asc"
+// @Success      202          {object}  exports.Export
+// @Failure      400          {object}  model.ErrorResponse
+// @Failure      401          {object}  model.ErrorResponse
+// @Failure      403          {object}  model.ErrorResponse
+// @Router       /transactions/exports [post]
+func (h *Transactions) RequestExport(c *fiber.Ctx) error {
+	if err := h.validateUserActive(c); err != nil {
+		return err
+	}
+
+	filter, err := transactionFilter(c)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	if !scopeFilter(c, &filter) {
 		return utils.SendErrorResponse(c, fiber.StatusForbidden, "forbidden")
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	export, err := h.exportService.Request(ctx, exports.Request{
+		UserID:   utils.ExtractUserIDFromJWT(c),
+		TenantID: utils.ExtractTenantFromJWT(c),
+		Format:   c.Query("format"),
+		Filter:   filter,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInputError) {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		h.env.Logger.Error("Failed to request transaction export", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to request export")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionTransactionExport,
+		TargetType: audit.TargetExport,
+		TargetID:   export.ID,
+		Metadata:   map[string]any{"format": export.Format},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusAccepted, export)
+}
+
+// ListExports godoc
+// @Summary      List transaction exports
+// @Description  Lists the caller's most recent exports, newest first
+// @Tags         transactions
+// @Produce      json
+// @Success      200  {array}   exports.Export
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /transactions/exports [get]
+func (h *Transactions) ListExports(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	list, err := h.exportService.List(ctx, utils.ExtractUserIDFromJWT(c))
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		h.env.Logger.Error("Failed to list transaction exports", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list exports")
 	}
 
-	return utils.SendSuccessResponse(c, fiber.StatusOK, fiber.Map{"transactions": txs})
+	return utils.SendSuccessResponse(c, fiber.StatusOK, list)
+}
+
+// GetExport godoc
+// @Summary      Get a transaction export
+// @Description  Returns the status of one of the caller's exports. Completed exports carry a signed download_url that expires at link_expires_at; fetch the export again for a fresh one.
+// @Tags         transactions
+// @Produce      json
+// @Param        id   path      string  true  "Export ID"
+// @Success      200  {object}  exports.Export
+// @Failure      401  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /transactions/exports/{id} [get]
+func (h *Transactions) GetExport(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	export, err := h.exportService.Get(ctx, c.Params("id"), utils.ExtractUserIDFromJWT(c))
+	if err != nil {
+		if errors.Is(err, model.ErrExportNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to get transaction export", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get export")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, export)
+}
+
+// DownloadExport godoc
+// @Summary      Download a transaction export
+// @Description  Streams an export file. Authorized by the signed link from the export, not a token.
+// @Tags         transactions
+// @Produce      octet-stream
+// @Param        id         path      string  true  "Export ID"
+// @Param        expires    query     int     true  "Link expiry (Unix seconds)"
+// @Param        signature  query     string  true  "Link signature"
+// @Success      200        {file}    file
+// @Failure      403        {object}  model.ErrorResponse
+// @Failure      404        {object}  model.ErrorResponse
+// @Router       /transactions/exports/{id}/download [get]
+func (h *Transactions) DownloadExport(c *fiber.Ctx) error {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInvalidDownloadLink.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	download, err := h.exportService.Open(ctx, c.Params("id"), expires, c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidDownloadLink):
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+		case errors.Is(err, model.ErrExportNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to open transaction export", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to download export")
+	}
+
+	c.Set(fiber.HeaderContentType, download.ContentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+download.FileName+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	// Fiber closes the body once it has been sent
+	return c.SendStream(download.Body, int(download.Size))
 }
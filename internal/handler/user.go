@@ -1,19 +1,395 @@
 package handler
 
 import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/auth"
 	"codematic/internal/domain/user"
+	"codematic/internal/middleware"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
+var errCannotModifySelf = errors.New("you cannot change your own account")
+
 type User struct {
-	service user.Service
-	env     *Environment
+	service     user.Service
+	authService auth.Service
+	env         *Environment
 }
 
 func (h *User) Init(basePath string, env *Environment) error {
 	h.env = env
 
 	h.service = env.Services.User
+	h.authService = env.Services.Auth
+
+	// Tenant admin user management; every query is scoped to the caller's tenant
+	group := env.Fiber.Group(basePath + "/users")
+	protected := group.Use(
+		middleware.JWTMiddleware(env.JWTManager, env.CacheManager),
+		middleware.RequireTenant(),
+	)
+
+	protected.Put("/me/username", h.SetUsername)
+	protected.Get("/", middleware.RequirePermission(model.PermUsersRead), h.List)
+	protected.Get("/:id", middleware.RequirePermission(model.PermUsersRead), h.GetByID)
+	protected.Post("/", middleware.RequirePermission(model.PermUsersWrite), h.Create)
+	protected.Post("/:id/deactivate", middleware.RequirePermission(model.PermUsersWrite), h.Deactivate)
+	protected.Post("/:id/reactivate", middleware.RequirePermission(model.PermUsersWrite), h.Reactivate)
+	protected.Put("/:id/role", middleware.RequirePermission(model.PermUsersWrite), h.UpdateRole)
 
 	return nil
 
 }
+
+// List godoc
+// @Summary      List tenant users
+// @Description  Lists users of the caller's tenant, newest first. Search matches email or phone.
+// @Tags         users
+// @Produce      json
+// @Param        search  query   string  false  "Email or phone contains"
+// @Param        role    query   string  false  "USER or TENANT_ADMIN"
+// @Param        status  query   string  false  "active or inactive"
+// @Param        limit   query   int     false  "Limit (default 20, max 100)"
+// @Param        offset  query   int     false  "Offset"
+// @Success      200     {object}  user.UserList
+// @Failure      400     {object}  model.ErrorResponse
+// @Router       /users [get]
+func (h *User) List(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	filter := user.ListUsersFilter{
+		Search: c.Query("search"),
+		Role:   c.Query("role"),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	switch c.Query("status") {
+	case "":
+	case "active":
+		active := true
+		filter.IsActive = &active
+	case "inactive":
+		active := false
+		filter.IsActive = &active
+	default:
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "status must be active or inactive")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	users, err := h.service.ListTenantUsers(ctx, utils.ExtractTenantFromJWT(c), filter)
+	if err != nil {
+		h.env.Logger.Error("Failed to list users", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list users")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, users)
+}
+
+// GetByID godoc
+// @Summary      Get a tenant user
+// @Description  Gets a user of the caller's tenant
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  user.User
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /users/{id} [get]
+func (h *User) GetByID(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	u, err := h.service.GetTenantUser(ctx, utils.ExtractTenantFromJWT(c), c.Params("id"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, u)
+}
+
+// Create godoc
+// @Summary      Create a tenant user
+// @Description  Creates a user and wallet in the caller's tenant. Only tenant admins can create other tenant admins.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      user.CreateTenantUserRequest  true  "User payload"
+// @Success      201   {object}  user.User
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Router       /users [post]
+func (h *User) Create(c *fiber.Ctx) error {
+	var req user.CreateTenantUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	role := model.RoleUser
+	if req.Role != "" {
+		role = model.UserRole(req.Role)
+	}
+	if !canManageRole(c, role.String()) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	created, err := h.authService.Signup(ctx, &auth.SignupRequest{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Password:  req.Password,
+		TenantID:  tenantID,
+		Role:      role,
+	})
+	if err != nil {
+		h.env.Logger.Error("Failed to create user", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	u, err := h.service.GetTenantUser(ctx, tenantID, created.ID)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to load created user")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionUserCreate,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		After:      u,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusCreated, u)
+}
+
+// Deactivate godoc
+// @Summary      Deactivate a tenant user
+// @Description  Blocks the user from logging in and revokes all of their active sessions
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  user.User
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /users/{id}/deactivate [post]
+func (h *User) Deactivate(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+// Reactivate godoc
+// @Summary      Reactivate a tenant user
+// @Description  Allows a deactivated user to log in again
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  user.User
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /users/{id}/reactivate [post]
+func (h *User) Reactivate(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+func (h *User) setActive(c *fiber.Ctx, active bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+	userID := c.Params("id")
+
+	before, err := h.checkTarget(ctx, c, tenantID, userID)
+	if err != nil {
+		return sendUserError(c, err)
+	}
+
+	u, err := h.service.SetTenantUserActive(ctx, tenantID, userID, active)
+	if err != nil {
+		return sendUserError(c, err)
+	}
+
+	action := audit.ActionUserDeactivate
+	if active {
+		action = audit.ActionUserReactivate
+	}
+	recordAudit(h.env, c, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     before,
+		After:      u,
+	})
+
+	if !active {
+		if err := h.authService.RevokeUserSessions(ctx, userID); err != nil {
+			h.env.Logger.Error("Failed to revoke sessions of deactivated user",
+				zap.String("userID", userID), zap.Error(err))
+			return utils.SendErrorResponse(c, fiber.StatusInternalServerError,
+				"User deactivated but sessions could not be revoked")
+		}
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, u)
+}
+
+// UpdateRole godoc
+// @Summary      Change a tenant user's role
+// @Description  Sets the user's base role to USER or TENANT_ADMIN and signs them out so the new role applies immediately. Only tenant admins can grant or revoke TENANT_ADMIN.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                  true  "User ID"
+// @Param        body  body      user.UpdateRoleRequest  true  "Role payload"
+// @Success      200   {object}  user.User
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Router       /users/{id}/role [put]
+func (h *User) UpdateRole(c *fiber.Ctx) error {
+	var req user.UpdateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if !canManageRole(c, req.Role) {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+	userID := c.Params("id")
+
+	before, err := h.checkTarget(ctx, c, tenantID, userID)
+	if err != nil {
+		return sendUserError(c, err)
+	}
+
+	u, err := h.service.UpdateTenantUserRole(ctx, tenantID, userID, req.Role)
+	if err != nil {
+		return sendUserError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionUserRoleChange,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     before,
+		After:      u,
+	})
+
+	// Tokens carry the role and its permissions, so force a fresh login
+	if err := h.authService.RevokeUserSessions(ctx, userID); err != nil {
+		h.env.Logger.Error("Failed to revoke sessions after role change",
+			zap.String("userID", userID), zap.Error(err))
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, u)
+}
+
+// checkTarget stops callers from changing their own account and non-admin
+// staff from changing tenant admins. It returns the target as it is now.
+func (h *User) checkTarget(ctx context.Context, c *fiber.Ctx, tenantID, userID string) (user.User, error) {
+	if userID == utils.ExtractUserIDFromJWT(c) {
+		return user.User{}, errCannotModifySelf
+	}
+
+	target, err := h.service.GetTenantUser(ctx, tenantID, userID)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	if !canManageRole(c, target.Role) {
+		return user.User{}, model.ErrInsufficientPermission
+	}
+	return target, nil
+}
+
+// SetUsername godoc
+// @Summary      Set your username
+// @Description  Sets the caller's username, which other users of the tenant can send money to. Usernames are 3-30 characters of lower case letters, digits, dots and underscores, start with a letter and are unique within the tenant. A leading @ is dropped.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      user.SetUsernameRequest  true  "Username payload"
+// @Success      200   {object}  user.User
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      409   {object}  model.ErrorResponse
+// @Router       /users/me/username [put]
+func (h *User) SetUsername(c *fiber.Ctx) error {
+	var req user.SetUsernameRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	userID := utils.ExtractUserIDFromJWT(c)
+	u, err := h.service.SetUsername(ctx, userID, req.Username)
+	if err != nil {
+		return sendUserError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionUsernameChange,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      u,
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, u)
+}
+
+func sendUserError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrUserNotFound):
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrInsufficientPermission), errors.Is(err, errCannotModifySelf):
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, model.ErrUsernameTaken):
+		return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+	default:
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+}
+
+// canManageRole reports whether the caller may create or modify users with
+// role. TENANT_ADMIN accounts are reserved to tenant admins, so a custom role
+// holding users:write cannot mint admins.
+func canManageRole(c *fiber.Ctx, role string) bool {
+	if role != model.RoleTenantAdmin.String() {
+		return true
+	}
+	return utils.ExtractUserRoleFromJWT(c) == model.RoleTenantAdmin.String()
+}
@@ -1,17 +1,23 @@
 package handler
 
 import (
+	"bytes"
+	"codematic/internal/domain/audit"
 	"codematic/internal/domain/idempotency"
+	"codematic/internal/domain/statements"
 	"codematic/internal/domain/user"
 	"codematic/internal/domain/wallet"
 	"codematic/internal/middleware"
 	"codematic/internal/shared/model"
 	"codematic/internal/shared/utils"
 	"context"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
 )
 
 type Wallet struct {
@@ -28,6 +34,15 @@ func (h *Wallet) Init(basePath string, env *Environment) error {
 	h.service = env.Services.Wallet
 	h.userService = env.Services.User
 
+	// Staff routes are registered first: the user-only middleware below is
+	// mounted on the /wallet prefix, which /wallets also starts with
+	staff := env.Fiber.Group(basePath+"/wallets", middleware.JWTMiddleware(
+		env.JWTManager,
+		env.CacheManager,
+	))
+	staff.Get("/:id", middleware.RequirePermission(model.PermWalletsRead), h.GetWallet)
+	staff.Patch("/:id/status", middleware.RequirePermission(model.PermWalletsFreeze), h.UpdateStatus)
+
 	group := env.Fiber.Group(basePath + "/wallet")
 
 	protected := group.Use(middleware.JWTMiddleware(
@@ -38,13 +53,68 @@ func (h *Wallet) Init(basePath string, env *Environment) error {
 	idm := middleware.NewIdempotencyMiddleware(idempotencyRepo)
 
 	userOnly := protected.Use(utils.RequireRole(model.RoleUser))
+	noImpersonation := middleware.BlockImpersonatedMoneyMovement()
 
 	// Add idempotency middleware to transaction-creating routes
-	userOnly.Post("/initiate_deposit", idm.Handle, h.InitiateDeposit)
-	userOnly.Post("/withdraw", idm.Handle, h.Withdraw)
-	userOnly.Post("/transfer", idm.Handle, h.Transfer)
+	userOnly.Post("/initiate_deposit", noImpersonation, idm.Handle, h.InitiateDeposit)
+	withdrawLimit := middleware.RateLimitMiddleware(env.CacheManager, middleware.RateLimitRule{
+		Name: "withdraw", Limit: env.Config.RateLimitWithdraw, Key: middleware.KeyByUser,
+	})
+	transferLimit := middleware.RateLimitMiddleware(env.CacheManager, middleware.RateLimitRule{
+		Name: "transfer", Limit: env.Config.RateLimitTransfer, Key: middleware.KeyByUser,
+	})
+
+	userOnly.Post("/withdraw", noImpersonation, withdrawLimit, idm.Handle, h.Withdraw)
+	userOnly.Post("/transfer", noImpersonation, transferLimit, idm.Handle, h.Transfer)
+	recipientLimit := middleware.RateLimitMiddleware(env.CacheManager, middleware.RateLimitRule{
+		Name: "recipient_lookup", Limit: env.Config.RateLimitRecipientLookup, Key: middleware.KeyByUser,
+	})
+	userOnly.Post("/recipients/resolve", recipientLimit, h.ResolveRecipient)
+	userOnly.Post("/send", noImpersonation, transferLimit, recipientLimit, idm.Handle, h.SendToUser)
 	userOnly.Post("/get-balance", h.GetBalance)
 	userOnly.Post("/get-transactions", h.GetTransactions)
+	userOnly.Get("/:wallet_id/statement", h.GetStatement)
+	userOnly.Get("/:wallet_id/statements", h.ListStatements)
+	userOnly.Get("/:wallet_id/statements/:id", h.DownloadStatement)
+
+	return nil
+}
+
+// requirePIN checks the transaction PIN sent with a withdrawal or transfer
+func (h *Wallet) requirePIN(userID, pin string) error {
+	if pin == "" {
+		return model.ErrTransactionPINRequired
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := h.env.Services.PIN.Verify(ctx, userID, pin)
+	if err != nil && !errors.Is(err, model.ErrInvalidTransactionPIN) &&
+		!errors.Is(err, model.ErrTransactionPINNotSet) && !errors.Is(err, model.ErrTransactionPINLocked) {
+		h.env.Logger.Error("Failed to verify transaction PIN", zap.String("userID", userID), zap.Error(err))
+	}
+	return err
+}
+
+// requireStepUp demands a fresh second factor for withdrawals at or above the
+// configured threshold, even though the session already passed login 2FA.
+func (h *Wallet) requireStepUp(userID string, amount decimal.Decimal, code string) error {
+	threshold := h.env.Config.MFAStepUpWithdrawalAmount
+	if !threshold.IsPositive() || amount.LessThan(threshold) {
+		return nil
+	}
+
+	if code == "" {
+		return model.ErrMFAStepUpRequired
+	}
+
+	if err := h.env.Services.MFA.Verify(context.Background(), userID, code); err != nil {
+		if errors.Is(err, model.ErrMFANotEnabled) {
+			return errors.New("enable two-factor authentication to make withdrawals of this size")
+		}
+		return model.ErrInvalidMFACode
+	}
 
 	return nil
 }
@@ -138,7 +208,18 @@ func (h *Wallet) InitiateDeposit(c *fiber.Ctx) error {
 			err.Error(),
 		)
 	}
-	h.env.Logger.Sugar().Infof("Deposit response: %+v", response)
+	h.env.Logger.Sugar().Infow("Deposit initiated", "reference", response.Reference)
+
+	recordAudit(h.env, c, audit.Entry{
+		Action: audit.ActionDepositInitiate,
+		Metadata: map[string]any{
+			"amount":    amount.String(),
+			"currency":  currency,
+			"channel":   string(req.Channel),
+			"reference": response.Reference,
+			"provider":  response.Provider,
+		},
+	})
 
 	return utils.SendSuccessResponse(c, fiber.StatusOK, fiber.Map{
 		"authorization_url": response.AuthorizationURL,
@@ -153,13 +234,18 @@ func (h *Wallet) InitiateDeposit(c *fiber.Ctx) error {
 
 // Withdraw godoc
 // @Summary      Withdraw funds from a wallet
-// @Description  Withdraws a specified amount from the user's wallet
+// @Description  Withdraws a specified amount from the user's wallet. Needs the user's transaction PIN in X-Transaction-PIN; amounts at or above MFA_STEP_UP_WITHDRAWAL_AMOUNT also need a TOTP code in X-MFA-Code.
 // @Tags         wallet
 // @Accept       json
 // @Produce      json
 // @Param        withdrawRequest  body  object  true  "Withdraw request"
+// @Param        X-Transaction-PIN  header  string  true   "Transaction PIN"
+// @Param        X-MFA-Code       header  string  false  "TOTP or recovery code for high-value withdrawals"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
 // @Router       /wallet/withdraw [post]
 func (h *Wallet) Withdraw(c *fiber.Ctx) error {
 	// Validate user is active before proceeding
@@ -187,9 +273,20 @@ func (h *Wallet) Withdraw(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "invalid amount")
 	}
 
+	if err := h.requirePIN(userID, c.Get(HeaderTransactionPIN)); err != nil {
+		if sendPINError(c, err) {
+			return nil
+		}
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to verify PIN")
+	}
+
+	if err := h.requireStepUp(userID, amount, c.Get("X-MFA-Code")); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+	}
+
 	form := wallet.WithdrawalForm{
 		UserID:   req.UserID,
-		TenantID: req.TenantID,
+		TenantID: utils.ExtractTenantFromJWT(c),
 		WalletID: req.WalletID,
 		Amount:   amount,
 		Provider: req.Provider,
@@ -198,22 +295,39 @@ func (h *Wallet) Withdraw(c *fiber.Ctx) error {
 
 	ctx := context.Background()
 	if err := h.service.Withdraw(ctx, form); err != nil {
+		if errors.Is(err, model.ErrWalletNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, model.ErrWalletNotActive) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionWithdraw,
+		TargetType: audit.TargetWallet,
+		TargetID:   form.WalletID,
+		Metadata:   map[string]any{"amount": amount.String(), "provider": form.Provider},
+	})
+
 	return utils.SendSuccessResponse(c, fiber.StatusOK, fiber.Map{"status": "success"})
 
 }
 
 // Transfer godoc
 // @Summary      Transfer funds between wallets
-// @Description  Transfers a specified amount from one wallet to another
+// @Description  Transfers a specified amount from one wallet to another of the same currency within the caller's tenant. The sender is debited the amount plus any fee and the receiver credited the amount, each with its own transaction. Needs the user's transaction PIN in X-Transaction-PIN.
 // @Tags         wallet
 // @Accept       json
 // @Produce      json
 // @Param        transferRequest  body  object  true  "Transfer request"
-// @Success      200  {object}  map[string]string
+// @Param        X-Transaction-PIN  header  string  true  "Transaction PIN"
+// @Success      200  {object}  wallet.TransferDetails
 // @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
 // @Router       /wallet/transfer [post]
 func (h *Wallet) Transfer(c *fiber.Ctx) error {
 	// Validate user is active before proceeding
@@ -238,9 +352,16 @@ func (h *Wallet) Transfer(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "invalid amount")
 	}
 
+	if err := h.requirePIN(userID, c.Get(HeaderTransactionPIN)); err != nil {
+		if sendPINError(c, err) {
+			return nil
+		}
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to verify PIN")
+	}
+
 	form := wallet.TransferForm{
 		UserID:       req.UserID,
-		TenantID:     req.TenantID,
+		TenantID:     utils.ExtractTenantFromJWT(c),
 		FromWalletID: req.FromWalletID,
 		ToWalletID:   req.ToWalletID,
 		Amount:       amount,
@@ -248,11 +369,148 @@ func (h *Wallet) Transfer(c *fiber.Ctx) error {
 	}
 
 	ctx := context.Background()
-	if err := h.service.Transfer(ctx, form); err != nil {
+	transfer, err := h.service.Transfer(ctx, form)
+	if err != nil {
+		if errors.Is(err, model.ErrWalletNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, model.ErrWalletNotActive) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return utils.SendSuccessResponse(c, fiber.StatusOK, fiber.Map{"status": "success"})
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionTransfer,
+		TargetType: audit.TargetWallet,
+		TargetID:   form.FromWalletID,
+		Metadata: map[string]any{
+			"amount":       amount.String(),
+			"to_wallet_id": form.ToWalletID,
+			"transfer_id":  transfer.ID,
+		},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, transfer)
+}
+
+// ResolveRecipient godoc
+// @Summary      Look up a P2P recipient
+// @Description  Finds the user of the caller's tenant that an email, phone number or username refers to and returns their masked name, so the sender can confirm who they are paying. The recipient must have a wallet in the given currency.
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        request  body  wallet.ResolveRecipientRequest  true  "Recipient lookup"
+// @Success      200  {object}  wallet.Recipient
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Router       /wallet/recipients/resolve [post]
+func (h *Wallet) ResolveRecipient(c *fiber.Ctx) error {
+	if err := h.validateUserActive(c); err != nil {
+		return err
+	}
+
+	var req wallet.ResolveRecipientRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "invalid input")
+	}
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	recipient, err := h.service.ResolveRecipient(context.Background(),
+		utils.ExtractTenantFromJWT(c), utils.ExtractUserIDFromJWT(c), req)
+	if err != nil {
+		return h.sendRecipientError(c, err)
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, recipient)
+}
+
+// SendToUser godoc
+// @Summary      Send money to another user
+// @Description  Transfers money to another user of the same tenant, found by email, phone number or username. The money goes to the recipient's wallet in the currency of the sending wallet. The tenant must allow P2P transfers. Needs the user's transaction PIN in X-Transaction-PIN.
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        request  body  wallet.SendToUserRequest  true  "Send request"
+// @Param        X-Transaction-PIN  header  string  true  "Transaction PIN"
+// @Success      200  {object}  wallet.TransferDetails
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Router       /wallet/send [post]
+func (h *Wallet) SendToUser(c *fiber.Ctx) error {
+	if err := h.validateUserActive(c); err != nil {
+		return err
+	}
+
+	var req wallet.SendToUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "invalid input")
+	}
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "invalid amount")
+	}
+
+	userID := utils.ExtractUserIDFromJWT(c)
+	if err := h.requirePIN(userID, c.Get(HeaderTransactionPIN)); err != nil {
+		if sendPINError(c, err) {
+			return nil
+		}
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to verify PIN")
+	}
+
+	form := wallet.SendToUserForm{
+		UserID:       userID,
+		TenantID:     utils.ExtractTenantFromJWT(c),
+		FromWalletID: req.FromWalletID,
+		Recipient:    req.Recipient,
+		Amount:       amount,
+		Metadata:     req.Metadata,
+	}
+
+	transfer, err := h.service.SendToUser(context.Background(), form)
+	if err != nil {
+		return h.sendRecipientError(c, err)
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		Action:     audit.ActionTransfer,
+		TargetType: audit.TargetWallet,
+		TargetID:   form.FromWalletID,
+		Metadata: map[string]any{
+			"amount":       amount.String(),
+			"to_wallet_id": transfer.Receiver.WalletID,
+			"transfer_id":  transfer.ID,
+		},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, transfer)
+}
+
+// sendRecipientError maps P2P lookup and transfer errors to responses
+func (h *Wallet) sendRecipientError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrP2PTransfersDisabled):
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+	case errors.Is(err, model.ErrRecipientNotFound), errors.Is(err, model.ErrWalletNotFound):
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrWalletNotActive):
+		return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, model.ErrInvalidInputError), errors.Is(err, model.ErrInsufficientBalance):
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 }
 
 // GetBalance godoc
@@ -320,3 +578,221 @@ func (h *Wallet) GetTransactions(c *fiber.Ctx) error {
 	}
 	return utils.SendSuccessResponse(c, fiber.StatusOK, fiber.Map{"transactions": txs})
 }
+
+// GetStatement godoc
+// @Summary      Get a wallet statement
+// @Description  Builds a statement of one of the caller's wallets for any period of up to 366 days: opening balance, each completed transaction with its running balance, totals and closing balance. Dates are UTC and both ends are inclusive.
+// @Tags         wallet
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/pdf
+// @Param        wallet_id  path      string  true   "Wallet ID"
+// @Param        from       query     string  true   "First day (YYYY-MM-DD)"
+// @Param        to         query     string  true   "Last day (YYYY-MM-DD)"
+// @Param        format     query     string  false  "json (default), csv or pdf"
+// @Success      200        {object}  statements.Statement
+// @Failure      400        {object}  model.ErrorResponse
+// @Failure      404        {object}  model.ErrorResponse
+// @Router       /wallet/{wallet_id}/statement [get]
+func (h *Wallet) GetStatement(c *fiber.Ctx) error {
+	from, err := time.Parse(time.DateOnly, c.Query("from"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "from must be a date (YYYY-MM-DD)")
+	}
+	to, err := time.Parse(time.DateOnly, c.Query("to"))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "to must be a date (YYYY-MM-DD)")
+	}
+
+	format := strings.ToLower(c.Query("format", statements.FormatJSON))
+	if format != statements.FormatJSON && format != statements.FormatCSV && format != statements.FormatPDF {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "format must be json, csv or pdf")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	service := h.env.Services.Statements
+	statement, err := service.Generate(ctx, c.Params("wallet_id"), utils.ExtractUserIDFromJWT(c), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidStatementPeriod):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrWalletNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to generate wallet statement", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to generate statement")
+	}
+
+	if format == statements.FormatJSON {
+		return utils.SendSuccessResponse(c, fiber.StatusOK, statement)
+	}
+
+	var buf bytes.Buffer
+	if err := service.Render(statement, format, &buf); err != nil {
+		h.env.Logger.Error("Failed to render wallet statement", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to generate statement")
+	}
+
+	fileName := "statement-" + from.Format("20060102") + "-" + to.Format("20060102") + "." + format
+	contentType := "text/csv"
+	if format == statements.FormatPDF {
+		contentType = "application/pdf"
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+fileName+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Send(buf.Bytes())
+}
+
+// ListStatements godoc
+// @Summary      List monthly wallet statements
+// @Description  Lists the monthly statements stored for one of the caller's wallets, newest first
+// @Tags         wallet
+// @Produce      json
+// @Param        wallet_id  path      string  true  "Wallet ID"
+// @Success      200        {array}   statements.Summary
+// @Failure      404        {object}  model.ErrorResponse
+// @Router       /wallet/{wallet_id}/statements [get]
+func (h *Wallet) ListStatements(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	list, err := h.env.Services.Statements.List(ctx, c.Params("wallet_id"), utils.ExtractUserIDFromJWT(c))
+	if err != nil {
+		if errors.Is(err, model.ErrWalletNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to list wallet statements", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list statements")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, list)
+}
+
+// DownloadStatement godoc
+// @Summary      Download a monthly wallet statement
+// @Description  Streams a stored monthly statement as PDF or CSV
+// @Tags         wallet
+// @Produce      application/pdf
+// @Produce      text/csv
+// @Param        wallet_id  path      string  true   "Wallet ID"
+// @Param        id         path      string  true   "Statement ID"
+// @Param        format     query     string  false  "pdf (default) or csv"
+// @Success      200        {file}    file
+// @Failure      400        {object}  model.ErrorResponse
+// @Failure      404        {object}  model.ErrorResponse
+// @Router       /wallet/{wallet_id}/statements/{id} [get]
+func (h *Wallet) DownloadStatement(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	format := strings.ToLower(c.Query("format", statements.FormatPDF))
+	download, err := h.env.Services.Statements.Open(ctx, c.Params("id"), c.Params("wallet_id"),
+		utils.ExtractUserIDFromJWT(c), format)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidInputError):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrStatementNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to open wallet statement", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to download statement")
+	}
+
+	c.Set(fiber.HeaderContentType, download.ContentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+download.FileName+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	// Fiber closes the body once it has been sent
+	return c.SendStream(download.Body)
+}
+
+// staffTenant is the tenant whose wallets the caller manages; platform
+// admins manage every tenant's
+func staffTenant(c *fiber.Ctx) string {
+	if utils.ExtractUserRoleFromJWT(c) == model.RolePlatformAdmin.String() {
+		return ""
+	}
+	return utils.ExtractTenantFromJWT(c)
+}
+
+// GetWallet godoc
+// @Summary      Get a wallet
+// @Description  Returns any wallet of the caller's tenant with its balance and status. Needs wallets:read.
+// @Tags         wallet
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  wallet.Wallet
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /wallets/{id} [get]
+func (h *Wallet) GetWallet(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	w, err := h.service.GetWallet(ctx, c.Params("id"), staffTenant(c))
+	if err != nil {
+		if errors.Is(err, model.ErrWalletNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to get wallet", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get wallet")
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, w)
+}
+
+// UpdateStatus godoc
+// @Summary      Freeze, unfreeze or close a wallet
+// @Description  Sets a wallet's status to active, frozen or closed. Frozen and closed wallets cannot withdraw or transfer; closing needs a zero balance and cannot be undone. Tenant staff manage their own tenant's wallets. Needs wallets:freeze.
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                      true  "Wallet ID"
+// @Param        body  body      wallet.WalletStatusRequest  true  "New status"
+// @Success      200   {object}  wallet.WalletStatusChange
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Router       /wallets/{id}/status [patch]
+func (h *Wallet) UpdateStatus(c *fiber.Ctx) error {
+	var req wallet.WalletStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest,
+			model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	change, err := h.service.UpdateWalletStatus(ctx, c.Params("id"), staffTenant(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidInputError):
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrWalletNotFound):
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		h.env.Logger.Error("Failed to update wallet status", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to update wallet status")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   change.TenantID,
+		Action:     audit.ActionWalletStatusChange,
+		TargetType: audit.TargetWallet,
+		TargetID:   change.WalletID,
+		Before:     map[string]string{"status": change.From},
+		After:      map[string]string{"status": change.To},
+		Metadata:   map[string]any{"reason": change.Reason},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, change)
+}
@@ -1,25 +1,41 @@
 package handler
 
 import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/domain/tenants"
 	"codematic/internal/domain/webhook"
+	"codematic/internal/middleware"
 	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
 type Webhook struct {
-	service webhook.Service
-	env     *Environment
+	service       webhook.Service
+	tenantService tenants.Service
+	env           *Environment
 }
 
 func (h *Webhook) Init(basePath string, env *Environment) error {
 
 	h.env = env
 	h.service = env.Services.Webhook
+	h.tenantService = env.Services.Tenants
 
 	group := env.Fiber.Group(basePath + "/webhook")
 	group.Post("/:provider", h.Receive)
 
+	// Providers call Receive unauthenticated, so only the config routes
+	// need a token
+	auth := middleware.JWTMiddleware(env.JWTManager, env.CacheManager)
+	manage := middleware.RequirePermission(model.PermWebhooksManage)
+	group.Get("/config", auth, manage, h.GetConfig)
+	group.Put("/config", auth, manage, h.UpdateConfig)
+
 	return nil
 }
 
@@ -43,8 +59,10 @@ func (h *Webhook) Receive(c *fiber.Ctx) error {
 		headers[string(key)] = string(val)
 	})
 
-	h.env.Logger.Sugar().Info("payload", string(payload))
-	h.env.Logger.Sugar().Info("headers", headers)
+	h.env.Logger.Info("webhook received",
+		zap.String("provider", provider),
+		zap.Int("payload_size", len(payload)),
+	)
 
 	err := h.service.HandleWebhook(c.Context(), provider, headers, payload)
 	if err != nil {
@@ -57,6 +75,76 @@ func (h *Webhook) Receive(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "webhook processed"})
 }
 
+// GetConfig godoc
+// @Summary      Get the tenant's webhook config
+// @Description  Returns where the caller's tenant receives outgoing webhooks. Needs webhooks:manage.
+// @Tags         webhook
+// @Produce      json
+// @Success      200  {object}  tenants.WebhookConfig
+// @Failure      403  {object}  model.ErrorResponse
+// @Failure      404  {object}  model.ErrorResponse
+// @Router       /webhook/config [get]
+func (h *Webhook) GetConfig(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tenant, err := h.tenantService.GetTenantByID(ctx, utils.ExtractTenantFromJWT(c))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, tenants.WebhookConfig{WebhookURL: tenant.WebhookURL})
+}
+
+// UpdateConfig godoc
+// @Summary      Update the tenant's webhook config
+// @Description  Sets the HTTPS URL the caller's tenant receives outgoing webhooks at; an empty URL turns them off. Needs webhooks:manage.
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        body  body      tenants.WebhookConfig  true  "Webhook config"
+// @Success      200   {object}  tenants.WebhookConfig
+// @Failure      400   {object}  model.ErrorResponse
+// @Failure      403   {object}  model.ErrorResponse
+// @Failure      404   {object}  model.ErrorResponse
+// @Router       /webhook/config [put]
+func (h *Webhook) UpdateConfig(c *fiber.Ctx) error {
+	var req tenants.WebhookConfig
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, model.ErrInvalidInputError.Error())
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tenantID := utils.ExtractTenantFromJWT(c)
+	before, err := h.tenantService.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	tenant, err := h.tenantService.UpdateWebhookURL(ctx, tenantID, req.WebhookURL)
+	if err != nil {
+		h.env.Logger.Error("Failed to update webhook config", zap.Error(err))
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to update webhook config")
+	}
+
+	recordAudit(h.env, c, audit.Entry{
+		TenantID:   tenant.ID,
+		Action:     audit.ActionWebhookConfigUpdate,
+		TargetType: audit.TargetTenant,
+		TargetID:   tenant.ID,
+		Before:     tenants.WebhookConfig{WebhookURL: before.WebhookURL},
+		After:      tenants.WebhookConfig{WebhookURL: tenant.WebhookURL},
+	})
+
+	return utils.SendSuccessResponse(c, fiber.StatusOK, tenants.WebhookConfig{WebhookURL: tenant.WebhookURL})
+}
+
 // Replay godoc
 // @Summary      Replay a webhook event
 // @Description  Replays a previously failed webhook event by its ID
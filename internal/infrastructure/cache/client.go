@@ -44,6 +44,10 @@ func NewRedisCacheManager(rc *redis.Client) CacheManager {
 		NewRedisProviderCacheStore(rc, logger),
 		NewRedisWalletCacheStore(rc, logger),
 		NewRedisTransactionCacheStore(rc, logger),
+		NewRedisRefreshTokenStore(rc, logger),
+		NewRedisMFAChallengeStore(rc, logger),
+		NewRedisRateLimitStore(rc, logger),
+		NewRedisLockoutStore(rc, logger),
 	)
 }
 
@@ -52,6 +56,10 @@ type CacheManager interface {
 	ProviderCacheStore
 	WalletCacheStore
 	TransactionCacheStore
+	RefreshTokenStore
+	MFAChallengeStore
+	RateLimitStore
+	LockoutStore
 }
 
 type unifiedCacheManager struct {
@@ -59,16 +67,28 @@ type unifiedCacheManager struct {
 	ProviderCacheStore
 	WalletCacheStore
 	TransactionCacheStore
+	RefreshTokenStore
+	MFAChallengeStore
+	RateLimitStore
+	LockoutStore
 }
 
 func NewCacheManager(sessionStore SessionStore,
 	providerCacheStore ProviderCacheStore,
 	walletCacheStore WalletCacheStore,
-	transactionCacheStore TransactionCacheStore) CacheManager {
+	transactionCacheStore TransactionCacheStore,
+	refreshTokenStore RefreshTokenStore,
+	mfaChallengeStore MFAChallengeStore,
+	rateLimitStore RateLimitStore,
+	lockoutStore LockoutStore) CacheManager {
 	return &unifiedCacheManager{
 		SessionStore:          sessionStore,
 		ProviderCacheStore:    providerCacheStore,
 		WalletCacheStore:      walletCacheStore,
 		TransactionCacheStore: transactionCacheStore,
+		RefreshTokenStore:     refreshTokenStore,
+		MFAChallengeStore:     mfaChallengeStore,
+		RateLimitStore:        rateLimitStore,
+		LockoutStore:          lockoutStore,
 	}
 }
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"codematic/internal/shared/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var ErrMFAChallengeNotFound = errors.New("mfa challenge not found")
+
+type MFAChallengeStore interface {
+	SetMFAChallenge(ctx context.Context, id string, challenge *model.MFAChallenge, ttl time.Duration) error
+	GetMFAChallenge(ctx context.Context, id string) (*model.MFAChallenge, error)
+	DeleteMFAChallenge(ctx context.Context, id string) error
+	IncrMFAChallengeAttempts(ctx context.Context, id string, ttl time.Duration) (int64, error)
+}
+
+type RedisMFAChallengeStore struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewRedisMFAChallengeStore(client *redis.Client, logger *zap.Logger) MFAChallengeStore {
+	return &RedisMFAChallengeStore{client: client, logger: logger}
+}
+
+func mfaChallengeKey(id string) string {
+	return "mfa_challenge:" + id
+}
+
+func mfaChallengeAttemptsKey(id string) string {
+	return "mfa_challenge_attempts:" + id
+}
+
+func (r *RedisMFAChallengeStore) SetMFAChallenge(ctx context.Context, id string,
+	challenge *model.MFAChallenge, ttl time.Duration) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		r.logger.Error("Failed to marshal mfa challenge", zap.Error(err))
+		return err
+	}
+
+	if err := r.client.Set(ctx, mfaChallengeKey(id), data, ttl).Err(); err != nil {
+		r.logger.Error("Failed to set mfa challenge in redis", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *RedisMFAChallengeStore) GetMFAChallenge(ctx context.Context,
+	id string) (*model.MFAChallenge, error) {
+	data, err := r.client.Get(ctx, mfaChallengeKey(id)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrMFAChallengeNotFound
+		}
+		r.logger.Error("Failed to get mfa challenge from redis", zap.Error(err))
+		return nil, err
+	}
+
+	var challenge model.MFAChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		r.logger.Error("Failed to unmarshal mfa challenge", zap.Error(err))
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *RedisMFAChallengeStore) DeleteMFAChallenge(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, mfaChallengeKey(id), mfaChallengeAttemptsKey(id)).Err(); err != nil {
+		r.logger.Error("Failed to delete mfa challenge from redis", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// IncrMFAChallengeAttempts counts code submissions against a challenge so it
+// can be discarded after too many wrong codes.
+func (r *RedisMFAChallengeStore) IncrMFAChallengeAttempts(ctx context.Context, id string,
+	ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, mfaChallengeAttemptsKey(id))
+	pipe.Expire(ctx, mfaChallengeAttemptsKey(id), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to count mfa challenge attempts", zap.Error(err))
+		return 0, err
+	}
+	return incr.Val(), nil
+}
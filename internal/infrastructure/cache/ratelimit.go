@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// RateLimitResult describes the state of a window after a request was counted
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until the oldest request leaves the window,
+	// i.e. until at least one more request will be allowed
+	ResetAfter time.Duration
+}
+
+type RateLimitStore interface {
+	AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+}
+
+// LockoutStore tracks consecutive login failures and temporary account locks
+type LockoutStore interface {
+	RecordLoginFailure(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	ResetLoginFailures(ctx context.Context, key string) error
+	LockAccount(ctx context.Context, key string, duration time.Duration) error
+	GetLockout(ctx context.Context, key string) (time.Duration, error)
+	UnlockAccount(ctx context.Context, key string) error
+
+	// RecordAttempt counts an attempt before it is checked, so concurrent
+	// attempts cannot all pass a lockout check made before any of them
+	// failed. It returns the attempts made in the window, or how long the key
+	// stays locked without counting anything.
+	RecordAttempt(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+	// LockAttempts locks the key and keeps its attempt count until the lock
+	// expires, when both are dropped together
+	LockAttempts(ctx context.Context, key string, duration time.Duration) error
+}
+
+type RedisRateLimitStore struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewRedisRateLimitStore(client *redis.Client, logger *zap.Logger) RateLimitStore {
+	return &RedisRateLimitStore{client: client, logger: logger}
+}
+
+type RedisLockoutStore struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewRedisLockoutStore(client *redis.Client, logger *zap.Logger) LockoutStore {
+	return &RedisLockoutStore{client: client, logger: logger}
+}
+
+func rateLimitKey(key string) string {
+	return "ratelimit:" + key
+}
+
+func loginFailuresKey(key string) string {
+	return "login_failures:" + key
+}
+
+func loginLockoutKey(key string) string {
+	return "login_lockout:" + key
+}
+
+// recordAttemptScript returns {0, lock_ms} while the key is locked, otherwise
+// counts the attempt and returns {attempts, 0}. The window starts with the
+// first attempt and is not extended by later ones.
+var recordAttemptScript = redis.NewScript(`
+local locked = redis.call('PTTL', KEYS[2])
+if locked > 0 then
+  return {0, locked}
+end
+
+local attempts = redis.call('INCR', KEYS[1])
+if attempts == 1 or redis.call('PTTL', KEYS[1]) < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {attempts, 0}
+`)
+
+// slidingWindowScript keeps one sorted-set member per request scored by its
+// timestamp, drops members older than the window and admits the request if
+// fewer than limit remain. Returns {allowed, remaining, reset_ms}.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+local count = redis.call('ZCARD', KEYS[1])
+
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[4])
+  redis.call('PEXPIRE', KEYS[1], window)
+  count = count + 1
+  allowed = 1
+end
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+  reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`)
+
+func (r *RedisRateLimitStore) AllowRequest(ctx context.Context, key string, limit int,
+	window time.Duration) (RateLimitResult, error) {
+	now := time.Now().UnixMilli()
+	member := strconv.FormatInt(now, 10) + "-" + uuid.NewString()
+
+	res, err := slidingWindowScript.Run(ctx, r.client, []string{rateLimitKey(key)},
+		now, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		r.logger.Error("Failed to evaluate rate limit", zap.String("key", key), zap.Error(err))
+		return RateLimitResult{}, err
+	}
+
+	return RateLimitResult{
+		Allowed:    res[0] == 1,
+		Limit:      limit,
+		Remaining:  int(max(res[1], 0)),
+		ResetAfter: time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
+
+func (r *RedisLockoutStore) RecordLoginFailure(ctx context.Context, key string,
+	ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, loginFailuresKey(key))
+	pipe.Expire(ctx, loginFailuresKey(key), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to record login failure", zap.Error(err))
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *RedisLockoutStore) ResetLoginFailures(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, loginFailuresKey(key)).Err(); err != nil {
+		r.logger.Error("Failed to reset login failures", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *RedisLockoutStore) LockAccount(ctx context.Context, key string,
+	duration time.Duration) error {
+	if err := r.client.Set(ctx, loginLockoutKey(key), 1, duration).Err(); err != nil {
+		r.logger.Error("Failed to lock account", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetLockout returns how long the account stays locked, or zero if it is not
+func (r *RedisLockoutStore) GetLockout(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, loginLockoutKey(key)).Result()
+	if err != nil {
+		r.logger.Error("Failed to get account lockout", zap.Error(err))
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (r *RedisLockoutStore) UnlockAccount(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, loginLockoutKey(key), loginFailuresKey(key)).Err(); err != nil {
+		r.logger.Error("Failed to unlock account", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *RedisLockoutStore) RecordAttempt(ctx context.Context, key string,
+	window time.Duration) (int64, time.Duration, error) {
+	res, err := recordAttemptScript.Run(ctx, r.client,
+		[]string{loginFailuresKey(key), loginLockoutKey(key)}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		r.logger.Error("Failed to record attempt", zap.Error(err))
+		return 0, 0, err
+	}
+	return res[0], time.Duration(res[1]) * time.Millisecond, nil
+}
+
+func (r *RedisLockoutStore) LockAttempts(ctx context.Context, key string, duration time.Duration) error {
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, loginLockoutKey(key), 1, duration)
+	pipe.PExpire(ctx, loginFailuresKey(key), duration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to lock attempts", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestRecordAttemptCountsConcurrentAttempts(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	store := NewRedisLockoutStore(client, zap.NewNop())
+
+	const n = 20
+	seen := make(chan int64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempts, _, err := store.RecordAttempt(ctx, "pin:user-1", time.Hour)
+			if err != nil {
+				t.Errorf("RecordAttempt: %v", err)
+			}
+			seen <- attempts
+		}()
+	}
+	wg.Wait()
+	close(seen)
+
+	// Every attempt gets its own number, so only the first few can be checked
+	counts := map[int64]bool{}
+	for attempts := range seen {
+		if counts[attempts] {
+			t.Errorf("attempt number %d handed out twice", attempts)
+		}
+		counts[attempts] = true
+	}
+	for i := int64(1); i <= n; i++ {
+		if !counts[i] {
+			t.Errorf("attempt number %d never handed out", i)
+		}
+	}
+}
+
+func TestLockAttemptsKeepsCountUntilLockExpires(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	store := NewRedisLockoutStore(client, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := store.RecordAttempt(ctx, "pin:user-1", 24*time.Hour); err != nil {
+			t.Fatalf("RecordAttempt: %v", err)
+		}
+	}
+	if err := store.LockAttempts(ctx, "pin:user-1", 15*time.Minute); err != nil {
+		t.Fatalf("LockAttempts: %v", err)
+	}
+
+	attempts, locked, err := store.RecordAttempt(ctx, "pin:user-1", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("RecordAttempt while locked: %v", err)
+	}
+	if attempts != 0 || locked <= 0 {
+		t.Fatalf("RecordAttempt while locked = (%d, %v), want (0, > 0)", attempts, locked)
+	}
+	if got, _ := client.Get(ctx, loginFailuresKey("pin:user-1")).Int(); got != 3 {
+		t.Errorf("attempts kept during lock = %d, want 3", got)
+	}
+
+	mr.FastForward(15*time.Minute + time.Second)
+
+	attempts, locked, err = store.RecordAttempt(ctx, "pin:user-1", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("RecordAttempt after lock: %v", err)
+	}
+	if attempts != 1 || locked != 0 {
+		t.Errorf("RecordAttempt after lock = (%d, %v), want (1, 0)", attempts, locked)
+	}
+}
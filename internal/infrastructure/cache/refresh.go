@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"codematic/internal/shared/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRefreshFamilyNotFound = errors.New("refresh token family not found")
+	ErrRefreshTokenMismatch  = errors.New("refresh token is not the current token of its family")
+)
+
+type RefreshTokenStore interface {
+	SetRefreshFamily(ctx context.Context, family *model.RefreshFamily, ttl time.Duration) error
+	GetRefreshFamily(ctx context.Context, familyID string) (*model.RefreshFamily, error)
+	DeleteRefreshFamily(ctx context.Context, familyID string) error
+	RotateRefreshToken(ctx context.Context, familyID, presentedTokenID, newTokenID,
+		newSessionTokenID string, ttl time.Duration) error
+	ListRefreshFamiliesForUser(ctx context.Context, userID string) ([]string, error)
+}
+
+type RedisRefreshTokenStore struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewRedisRefreshTokenStore(client *redis.Client, logger *zap.Logger) RefreshTokenStore {
+	return &RedisRefreshTokenStore{client: client, logger: logger}
+}
+
+func refreshFamilyKey(familyID string) string {
+	return "refresh_family:" + familyID
+}
+
+func userRefreshFamiliesKey(userID string) string {
+	return "user_refresh_families:" + userID
+}
+
+// rotateScript swaps the family's current token only if the presented token is
+// still current, so two concurrent refreshes cannot both succeed.
+var rotateScript = redis.NewScript(`
+local raw = redis.call('GET', KEYS[1])
+if not raw then
+  return 0
+end
+local family = cjson.decode(raw)
+if family.current_token_id ~= ARGV[1] then
+  return -1
+end
+family.current_token_id = ARGV[2]
+family.session_token_id = ARGV[3]
+redis.call('SET', KEYS[1], cjson.encode(family), 'PX', ARGV[4])
+return 1
+`)
+
+func (r *RedisRefreshTokenStore) SetRefreshFamily(ctx context.Context,
+	family *model.RefreshFamily, ttl time.Duration) error {
+	data, err := json.Marshal(family)
+	if err != nil {
+		r.logger.Error("Failed to marshal refresh family", zap.Error(err))
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, refreshFamilyKey(family.FamilyID), data, ttl)
+	pipe.SAdd(ctx, userRefreshFamiliesKey(family.UserID), family.FamilyID)
+	pipe.Expire(ctx, userRefreshFamiliesKey(family.UserID), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to set refresh family in redis", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *RedisRefreshTokenStore) GetRefreshFamily(ctx context.Context,
+	familyID string) (*model.RefreshFamily, error) {
+	data, err := r.client.Get(ctx, refreshFamilyKey(familyID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrRefreshFamilyNotFound
+		}
+		r.logger.Error("Failed to get refresh family from redis", zap.Error(err))
+		return nil, err
+	}
+
+	var family model.RefreshFamily
+	if err := json.Unmarshal([]byte(data), &family); err != nil {
+		r.logger.Error("Failed to unmarshal refresh family", zap.Error(err))
+		return nil, err
+	}
+	return &family, nil
+}
+
+func (r *RedisRefreshTokenStore) DeleteRefreshFamily(ctx context.Context, familyID string) error {
+	family, err := r.GetRefreshFamily(ctx, familyID)
+	if err != nil && err != ErrRefreshFamilyNotFound {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, refreshFamilyKey(familyID))
+	if family != nil {
+		pipe.SRem(ctx, userRefreshFamiliesKey(family.UserID), familyID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to delete refresh family from redis", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *RedisRefreshTokenStore) RotateRefreshToken(ctx context.Context, familyID,
+	presentedTokenID, newTokenID, newSessionTokenID string, ttl time.Duration) error {
+	res, err := rotateScript.Run(ctx, r.client, []string{refreshFamilyKey(familyID)},
+		presentedTokenID, newTokenID, newSessionTokenID, ttl.Milliseconds()).Int()
+	if err != nil {
+		r.logger.Error("Failed to rotate refresh token", zap.Error(err))
+		return err
+	}
+
+	switch res {
+	case 0:
+		return ErrRefreshFamilyNotFound
+	case -1:
+		return ErrRefreshTokenMismatch
+	}
+	return nil
+}
+
+func (r *RedisRefreshTokenStore) ListRefreshFamiliesForUser(ctx context.Context,
+	userID string) ([]string, error) {
+	ids, err := r.client.SMembers(ctx, userRefreshFamiliesKey(userID)).Result()
+	if err != nil && err != redis.Nil {
+		r.logger.Error("Failed to list refresh families", zap.Error(err))
+		return nil, err
+	}
+	return ids, nil
+}
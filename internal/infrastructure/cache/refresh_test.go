@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"codematic/internal/shared/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestRefreshStore(t *testing.T) RefreshTokenStore {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisRefreshTokenStore(client, zap.NewNop())
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	store := newTestRefreshStore(t)
+
+	family := &model.RefreshFamily{
+		FamilyID:       "family-1",
+		UserID:         "user-1",
+		CurrentTokenID: "refresh-1",
+		SessionTokenID: "session-1",
+	}
+	if err := store.SetRefreshFamily(ctx, family, time.Hour); err != nil {
+		t.Fatalf("SetRefreshFamily: %v", err)
+	}
+
+	if err := store.RotateRefreshToken(ctx, "family-1", "refresh-1", "refresh-2", "session-2", time.Hour); err != nil {
+		t.Fatalf("first rotation: %v", err)
+	}
+
+	got, err := store.GetRefreshFamily(ctx, "family-1")
+	if err != nil {
+		t.Fatalf("GetRefreshFamily: %v", err)
+	}
+	if got.CurrentTokenID != "refresh-2" || got.SessionTokenID != "session-2" || got.UserID != "user-1" {
+		t.Errorf("family after rotation = %+v", got)
+	}
+
+	// A second refresh racing with the same token must lose
+	err = store.RotateRefreshToken(ctx, "family-1", "refresh-1", "refresh-3", "session-3", time.Hour)
+	if !errors.Is(err, ErrRefreshTokenMismatch) {
+		t.Fatalf("replayed rotation error = %v, want %v", err, ErrRefreshTokenMismatch)
+	}
+
+	if err := store.DeleteRefreshFamily(ctx, "family-1"); err != nil {
+		t.Fatalf("DeleteRefreshFamily: %v", err)
+	}
+	err = store.RotateRefreshToken(ctx, "family-1", "refresh-2", "refresh-3", "session-3", time.Hour)
+	if !errors.Is(err, ErrRefreshFamilyNotFound) {
+		t.Fatalf("rotation of deleted family error = %v, want %v", err, ErrRefreshFamilyNotFound)
+	}
+}
@@ -15,7 +15,8 @@ type SessionStore interface {
 		ttl time.Duration) error
 	GetSession(ctx context.Context, key string) (*model.UserSessionInfo, error)
 	DeleteSession(ctx context.Context, key string) error
-	GetTokenIDForUser(ctx context.Context, userID string) (string, error)
+	TouchSession(ctx context.Context, key string, lastSeen time.Time) error
+	ListSessionsForUser(ctx context.Context, userID string) ([]*model.UserSessionInfo, error)
 }
 
 type RedisSessionStore struct {
@@ -27,6 +28,11 @@ func NewRedisSessionStore(client *redis.Client, logger *zap.Logger) SessionStore
 	return &RedisSessionStore{client: client, logger: logger}
 }
 
+// userSessionsKey holds the set of active session token IDs for a user
+func userSessionsKey(userID string) string {
+	return "user_sessions:" + userID
+}
+
 func (r *RedisSessionStore) SetSession(ctx context.Context, key string,
 	value *model.UserSessionInfo, ttl time.Duration) error {
 
@@ -40,10 +46,11 @@ func (r *RedisSessionStore) SetSession(ctx context.Context, key string,
 	}
 
 	if value != nil && value.UserID != "" && value.TokenID != "" {
-		userTokenKey := "user_token:" + value.UserID
-		err := r.client.Set(ctx, userTokenKey, value.TokenID, ttl).Err()
-		if err != nil {
-			r.logger.Error("Failed to set user_token mapping in redis", zap.Error(err))
+		pipe := r.client.TxPipeline()
+		pipe.SAdd(ctx, userSessionsKey(value.UserID), value.TokenID)
+		pipe.Expire(ctx, userSessionsKey(value.UserID), ttl)
+		if _, err := pipe.Exec(ctx); err != nil {
+			r.logger.Error("Failed to set user_sessions mapping in redis", zap.Error(err))
 		}
 	}
 
@@ -72,27 +79,82 @@ func (r *RedisSessionStore) GetSession(ctx context.Context, key string) (
 }
 
 func (r *RedisSessionStore) DeleteSession(ctx context.Context, key string) error {
+	session, _ := r.GetSession(ctx, key)
+
 	err := r.client.Del(ctx, key).Err()
 	if err != nil {
 		r.logger.Error("Failed to delete session from redis", zap.Error(err), zap.String("key", key))
+		return err
 	}
-	return err
+
+	if session != nil && session.UserID != "" {
+		if err := r.client.SRem(ctx, userSessionsKey(session.UserID), key).Err(); err != nil {
+			r.logger.Error("Failed to remove session from user_sessions", zap.Error(err))
+		}
+	}
+	return nil
 }
 
-func (r *RedisSessionStore) GetTokenIDForUser(ctx context.Context,
-	userID string) (string, error) {
-	userTokenKey := "user_token:" + userID
-	tokenID, err := r.client.Get(ctx, userTokenKey).Result()
+// TouchSession updates LastSeen without extending the session TTL
+func (r *RedisSessionStore) TouchSession(ctx context.Context, key string,
+	lastSeen time.Time) error {
+	session, err := r.GetSession(ctx, key)
+	if err != nil {
+		return err
+	}
 
+	session.LastSeen = lastSeen
+	data, err := json.Marshal(session)
 	if err != nil {
-		if err == redis.Nil {
-			r.logger.Debug("User token mapping not found in redis",
-				zap.String("userID", userID))
-			return "", nil
+		return err
+	}
+
+	if err := r.client.SetArgs(ctx, key, data, redis.SetArgs{KeepTTL: true, Mode: "XX"}).Err(); err != nil &&
+		err != redis.Nil {
+		r.logger.Error("Failed to touch session in redis", zap.Error(err), zap.String("key", key))
+		return err
+	}
+	return nil
+}
+
+// ListSessionsForUser returns the user's live sessions, pruning IDs whose
+// session has already expired.
+func (r *RedisSessionStore) ListSessionsForUser(ctx context.Context,
+	userID string) ([]*model.UserSessionInfo, error) {
+	tokenIDs, err := r.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil && err != redis.Nil {
+		r.logger.Error("Failed to list user sessions", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+	if len(tokenIDs) == 0 {
+		return []*model.UserSessionInfo{}, nil
+	}
+
+	values, err := r.client.MGet(ctx, tokenIDs...).Result()
+	if err != nil {
+		r.logger.Error("Failed to load user sessions", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	sessions := make([]*model.UserSessionInfo, 0, len(values))
+	var stale []interface{}
+	for i, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			stale = append(stale, tokenIDs[i])
+			continue
+		}
+		var session model.UserSessionInfo
+		if err := json.Unmarshal([]byte(raw), &session); err != nil {
+			stale = append(stale, tokenIDs[i])
+			continue
 		}
-		r.logger.Error("Failed to get user_token mapping from redis",
-			zap.Error(err), zap.String("userID", userID))
-		return "", err
+		sessions = append(sessions, &session)
 	}
-	return tokenID, nil
+
+	if len(stale) > 0 {
+		_ = r.client.SRem(ctx, userSessionsKey(userID), stale...).Err()
+	}
+
+	return sessions, nil
 }
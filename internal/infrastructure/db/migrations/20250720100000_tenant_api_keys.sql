@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE api_keys (
+  "id" uuid PRIMARY KEY,
+  "tenant_id" uuid NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+  "created_by" uuid REFERENCES users(id) ON DELETE SET NULL,
+  "name" VARCHAR(100) NOT NULL,
+  "key_type" VARCHAR(20) NOT NULL CHECK (key_type IN ('publishable', 'secret')),
+  "prefix" VARCHAR(32) UNIQUE NOT NULL,
+  "key_hash" VARCHAR(128) NOT NULL,
+  "scopes" TEXT[] DEFAULT '{}' NOT NULL,
+  "allowed_ips" TEXT[] DEFAULT '{}' NOT NULL,
+  "last_used_at" TIMESTAMP WITH TIME ZONE,
+  "expires_at" TIMESTAMP WITH TIME ZONE,
+  "revoked_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_api_keys_tenant_id ON api_keys(tenant_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "api_keys" cascade;
+
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE users ADD COLUMN "email_verified_at" TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE tenants ADD COLUMN "require_email_verification" BOOLEAN DEFAULT false NOT NULL;
+
+CREATE TABLE user_tokens (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  "purpose" VARCHAR(32) NOT NULL CHECK (purpose IN ('password_reset', 'email_verification')),
+  "token_hash" VARCHAR(128) UNIQUE NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "used_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens(user_id, purpose);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "user_tokens" cascade;
+ALTER TABLE tenants DROP COLUMN IF EXISTS "require_email_verification";
+ALTER TABLE users DROP COLUMN IF EXISTS "email_verified_at";
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE user_mfa (
+  "user_id" uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  "secret_encrypted" TEXT NOT NULL,
+  "enabled_at" TIMESTAMP WITH TIME ZONE,
+  "last_used_step" BIGINT DEFAULT 0 NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+CREATE TABLE mfa_recovery_codes (
+  "id" uuid PRIMARY KEY,
+  "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  "code_hash" VARCHAR(128) NOT NULL,
+  "used_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "mfa_recovery_codes" cascade;
+drop table if exists "user_mfa" cascade;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE roles (
+  "id" uuid PRIMARY KEY,
+  "tenant_id" uuid NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+  "name" VARCHAR(100) NOT NULL,
+  "description" TEXT DEFAULT '' NOT NULL,
+  "permissions" TEXT[] DEFAULT '{}' NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  UNIQUE ("tenant_id", "name")
+);
+
+CREATE TABLE user_roles (
+  "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  "role_id" uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  PRIMARY KEY ("user_id", "role_id")
+);
+
+CREATE INDEX idx_user_roles_role_id ON user_roles(role_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "user_roles" cascade;
+drop table if exists "roles" cascade;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE user_invites (
+  "id" uuid PRIMARY KEY,
+  "tenant_id" uuid NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+  "email" VARCHAR NOT NULL,
+  "role" VARCHAR(32) DEFAULT 'USER' NOT NULL CHECK (role IN ('TENANT_ADMIN', 'USER')),
+  "token_hash" VARCHAR(128) UNIQUE NOT NULL,
+  "invited_by" uuid REFERENCES users(id) ON DELETE SET NULL,
+  "user_id" uuid REFERENCES users(id) ON DELETE SET NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "accepted_at" TIMESTAMP WITH TIME ZONE,
+  "revoked_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_user_invites_tenant_id ON user_invites(tenant_id);
+
+-- At most one open invite per email within a tenant
+CREATE UNIQUE INDEX idx_user_invites_pending_email ON user_invites(tenant_id, email)
+  WHERE accepted_at IS NULL AND revoked_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "user_invites" cascade;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE jwt_signing_keys (
+  "kid" VARCHAR(64) PRIMARY KEY,
+  "algorithm" VARCHAR(16) NOT NULL CHECK (algorithm IN ('EdDSA', 'RS256')),
+  "private_key_encrypted" TEXT NOT NULL,
+  "activates_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_jwt_signing_keys_expires_at ON jwt_signing_keys(expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "jwt_signing_keys" cascade;
+
+-- +goose StatementEnd
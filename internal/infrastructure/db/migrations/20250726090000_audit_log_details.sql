@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Platform actions have no tenant, and failed logins or system jobs no user
+ALTER TABLE audit_logs ALTER COLUMN tenant_id DROP NOT NULL;
+ALTER TABLE audit_logs ALTER COLUMN user_id DROP NOT NULL;
+
+ALTER TABLE audit_logs
+  ADD COLUMN "actor_role" VARCHAR(32),
+  ADD COLUMN "target_type" VARCHAR(64),
+  ADD COLUMN "target_id" VARCHAR(128),
+  ADD COLUMN "changes" jsonb,
+  ADD COLUMN "ip_address" VARCHAR(64),
+  ADD COLUMN "user_agent" TEXT;
+
+CREATE INDEX idx_audit_logs_tenant_created_at ON audit_logs(tenant_id, created_at DESC);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at DESC);
+CREATE INDEX idx_audit_logs_user_id ON audit_logs(user_id);
+CREATE INDEX idx_audit_logs_target ON audit_logs(target_type, target_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS idx_audit_logs_target;
+DROP INDEX IF EXISTS idx_audit_logs_user_id;
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_tenant_created_at;
+
+ALTER TABLE audit_logs
+  DROP COLUMN IF EXISTS "user_agent",
+  DROP COLUMN IF EXISTS "ip_address",
+  DROP COLUMN IF EXISTS "changes",
+  DROP COLUMN IF EXISTS "target_id",
+  DROP COLUMN IF EXISTS "target_type",
+  DROP COLUMN IF EXISTS "actor_role";
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE transaction_pins (
+  "user_id" uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  "pin_hash" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+  CHECK (purpose IN ('password_reset', 'email_verification', 'pin_reset'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DELETE FROM user_tokens WHERE purpose = 'pin_reset';
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+  CHECK (purpose IN ('password_reset', 'email_verification'));
+drop table if exists "transaction_pins" cascade;
+
+-- +goose StatementEnd
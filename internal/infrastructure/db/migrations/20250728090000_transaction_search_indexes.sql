@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Keyset pagination walks (created_at, id) within the usual scopes
+CREATE INDEX idx_transactions_created_at_id ON transactions(created_at DESC, id DESC);
+CREATE INDEX idx_transactions_tenant_created_at ON transactions(tenant_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_wallet_created_at ON transactions(wallet_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata);
+CREATE INDEX idx_wallets_user_id ON wallets(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS idx_wallets_user_id;
+DROP INDEX IF EXISTS idx_transactions_metadata;
+DROP INDEX IF EXISTS idx_transactions_wallet_created_at;
+DROP INDEX IF EXISTS idx_transactions_tenant_created_at;
+DROP INDEX IF EXISTS idx_transactions_created_at_id;
+
+-- +goose StatementEnd
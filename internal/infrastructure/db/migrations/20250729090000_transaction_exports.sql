@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE transaction_exports (
+  "id" uuid PRIMARY KEY,
+  "tenant_id" uuid REFERENCES tenants(id) ON DELETE CASCADE,
+  "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  "format" TEXT NOT NULL CHECK (format IN ('csv', 'xlsx')),
+  "filters" jsonb NOT NULL DEFAULT '{}',
+  "status" TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'expired')),
+  "attempts" INT NOT NULL DEFAULT 0,
+  "row_count" BIGINT NOT NULL DEFAULT 0,
+  "file_key" TEXT,
+  "file_size" BIGINT NOT NULL DEFAULT 0,
+  "error" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  "started_at" TIMESTAMP WITH TIME ZONE,
+  "completed_at" TIMESTAMP WITH TIME ZONE,
+  "expires_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_transaction_exports_user ON transaction_exports (user_id, created_at DESC);
+CREATE INDEX idx_transaction_exports_queue ON transaction_exports (status, created_at)
+  WHERE status IN ('pending', 'processing');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "transaction_exports" cascade;
+
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE wallet_statements (
+  "id" uuid PRIMARY KEY,
+  "wallet_id" uuid NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+  "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  "currency_code" VARCHAR NOT NULL,
+  "period_start" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "period_end" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "opening_balance" DECIMAL(18, 2) NOT NULL,
+  "total_credits" DECIMAL(18, 2) NOT NULL,
+  "total_debits" DECIMAL(18, 2) NOT NULL,
+  "total_fees" DECIMAL(18, 2) NOT NULL,
+  "closing_balance" DECIMAL(18, 2) NOT NULL,
+  "entry_count" INT NOT NULL,
+  "pdf_key" TEXT NOT NULL,
+  "csv_key" TEXT NOT NULL,
+  "emailed_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  UNIQUE (wallet_id, period_start, period_end)
+);
+
+CREATE INDEX idx_wallet_statements_wallet ON wallet_statements (wallet_id, period_start DESC);
+
+-- Statements sum a wallet's completed transactions by date
+CREATE INDEX IF NOT EXISTS idx_transactions_wallet_status_created
+  ON transactions (wallet_id, status, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS idx_transactions_wallet_status_created;
+drop table if exists "wallet_statements" cascade;
+
+-- +goose StatementEnd
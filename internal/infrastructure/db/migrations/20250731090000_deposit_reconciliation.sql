@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Deposits nobody paid for are expired by the reconciliation job
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+  CHECK (status IN ('pending', 'completed', 'failed', 'expired'));
+
+-- The reconciliation job walks pending deposits oldest first
+CREATE INDEX idx_transactions_pending_deposits ON transactions (created_at, id)
+  WHERE type = 'deposit' AND status = 'pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS idx_transactions_pending_deposits;
+UPDATE transactions SET status = 'failed' WHERE status = 'expired';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+  CHECK (status IN ('pending', 'completed', 'failed'));
+
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE reconciliation_runs (
+  "id" uuid PRIMARY KEY,
+  "provider_id" uuid NOT NULL REFERENCES providers(id),
+  "source" TEXT NOT NULL CHECK (source IN ('upload', 'api')),
+  "file_name" TEXT,
+  "period_start" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "period_end" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "status" TEXT NOT NULL DEFAULT 'processing'
+    CHECK (status IN ('processing', 'completed', 'failed')),
+  "total_items" INT NOT NULL DEFAULT 0,
+  "matched" INT NOT NULL DEFAULT 0,
+  "missing" INT NOT NULL DEFAULT 0,
+  "mismatched" INT NOT NULL DEFAULT 0,
+  "extra" INT NOT NULL DEFAULT 0,
+  "error" TEXT,
+  "created_by" uuid REFERENCES users(id) ON DELETE SET NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL,
+  "completed_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_reconciliation_runs_provider ON reconciliation_runs (provider_id, created_at DESC);
+
+-- Items of a run that did not match; open ones form finance's exception queue
+CREATE TABLE reconciliation_exceptions (
+  "id" uuid PRIMARY KEY,
+  "run_id" uuid NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+  "provider_id" uuid NOT NULL REFERENCES providers(id),
+  "reference" TEXT NOT NULL,
+  "classification" TEXT NOT NULL CHECK (classification IN ('missing', 'mismatched', 'extra')),
+  "transaction_id" uuid REFERENCES transactions(id) ON DELETE SET NULL,
+  "provider_amount" NUMERIC(18, 2),
+  "provider_currency" TEXT,
+  "provider_status" TEXT,
+  "amount" NUMERIC(18, 2),
+  "currency_code" TEXT,
+  "transaction_status" TEXT,
+  "detail" TEXT NOT NULL,
+  "status" TEXT NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'resolved', 'ignored', 'superseded')),
+  "resolved_by" uuid REFERENCES users(id) ON DELETE SET NULL,
+  "resolved_at" TIMESTAMP WITH TIME ZONE,
+  "resolution_note" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_reconciliation_exceptions_run ON reconciliation_exceptions (run_id);
+CREATE INDEX idx_reconciliation_exceptions_queue ON reconciliation_exceptions (status, created_at DESC);
+CREATE INDEX idx_reconciliation_exceptions_open_reference ON reconciliation_exceptions (provider_id, reference)
+  WHERE status = 'open';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists "reconciliation_exceptions" cascade;
+drop table if exists "reconciliation_runs" cascade;
+
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- What a tenant credits when a customer pays more or less than initiated
+ALTER TABLE tenants ADD COLUMN deposit_amount_policy TEXT NOT NULL DEFAULT 'exact'
+  CHECK (deposit_amount_policy IN ('exact', 'allow_overpayment', 'allow_partial'));
+
+-- Deposits paid with an unexpected amount or currency wait for review
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+  CHECK (status IN ('pending', 'completed', 'failed', 'expired', 'flagged'));
+
+CREATE INDEX idx_transactions_flagged ON transactions (tenant_id, created_at)
+  WHERE status = 'flagged';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS idx_transactions_flagged;
+UPDATE transactions SET status = 'pending' WHERE status = 'flagged';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+  CHECK (status IN ('pending', 'completed', 'failed', 'expired'));
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS deposit_amount_policy;
+
+-- +goose StatementEnd
@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE pricing_plans (
+  id UUID PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT,
+  is_default BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- At most one plan applies to tenants without one of their own
+CREATE UNIQUE INDEX idx_pricing_plans_default ON pricing_plans (is_default) WHERE is_default;
+
+-- A rule prices one transaction type. Channel, currency and provider narrow
+-- it when set; the most specific matching rule wins.
+CREATE TABLE pricing_rules (
+  id UUID PRIMARY KEY,
+  plan_id UUID NOT NULL REFERENCES pricing_plans(id) ON DELETE CASCADE,
+  transaction_type TEXT NOT NULL CHECK (transaction_type IN ('deposit', 'withdrawal', 'transfer')),
+  channel TEXT,
+  currency_code VARCHAR REFERENCES currencies(code),
+  provider_id UUID REFERENCES providers(id) ON DELETE CASCADE,
+  fee_type TEXT NOT NULL CHECK (fee_type IN ('flat', 'percentage', 'tiered')),
+  flat_amount DECIMAL(18, 2) NOT NULL DEFAULT 0,
+  percentage DECIMAL(7, 4) NOT NULL DEFAULT 0,
+  tiers JSONB,
+  min_fee DECIMAL(18, 2),
+  max_fee DECIMAL(18, 2),
+  bearer TEXT NOT NULL DEFAULT 'payer' CHECK (bearer IN ('payer', 'receiver')),
+  position INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pricing_rules_plan ON pricing_rules (plan_id, transaction_type);
+
+CREATE TABLE tenant_pricing_plans (
+  tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+  plan_id UUID NOT NULL REFERENCES pricing_plans(id) ON DELETE CASCADE,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Fees collected by the platform, one balance per currency
+CREATE TABLE revenue_wallets (
+  currency_code VARCHAR PRIMARY KEY REFERENCES currencies(code),
+  balance DECIMAL(18, 2) NOT NULL DEFAULT 0,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE fee_entries (
+  id UUID PRIMARY KEY,
+  transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+  currency_code VARCHAR NOT NULL REFERENCES currencies(code),
+  amount DECIMAL(18, 2) NOT NULL CHECK (amount > 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_fee_entries_tenant ON fee_entries (tenant_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS fee_entries;
+DROP TABLE IF EXISTS revenue_wallets;
+DROP TABLE IF EXISTS tenant_pricing_plans;
+DROP TABLE IF EXISTS pricing_rules;
+DROP TABLE IF EXISTS pricing_plans;
+
+-- +goose StatementEnd
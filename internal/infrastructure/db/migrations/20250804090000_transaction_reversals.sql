@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Reversals post compensating transactions instead of editing the original
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+  CHECK (type IN ('deposit', 'withdrawal', 'transfer', 'reversal'));
+
+-- A full or partial reversal of a completed transaction. The debit and credit
+-- legs are the compensating transactions on the wallets involved.
+CREATE TABLE transaction_reversals (
+  id UUID PRIMARY KEY,
+  tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+  original_transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  currency_code VARCHAR NOT NULL REFERENCES currencies(code),
+  amount DECIMAL(18, 2) NOT NULL CHECK (amount > 0),
+  reason TEXT NOT NULL,
+  status TEXT NOT NULL CHECK (status IN ('pending', 'completed', 'failed')),
+  debit_transaction_id UUID REFERENCES transactions(id),
+  credit_transaction_id UUID REFERENCES transactions(id),
+  -- How a reversed deposit goes back to the customer
+  refund_status TEXT CHECK (refund_status IN ('submitted', 'manual')),
+  refund_reference TEXT,
+  error TEXT,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_transaction_reversals_original ON transaction_reversals (original_transaction_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS transaction_reversals;
+DELETE FROM transactions WHERE type = 'reversal';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+  CHECK (type IN ('deposit', 'withdrawal', 'transfer'));
+
+-- +goose StatementEnd
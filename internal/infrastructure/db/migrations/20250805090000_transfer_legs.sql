@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Internal transfers move money between wallets without a provider
+ALTER TABLE transactions ALTER COLUMN provider_id DROP NOT NULL;
+
+-- A transfer debits the sender's wallet (transaction_id) and credits the
+-- receiver's (credit_transaction_id). Nothing wrote to transfers before, so
+-- the new columns need no backfill.
+ALTER TABLE transfers
+  ADD COLUMN credit_transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  ADD COLUMN currency_code VARCHAR NOT NULL REFERENCES currencies(code),
+  ADD COLUMN fee DECIMAL(18, 2) NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_transfers_sender_wallet ON transfers (sender_wallet_id, created_at);
+CREATE INDEX idx_transfers_receiver_wallet ON transfers (receiver_wallet_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS idx_transfers_receiver_wallet;
+DROP INDEX IF EXISTS idx_transfers_sender_wallet;
+DELETE FROM transfers;
+ALTER TABLE transfers
+  DROP COLUMN IF EXISTS fee,
+  DROP COLUMN IF EXISTS currency_code,
+  DROP COLUMN IF EXISTS credit_transaction_id;
+DELETE FROM transactions WHERE provider_id IS NULL;
+ALTER TABLE transactions ALTER COLUMN provider_id SET NOT NULL;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Names shown, masked, to someone about to send a user money, and the
+-- username they can be sent money by
+ALTER TABLE users
+  ADD COLUMN first_name TEXT,
+  ADD COLUMN last_name TEXT,
+  ADD COLUMN username TEXT;
+
+-- Usernames are unique within a tenant, ignoring case
+CREATE UNIQUE INDEX idx_users_tenant_username ON users (tenant_id, lower(username))
+  WHERE username IS NOT NULL;
+CREATE INDEX idx_users_tenant_phone ON users (tenant_id, phone);
+
+-- Whether users of a tenant may send money to each other by email, phone or
+-- username
+ALTER TABLE tenants ADD COLUMN allow_p2p_transfers BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE tenants DROP COLUMN IF EXISTS allow_p2p_transfers;
+DROP INDEX IF EXISTS idx_users_tenant_phone;
+DROP INDEX IF EXISTS idx_users_tenant_username;
+ALTER TABLE users
+  DROP COLUMN IF EXISTS username,
+  DROP COLUMN IF EXISTS last_name,
+  DROP COLUMN IF EXISTS first_name;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+  id,
+  tenant_id,
+  created_by,
+  name,
+  key_type,
+  prefix,
+  key_hash,
+  scopes,
+  allowed_ips,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT * FROM api_keys WHERE prefix = $1;
+
+-- name: GetAPIKeyByID :one
+SELECT * FROM api_keys WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListAPIKeysByTenant :many
+SELECT * FROM api_keys
+WHERE tenant_id = $1
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKey :exec
+UPDATE api_keys
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL;
+
+-- name: SetAPIKeyExpiry :exec
+UPDATE api_keys
+SET expires_at = $3, updated_at = NOW()
+WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL;
+
+-- name: TouchAPIKeyLastUsed :exec
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
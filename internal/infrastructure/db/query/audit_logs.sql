@@ -0,0 +1,32 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (
+  id, tenant_id, user_id, actor_role, action, target_type, target_id,
+  changes, metadata, ip_address, user_agent
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+
+-- name: SearchAuditLogs :many
+SELECT * FROM audit_logs
+WHERE (sqlc.narg(tenant_id)::uuid IS NULL OR tenant_id = sqlc.narg(tenant_id))
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(action)::text IS NULL
+    OR action = sqlc.narg(action)
+    OR action LIKE sqlc.narg(action) || '.%')
+  AND (sqlc.narg(target_type)::text IS NULL OR target_type = sqlc.narg(target_type))
+  AND (sqlc.narg(target_id)::text IS NULL OR target_id = sqlc.narg(target_id))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountAuditLogs :one
+SELECT count(*) FROM audit_logs
+WHERE (sqlc.narg(tenant_id)::uuid IS NULL OR tenant_id = sqlc.narg(tenant_id))
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(action)::text IS NULL
+    OR action = sqlc.narg(action)
+    OR action LIKE sqlc.narg(action) || '.%')
+  AND (sqlc.narg(target_type)::text IS NULL OR target_type = sqlc.narg(target_type))
+  AND (sqlc.narg(target_id)::text IS NULL OR target_id = sqlc.narg(target_id))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to));
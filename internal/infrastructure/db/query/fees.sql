@@ -0,0 +1,70 @@
+-- name: CreatePricingPlan :one
+INSERT INTO pricing_plans (id, name, description, is_default)
+VALUES (sqlc.arg(id), sqlc.arg(name), sqlc.narg(description), sqlc.arg(is_default))
+RETURNING *;
+
+-- name: UpdatePricingPlan :one
+UPDATE pricing_plans
+SET name = sqlc.arg(name), description = sqlc.narg(description), is_default = sqlc.arg(is_default),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ClearDefaultPricingPlan :exec
+UPDATE pricing_plans SET is_default = false, updated_at = now()
+WHERE is_default AND id <> sqlc.arg(id);
+
+-- name: GetPricingPlan :one
+SELECT * FROM pricing_plans WHERE id = sqlc.arg(id);
+
+-- name: GetDefaultPricingPlan :one
+SELECT * FROM pricing_plans WHERE is_default;
+
+-- name: ListPricingPlans :many
+SELECT * FROM pricing_plans ORDER BY name;
+
+-- name: DeletePricingPlan :execrows
+DELETE FROM pricing_plans WHERE id = sqlc.arg(id);
+
+-- name: CreatePricingRule :exec
+INSERT INTO pricing_rules (
+  id, plan_id, transaction_type, channel, currency_code, provider_id, fee_type,
+  flat_amount, percentage, tiers, min_fee, max_fee, bearer, position
+) VALUES (
+  sqlc.arg(id), sqlc.arg(plan_id), sqlc.arg(transaction_type), sqlc.narg(channel),
+  sqlc.narg(currency_code), sqlc.narg(provider_id), sqlc.arg(fee_type), sqlc.arg(flat_amount),
+  sqlc.arg(percentage), sqlc.narg(tiers), sqlc.narg(min_fee), sqlc.narg(max_fee), sqlc.arg(bearer),
+  sqlc.arg(position)
+);
+
+-- name: DeletePricingRules :exec
+DELETE FROM pricing_rules WHERE plan_id = sqlc.arg(plan_id);
+
+-- name: ListPricingRules :many
+SELECT * FROM pricing_rules WHERE plan_id = sqlc.arg(plan_id) ORDER BY position, id;
+
+-- name: GetTenantPricingPlan :one
+SELECT p.* FROM pricing_plans p
+JOIN tenant_pricing_plans tp ON tp.plan_id = p.id
+WHERE tp.tenant_id = sqlc.arg(tenant_id);
+
+-- name: SetTenantPricingPlan :exec
+INSERT INTO tenant_pricing_plans (tenant_id, plan_id)
+VALUES (sqlc.arg(tenant_id), sqlc.arg(plan_id))
+ON CONFLICT (tenant_id) DO UPDATE SET plan_id = EXCLUDED.plan_id, updated_at = now();
+
+-- name: ClearTenantPricingPlan :exec
+DELETE FROM tenant_pricing_plans WHERE tenant_id = sqlc.arg(tenant_id);
+
+-- name: CreateFeeEntry :exec
+INSERT INTO fee_entries (id, transaction_id, tenant_id, currency_code, amount)
+VALUES (sqlc.arg(id), sqlc.arg(transaction_id), sqlc.arg(tenant_id), sqlc.arg(currency_code), sqlc.arg(amount));
+
+-- name: CreditRevenueWallet :exec
+INSERT INTO revenue_wallets (currency_code, balance)
+VALUES (sqlc.arg(currency_code), sqlc.arg(amount))
+ON CONFLICT (currency_code) DO UPDATE
+SET balance = revenue_wallets.balance + EXCLUDED.balance, updated_at = now();
+
+-- name: ListRevenueWallets :many
+SELECT * FROM revenue_wallets ORDER BY currency_code;
@@ -0,0 +1,21 @@
+-- name: CreateJWTSigningKey :one
+INSERT INTO jwt_signing_keys (kid, algorithm, private_key_encrypted, activates_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListLiveJWTSigningKeys :many
+SELECT * FROM jwt_signing_keys
+WHERE expires_at IS NULL OR expires_at > now()
+ORDER BY activates_at DESC;
+
+-- name: ExpireJWTSigningKeys :exec
+UPDATE jwt_signing_keys
+SET expires_at = $2
+WHERE kid <> $1 AND expires_at IS NULL;
+
+-- name: DeleteExpiredJWTSigningKeys :exec
+DELETE FROM jwt_signing_keys
+WHERE expires_at IS NOT NULL AND expires_at < now();
+
+-- name: LockJWTSigningKeys :exec
+SELECT pg_advisory_xact_lock(hashtext('jwt_signing_keys'));
@@ -0,0 +1,77 @@
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (id, provider_id, source, file_name, period_start, period_end, created_by)
+VALUES (sqlc.arg(id), sqlc.arg(provider_id), sqlc.arg(source), sqlc.narg(file_name),
+  sqlc.arg(period_start), sqlc.arg(period_end), sqlc.narg(created_by))
+RETURNING *;
+
+-- name: CompleteReconciliationRun :exec
+UPDATE reconciliation_runs
+SET status = 'completed', total_items = sqlc.arg(total_items), matched = sqlc.arg(matched),
+  missing = sqlc.arg(missing), mismatched = sqlc.arg(mismatched), extra = sqlc.arg(extra),
+  completed_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: FailReconciliationRun :exec
+UPDATE reconciliation_runs
+SET status = 'failed', error = sqlc.arg(error), completed_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: GetReconciliationRun :one
+SELECT * FROM reconciliation_runs WHERE id = sqlc.arg(id);
+
+-- name: ListReconciliationRuns :many
+SELECT * FROM reconciliation_runs
+WHERE (sqlc.narg(provider_id)::uuid IS NULL OR provider_id = sqlc.narg(provider_id))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListTransactionsByReferences :many
+SELECT * FROM transactions WHERE reference = ANY(sqlc.arg(refs)::text[]);
+
+-- name: ListCompletedProviderDeposits :many
+SELECT * FROM transactions
+WHERE provider_id = sqlc.arg(provider_id) AND type = 'deposit' AND status = 'completed'
+  AND created_at >= sqlc.arg(period_start) AND created_at < sqlc.arg(period_end)
+ORDER BY created_at, id;
+
+-- name: CreateReconciliationException :exec
+INSERT INTO reconciliation_exceptions (
+  id, run_id, provider_id, reference, classification, transaction_id, provider_amount,
+  provider_currency, provider_status, amount, currency_code, transaction_status, detail
+) VALUES (
+  sqlc.arg(id), sqlc.arg(run_id), sqlc.arg(provider_id), sqlc.arg(reference), sqlc.arg(classification),
+  sqlc.narg(transaction_id), sqlc.narg(provider_amount), sqlc.narg(provider_currency),
+  sqlc.narg(provider_status), sqlc.narg(amount), sqlc.narg(currency_code),
+  sqlc.narg(transaction_status), sqlc.arg(detail)
+);
+
+-- name: SupersedeReconciliationExceptions :execrows
+UPDATE reconciliation_exceptions
+SET status = 'superseded', resolved_at = now(), resolution_note = sqlc.arg(resolution_note)
+WHERE provider_id = sqlc.arg(provider_id) AND run_id <> sqlc.arg(run_id) AND status = 'open'
+  AND reference = ANY(sqlc.arg(refs)::text[]);
+
+-- name: GetReconciliationException :one
+SELECT * FROM reconciliation_exceptions WHERE id = sqlc.arg(id);
+
+-- name: SearchReconciliationExceptions :many
+SELECT * FROM reconciliation_exceptions
+WHERE (sqlc.narg(run_id)::uuid IS NULL OR run_id = sqlc.narg(run_id))
+  AND (sqlc.narg(provider_id)::uuid IS NULL OR provider_id = sqlc.narg(provider_id))
+  AND (sqlc.narg(classification)::text IS NULL OR classification = sqlc.narg(classification))
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountReconciliationExceptions :one
+SELECT count(*) FROM reconciliation_exceptions
+WHERE (sqlc.narg(run_id)::uuid IS NULL OR run_id = sqlc.narg(run_id))
+  AND (sqlc.narg(provider_id)::uuid IS NULL OR provider_id = sqlc.narg(provider_id))
+  AND (sqlc.narg(classification)::text IS NULL OR classification = sqlc.narg(classification))
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status));
+
+-- name: ResolveReconciliationException :execrows
+UPDATE reconciliation_exceptions
+SET status = sqlc.arg(status), resolved_by = sqlc.arg(resolved_by), resolved_at = now(),
+  resolution_note = sqlc.narg(resolution_note)
+WHERE id = sqlc.arg(id) AND status = 'open';
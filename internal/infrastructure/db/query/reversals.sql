@@ -0,0 +1,46 @@
+-- name: LockTransaction :one
+SELECT * FROM transactions WHERE id = sqlc.arg(id) FOR UPDATE;
+
+-- name: SumReversals :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS total
+FROM transaction_reversals
+WHERE original_transaction_id = sqlc.arg(original_transaction_id) AND status <> 'failed';
+
+-- name: CreateReversal :one
+INSERT INTO transaction_reversals (
+  id, tenant_id, original_transaction_id, currency_code, amount, reason, status,
+  debit_transaction_id, credit_transaction_id, created_by
+) VALUES (
+  sqlc.arg(id), sqlc.arg(tenant_id), sqlc.arg(original_transaction_id), sqlc.arg(currency_code),
+  sqlc.arg(amount), sqlc.arg(reason), sqlc.arg(status), sqlc.narg(debit_transaction_id),
+  sqlc.narg(credit_transaction_id), sqlc.narg(created_by)
+)
+RETURNING *;
+
+-- name: FinishReversal :one
+UPDATE transaction_reversals
+SET status = sqlc.arg(status), refund_status = sqlc.narg(refund_status),
+  refund_reference = sqlc.narg(refund_reference), error = sqlc.narg(error), updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: ListReversalsByTransaction :many
+SELECT * FROM transaction_reversals
+WHERE original_transaction_id = sqlc.arg(original_transaction_id)
+ORDER BY created_at, id;
+
+-- name: RecordReversalRefund :execrows
+UPDATE transaction_reversals
+SET refund_status = sqlc.arg(refund_status), refund_reference = sqlc.narg(refund_reference), updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending';
+
+-- name: ListStaleReversals :many
+SELECT * FROM transaction_reversals
+WHERE status = 'pending' AND updated_at < sqlc.arg(updated_before)
+ORDER BY updated_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ClaimReversal :execrows
+UPDATE transaction_reversals
+SET updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending' AND updated_at < sqlc.arg(updated_before);
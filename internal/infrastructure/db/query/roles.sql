@@ -0,0 +1,39 @@
+-- name: CreateRole :one
+INSERT INTO roles (id, tenant_id, name, description, permissions)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetRoleByID :one
+SELECT * FROM roles
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListRolesByTenant :many
+SELECT * FROM roles
+WHERE tenant_id = $1
+ORDER BY name;
+
+-- name: UpdateRole :one
+UPDATE roles
+SET name = $3, description = $4, permissions = $5, updated_at = now()
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: DeleteRole :execrows
+DELETE FROM roles
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: AssignRoleToUser :exec
+INSERT INTO user_roles (user_id, role_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnassignRoleFromUser :execrows
+DELETE FROM user_roles
+WHERE user_id = $1 AND role_id = $2;
+
+-- name: ListRolesForUser :many
+SELECT r.id, r.tenant_id, r.name, r.description, r.permissions, r.created_at, r.updated_at
+FROM roles r
+JOIN user_roles ur ON ur.role_id = r.id
+WHERE ur.user_id = $1
+ORDER BY r.name;
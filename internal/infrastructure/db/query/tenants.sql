@@ -18,5 +18,17 @@ SET name = $2, slug = $3, webhook_url = $4, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateTenantWebhookURL :one
+UPDATE tenants
+SET webhook_url = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteTenant :exec
 DELETE FROM tenants WHERE id = $1;
+
+-- name: UpdateTenantSettings :one
+UPDATE tenants
+SET require_email_verification = $2, deposit_amount_policy = $3, allow_p2p_transfers = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,52 @@
+-- name: CreateTransactionExport :one
+INSERT INTO transaction_exports (id, tenant_id, user_id, format, filters)
+VALUES (sqlc.arg(id), sqlc.narg(tenant_id), sqlc.arg(user_id), sqlc.arg(format), sqlc.arg(filters))
+RETURNING *;
+
+-- name: GetTransactionExport :one
+SELECT * FROM transaction_exports WHERE id = sqlc.arg(id);
+
+-- name: ListTransactionExportsByUser :many
+SELECT * FROM transaction_exports
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ClaimTransactionExport :one
+UPDATE transaction_exports
+SET status = 'processing', started_at = now(), attempts = attempts + 1
+WHERE id = (
+  SELECT e.id FROM transaction_exports e
+  WHERE e.status = 'pending'
+    OR (e.status = 'processing' AND e.started_at < sqlc.arg(stale_before)
+      AND e.attempts < sqlc.arg(max_attempts))
+  ORDER BY e.created_at
+  FOR UPDATE SKIP LOCKED
+  LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteTransactionExport :exec
+UPDATE transaction_exports
+SET status = 'completed', file_key = sqlc.arg(file_key), row_count = sqlc.arg(row_count),
+  file_size = sqlc.arg(file_size), completed_at = now(), expires_at = sqlc.arg(expires_at)
+WHERE id = sqlc.arg(id);
+
+-- name: FailTransactionExport :exec
+UPDATE transaction_exports
+SET status = 'failed', error = sqlc.arg(error), completed_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: ListExpiredTransactionExports :many
+SELECT * FROM transaction_exports
+WHERE (status = 'completed' AND expires_at < now())
+  OR (status = 'processing' AND started_at < sqlc.arg(stale_before)
+    AND attempts >= sqlc.arg(max_attempts))
+LIMIT 100;
+
+-- name: ExpireTransactionExport :exec
+UPDATE transaction_exports
+SET status = CASE WHEN status = 'completed' THEN 'expired' ELSE 'failed' END,
+  error = CASE WHEN status = 'completed' THEN error ELSE 'export timed out' END,
+  file_key = NULL
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,13 @@
+-- name: CreateTransactionPin :execrows
+INSERT INTO transaction_pins (user_id, pin_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO NOTHING;
+
+-- name: GetTransactionPin :one
+SELECT * FROM transaction_pins
+WHERE user_id = $1;
+
+-- name: UpdateTransactionPin :execrows
+UPDATE transaction_pins
+SET pin_hash = $2, updated_at = now()
+WHERE user_id = $1;
@@ -42,4 +42,98 @@ SELECT * FROM transactions WHERE tenant_id = $1 ORDER BY created_at DESC LIMIT $
 SELECT * FROM transactions ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 
 -- name: ListTransactionsByStatus :many
-SELECT * FROM transactions WHERE status = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3; 
\ No newline at end of file
+SELECT * FROM transactions WHERE status = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3;
+
+-- name: CountTransactions :one
+SELECT count(*) FROM transactions
+WHERE (sqlc.narg(tenant_id)::uuid IS NULL OR tenant_id = sqlc.narg(tenant_id))
+  AND (sqlc.narg(user_id)::uuid IS NULL
+    OR wallet_id IN (SELECT w.id FROM wallets w WHERE w.user_id = sqlc.narg(user_id)))
+  AND (sqlc.narg(wallet_id)::uuid IS NULL OR wallet_id = sqlc.narg(wallet_id))
+  AND (sqlc.narg(provider_id)::uuid IS NULL OR provider_id = sqlc.narg(provider_id))
+  AND (sqlc.narg(reference)::text IS NULL OR reference = sqlc.narg(reference))
+  AND (cardinality(sqlc.arg(types)::text[]) = 0 OR type = ANY(sqlc.arg(types)::text[]))
+  AND (cardinality(sqlc.arg(statuses)::text[]) = 0 OR status = ANY(sqlc.arg(statuses)::text[]))
+  AND (cardinality(sqlc.arg(currencies)::text[]) = 0 OR currency_code = ANY(sqlc.arg(currencies)::text[]))
+  AND (sqlc.narg(min_amount)::numeric IS NULL OR amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::numeric IS NULL OR amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to))
+  AND (sqlc.narg(metadata)::jsonb IS NULL OR metadata @> sqlc.narg(metadata))
+  AND (cardinality(sqlc.arg(metadata_keys)::text[]) = 0 OR metadata ?& sqlc.arg(metadata_keys)::text[]);
+
+-- name: SearchTransactionsByAmount :many
+SELECT * FROM transactions
+WHERE (sqlc.narg(tenant_id)::uuid IS NULL OR tenant_id = sqlc.narg(tenant_id))
+  AND (sqlc.narg(user_id)::uuid IS NULL
+    OR wallet_id IN (SELECT w.id FROM wallets w WHERE w.user_id = sqlc.narg(user_id)))
+  AND (sqlc.narg(wallet_id)::uuid IS NULL OR wallet_id = sqlc.narg(wallet_id))
+  AND (sqlc.narg(provider_id)::uuid IS NULL OR provider_id = sqlc.narg(provider_id))
+  AND (sqlc.narg(reference)::text IS NULL OR reference = sqlc.narg(reference))
+  AND (cardinality(sqlc.arg(types)::text[]) = 0 OR type = ANY(sqlc.arg(types)::text[]))
+  AND (cardinality(sqlc.arg(statuses)::text[]) = 0 OR status = ANY(sqlc.arg(statuses)::text[]))
+  AND (cardinality(sqlc.arg(currencies)::text[]) = 0 OR currency_code = ANY(sqlc.arg(currencies)::text[]))
+  AND (sqlc.narg(min_amount)::numeric IS NULL OR amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::numeric IS NULL OR amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to))
+  AND (sqlc.narg(metadata)::jsonb IS NULL OR metadata @> sqlc.narg(metadata))
+  AND (cardinality(sqlc.arg(metadata_keys)::text[]) = 0 OR metadata ?& sqlc.arg(metadata_keys)::text[])
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort_asc)::boolean
+      AND (amount, id) > (sqlc.narg(cursor_amount)::numeric, sqlc.narg(cursor_id)))
+    OR (NOT sqlc.arg(sort_asc)::boolean
+      AND (amount, id) < (sqlc.narg(cursor_amount)::numeric, sqlc.narg(cursor_id))))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_asc)::boolean THEN amount END ASC,
+  CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN amount END DESC,
+  CASE WHEN sqlc.arg(sort_asc)::boolean THEN id END ASC,
+  CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN id END DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SearchTransactionsByCreatedAt :many
+SELECT * FROM transactions
+WHERE (sqlc.narg(tenant_id)::uuid IS NULL OR tenant_id = sqlc.narg(tenant_id))
+  AND (sqlc.narg(user_id)::uuid IS NULL
+    OR wallet_id IN (SELECT w.id FROM wallets w WHERE w.user_id = sqlc.narg(user_id)))
+  AND (sqlc.narg(wallet_id)::uuid IS NULL OR wallet_id = sqlc.narg(wallet_id))
+  AND (sqlc.narg(provider_id)::uuid IS NULL OR provider_id = sqlc.narg(provider_id))
+  AND (sqlc.narg(reference)::text IS NULL OR reference = sqlc.narg(reference))
+  AND (cardinality(sqlc.arg(types)::text[]) = 0 OR type = ANY(sqlc.arg(types)::text[]))
+  AND (cardinality(sqlc.arg(statuses)::text[]) = 0 OR status = ANY(sqlc.arg(statuses)::text[]))
+  AND (cardinality(sqlc.arg(currencies)::text[]) = 0 OR currency_code = ANY(sqlc.arg(currencies)::text[]))
+  AND (sqlc.narg(min_amount)::numeric IS NULL OR amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::numeric IS NULL OR amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to))
+  AND (sqlc.narg(metadata)::jsonb IS NULL OR metadata @> sqlc.narg(metadata))
+  AND (cardinality(sqlc.arg(metadata_keys)::text[]) = 0 OR metadata ?& sqlc.arg(metadata_keys)::text[])
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort_asc)::boolean
+      AND (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)))
+    OR (NOT sqlc.arg(sort_asc)::boolean
+      AND (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id))))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_asc)::boolean THEN created_at END ASC,
+  CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort_asc)::boolean THEN id END ASC,
+  CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN id END DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListPendingDeposits :many
+SELECT * FROM transactions
+WHERE type = 'deposit' AND status = 'pending' AND created_at < sqlc.arg(created_before)
+  AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::uuid)
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: TransitionTransactionStatus :execrows
+UPDATE transactions
+SET status = sqlc.arg(status), amount = sqlc.arg(amount), error_reason = sqlc.narg(error_reason), updated_at = now()
+WHERE id = sqlc.arg(id) AND status = ANY(sqlc.arg(from_statuses)::text[]);
+
+-- name: FlagTransaction :execrows
+UPDATE transactions
+SET status = 'flagged', error_reason = sqlc.arg(error_reason),
+  metadata = COALESCE(metadata, '{}'::jsonb) || sqlc.arg(review)::jsonb, updated_at = now()
+WHERE id = sqlc.arg(id) AND status = ANY(sqlc.arg(from_statuses)::text[]);
@@ -0,0 +1,18 @@
+-- name: CreateTransfer :exec
+INSERT INTO transfers (
+  id, tenant_id, sender_wallet_id, receiver_wallet_id, transaction_id, credit_transaction_id,
+  currency_code, amount, fee, status
+) VALUES (
+  sqlc.arg(id), sqlc.arg(tenant_id), sqlc.arg(sender_wallet_id), sqlc.arg(receiver_wallet_id),
+  sqlc.arg(transaction_id), sqlc.arg(credit_transaction_id), sqlc.arg(currency_code), sqlc.arg(amount),
+  sqlc.arg(fee), sqlc.arg(status)
+);
+
+-- name: GetTransferDetails :one
+SELECT t.id, t.tenant_id, t.sender_wallet_id, t.receiver_wallet_id, t.transaction_id,
+  t.credit_transaction_id, t.currency_code, t.amount, t.fee, t.status, t.created_at, t.updated_at,
+  sw.user_id AS sender_user_id, rw.user_id AS receiver_user_id
+FROM transfers t
+JOIN wallets sw ON sw.id = t.sender_wallet_id
+JOIN wallets rw ON rw.id = t.receiver_wallet_id
+WHERE t.id = sqlc.arg(id);
@@ -0,0 +1,39 @@
+-- name: CreateUserInvite :one
+INSERT INTO user_invites (id, tenant_id, email, role, token_hash, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetUserInviteByID :one
+SELECT * FROM user_invites
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: GetUserInviteByTokenHash :one
+SELECT * FROM user_invites
+WHERE token_hash = $1;
+
+-- name: ListUserInvitesByTenant :many
+SELECT * FROM user_invites
+WHERE tenant_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: RenewUserInvite :one
+UPDATE user_invites
+SET token_hash = $3, expires_at = $4, updated_at = now()
+WHERE id = $1 AND tenant_id = $2 AND accepted_at IS NULL AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeUserInvite :execrows
+UPDATE user_invites
+SET revoked_at = now(), updated_at = now()
+WHERE id = $1 AND tenant_id = $2 AND accepted_at IS NULL AND revoked_at IS NULL;
+
+-- name: RevokePendingUserInvites :exec
+UPDATE user_invites
+SET revoked_at = now(), updated_at = now()
+WHERE tenant_id = $1 AND email = $2 AND accepted_at IS NULL AND revoked_at IS NULL;
+
+-- name: AcceptUserInvite :execrows
+UPDATE user_invites
+SET accepted_at = now(), user_id = $2, updated_at = now()
+WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now();
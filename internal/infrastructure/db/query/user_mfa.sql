@@ -0,0 +1,44 @@
+-- name: UpsertUserMFA :one
+INSERT INTO user_mfa (user_id, secret_encrypted)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret_encrypted = EXCLUDED.secret_encrypted,
+    enabled_at = NULL,
+    last_used_step = 0,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetUserMFA :one
+SELECT * FROM user_mfa
+WHERE user_id = $1;
+
+-- name: EnableUserMFA :exec
+UPDATE user_mfa
+SET enabled_at = now(), updated_at = now()
+WHERE user_id = $1;
+
+-- name: DeleteUserMFA :exec
+DELETE FROM user_mfa
+WHERE user_id = $1;
+
+-- name: UpdateUserMFALastUsedStep :execrows
+UPDATE user_mfa
+SET last_used_step = $2, updated_at = now()
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: CreateMFARecoveryCode :exec
+INSERT INTO mfa_recovery_codes (id, user_id, code_hash)
+VALUES ($1, $2, $3);
+
+-- name: DeleteMFARecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseMFARecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedMFARecoveryCodes :one
+SELECT COUNT(*) FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
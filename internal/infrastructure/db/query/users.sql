@@ -1,6 +1,7 @@
 -- name: CreateUser :one
-INSERT INTO users (id, tenant_id, email, phone, password_hash, is_active, role, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())
+INSERT INTO users (id, tenant_id, email, phone, password_hash, is_active, role, first_name, last_name,
+  created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now())
 RETURNING *;
 
 -- name: GetUserByID :one
@@ -28,3 +29,65 @@ WHERE id = $1;
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = now(), updated_at = now()
+WHERE id = $1 AND email_verified_at IS NULL;
+
+-- name: GetTenantUserByID :one
+SELECT * FROM users
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: SearchTenantUsers :many
+SELECT * FROM users
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(search)::text IS NULL
+    OR email ILIKE '%' || sqlc.narg(search) || '%'
+    OR phone ILIKE '%' || sqlc.narg(search) || '%')
+  AND (sqlc.narg(role)::text IS NULL OR role = sqlc.narg(role))
+  AND (sqlc.narg(is_active)::boolean IS NULL OR is_active = sqlc.narg(is_active))
+ORDER BY created_at DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountTenantUsers :one
+SELECT count(*) FROM users
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(search)::text IS NULL
+    OR email ILIKE '%' || sqlc.narg(search) || '%'
+    OR phone ILIKE '%' || sqlc.narg(search) || '%')
+  AND (sqlc.narg(role)::text IS NULL OR role = sqlc.narg(role))
+  AND (sqlc.narg(is_active)::boolean IS NULL OR is_active = sqlc.narg(is_active));
+
+-- name: SetTenantUserActive :one
+UPDATE users
+SET is_active = $3, updated_at = now()
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: UpdateTenantUserRole :one
+UPDATE users
+SET role = $3, updated_at = now()
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: GetTenantUserByUsername :one
+SELECT * FROM users
+WHERE tenant_id = sqlc.arg(tenant_id) AND lower(username) = lower(sqlc.arg(username)::text);
+
+-- name: ListTenantUsersByPhone :many
+SELECT * FROM users
+WHERE tenant_id = sqlc.arg(tenant_id) AND phone = sqlc.arg(phone)
+ORDER BY created_at
+LIMIT 2;
+
+-- name: SetUsername :one
+UPDATE users
+SET username = sqlc.narg(username), updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
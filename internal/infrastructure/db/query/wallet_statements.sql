@@ -0,0 +1,60 @@
+-- name: GetStatementBalances :one
+SELECT w.user_id, wt.currency AS currency_code, w.balance,
+  COALESCE(SUM(CASE WHEN t.type = 'deposit' OR t.metadata->>'direction' = 'credit'
+      THEN t.amount ELSE -t.amount END - t.fee), 0)::numeric AS net_since_start,
+  COALESCE(SUM(CASE WHEN t.type = 'deposit' OR t.metadata->>'direction' = 'credit'
+      THEN t.amount ELSE -t.amount END - t.fee)
+    FILTER (WHERE t.created_at >= sqlc.arg(period_end)), 0)::numeric AS net_since_end
+FROM wallets w
+JOIN wallet_types wt ON wt.id = w.wallet_type_id
+LEFT JOIN transactions t ON t.wallet_id = w.id AND t.status = 'completed'
+  AND t.created_at >= sqlc.arg(period_start)
+WHERE w.id = sqlc.arg(wallet_id)
+GROUP BY w.user_id, wt.currency, w.balance;
+
+-- name: ListStatementTransactions :many
+SELECT * FROM transactions
+WHERE wallet_id = sqlc.arg(wallet_id) AND status = 'completed'
+  AND created_at >= sqlc.arg(period_start) AND created_at < sqlc.arg(period_end)
+ORDER BY created_at, id;
+
+-- name: ListStatementWallets :many
+SELECT w.id, w.user_id, u.email, u.email_verified_at
+FROM wallets w
+JOIN users u ON u.id = w.user_id
+WHERE w.status <> 'closed' AND u.is_active
+  AND w.created_at < sqlc.arg(period_end) AND w.id > sqlc.arg(after_id)
+ORDER BY w.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: CreateWalletStatement :execrows
+INSERT INTO wallet_statements (
+  id, wallet_id, user_id, currency_code, period_start, period_end, opening_balance,
+  total_credits, total_debits, total_fees, closing_balance, entry_count, pdf_key, csv_key
+) VALUES (
+  sqlc.arg(id), sqlc.arg(wallet_id), sqlc.arg(user_id), sqlc.arg(currency_code), sqlc.arg(period_start),
+  sqlc.arg(period_end), sqlc.arg(opening_balance), sqlc.arg(total_credits), sqlc.arg(total_debits),
+  sqlc.arg(total_fees), sqlc.arg(closing_balance), sqlc.arg(entry_count), sqlc.arg(pdf_key), sqlc.arg(csv_key)
+)
+ON CONFLICT (wallet_id, period_start, period_end) DO NOTHING;
+
+-- name: GetWalletStatement :one
+SELECT * FROM wallet_statements WHERE id = sqlc.arg(id);
+
+-- name: GetWalletStatementByPeriod :one
+SELECT * FROM wallet_statements
+WHERE wallet_id = sqlc.arg(wallet_id) AND period_start = sqlc.arg(period_start)
+  AND period_end = sqlc.arg(period_end);
+
+-- name: ListWalletStatements :many
+SELECT * FROM wallet_statements
+WHERE wallet_id = sqlc.arg(wallet_id)
+ORDER BY period_start DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ClaimWalletStatementEmail :execrows
+UPDATE wallet_statements SET emailed_at = now()
+WHERE id = sqlc.arg(id) AND emailed_at IS NULL;
+
+-- name: ReleaseWalletStatementEmail :exec
+UPDATE wallet_statements SET emailed_at = NULL WHERE id = sqlc.arg(id);
@@ -6,6 +6,19 @@ RETURNING *;
 -- name: GetWalletByID :one
 SELECT * FROM wallets WHERE id = $1;
 
+-- name: GetWalletWithTenant :one
+SELECT w.*, u.tenant_id
+FROM wallets w
+JOIN users u ON u.id = w.user_id
+WHERE w.id = $1;
+
+-- name: GetWalletForUpdate :one
+SELECT w.*, u.tenant_id
+FROM wallets w
+JOIN users u ON u.id = w.user_id
+WHERE w.id = $1
+FOR UPDATE OF w;
+
 -- name: ListActiveWalletTypes :many
 SELECT * FROM wallet_types WHERE is_active ORDER BY currency ASC;
 
@@ -15,6 +28,9 @@ SELECT * FROM wallets WHERE user_id = $1 ORDER BY created_at DESC;
 -- name: UpdateWalletBalance :exec
 UPDATE wallets SET balance = $1, updated_at = now() WHERE id = $2;
 
+-- name: UpdateWalletStatus :exec
+UPDATE wallets SET status = $1, updated_at = now() WHERE id = $2;
+
 -- name: UpdateWalletType :exec
 UPDATE wallets SET wallet_type_id = $1, updated_at = now() WHERE id = $2;
 
@@ -56,6 +72,12 @@ FROM wallet_types
 WHERE currency = $1
 LIMIT 1;
 
+-- name: GetWalletCurrency :one
+SELECT wt.currency
+FROM wallets w
+JOIN wallet_types wt ON w.wallet_type_id = wt.id
+WHERE w.id = $1;
+
 -- name: GetWalletByUserAndCurrency :one
 SELECT w.*
 FROM wallets w
@@ -11,13 +11,36 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+type ApiKey struct {
+	ID         pgtype.UUID
+	TenantID   pgtype.UUID
+	CreatedBy  pgtype.UUID
+	Name       string
+	KeyType    string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	AllowedIps []string
+	LastUsedAt pgtype.Timestamptz
+	ExpiresAt  pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
 type AuditLog struct {
-	ID        pgtype.UUID
-	TenantID  pgtype.UUID
-	UserID    pgtype.UUID
-	Action    string
-	Metadata  []byte
-	CreatedAt pgtype.Timestamptz
+	ID         pgtype.UUID
+	TenantID   pgtype.UUID
+	UserID     pgtype.UUID
+	Action     string
+	Metadata   []byte
+	CreatedAt  pgtype.Timestamptz
+	ActorRole  pgtype.Text
+	TargetType pgtype.Text
+	TargetID   pgtype.Text
+	Changes    []byte
+	IpAddress  pgtype.Text
+	UserAgent  pgtype.Text
 }
 
 type Currency struct {
@@ -40,6 +63,15 @@ type Deposit struct {
 	UpdatedAt     pgtype.Timestamp
 }
 
+type FeeEntry struct {
+	ID            pgtype.UUID
+	TransactionID pgtype.UUID
+	TenantID      pgtype.UUID
+	CurrencyCode  string
+	Amount        decimal.Decimal
+	CreatedAt     pgtype.Timestamptz
+}
+
 type IdempotencyKey struct {
 	ID             pgtype.UUID
 	TenantID       pgtype.UUID
@@ -53,6 +85,50 @@ type IdempotencyKey struct {
 	UpdatedAt      pgtype.Timestamptz
 }
 
+type JwtSigningKey struct {
+	Kid                 string
+	Algorithm           string
+	PrivateKeyEncrypted string
+	ActivatesAt         pgtype.Timestamptz
+	ExpiresAt           pgtype.Timestamptz
+	CreatedAt           pgtype.Timestamptz
+}
+
+type MfaRecoveryCode struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	CodeHash  string
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type PricingPlan struct {
+	ID          pgtype.UUID
+	Name        string
+	Description pgtype.Text
+	IsDefault   bool
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+}
+
+type PricingRule struct {
+	ID              pgtype.UUID
+	PlanID          pgtype.UUID
+	TransactionType string
+	Channel         pgtype.Text
+	CurrencyCode    pgtype.Text
+	ProviderID      pgtype.UUID
+	FeeType         string
+	FlatAmount      decimal.Decimal
+	Percentage      decimal.Decimal
+	Tiers           []byte
+	MinFee          *decimal.Decimal
+	MaxFee          *decimal.Decimal
+	Bearer          string
+	Position        int32
+	CreatedAt       pgtype.Timestamptz
+}
+
 type Provider struct {
 	ID        pgtype.UUID
 	Name      string
@@ -87,13 +163,78 @@ type ProviderSupportedCurrency struct {
 	UpdatedAt    pgtype.Timestamptz
 }
 
+type ReconciliationException struct {
+	ID                pgtype.UUID
+	RunID             pgtype.UUID
+	ProviderID        pgtype.UUID
+	Reference         string
+	Classification    string
+	TransactionID     pgtype.UUID
+	ProviderAmount    *decimal.Decimal
+	ProviderCurrency  pgtype.Text
+	ProviderStatus    pgtype.Text
+	Amount            *decimal.Decimal
+	CurrencyCode      pgtype.Text
+	TransactionStatus pgtype.Text
+	Detail            string
+	Status            string
+	ResolvedBy        pgtype.UUID
+	ResolvedAt        pgtype.Timestamptz
+	ResolutionNote    pgtype.Text
+	CreatedAt         pgtype.Timestamptz
+}
+
+type ReconciliationRun struct {
+	ID          pgtype.UUID
+	ProviderID  pgtype.UUID
+	Source      string
+	FileName    pgtype.Text
+	PeriodStart pgtype.Timestamptz
+	PeriodEnd   pgtype.Timestamptz
+	Status      string
+	TotalItems  int32
+	Matched     int32
+	Missing     int32
+	Mismatched  int32
+	Extra       int32
+	Error       pgtype.Text
+	CreatedBy   pgtype.UUID
+	CreatedAt   pgtype.Timestamptz
+	CompletedAt pgtype.Timestamptz
+}
+
+type RevenueWallet struct {
+	CurrencyCode string
+	Balance      decimal.Decimal
+	UpdatedAt    pgtype.Timestamptz
+}
+
+type Role struct {
+	ID          pgtype.UUID
+	TenantID    pgtype.UUID
+	Name        string
+	Description string
+	Permissions []string
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+}
+
 type Tenant struct {
-	ID         pgtype.UUID
-	Name       string
-	Slug       string
-	WebhookUrl string
-	CreatedAt  pgtype.Timestamptz
-	UpdatedAt  pgtype.Timestamptz
+	ID                       pgtype.UUID
+	Name                     string
+	Slug                     string
+	WebhookUrl               string
+	CreatedAt                pgtype.Timestamptz
+	UpdatedAt                pgtype.Timestamptz
+	RequireEmailVerification bool
+	DepositAmountPolicy      string
+	AllowP2pTransfers        bool
+}
+
+type TenantPricingPlan struct {
+	TenantID  pgtype.UUID
+	PlanID    pgtype.UUID
+	UpdatedAt pgtype.Timestamptz
 }
 
 type Transaction struct {
@@ -113,28 +254,118 @@ type Transaction struct {
 	UpdatedAt    pgtype.Timestamptz
 }
 
+type TransactionExport struct {
+	ID          pgtype.UUID
+	TenantID    pgtype.UUID
+	UserID      pgtype.UUID
+	Format      string
+	Filters     []byte
+	Status      string
+	Attempts    int32
+	RowCount    int64
+	FileKey     pgtype.Text
+	FileSize    int64
+	Error       pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+	StartedAt   pgtype.Timestamptz
+	CompletedAt pgtype.Timestamptz
+	ExpiresAt   pgtype.Timestamptz
+}
+
+type TransactionPin struct {
+	UserID    pgtype.UUID
+	PinHash   string
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type TransactionReversal struct {
+	ID                    pgtype.UUID
+	TenantID              pgtype.UUID
+	OriginalTransactionID pgtype.UUID
+	CurrencyCode          string
+	Amount                decimal.Decimal
+	Reason                string
+	Status                string
+	DebitTransactionID    pgtype.UUID
+	CreditTransactionID   pgtype.UUID
+	RefundStatus          pgtype.Text
+	RefundReference       pgtype.Text
+	Error                 pgtype.Text
+	CreatedBy             pgtype.UUID
+	CreatedAt             pgtype.Timestamptz
+	UpdatedAt             pgtype.Timestamptz
+}
+
 type Transfer struct {
-	ID               pgtype.UUID
-	TenantID         pgtype.UUID
-	SenderWalletID   pgtype.UUID
-	ReceiverWalletID pgtype.UUID
-	TransactionID    pgtype.UUID
-	Amount           decimal.Decimal
-	Status           string
-	CreatedAt        pgtype.Timestamptz
-	UpdatedAt        pgtype.Timestamptz
+	ID                  pgtype.UUID
+	TenantID            pgtype.UUID
+	SenderWalletID      pgtype.UUID
+	ReceiverWalletID    pgtype.UUID
+	TransactionID       pgtype.UUID
+	Amount              decimal.Decimal
+	Status              string
+	CreatedAt           pgtype.Timestamptz
+	UpdatedAt           pgtype.Timestamptz
+	CreditTransactionID pgtype.UUID
+	CurrencyCode        string
+	Fee                 decimal.Decimal
 }
 
 type User struct {
-	ID           pgtype.UUID
-	TenantID     pgtype.UUID
-	Email        string
-	Phone        pgtype.Text
-	PasswordHash string
-	Role         pgtype.Text
-	IsActive     pgtype.Bool
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
+	ID              pgtype.UUID
+	TenantID        pgtype.UUID
+	Email           string
+	Phone           pgtype.Text
+	PasswordHash    string
+	Role            pgtype.Text
+	IsActive        pgtype.Bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+	FirstName       pgtype.Text
+	LastName        pgtype.Text
+	Username        pgtype.Text
+}
+
+type UserInvite struct {
+	ID         pgtype.UUID
+	TenantID   pgtype.UUID
+	Email      string
+	Role       string
+	TokenHash  string
+	InvitedBy  pgtype.UUID
+	UserID     pgtype.UUID
+	ExpiresAt  pgtype.Timestamptz
+	AcceptedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type UserMfa struct {
+	UserID          pgtype.UUID
+	SecretEncrypted string
+	EnabledAt       pgtype.Timestamptz
+	LastUsedStep    int64
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+}
+
+type UserRole struct {
+	UserID    pgtype.UUID
+	RoleID    pgtype.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+type UserToken struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	Purpose   string
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
 }
 
 type VirtualAccount struct {
@@ -159,6 +390,25 @@ type Wallet struct {
 	UpdatedAt    pgtype.Timestamptz
 }
 
+type WalletStatement struct {
+	ID             pgtype.UUID
+	WalletID       pgtype.UUID
+	UserID         pgtype.UUID
+	CurrencyCode   string
+	PeriodStart    pgtype.Timestamptz
+	PeriodEnd      pgtype.Timestamptz
+	OpeningBalance decimal.Decimal
+	TotalCredits   decimal.Decimal
+	TotalDebits    decimal.Decimal
+	TotalFees      decimal.Decimal
+	ClosingBalance decimal.Decimal
+	EntryCount     int32
+	PdfKey         string
+	CsvKey         string
+	EmailedAt      pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+}
+
 type WalletType struct {
 	ID          pgtype.UUID
 	Name        string
@@ -14,7 +14,7 @@ import (
 const createTenant = `-- name: CreateTenant :one
 INSERT INTO tenants (id, name, slug, webhook_url, created_at, updated_at)
 VALUES ($1, $2, $3, $4, NOW(), NOW())
-RETURNING id, name, slug, webhook_url, created_at, updated_at
+RETURNING id, name, slug, webhook_url, created_at, updated_at, require_email_verification, deposit_amount_policy, allow_p2p_transfers
 `
 
 type CreateTenantParams struct {
@@ -39,6 +39,9 @@ func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Ten
 		&i.WebhookUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequireEmailVerification,
+		&i.DepositAmountPolicy,
+		&i.AllowP2pTransfers,
 	)
 	return i, err
 }
@@ -53,7 +56,7 @@ func (q *Queries) DeleteTenant(ctx context.Context, id pgtype.UUID) error {
 }
 
 const getTenantByID = `-- name: GetTenantByID :one
-SELECT id, name, slug, webhook_url, created_at, updated_at FROM tenants WHERE id = $1
+SELECT id, name, slug, webhook_url, created_at, updated_at, require_email_verification, deposit_amount_policy, allow_p2p_transfers FROM tenants WHERE id = $1
 `
 
 func (q *Queries) GetTenantByID(ctx context.Context, id pgtype.UUID) (Tenant, error) {
@@ -66,12 +69,15 @@ func (q *Queries) GetTenantByID(ctx context.Context, id pgtype.UUID) (Tenant, er
 		&i.WebhookUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequireEmailVerification,
+		&i.DepositAmountPolicy,
+		&i.AllowP2pTransfers,
 	)
 	return i, err
 }
 
 const getTenantBySlug = `-- name: GetTenantBySlug :one
-SELECT id, name, slug, webhook_url, created_at, updated_at FROM tenants WHERE slug = $1
+SELECT id, name, slug, webhook_url, created_at, updated_at, require_email_verification, deposit_amount_policy, allow_p2p_transfers FROM tenants WHERE slug = $1
 `
 
 func (q *Queries) GetTenantBySlug(ctx context.Context, slug string) (Tenant, error) {
@@ -84,12 +90,15 @@ func (q *Queries) GetTenantBySlug(ctx context.Context, slug string) (Tenant, err
 		&i.WebhookUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequireEmailVerification,
+		&i.DepositAmountPolicy,
+		&i.AllowP2pTransfers,
 	)
 	return i, err
 }
 
 const listTenants = `-- name: ListTenants :many
-SELECT id, name, slug, webhook_url, created_at, updated_at FROM tenants
+SELECT id, name, slug, webhook_url, created_at, updated_at, require_email_verification, deposit_amount_policy, allow_p2p_transfers FROM tenants
 `
 
 func (q *Queries) ListTenants(ctx context.Context) ([]Tenant, error) {
@@ -108,6 +117,9 @@ func (q *Queries) ListTenants(ctx context.Context) ([]Tenant, error) {
 			&i.WebhookUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RequireEmailVerification,
+			&i.DepositAmountPolicy,
+			&i.AllowP2pTransfers,
 		); err != nil {
 			return nil, err
 		}
@@ -123,7 +135,7 @@ const updateTenant = `-- name: UpdateTenant :one
 UPDATE tenants
 SET name = $2, slug = $3, webhook_url = $4, updated_at = NOW()
 WHERE id = $1
-RETURNING id, name, slug, webhook_url, created_at, updated_at
+RETURNING id, name, slug, webhook_url, created_at, updated_at, require_email_verification, deposit_amount_policy, allow_p2p_transfers
 `
 
 type UpdateTenantParams struct {
@@ -148,6 +160,69 @@ func (q *Queries) UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Ten
 		&i.WebhookUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequireEmailVerification,
+		&i.DepositAmountPolicy,
+		&i.AllowP2pTransfers,
+	)
+	return i, err
+}
+
+const updateTenantSettings = `-- name: UpdateTenantSettings :one
+UPDATE tenants
+SET require_email_verification = $2, deposit_amount_policy = $3, allow_p2p_transfers = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, slug, webhook_url, created_at, updated_at, require_email_verification, deposit_amount_policy, allow_p2p_transfers
+`
+
+type UpdateTenantSettingsParams struct {
+	ID                       pgtype.UUID
+	RequireEmailVerification bool
+	DepositAmountPolicy      string
+	AllowP2pTransfers        bool
+}
+
+func (q *Queries) UpdateTenantSettings(ctx context.Context, arg UpdateTenantSettingsParams) (Tenant, error) {
+	row := q.db.QueryRow(ctx, updateTenantSettings, arg.ID, arg.RequireEmailVerification, arg.DepositAmountPolicy, arg.AllowP2pTransfers)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.WebhookUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RequireEmailVerification,
+		&i.DepositAmountPolicy,
+		&i.AllowP2pTransfers,
+	)
+	return i, err
+}
+
+const updateTenantWebhookURL = `-- name: UpdateTenantWebhookURL :one
+UPDATE tenants
+SET webhook_url = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, slug, webhook_url, created_at, updated_at, require_email_verification, deposit_amount_policy, allow_p2p_transfers
+`
+
+type UpdateTenantWebhookURLParams struct {
+	ID         pgtype.UUID
+	WebhookUrl string
+}
+
+func (q *Queries) UpdateTenantWebhookURL(ctx context.Context, arg UpdateTenantWebhookURLParams) (Tenant, error) {
+	row := q.db.QueryRow(ctx, updateTenantWebhookURL, arg.ID, arg.WebhookUrl)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.WebhookUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RequireEmailVerification,
+		&i.DepositAmountPolicy,
+		&i.AllowP2pTransfers,
 	)
 	return i, err
 }
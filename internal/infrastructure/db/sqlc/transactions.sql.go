@@ -12,6 +12,64 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+const countTransactions = `-- name: CountTransactions :one
+SELECT count(*) FROM transactions
+WHERE ($1::uuid IS NULL OR tenant_id = $1)
+  AND ($2::uuid IS NULL
+    OR wallet_id IN (SELECT w.id FROM wallets w WHERE w.user_id = $2))
+  AND ($3::uuid IS NULL OR wallet_id = $3)
+  AND ($4::uuid IS NULL OR provider_id = $4)
+  AND ($5::text IS NULL OR reference = $5)
+  AND (cardinality($6::text[]) = 0 OR type = ANY($6::text[]))
+  AND (cardinality($7::text[]) = 0 OR status = ANY($7::text[]))
+  AND (cardinality($8::text[]) = 0 OR currency_code = ANY($8::text[]))
+  AND ($9::numeric IS NULL OR amount >= $9)
+  AND ($10::numeric IS NULL OR amount <= $10)
+  AND ($11::timestamptz IS NULL OR created_at >= $11)
+  AND ($12::timestamptz IS NULL OR created_at < $12)
+  AND ($13::jsonb IS NULL OR metadata @> $13)
+  AND (cardinality($14::text[]) = 0 OR metadata ?& $14::text[])
+`
+
+type CountTransactionsParams struct {
+	TenantID     pgtype.UUID
+	UserID       pgtype.UUID
+	WalletID     pgtype.UUID
+	ProviderID   pgtype.UUID
+	Reference    pgtype.Text
+	Types        []string
+	Statuses     []string
+	Currencies   []string
+	MinAmount    *decimal.Decimal
+	MaxAmount    *decimal.Decimal
+	CreatedFrom  pgtype.Timestamptz
+	CreatedTo    pgtype.Timestamptz
+	Metadata     []byte
+	MetadataKeys []string
+}
+
+func (q *Queries) CountTransactions(ctx context.Context, arg CountTransactionsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countTransactions,
+		arg.TenantID,
+		arg.UserID,
+		arg.WalletID,
+		arg.ProviderID,
+		arg.Reference,
+		arg.Types,
+		arg.Statuses,
+		arg.Currencies,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Metadata,
+		arg.MetadataKeys,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTransaction = `-- name: CreateTransaction :one
 INSERT INTO transactions (
   id,
@@ -83,6 +141,28 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 	return i, err
 }
 
+const flagTransaction = `-- name: FlagTransaction :execrows
+UPDATE transactions
+SET status = 'flagged', error_reason = $1,
+  metadata = COALESCE(metadata, '{}'::jsonb) || $2::jsonb, updated_at = now()
+WHERE id = $3 AND status = ANY($4::text[])
+`
+
+type FlagTransactionParams struct {
+	ErrorReason  pgtype.Text
+	Review       []byte
+	ID           pgtype.UUID
+	FromStatuses []string
+}
+
+func (q *Queries) FlagTransaction(ctx context.Context, arg FlagTransactionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, flagTransaction, arg.ErrorReason, arg.Review, arg.ID, arg.FromStatuses)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getTransactionByID = `-- name: GetTransactionByID :one
 SELECT id, tenant_id, wallet_id, provider_id, currency_code, reference, type, status, amount, fee, metadata, error_reason, created_at, updated_at FROM transactions WHERE id = $1
 `
@@ -179,6 +259,56 @@ func (q *Queries) ListAllTransactions(ctx context.Context, arg ListAllTransactio
 	return items, nil
 }
 
+const listPendingDeposits = `-- name: ListPendingDeposits :many
+SELECT id, tenant_id, wallet_id, provider_id, currency_code, reference, type, status, amount, fee, metadata, error_reason, created_at, updated_at FROM transactions
+WHERE type = 'deposit' AND status = 'pending' AND created_at < $1
+  AND (created_at, id) > ($2::timestamptz, $3::uuid)
+ORDER BY created_at, id
+LIMIT $4
+`
+
+type ListPendingDepositsParams struct {
+	CreatedBefore  pgtype.Timestamptz
+	AfterCreatedAt pgtype.Timestamptz
+	AfterID        pgtype.UUID
+	RowLimit       int32
+}
+
+func (q *Queries) ListPendingDeposits(ctx context.Context, arg ListPendingDepositsParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, listPendingDeposits, arg.CreatedBefore, arg.AfterCreatedAt, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WalletID,
+			&i.ProviderID,
+			&i.CurrencyCode,
+			&i.Reference,
+			&i.Type,
+			&i.Status,
+			&i.Amount,
+			&i.Fee,
+			&i.Metadata,
+			&i.ErrorReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransactionsByStatus = `-- name: ListTransactionsByStatus :many
 SELECT id, tenant_id, wallet_id, provider_id, currency_code, reference, type, status, amount, fee, metadata, error_reason, created_at, updated_at FROM transactions WHERE status = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
 `
@@ -359,6 +489,244 @@ func (q *Queries) ListTransactionsByWalletID(ctx context.Context, arg ListTransa
 	return items, nil
 }
 
+const searchTransactionsByAmount = `-- name: SearchTransactionsByAmount :many
+SELECT id, tenant_id, wallet_id, provider_id, currency_code, reference, type, status, amount, fee, metadata, error_reason, created_at, updated_at FROM transactions
+WHERE ($1::uuid IS NULL OR tenant_id = $1)
+  AND ($2::uuid IS NULL
+    OR wallet_id IN (SELECT w.id FROM wallets w WHERE w.user_id = $2))
+  AND ($3::uuid IS NULL OR wallet_id = $3)
+  AND ($4::uuid IS NULL OR provider_id = $4)
+  AND ($5::text IS NULL OR reference = $5)
+  AND (cardinality($6::text[]) = 0 OR type = ANY($6::text[]))
+  AND (cardinality($7::text[]) = 0 OR status = ANY($7::text[]))
+  AND (cardinality($8::text[]) = 0 OR currency_code = ANY($8::text[]))
+  AND ($9::numeric IS NULL OR amount >= $9)
+  AND ($10::numeric IS NULL OR amount <= $10)
+  AND ($11::timestamptz IS NULL OR created_at >= $11)
+  AND ($12::timestamptz IS NULL OR created_at < $12)
+  AND ($13::jsonb IS NULL OR metadata @> $13)
+  AND (cardinality($14::text[]) = 0 OR metadata ?& $14::text[])
+  AND ($15::uuid IS NULL
+    OR ($16::boolean
+      AND (amount, id) > ($17::numeric, $15))
+    OR (NOT $16::boolean
+      AND (amount, id) < ($17::numeric, $15)))
+ORDER BY
+  CASE WHEN $16::boolean THEN amount END ASC,
+  CASE WHEN NOT $16::boolean THEN amount END DESC,
+  CASE WHEN $16::boolean THEN id END ASC,
+  CASE WHEN NOT $16::boolean THEN id END DESC
+LIMIT $18
+`
+
+type SearchTransactionsByAmountParams struct {
+	TenantID     pgtype.UUID
+	UserID       pgtype.UUID
+	WalletID     pgtype.UUID
+	ProviderID   pgtype.UUID
+	Reference    pgtype.Text
+	Types        []string
+	Statuses     []string
+	Currencies   []string
+	MinAmount    *decimal.Decimal
+	MaxAmount    *decimal.Decimal
+	CreatedFrom  pgtype.Timestamptz
+	CreatedTo    pgtype.Timestamptz
+	Metadata     []byte
+	MetadataKeys []string
+	CursorID     pgtype.UUID
+	SortAsc      bool
+	CursorAmount *decimal.Decimal
+	RowLimit     int32
+}
+
+func (q *Queries) SearchTransactionsByAmount(ctx context.Context, arg SearchTransactionsByAmountParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, searchTransactionsByAmount,
+		arg.TenantID,
+		arg.UserID,
+		arg.WalletID,
+		arg.ProviderID,
+		arg.Reference,
+		arg.Types,
+		arg.Statuses,
+		arg.Currencies,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Metadata,
+		arg.MetadataKeys,
+		arg.CursorID,
+		arg.SortAsc,
+		arg.CursorAmount,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WalletID,
+			&i.ProviderID,
+			&i.CurrencyCode,
+			&i.Reference,
+			&i.Type,
+			&i.Status,
+			&i.Amount,
+			&i.Fee,
+			&i.Metadata,
+			&i.ErrorReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransactionsByCreatedAt = `-- name: SearchTransactionsByCreatedAt :many
+SELECT id, tenant_id, wallet_id, provider_id, currency_code, reference, type, status, amount, fee, metadata, error_reason, created_at, updated_at FROM transactions
+WHERE ($1::uuid IS NULL OR tenant_id = $1)
+  AND ($2::uuid IS NULL
+    OR wallet_id IN (SELECT w.id FROM wallets w WHERE w.user_id = $2))
+  AND ($3::uuid IS NULL OR wallet_id = $3)
+  AND ($4::uuid IS NULL OR provider_id = $4)
+  AND ($5::text IS NULL OR reference = $5)
+  AND (cardinality($6::text[]) = 0 OR type = ANY($6::text[]))
+  AND (cardinality($7::text[]) = 0 OR status = ANY($7::text[]))
+  AND (cardinality($8::text[]) = 0 OR currency_code = ANY($8::text[]))
+  AND ($9::numeric IS NULL OR amount >= $9)
+  AND ($10::numeric IS NULL OR amount <= $10)
+  AND ($11::timestamptz IS NULL OR created_at >= $11)
+  AND ($12::timestamptz IS NULL OR created_at < $12)
+  AND ($13::jsonb IS NULL OR metadata @> $13)
+  AND (cardinality($14::text[]) = 0 OR metadata ?& $14::text[])
+  AND ($15::uuid IS NULL
+    OR ($16::boolean
+      AND (created_at, id) > ($17::timestamptz, $15))
+    OR (NOT $16::boolean
+      AND (created_at, id) < ($17::timestamptz, $15)))
+ORDER BY
+  CASE WHEN $16::boolean THEN created_at END ASC,
+  CASE WHEN NOT $16::boolean THEN created_at END DESC,
+  CASE WHEN $16::boolean THEN id END ASC,
+  CASE WHEN NOT $16::boolean THEN id END DESC
+LIMIT $18
+`
+
+type SearchTransactionsByCreatedAtParams struct {
+	TenantID        pgtype.UUID
+	UserID          pgtype.UUID
+	WalletID        pgtype.UUID
+	ProviderID      pgtype.UUID
+	Reference       pgtype.Text
+	Types           []string
+	Statuses        []string
+	Currencies      []string
+	MinAmount       *decimal.Decimal
+	MaxAmount       *decimal.Decimal
+	CreatedFrom     pgtype.Timestamptz
+	CreatedTo       pgtype.Timestamptz
+	Metadata        []byte
+	MetadataKeys    []string
+	CursorID        pgtype.UUID
+	SortAsc         bool
+	CursorCreatedAt pgtype.Timestamptz
+	RowLimit        int32
+}
+
+func (q *Queries) SearchTransactionsByCreatedAt(ctx context.Context, arg SearchTransactionsByCreatedAtParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, searchTransactionsByCreatedAt,
+		arg.TenantID,
+		arg.UserID,
+		arg.WalletID,
+		arg.ProviderID,
+		arg.Reference,
+		arg.Types,
+		arg.Statuses,
+		arg.Currencies,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Metadata,
+		arg.MetadataKeys,
+		arg.CursorID,
+		arg.SortAsc,
+		arg.CursorCreatedAt,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WalletID,
+			&i.ProviderID,
+			&i.CurrencyCode,
+			&i.Reference,
+			&i.Type,
+			&i.Status,
+			&i.Amount,
+			&i.Fee,
+			&i.Metadata,
+			&i.ErrorReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const transitionTransactionStatus = `-- name: TransitionTransactionStatus :execrows
+UPDATE transactions
+SET status = $1, amount = $2, error_reason = $3, updated_at = now()
+WHERE id = $4 AND status = ANY($5::text[])
+`
+
+type TransitionTransactionStatusParams struct {
+	Status       string
+	Amount       decimal.Decimal
+	ErrorReason  pgtype.Text
+	ID           pgtype.UUID
+	FromStatuses []string
+}
+
+func (q *Queries) TransitionTransactionStatus(ctx context.Context, arg TransitionTransactionStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, transitionTransactionStatus,
+		arg.Status,
+		arg.Amount,
+		arg.ErrorReason,
+		arg.ID,
+		arg.FromStatuses,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateTransactionStatusAndAmount = `-- name: UpdateTransactionStatusAndAmount :exec
 UPDATE transactions
 SET status = $1, amount = $2, updated_at = now()
@@ -11,10 +11,40 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countTenantUsers = `-- name: CountTenantUsers :one
+SELECT count(*) FROM users
+WHERE tenant_id = $1
+  AND ($2::text IS NULL
+    OR email ILIKE '%' || $2 || '%'
+    OR phone ILIKE '%' || $2 || '%')
+  AND ($3::text IS NULL OR role = $3)
+  AND ($4::boolean IS NULL OR is_active = $4)
+`
+
+type CountTenantUsersParams struct {
+	TenantID pgtype.UUID
+	Search   pgtype.Text
+	Role     pgtype.Text
+	IsActive pgtype.Bool
+}
+
+func (q *Queries) CountTenantUsers(ctx context.Context, arg CountTenantUsersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countTenantUsers,
+		arg.TenantID,
+		arg.Search,
+		arg.Role,
+		arg.IsActive,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, tenant_id, email, phone, password_hash, is_active, role, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())
-RETURNING id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at
+INSERT INTO users (id, tenant_id, email, phone, password_hash, is_active, role, first_name, last_name,
+  created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now())
+RETURNING id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username
 `
 
 type CreateUserParams struct {
@@ -25,6 +55,8 @@ type CreateUserParams struct {
 	PasswordHash string
 	IsActive     pgtype.Bool
 	Role         pgtype.Text
+	FirstName    pgtype.Text
+	LastName     pgtype.Text
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -36,6 +68,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.PasswordHash,
 		arg.IsActive,
 		arg.Role,
+		arg.FirstName,
+		arg.LastName,
 	)
 	var i User
 	err := row.Scan(
@@ -48,6 +82,10 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
 	)
 	return i, err
 }
@@ -73,22 +111,88 @@ func (q *Queries) DeleteUser(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const getTenantUserByID = `-- name: GetTenantUserByID :one
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username FROM users
+WHERE id = $1 AND tenant_id = $2
+`
+
+type GetTenantUserByIDParams struct {
+	ID       pgtype.UUID
+	TenantID pgtype.UUID
+}
+
+func (q *Queries) GetTenantUserByID(ctx context.Context, arg GetTenantUserByIDParams) (User, error) {
+	row := q.db.QueryRow(ctx, getTenantUserByID, arg.ID, arg.TenantID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Email,
+		&i.Phone,
+		&i.PasswordHash,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
+	)
+	return i, err
+}
+
+const getTenantUserByUsername = `-- name: GetTenantUserByUsername :one
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username FROM users
+WHERE tenant_id = $1 AND lower(username) = lower($2::text)
+`
+
+type GetTenantUserByUsernameParams struct {
+	TenantID pgtype.UUID
+	Username string
+}
+
+func (q *Queries) GetTenantUserByUsername(ctx context.Context, arg GetTenantUserByUsernameParams) (User, error) {
+	row := q.db.QueryRow(ctx, getTenantUserByUsername, arg.TenantID, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Email,
+		&i.Phone,
+		&i.PasswordHash,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, role FROM users
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username, role FROM users
 WHERE email = $1
 `
 
 type GetUserByEmailRow struct {
-	ID           pgtype.UUID
-	TenantID     pgtype.UUID
-	Email        string
-	Phone        pgtype.Text
-	PasswordHash string
-	Role         pgtype.Text
-	IsActive     pgtype.Bool
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
-	Role_2       pgtype.Text
+	ID              pgtype.UUID
+	TenantID        pgtype.UUID
+	Email           string
+	Phone           pgtype.Text
+	PasswordHash    string
+	Role            pgtype.Text
+	IsActive        pgtype.Bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+	FirstName       pgtype.Text
+	LastName        pgtype.Text
+	Username        pgtype.Text
+	Role_2          pgtype.Text
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
@@ -104,13 +208,17 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
 		&i.Role_2,
 	)
 	return i, err
 }
 
 const getUserByEmailAndTenantID = `-- name: GetUserByEmailAndTenantID :one
-SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, role FROM users
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username, role FROM users
 WHERE email = $1 AND tenant_id = $2
 `
 
@@ -120,16 +228,20 @@ type GetUserByEmailAndTenantIDParams struct {
 }
 
 type GetUserByEmailAndTenantIDRow struct {
-	ID           pgtype.UUID
-	TenantID     pgtype.UUID
-	Email        string
-	Phone        pgtype.Text
-	PasswordHash string
-	Role         pgtype.Text
-	IsActive     pgtype.Bool
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
-	Role_2       pgtype.Text
+	ID              pgtype.UUID
+	TenantID        pgtype.UUID
+	Email           string
+	Phone           pgtype.Text
+	PasswordHash    string
+	Role            pgtype.Text
+	IsActive        pgtype.Bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+	FirstName       pgtype.Text
+	LastName        pgtype.Text
+	Username        pgtype.Text
+	Role_2          pgtype.Text
 }
 
 func (q *Queries) GetUserByEmailAndTenantID(ctx context.Context, arg GetUserByEmailAndTenantIDParams) (GetUserByEmailAndTenantIDRow, error) {
@@ -145,27 +257,35 @@ func (q *Queries) GetUserByEmailAndTenantID(ctx context.Context, arg GetUserByEm
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
 		&i.Role_2,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, role FROM users
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username, role FROM users
 WHERE id = $1
 `
 
 type GetUserByIDRow struct {
-	ID           pgtype.UUID
-	TenantID     pgtype.UUID
-	Email        string
-	Phone        pgtype.Text
-	PasswordHash string
-	Role         pgtype.Text
-	IsActive     pgtype.Bool
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
-	Role_2       pgtype.Text
+	ID              pgtype.UUID
+	TenantID        pgtype.UUID
+	Email           string
+	Phone           pgtype.Text
+	PasswordHash    string
+	Role            pgtype.Text
+	IsActive        pgtype.Bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+	FirstName       pgtype.Text
+	LastName        pgtype.Text
+	Username        pgtype.Text
+	Role_2          pgtype.Text
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (GetUserByIDRow, error) {
@@ -181,28 +301,82 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (GetUserByIDR
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
 		&i.Role_2,
 	)
 	return i, err
 }
 
+const listTenantUsersByPhone = `-- name: ListTenantUsersByPhone :many
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username FROM users
+WHERE tenant_id = $1 AND phone = $2
+ORDER BY created_at
+LIMIT 2
+`
+
+type ListTenantUsersByPhoneParams struct {
+	TenantID pgtype.UUID
+	Phone    pgtype.Text
+}
+
+func (q *Queries) ListTenantUsersByPhone(ctx context.Context, arg ListTenantUsersByPhoneParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listTenantUsersByPhone, arg.TenantID, arg.Phone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.Email,
+			&i.Phone,
+			&i.PasswordHash,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EmailVerifiedAt,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsersByTenant = `-- name: ListUsersByTenant :many
-SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, role FROM users
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username, role FROM users
 WHERE tenant_id = $1
 ORDER BY created_at DESC
 `
 
 type ListUsersByTenantRow struct {
-	ID           pgtype.UUID
-	TenantID     pgtype.UUID
-	Email        string
-	Phone        pgtype.Text
-	PasswordHash string
-	Role         pgtype.Text
-	IsActive     pgtype.Bool
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
-	Role_2       pgtype.Text
+	ID              pgtype.UUID
+	TenantID        pgtype.UUID
+	Email           string
+	Phone           pgtype.Text
+	PasswordHash    string
+	Role            pgtype.Text
+	IsActive        pgtype.Bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+	FirstName       pgtype.Text
+	LastName        pgtype.Text
+	Username        pgtype.Text
+	Role_2          pgtype.Text
 }
 
 func (q *Queries) ListUsersByTenant(ctx context.Context, tenantID pgtype.UUID) ([]ListUsersByTenantRow, error) {
@@ -224,6 +398,10 @@ func (q *Queries) ListUsersByTenant(ctx context.Context, tenantID pgtype.UUID) (
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EmailVerifiedAt,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
 			&i.Role_2,
 		); err != nil {
 			return nil, err
@@ -235,3 +413,193 @@ func (q *Queries) ListUsersByTenant(ctx context.Context, tenantID pgtype.UUID) (
 	}
 	return items, nil
 }
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = now(), updated_at = now()
+WHERE id = $1 AND email_verified_at IS NULL
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markUserEmailVerified, id)
+	return err
+}
+
+const searchTenantUsers = `-- name: SearchTenantUsers :many
+SELECT id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username FROM users
+WHERE tenant_id = $1
+  AND ($2::text IS NULL
+    OR email ILIKE '%' || $2 || '%'
+    OR phone ILIKE '%' || $2 || '%')
+  AND ($3::text IS NULL OR role = $3)
+  AND ($4::boolean IS NULL OR is_active = $4)
+ORDER BY created_at DESC
+LIMIT $5 OFFSET $6
+`
+
+type SearchTenantUsersParams struct {
+	TenantID  pgtype.UUID
+	Search    pgtype.Text
+	Role      pgtype.Text
+	IsActive  pgtype.Bool
+	RowLimit  int32
+	RowOffset int32
+}
+
+func (q *Queries) SearchTenantUsers(ctx context.Context, arg SearchTenantUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, searchTenantUsers,
+		arg.TenantID,
+		arg.Search,
+		arg.Role,
+		arg.IsActive,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.Email,
+			&i.Phone,
+			&i.PasswordHash,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EmailVerifiedAt,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTenantUserActive = `-- name: SetTenantUserActive :one
+UPDATE users
+SET is_active = $3, updated_at = now()
+WHERE id = $1 AND tenant_id = $2
+RETURNING id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username
+`
+
+type SetTenantUserActiveParams struct {
+	ID       pgtype.UUID
+	TenantID pgtype.UUID
+	IsActive pgtype.Bool
+}
+
+func (q *Queries) SetTenantUserActive(ctx context.Context, arg SetTenantUserActiveParams) (User, error) {
+	row := q.db.QueryRow(ctx, setTenantUserActive, arg.ID, arg.TenantID, arg.IsActive)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Email,
+		&i.Phone,
+		&i.PasswordHash,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
+	)
+	return i, err
+}
+
+const setUsername = `-- name: SetUsername :one
+UPDATE users
+SET username = $1, updated_at = now()
+WHERE id = $2
+RETURNING id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username
+`
+
+type SetUsernameParams struct {
+	Username pgtype.Text
+	ID       pgtype.UUID
+}
+
+func (q *Queries) SetUsername(ctx context.Context, arg SetUsernameParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUsername, arg.Username, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Email,
+		&i.Phone,
+		&i.PasswordHash,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
+	)
+	return i, err
+}
+
+const updateTenantUserRole = `-- name: UpdateTenantUserRole :one
+UPDATE users
+SET role = $3, updated_at = now()
+WHERE id = $1 AND tenant_id = $2
+RETURNING id, tenant_id, email, phone, password_hash, role, is_active, created_at, updated_at, email_verified_at, first_name, last_name, username
+`
+
+type UpdateTenantUserRoleParams struct {
+	ID       pgtype.UUID
+	TenantID pgtype.UUID
+	Role     pgtype.Text
+}
+
+func (q *Queries) UpdateTenantUserRole(ctx context.Context, arg UpdateTenantUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateTenantUserRole, arg.ID, arg.TenantID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Email,
+		&i.Phone,
+		&i.PasswordHash,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = now()
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           pgtype.UUID
+	PasswordHash string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
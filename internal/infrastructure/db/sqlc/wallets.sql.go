@@ -252,6 +252,55 @@ func (q *Queries) GetWalletByUserAndCurrency(ctx context.Context, arg GetWalletB
 	return i, err
 }
 
+const getWalletCurrency = `-- name: GetWalletCurrency :one
+SELECT wt.currency
+FROM wallets w
+JOIN wallet_types wt ON w.wallet_type_id = wt.id
+WHERE w.id = $1
+`
+
+func (q *Queries) GetWalletCurrency(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getWalletCurrency, id)
+	var currency string
+	err := row.Scan(&currency)
+	return currency, err
+}
+
+const getWalletForUpdate = `-- name: GetWalletForUpdate :one
+SELECT w.id, w.user_id, w.wallet_type_id, w.balance, w.status, w.created_at, w.updated_at, u.tenant_id
+FROM wallets w
+JOIN users u ON u.id = w.user_id
+WHERE w.id = $1
+FOR UPDATE OF w
+`
+
+type GetWalletForUpdateRow struct {
+	ID           pgtype.UUID
+	UserID       pgtype.UUID
+	WalletTypeID pgtype.UUID
+	Balance      decimal.Decimal
+	Status       string
+	CreatedAt    pgtype.Timestamptz
+	UpdatedAt    pgtype.Timestamptz
+	TenantID     pgtype.UUID
+}
+
+func (q *Queries) GetWalletForUpdate(ctx context.Context, id pgtype.UUID) (GetWalletForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getWalletForUpdate, id)
+	var i GetWalletForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WalletTypeID,
+		&i.Balance,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
 const getWalletTypeIDByCurrency = `-- name: GetWalletTypeIDByCurrency :one
 SELECT id
 FROM wallet_types
@@ -266,6 +315,40 @@ func (q *Queries) GetWalletTypeIDByCurrency(ctx context.Context, currency string
 	return id, err
 }
 
+const getWalletWithTenant = `-- name: GetWalletWithTenant :one
+SELECT w.id, w.user_id, w.wallet_type_id, w.balance, w.status, w.created_at, w.updated_at, u.tenant_id
+FROM wallets w
+JOIN users u ON u.id = w.user_id
+WHERE w.id = $1
+`
+
+type GetWalletWithTenantRow struct {
+	ID           pgtype.UUID
+	UserID       pgtype.UUID
+	WalletTypeID pgtype.UUID
+	Balance      decimal.Decimal
+	Status       string
+	CreatedAt    pgtype.Timestamptz
+	UpdatedAt    pgtype.Timestamptz
+	TenantID     pgtype.UUID
+}
+
+func (q *Queries) GetWalletWithTenant(ctx context.Context, id pgtype.UUID) (GetWalletWithTenantRow, error) {
+	row := q.db.QueryRow(ctx, getWalletWithTenant, id)
+	var i GetWalletWithTenantRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WalletTypeID,
+		&i.Balance,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
 const getWithdrawalByID = `-- name: GetWithdrawalByID :one
 SELECT id, user_id, transaction_id, external_txid, amount, status, created_at, updated_at
 FROM withdrawals
@@ -505,6 +588,20 @@ func (q *Queries) UpdateWalletBalance(ctx context.Context, arg UpdateWalletBalan
 	return err
 }
 
+const updateWalletStatus = `-- name: UpdateWalletStatus :exec
+UPDATE wallets SET status = $1, updated_at = now() WHERE id = $2
+`
+
+type UpdateWalletStatusParams struct {
+	Status string
+	ID     pgtype.UUID
+}
+
+func (q *Queries) UpdateWalletStatus(ctx context.Context, arg UpdateWalletStatusParams) error {
+	_, err := q.db.Exec(ctx, updateWalletStatus, arg.Status, arg.ID)
+	return err
+}
+
 const updateWalletType = `-- name: UpdateWalletType :exec
 UPDATE wallets SET wallet_type_id = $1, updated_at = now() WHERE id = $2
 `
@@ -4,4 +4,5 @@ package kafka
 const (
 	WalletDepositSuccessTopic = "wallet.deposit.success"
 	PaystackWalletEventTopic  = "wallet.paystack.events"
+	WalletReversalTopic       = "wallet.reversal"
 )
@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FileMailer writes each message to a .eml file in dir instead of sending it.
+// Intended for local development, where the links in the mail can be opened by hand.
+type FileMailer struct {
+	dir    string
+	logger *zap.Logger
+}
+
+func NewFileMailer(dir string, logger *zap.Logger) *FileMailer {
+	if dir == "" {
+		dir = "tmp/mail"
+	}
+	return &FileMailer{dir: dir, logger: logger}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("create mail outbox: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString()[:8])
+	path := filepath.Join(m.dir, name)
+
+	content := strings.Join([]string{
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + msg.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"",
+		msg.Text,
+	}, "\n")
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("write mail: %w", err)
+	}
+
+	// Attachments are written next to the message, sharing its name as a prefix
+	for _, a := range msg.Attachments {
+		attachmentPath := strings.TrimSuffix(path, ".eml") + "_" + filepath.Base(a.Name)
+		if err := os.WriteFile(attachmentPath, a.Data, 0o600); err != nil {
+			return fmt.Errorf("write mail attachment: %w", err)
+		}
+	}
+
+	m.logger.Info("email written to outbox", zap.String("subject", msg.Subject), zap.String("path", path))
+	return nil
+}
@@ -0,0 +1,44 @@
+package notifications
+
+import (
+	"codematic/internal/config"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Message is a single outgoing email
+type Message struct {
+	To          []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer delivers transactional email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer picks an implementation from MAIL_DRIVER: "smtp" sends real
+// email, anything else writes messages to MAIL_OUTBOX_DIR for local development.
+func NewMailer(cfg *config.Config, logger *zap.Logger) Mailer {
+	if cfg.MailDriver == "smtp" {
+		return NewSMTPMailer(SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		}, logger)
+	}
+	return NewFileMailer(cfg.MailOutboxDir, logger)
+}
@@ -0,0 +1,186 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPMailer struct {
+	cfg    SMTPConfig
+	logger *zap.Logger
+}
+
+func NewSMTPMailer(cfg SMTPConfig, logger *zap.Logger) *SMTPMailer {
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	return &SMTPMailer{cfg: cfg, logger: logger}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("mail has no recipients")
+	}
+
+	body, err := buildMIME(m.cfg.From, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(addr, auth, m.cfg.From, msg.To, body)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			m.logger.Error("failed to send email", zap.String("subject", msg.Subject), zap.Error(err))
+			return err
+		}
+		m.logger.Info("email sent", zap.String("subject", msg.Subject), zap.Int("recipients", len(msg.To)))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildMIME renders a multipart/alternative message with text and optional
+// HTML parts, wrapped in multipart/mixed when it has attachments
+func buildMIME(from string, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	var contentType string
+
+	if len(msg.Attachments) > 0 {
+		boundary, err := buildMixed(&buf, msg)
+		if err != nil {
+			return nil, err
+		}
+		contentType = "multipart/mixed; boundary=" + boundary
+	} else {
+		writer := multipart.NewWriter(&buf)
+		if err := writeAlternative(writer, msg); err != nil {
+			return nil, err
+		}
+		contentType = "multipart/alternative; boundary=" + writer.Boundary()
+	}
+
+	headers := []string{
+		"From: " + from,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + msg.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: " + contentType,
+	}
+	header := strings.Join(headers, "\r\n") + "\r\n\r\n"
+
+	return append([]byte(header), buf.Bytes()...), nil
+}
+
+// buildMixed writes the body parts followed by the attachments to buf and
+// returns the multipart boundary
+func buildMixed(buf *bytes.Buffer, msg Message) (string, error) {
+	mixed := multipart.NewWriter(buf)
+
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+	if err := writeAlternative(alternative, msg); err != nil {
+		return "", err
+	}
+
+	bodyPart, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()},
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := bodyPart.Write(body.Bytes()); err != nil {
+		return "", err
+	}
+
+	for _, a := range msg.Attachments {
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return "", err
+		}
+		if err := writeBase64(part, a.Data); err != nil {
+			return "", err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return "", err
+	}
+	return mixed.Boundary(), nil
+}
+
+// writeBase64 encodes data in 76 character lines as MIME requires
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := io.WriteString(w, encoded)
+	return err
+}
+
+func writeAlternative(writer *multipart.Writer, msg Message) error {
+	textPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=UTF-8"},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := textPart.Write([]byte(msg.Text)); err != nil {
+		return err
+	}
+
+	if msg.HTML != "" {
+		htmlPart, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {"text/html; charset=UTF-8"},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := htmlPart.Write([]byte(msg.HTML)); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
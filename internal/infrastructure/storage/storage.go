@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"codematic/internal/config"
+	"context"
+	"errors"
+	"io"
+
+	"go.uber.org/zap"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// BlobStore keeps generated files such as exports and statements. Keys are
+// slash separated paths chosen by the caller.
+type BlobStore interface {
+	// Put stores everything read from r under key, replacing any existing
+	// object, and returns the number of bytes written
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open returns the object under key, or ErrObjectNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore picks an implementation from STORAGE_DRIVER. Only "local",
+// which keeps objects under STORAGE_LOCAL_DIR, is built in; other drivers
+// plug in by implementing BlobStore.
+func NewBlobStore(cfg *config.Config, logger *zap.Logger) BlobStore {
+	if cfg.StorageDriver != "" && cfg.StorageDriver != "local" {
+		logger.Warn("unknown storage driver, using local storage", zap.String("driver", cfg.StorageDriver))
+	}
+	return NewLocalStore(cfg.StorageLocalDir)
+}
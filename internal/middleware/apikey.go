@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"codematic/internal/domain/apikeys"
+	"codematic/internal/infrastructure/cache"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthMiddleware accepts either a JWT or a tenant API key. API keys are read from
+// the X-API-Key header or an Authorization: Bearer cm_sk_... header. Both paths
+// populate the same c.Locals as JWTMiddleware; API key requests additionally set
+// "api_key_id" and "scopes" for RequireScope.
+func AuthMiddleware(jwtManager *utils.JWTManager, cacheManager cache.CacheManager,
+	apiKeyService apikeys.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rawKey := extractAPIKey(c)
+		if rawKey == "" {
+			claims, err := authenticateJWT(c, jwtManager, cacheManager)
+			if err != nil {
+				return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+			}
+			setClaimsLocals(c, claims)
+			if err := checkImpersonation(c, claims); err != nil {
+				return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
+			}
+			return c.Next()
+		}
+
+		principal, err := apiKeyService.Authenticate(c.Context(), rawKey)
+		if err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+		}
+
+		if len(principal.Key.AllowedIPs) > 0 &&
+			!ipAllowed(c.IP(), parseAllowList(principal.Key.AllowedIPs)) {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrAPIKeyIPNotAllowed.Error())
+		}
+
+		setClaimsLocals(c, principal.Claims)
+		c.Locals("api_key_id", principal.Key.ID)
+		c.Locals("scopes", principal.Key.Scopes)
+		return c.Next()
+	}
+}
+
+// RequireScope enforces that API key requests carry scope. JWT requests pass
+// through unchanged and remain governed by role checks.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if keyID, _ := c.Locals("api_key_id").(string); keyID == "" {
+			return c.Next()
+		}
+
+		scopes, _ := c.Locals("scopes").([]string)
+		for _, s := range scopes {
+			if s == scope {
+				return c.Next()
+			}
+		}
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientScope.Error())
+	}
+}
+
+func extractAPIKey(c *fiber.Ctx) string {
+	if key := c.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	authHeader := c.Get("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if apikeys.IsAPIKey(token) {
+			return token
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"codematic/internal/domain/audit"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderImpersonatedBy is set on every response to a request made with an
+// impersonation token, carrying the impersonating admin's user ID.
+const HeaderImpersonatedBy = "X-Impersonated-By"
+
+// impersonationReadRoutes are the non-GET routes a read-only impersonation
+// token may still call: reads exposed as POST, and the routes that end the
+// impersonation.
+var impersonationReadRoutes = map[string]bool{
+	fiber.MethodPost + " /api/wallet/get-balance":      true,
+	fiber.MethodPost + " /api/wallet/get-transactions": true,
+	fiber.MethodPost + " /api/auth/logout":             true,
+	fiber.MethodDelete + " /api/auth/impersonation":    true,
+}
+
+// checkImpersonation marks impersonated requests on the response and rejects
+// writes from read-only impersonation tokens.
+func checkImpersonation(c *fiber.Ctx, claims *model.Claims) error {
+	if !claims.IsImpersonated() {
+		return nil
+	}
+	c.Set(HeaderImpersonatedBy, claims.ImpersonatorID)
+
+	if claims.ImpersonationWrite {
+		return nil
+	}
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return nil
+	}
+	if impersonationReadRoutes[c.Method()+" "+c.Path()] {
+		return nil
+	}
+	return model.ErrImpersonationReadOnly
+}
+
+// BlockImpersonatedMoneyMovement rejects impersonation tokens that were not
+// explicitly allowed to move money. Use on deposit, withdrawal, transfer,
+// deposit review and reversal routes after JWTMiddleware or AuthMiddleware.
+func BlockImpersonatedMoneyMovement() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*model.Claims)
+		if ok && claims != nil && claims.IsImpersonated() && !claims.ImpersonationMoney {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrImpersonationNoMoney.Error())
+		}
+		return c.Next()
+	}
+}
+
+// ImpersonationAudit records every request made with an impersonation token,
+// including rejected ones, against the impersonating admin. Register it on the
+// app before any handler so it wraps their authentication middleware.
+func ImpersonationAudit(auditService audit.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		claims, ok := c.Locals("claims").(*model.Claims)
+		if !ok || claims == nil || !claims.IsImpersonated() {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		auditService.Record(ctx, audit.Entry{
+			TenantID:   claims.TenantID,
+			ActorID:    claims.ImpersonatorID,
+			Action:     audit.ActionImpersonationRequest,
+			TargetType: audit.TargetUser,
+			TargetID:   claims.UserID,
+			Metadata: map[string]any{
+				"method":   c.Method(),
+				"path":     c.Path(),
+				"status":   status,
+				"token_id": claims.ID,
+			},
+			IPAddress: c.IP(),
+			UserAgent: c.Get(fiber.HeaderUserAgent),
+		})
+
+		return err
+	}
+}
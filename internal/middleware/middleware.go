@@ -6,49 +6,68 @@ import (
 	"codematic/internal/shared/utils"
 	"context"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// sessionTouchInterval is how stale a session's LastSeen may get before it is refreshed
+const sessionTouchInterval = time.Minute
+
 // JWTMiddleware enforces authentication for protected routes only (not for login endpoints).
 // Use this middleware on routes that require a valid JWT (i.e., after login).
 func JWTMiddleware(jwtManager *utils.JWTManager,
 	cacheManager cache.CacheManager) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
-		tokenStr := jwtManager.ExtractTokenFromHeader(authHeader)
-		if tokenStr == "" {
-			return utils.SendErrorResponse(
-				c, fiber.StatusUnauthorized,
-				model.ErrMissingOrInvalidAuthorizationHeader.Error(),
-			)
+		claims, err := authenticateJWT(c, jwtManager, cacheManager)
+		if err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
 		}
 
-		claims, err := jwtManager.ParseJWT(tokenStr)
-		if err != nil {
-			return utils.SendErrorResponse(
-				c, fiber.StatusUnauthorized,
-				model.ErrInvalidOrExpiredToken.Error(),
-			)
+		setClaimsLocals(c, claims)
+		if err := checkImpersonation(c, claims); err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, err.Error())
 		}
+		return c.Next()
+	}
+}
 
-		if cacheManager != nil {
-			ctx := context.Background()
-			session, err := cacheManager.GetSession(ctx, claims.ID)
-			if err != nil || session == nil {
-				return utils.SendErrorResponse(
-					c, fiber.StatusUnauthorized,
-					model.ErrTokenRevoked.Error(),
-				)
-			}
+// authenticateJWT validates the bearer token on the request and checks that its
+// session is still active.
+func authenticateJWT(c *fiber.Ctx, jwtManager *utils.JWTManager,
+	cacheManager cache.CacheManager) (*model.Claims, error) {
+	authHeader := c.Get("Authorization")
+	tokenStr := jwtManager.ExtractTokenFromHeader(authHeader)
+	if tokenStr == "" {
+		return nil, model.ErrMissingOrInvalidAuthorizationHeader
+	}
+
+	claims, err := jwtManager.ParseJWT(tokenStr)
+	if err != nil {
+		return nil, model.ErrInvalidOrExpiredToken
+	}
+
+	if cacheManager != nil {
+		ctx := context.Background()
+		session, err := cacheManager.GetSession(ctx, claims.ID)
+		if err != nil || session == nil {
+			return nil, model.ErrTokenRevoked
 		}
 
-		c.Locals("user_id", claims.UserID)
-		c.Locals("token_id", claims.ID)
-		c.Locals("claims", claims)
-		c.Locals("role", claims.Role)
-		return c.Next()
+		// Throttled so busy clients don't rewrite the session on every request
+		if now := time.Now(); now.Sub(session.LastSeen) > sessionTouchInterval {
+			_ = cacheManager.TouchSession(ctx, claims.ID, now)
+		}
 	}
+
+	return claims, nil
+}
+
+func setClaimsLocals(c *fiber.Ctx, claims *model.Claims) {
+	c.Locals("user_id", claims.UserID)
+	c.Locals("token_id", claims.ID)
+	c.Locals("claims", claims)
+	c.Locals("role", claims.Role)
 }
 
 // TenantMiddleware extracts tenant from X-Tenant-ID header and sets it in context.
@@ -91,3 +110,38 @@ func RoleMiddleware(allowedRoles ...string) fiber.Handler {
 		return utils.SendErrorResponse(c, fiber.StatusForbidden, "Forbidden: insufficient role")
 	}
 }
+
+// RequirePermission enforces that the caller holds perm. JWT callers are
+// checked against the permissions in their claims; API key callers against the
+// key's scopes, which use the same names. Use after JWTMiddleware or AuthMiddleware.
+func RequirePermission(perm string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if keyID, _ := c.Locals("api_key_id").(string); keyID != "" {
+			scopes, _ := c.Locals("scopes").([]string)
+			if model.HasPermission(scopes, perm) {
+				return c.Next()
+			}
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientScope.Error())
+		}
+
+		claims, ok := c.Locals("claims").(*model.Claims)
+		if !ok || claims == nil {
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized: missing claims")
+		}
+		if !claims.HasPermission(perm) {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, model.ErrInsufficientPermission.Error())
+		}
+		return c.Next()
+	}
+}
+
+// RequireTenant rejects callers whose token is not bound to a tenant, such as
+// platform admins, on routes that act within the caller's tenant.
+func RequireTenant() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if utils.ExtractTenantFromJWT(c) == "" {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, "Forbidden: tenant context required")
+		}
+		return c.Next()
+	}
+}
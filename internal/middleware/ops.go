@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/infrastructure/cache"
+	"codematic/internal/shared/model"
+	"codematic/internal/shared/utils"
+	"crypto/subtle"
+	"encoding/base64"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OpsMiddleware guards operational endpoints (metrics, dashboard, swagger and the ops API).
+// When OPS_ALLOWED_IPS is set the caller IP must match one of its IPs/CIDRs. The request
+// must then carry either the configured ops basic-auth credentials or a PLATFORM_ADMIN JWT.
+func OpsMiddleware(cfg *config.Config, jwtManager *utils.JWTManager,
+	cacheManager cache.CacheManager) fiber.Handler {
+	allowList := parseAllowList(cfg.OpsAllowedIPs)
+	basicAuthEnabled := cfg.OpsBasicAuthUser != "" && cfg.OpsBasicAuthPass != ""
+
+	return func(c *fiber.Ctx) error {
+		if len(allowList) > 0 && !ipAllowed(c.IP(), allowList) {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, "Forbidden: IP not allowed")
+		}
+
+		authHeader := c.Get("Authorization")
+
+		if basicAuthEnabled && strings.HasPrefix(authHeader, "Basic ") {
+			if !validBasicAuth(authHeader, cfg.OpsBasicAuthUser, cfg.OpsBasicAuthPass) {
+				c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="ops"`)
+				return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized")
+			}
+			return c.Next()
+		}
+
+		claims, err := authenticateJWT(c, jwtManager, cacheManager)
+		if err != nil {
+			if basicAuthEnabled {
+				c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="ops"`)
+			}
+			return utils.SendErrorResponse(c, fiber.StatusUnauthorized, err.Error())
+		}
+
+		if claims.Role != model.RolePlatformAdmin.String() {
+			return utils.SendErrorResponse(c, fiber.StatusForbidden, "Forbidden: insufficient role")
+		}
+
+		setClaimsLocals(c, claims)
+		return c.Next()
+	}
+}
+
+func parseAllowList(entries []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			nets = append(nets, ipNet)
+		}
+	}
+	return nets
+}
+
+func ipAllowed(rawIP string, allowList []*net.IPNet) bool {
+	ip := net.ParseIP(rawIP)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range allowList {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func validBasicAuth(authHeader, user, pass string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authHeader, "Basic "))
+	if err != nil {
+		return false
+	}
+
+	gotUser, gotPass, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return false
+	}
+
+	userOK := subtle.ConstantTimeCompare([]byte(gotUser), []byte(user)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(gotPass), []byte(pass)) == 1
+	return userOK && passOK
+}
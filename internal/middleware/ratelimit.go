@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"codematic/internal/config"
+	"codematic/internal/infrastructure/cache"
+	"codematic/internal/shared/utils"
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitRule limits requests sharing the same key, e.g. the same client IP
+// or the same email in a login body. A rule whose key is empty is skipped.
+type RateLimitRule struct {
+	Name  string
+	Limit config.RateLimit
+	Key   func(c *fiber.Ctx) string
+}
+
+// RateLimitMiddleware applies every rule with a Redis sliding window. It sets
+// the RateLimit-Limit/Remaining/Reset headers for the most constrained rule and
+// answers 429 with Retry-After once any rule is exhausted. Redis errors fail open.
+func RateLimitMiddleware(store cache.RateLimitStore, rules ...RateLimitRule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var tightest *cache.RateLimitResult
+
+		for _, rule := range rules {
+			if !rule.Limit.Enabled() {
+				continue
+			}
+			value := rule.Key(c)
+			if value == "" {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			result, err := store.AllowRequest(ctx, rule.Name+":"+value, rule.Limit.Limit, rule.Limit.Window)
+			cancel()
+			if err != nil {
+				continue
+			}
+
+			if !result.Allowed {
+				setRateLimitHeaders(c, result)
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+				return utils.SendErrorResponse(c, fiber.StatusTooManyRequests,
+					"Too many requests, please try again later")
+			}
+
+			if tightest == nil || result.Remaining < tightest.Remaining {
+				tightest = &result
+			}
+		}
+
+		if tightest != nil {
+			setRateLimitHeaders(c, *tightest)
+		}
+		return c.Next()
+	}
+}
+
+func setRateLimitHeaders(c *fiber.Ctx, result cache.RateLimitResult) {
+	c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// KeyByIP keys a rule on the client IP
+func KeyByIP(c *fiber.Ctx) string {
+	return c.IP()
+}
+
+// KeyByUser keys a rule on the authenticated user; use after JWTMiddleware
+func KeyByUser(c *fiber.Ctx) string {
+	return utils.ExtractUserIDFromJWT(c)
+}
+
+// KeyByBodyField keys a rule on a top-level string field of the JSON body,
+// case-insensitively (e.g. "email" or "tenant_id").
+func KeyByBodyField(field string) func(c *fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		var body map[string]interface{}
+		if err := json.Unmarshal(c.Body(), &body); err != nil {
+			return ""
+		}
+		value, _ := body[field].(string)
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+}
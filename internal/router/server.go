@@ -51,12 +51,40 @@ func InitRouterWithConfig(env *config.Config, cache *redis.Client, zapLogger *za
 		AllowOrigins:  origins,
 		AllowMethods:  "GET,POST,PUT,DELETE,OPTIONS",
 		AllowHeaders:  "Origin, Content-Type, Accept, Authorization",
-		ExposeHeaders: "Content-Length",
+		ExposeHeaders: "Content-Length, X-Impersonated-By",
 		MaxAge:        300,
 	}))
 
 	// Custom zap logger middleware
-	app.Use(func(c *fiber.Ctx) error {
+	app.Use(requestLogger(zapLogger))
+
+	return app
+}
+
+// InitOpsRouter creates a separate Fiber app for operational endpoints, served on
+// the internal OPS_PORT listener.
+func InitOpsRouter(zapLogger *zap.Logger) *fiber.App {
+	app := fiber.New(fiber.Config{
+		IdleTimeout:  5 * time.Second,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	})
+
+	app.Use(requestLogger(zapLogger))
+
+	return app
+}
+
+// MountOpsRoutes registers metrics, the monitor dashboard and Swagger UI behind the ops guard.
+func MountOpsRoutes(app *fiber.App, guard fiber.Handler) {
+	app.Get("/metrics", guard, adaptor.HTTPHandler(promhttp.Handler()))
+
+	app.Get("/dashboard", guard, monitor.New())
+	app.Get("/swagger/*", guard, swagger.HandlerDefault) // Swagger UI endpoint
+}
+
+func requestLogger(zapLogger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
 		start := time.Now()
 		err := c.Next()
 		stop := time.Now()
@@ -71,14 +99,7 @@ func InitRouterWithConfig(env *config.Config, cache *redis.Client, zapLogger *za
 		)
 
 		return err
-	})
-
-	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
-
-	app.Get("/dashboard", monitor.New())
-	app.Get("/swagger/*", swagger.HandlerDefault) // Swagger UI endpoint
-
-	return app
+	}
 }
 
 func InitHandlers(env *handler.Environment, handlers []handler.IHandler) error {
@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"time"
+
 	"github.com/go-co-op/gocron/v2"
 )
 
@@ -10,3 +12,12 @@ type Job interface {
 	Task() any                        // The function to run
 	Params() []any                    // Params to pass to the task
 }
+
+// JobState describes a registered job for monitoring
+type JobState struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	Tags    []string   `json:"tags"`
+	LastRun *time.Time `json:"last_run"`
+	NextRun *time.Time `json:"next_run"`
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"codematic/internal/domain/wallet"
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"go.uber.org/zap"
+)
+
+// DepositReconciliationJob settles deposits whose webhook never arrived by
+// asking the provider, and expires the ones customers abandoned. Settling is
+// conditional on the deposit still being open, so overlapping runs and late
+// webhooks never credit a wallet twice.
+type DepositReconciliationJob struct {
+	Service     wallet.Service
+	VerifyAfter time.Duration
+	ExpireAfter time.Duration
+	Logger      *zap.Logger
+}
+
+func (j DepositReconciliationJob) Name() string {
+	return "DepositReconciliationJob"
+}
+
+func (j DepositReconciliationJob) Definition() gocron.JobDefinition {
+	return gocron.DurationJob(5 * time.Minute)
+}
+
+func (j DepositReconciliationJob) Task() any {
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		report, err := j.Service.ReconcileDeposits(ctx, j.VerifyAfter, j.ExpireAfter)
+		if err != nil {
+			j.Logger.Error("Deposit reconciliation stopped early", zap.Error(err))
+		}
+		if report == nil || report.Checked == 0 {
+			return
+		}
+
+		j.Logger.Info("Deposit reconciliation report",
+			zap.Int("checked", report.Checked),
+			zap.Int("completed", report.Completed),
+			zap.Int("failed", report.Failed),
+			zap.Int("expired", report.Expired),
+			zap.Int("flagged", report.Flagged),
+			zap.Int("stillOpen", report.StillOpen),
+			zap.Int("errors", report.Errors),
+			zap.Strings("errorReferences", report.ErrorRefs),
+			zap.Duration("duration", report.Duration))
+	}
+}
+
+func (j DepositReconciliationJob) Params() []any {
+	return nil
+}
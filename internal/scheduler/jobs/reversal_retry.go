@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"codematic/internal/domain/wallet"
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"go.uber.org/zap"
+)
+
+// ReversalRetryJob settles reversals left pending, such as a deposit refund
+// the provider accepted but that could not be settled. Refunds already
+// recorded as accepted are not sent again.
+type ReversalRetryJob struct {
+	Service    wallet.Service
+	RetryAfter time.Duration
+	Logger     *zap.Logger
+}
+
+func (j ReversalRetryJob) Name() string {
+	return "ReversalRetryJob"
+}
+
+func (j ReversalRetryJob) Definition() gocron.JobDefinition {
+	return gocron.DurationJob(5 * time.Minute)
+}
+
+func (j ReversalRetryJob) Task() any {
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		report, err := j.Service.RetryPendingReversals(ctx, j.RetryAfter)
+		if err != nil {
+			j.Logger.Error("Reversal retry stopped early", zap.Error(err))
+		}
+		if report == nil || report.Checked == 0 {
+			return
+		}
+
+		j.Logger.Info("Reversal retry report",
+			zap.Int("checked", report.Checked),
+			zap.Int("completed", report.Completed),
+			zap.Int("failed", report.Failed),
+			zap.Int("errors", report.Errors),
+			zap.Strings("errorIDs", report.ErrorIDs),
+			zap.Duration("duration", report.Duration))
+	}
+}
+
+func (j ReversalRetryJob) Params() []any {
+	return nil
+}
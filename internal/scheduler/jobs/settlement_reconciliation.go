@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"codematic/internal/domain/reconciliation"
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"go.uber.org/zap"
+)
+
+// SettlementReconciliationJob pulls yesterday's transaction report from each
+// active provider and reconciles it, queueing any exceptions for finance.
+type SettlementReconciliationJob struct {
+	Service reconciliation.Service
+	Logger  *zap.Logger
+}
+
+func (j SettlementReconciliationJob) Name() string {
+	return "SettlementReconciliationJob"
+}
+
+func (j SettlementReconciliationJob) Definition() gocron.JobDefinition {
+	return gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(3, 0, 0)))
+}
+
+func (j SettlementReconciliationJob) Task() any {
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		yesterday := time.Now().UTC().AddDate(0, 0, -1)
+		if err := j.Service.PullDaily(ctx, yesterday); err != nil {
+			j.Logger.Error("Settlement reconciliation failed", zap.Error(err))
+		}
+	}
+}
+
+func (j SettlementReconciliationJob) Params() []any {
+	return nil
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"codematic/internal/domain/signingkeys"
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"go.uber.org/zap"
+)
+
+// SigningKeysJob rotates the JWT signing key when it is due and reloads the
+// key set, so keys created by other instances are picked up before they sign.
+type SigningKeysJob struct {
+	Service signingkeys.Service
+	Logger  *zap.Logger
+}
+
+func (j SigningKeysJob) Name() string {
+	return "SigningKeysJob"
+}
+
+func (j SigningKeysJob) Definition() gocron.JobDefinition {
+	return gocron.DurationJob(signingkeys.RefreshInterval)
+}
+
+func (j SigningKeysJob) Task() any {
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := j.Service.Rotate(ctx); err != nil {
+			j.Logger.Error("Failed to refresh JWT signing keys", zap.Error(err))
+		}
+	}
+}
+
+func (j SigningKeysJob) Params() []any {
+	return nil
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"codematic/internal/domain/exports"
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"go.uber.org/zap"
+)
+
+// TransactionExportsJob generates queued transaction exports and deletes the
+// ones past their retention. Exports are claimed with row locks, so runs on
+// several instances, or overlapping runs, share the queue safely.
+type TransactionExportsJob struct {
+	Service exports.Service
+	Logger  *zap.Logger
+}
+
+func (j TransactionExportsJob) Name() string {
+	return "TransactionExportsJob"
+}
+
+func (j TransactionExportsJob) Definition() gocron.JobDefinition {
+	return gocron.DurationJob(15 * time.Second)
+}
+
+func (j TransactionExportsJob) Task() any {
+	return func() {
+		// Below the time after which a processing export counts as abandoned
+		ctx, cancel := context.WithTimeout(context.Background(), 25*time.Minute)
+		defer cancel()
+
+		if err := j.Service.ProcessPending(ctx); err != nil {
+			j.Logger.Error("Failed to process transaction exports", zap.Error(err))
+		}
+		if err := j.Service.Cleanup(ctx); err != nil {
+			j.Logger.Error("Failed to clean up transaction exports", zap.Error(err))
+		}
+	}
+}
+
+func (j TransactionExportsJob) Params() []any {
+	return nil
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"codematic/internal/domain/statements"
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"go.uber.org/zap"
+)
+
+// WalletStatementsJob stores, and optionally emails, last month's wallet
+// statements early on the first of each month. Statements already stored are
+// skipped, so the job can simply be run again after a failure.
+type WalletStatementsJob struct {
+	Service statements.Service
+	Logger  *zap.Logger
+}
+
+func (j WalletStatementsJob) Name() string {
+	return "WalletStatementsJob"
+}
+
+func (j WalletStatementsJob) Definition() gocron.JobDefinition {
+	return gocron.MonthlyJob(1, gocron.NewDaysOfTheMonth(1), gocron.NewAtTimes(gocron.NewAtTime(2, 0, 0)))
+}
+
+func (j WalletStatementsJob) Task() any {
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 6*time.Hour)
+		defer cancel()
+
+		lastMonth := time.Now().UTC().AddDate(0, 0, -1)
+		if err := j.Service.GenerateMonthly(ctx, lastMonth); err != nil {
+			j.Logger.Error("Failed to generate wallet statements", zap.Error(err))
+		}
+	}
+}
+
+func (j WalletStatementsJob) Params() []any {
+	return nil
+}
@@ -31,7 +31,7 @@ func (sc *Scheduler) RegisterJobs(ctx context.Context, jobs []Job) error {
 	for _, job := range jobs {
 		task := gocron.NewTask(job.Task(), job.Params()...)
 
-		_, err := sc.s.NewJob(job.Definition(), task)
+		_, err := sc.s.NewJob(job.Definition(), task, gocron.WithName(job.Name()))
 		if err != nil {
 			return err
 		}
@@ -43,6 +43,7 @@ func (sc *Scheduler) RegisterJobs(ctx context.Context, jobs []Job) error {
 	_, err := sc.s.NewJob(
 		gocron.DurationJob(1*time.Hour),
 		gocron.NewTask(sc.cleanupMemory),
+		gocron.WithName("MemoryCleanup"),
 	)
 	if err != nil {
 		return err
@@ -51,6 +52,29 @@ func (sc *Scheduler) RegisterJobs(ctx context.Context, jobs []Job) error {
 	return nil
 }
 
+// JobStates returns the current schedule state of every registered job
+func (sc *Scheduler) JobStates() []JobState {
+	jobs := sc.s.Jobs()
+	states := make([]JobState, 0, len(jobs))
+
+	for _, j := range jobs {
+		state := JobState{
+			ID:   j.ID().String(),
+			Name: j.Name(),
+			Tags: j.Tags(),
+		}
+		if lastRun, err := j.LastRun(); err == nil && !lastRun.IsZero() {
+			state.LastRun = &lastRun
+		}
+		if nextRun, err := j.NextRun(); err == nil && !nextRun.IsZero() {
+			state.NextRun = &nextRun
+		}
+		states = append(states, state)
+	}
+
+	return states
+}
+
 // Start the scheduler
 func (sc *Scheduler) Start() {
 	sc.logger.Info("Starting scheduler")
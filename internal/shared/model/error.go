@@ -9,6 +9,26 @@ var (
 	ErrInvalidOrExpiredToken               = errors.New("invalid or expired token")
 	ErrTokenRevoked                        = errors.New("token revoked")
 	ErrSessionNotFound                     = errors.New("session not found")
+	ErrInvalidRefreshToken                 = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused                  = errors.New("refresh token reuse detected; all sessions revoked")
+	ErrInvalidUserToken                    = errors.New("invalid, expired or already used token")
+	ErrEmailNotVerified                    = errors.New("email address not verified")
+	ErrEmailAlreadyVerified                = errors.New("email address already verified")
+	ErrMFAAlreadyEnabled                   = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled                       = errors.New("two-factor authentication not enabled")
+	ErrMFAEnrollmentNotStarted             = errors.New("two-factor enrollment not started")
+	ErrMFARequired                         = errors.New("two-factor authentication is mandatory for this role")
+	ErrInvalidMFACode                      = errors.New("invalid two-factor code")
+	ErrInvalidMFAChallenge                 = errors.New("invalid or expired two-factor challenge")
+	ErrMFAStepUpRequired                   = errors.New("two-factor code required for this operation")
+	ErrAccountLocked                       = errors.New("account temporarily locked after too many failed login attempts")
+	ErrRoleNotFound                        = errors.New("role not found")
+	ErrRoleNameTaken                       = errors.New("a role with this name already exists")
+	ErrInvalidPermission                   = errors.New("invalid permission")
+	ErrInsufficientPermission              = errors.New("insufficient permission")
+	ErrInviteNotFound                      = errors.New("invite not found")
+	ErrInvalidInvite                       = errors.New("invalid, expired or already used invite")
+	ErrUserAlreadyExists                   = errors.New("user already exists")
 	ErrMissingXTenantIDHeader              = errors.New("missing X-Tenant-ID header")
 	ErrInvalidTenantIDFormat               = errors.New("invalid tenant ID format")
 	ErrInvalidInputError                   = errors.New("invalid input")
@@ -18,4 +38,56 @@ var (
 	ErrUnsupportedProvider                 = errors.New("unsupported provider")
 
 	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	ErrInvalidAPIKey      = errors.New("invalid or revoked API key")
+	ErrAPIKeyNotFound     = errors.New("API key not found")
+	ErrInvalidAPIKeyScope = errors.New("invalid API key scope")
+	ErrInvalidAllowedIP   = errors.New("invalid IP or CIDR in allow-list")
+	ErrAPIKeyIPNotAllowed = errors.New("API key not allowed from this IP")
+	ErrInsufficientScope  = errors.New("API key missing required scope")
+
+	ErrImpersonationNotAllowed = errors.New("not allowed to impersonate this user")
+	ErrImpersonationReadOnly   = errors.New("impersonation session is read-only")
+	ErrImpersonationNoMoney    = errors.New("money movement is not allowed while impersonating")
+	ErrNotImpersonating        = errors.New("not an impersonation session")
+
+	ErrIncorrectPassword           = errors.New("incorrect password")
+	ErrTransactionPINRequired      = errors.New("transaction PIN required")
+	ErrTransactionPINNotSet        = errors.New("transaction PIN not set")
+	ErrTransactionPINAlreadySet    = errors.New("transaction PIN already set")
+	ErrInvalidTransactionPIN       = errors.New("invalid transaction PIN")
+	ErrInvalidTransactionPINFormat = errors.New("transaction PIN must be 4 to 6 digits")
+	ErrTransactionPINLocked        = errors.New("too many wrong PINs, try again later")
+
+	ErrInvalidCursor = errors.New("invalid or expired cursor")
+
+	ErrExportNotFound      = errors.New("export not found")
+	ErrExportTooLarge      = errors.New("export too large")
+	ErrInvalidDownloadLink = errors.New("invalid or expired download link")
+
+	ErrWalletNotFound         = errors.New("wallet not found")
+	ErrWalletNotActive        = errors.New("wallet is not active")
+	ErrTransactionNotFound    = errors.New("transaction not found")
+	ErrTransferNotFound       = errors.New("transfer not found")
+	ErrRecipientNotFound      = errors.New("recipient not found")
+	ErrP2PTransfersDisabled   = errors.New("transfers to other users are not enabled for this tenant")
+	ErrInvalidUsername        = errors.New("username must be 3 to 30 letters, digits, dots or underscores, starting with a letter")
+	ErrUsernameTaken          = errors.New("username is already taken")
+	ErrDepositNotFlagged      = errors.New("deposit is not flagged for review")
+	ErrStatementNotFound      = errors.New("statement not found")
+	ErrInvalidStatementPeriod = errors.New("invalid statement period")
+
+	ErrInvalidReport                   = errors.New("invalid provider report")
+	ErrReconciliationRunNotFound       = errors.New("reconciliation run not found")
+	ErrReconciliationExceptionNotFound = errors.New("reconciliation exception not found")
+	ErrExceptionAlreadyClosed          = errors.New("reconciliation exception is already closed")
+
+	ErrPricingPlanNotFound  = errors.New("pricing plan not found")
+	ErrPricingPlanNameTaken = errors.New("a pricing plan with this name already exists")
+	ErrFeeExceedsAmount     = errors.New("fee exceeds the amount")
+
+	ErrTransactionNotReversible = errors.New("transaction cannot be reversed")
+	ErrReversalExceedsOriginal  = errors.New("reversal exceeds what is left of the original transaction")
+	ErrInsufficientBalance      = errors.New("insufficient balance")
+	ErrRefundFailed             = errors.New("provider refund failed")
 )
@@ -1,19 +1,59 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type Claims struct {
 	UserID   string `json:"sub"`
 	Email    string `json:"email"`
 	TenantID string `json:"tenant_id"`
 	Role     string `json:"role"`
+	FamilyID string `json:"fid,omitempty"`
+
+	// Permissions resolved when the token was issued; see HasPermission
+	Permissions []string `json:"perms,omitempty"`
+
+	// Set on support impersonation tokens: the admin acting as the user, and
+	// whether the token may change data and move money. Impersonation tokens
+	// are read-only unless ImpersonationWrite is set.
+	ImpersonatorID     string `json:"imp,omitempty"`
+	ImpersonationWrite bool   `json:"imp_write,omitempty"`
+	ImpersonationMoney bool   `json:"imp_money,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// IsImpersonated reports whether the token was issued to an admin acting as the user
+func (c *Claims) IsImpersonated() bool {
+	return c.ImpersonatorID != ""
+}
+
+// HasPermission checks the token's permissions, falling back to the defaults
+// of its role for tokens issued before permissions were embedded.
+func (c *Claims) HasPermission(perm string) bool {
+	granted := c.Permissions
+	if granted == nil {
+		granted = DefaultPermissions(c.Role)
+	}
+	return HasPermission(granted, perm)
+}
+
 type JWTData struct {
 	UserID   string
 	Email    string
 	TenantID string
 	TokenID  string
 	Role     string
+	FamilyID string
+
+	Permissions []string
+
+	ImpersonatorID     string
+	ImpersonationWrite bool
+	ImpersonationMoney bool
+
+	// ExpiresIn shortens the access token lifetime when set
+	ExpiresIn time.Duration
 }
@@ -0,0 +1,75 @@
+package model
+
+import "strings"
+
+// Permissions are "<resource>:<action>" strings. A role grants a set of them;
+// "<resource>:*" grants every action on a resource and "*" grants everything.
+const (
+	PermAll = "*"
+
+	PermTransactionsRead   = "transactions:read"
+	PermTransactionsRefund = "transactions:refund"
+	PermWalletsRead        = "wallets:read"
+	PermWalletsFreeze      = "wallets:freeze"
+	PermWebhooksManage     = "webhooks:manage"
+	PermUsersRead          = "users:read"
+	PermUsersWrite         = "users:write"
+	PermRolesManage        = "roles:manage"
+	PermAPIKeysManage      = "api_keys:manage"
+	PermTenantsManage      = "tenants:manage"
+	PermAuditRead          = "audit:read"
+	PermDepositsReview     = "deposits:review"
+
+	PermReconciliationManage = "reconciliation:manage"
+	PermPricingManage        = "pricing:manage"
+)
+
+// TenantPermissions are the permissions that can be granted within a tenant,
+// i.e. everything a TENANT_ADMIN holds and custom roles may be built from.
+var TenantPermissions = []string{
+	PermTransactionsRead,
+	PermTransactionsRefund,
+	PermWalletsRead,
+	PermWalletsFreeze,
+	PermWebhooksManage,
+	PermUsersRead,
+	PermUsersWrite,
+	PermRolesManage,
+	PermAPIKeysManage,
+	PermAuditRead,
+	PermDepositsReview,
+}
+
+// DefaultPermissions returns the permissions built into a base role. Regular
+// users hold none: access to their own wallet and transactions is by ownership.
+func DefaultPermissions(role string) []string {
+	switch role {
+	case RolePlatformAdmin.String():
+		return []string{PermAll}
+	case RoleTenantAdmin.String():
+		return TenantPermissions
+	default:
+		return nil
+	}
+}
+
+// IsTenantPermission reports whether perm may be granted by a tenant role
+func IsTenantPermission(perm string) bool {
+	for _, p := range TenantPermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether granted includes perm, directly or by wildcard
+func HasPermission(granted []string, perm string) bool {
+	resource, _, _ := strings.Cut(perm, ":")
+	for _, g := range granted {
+		if g == PermAll || g == perm || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,10 @@ const (
 	RolePlatformAdmin UserRole = "PLATFORM_ADMIN"
 	RoleTenantAdmin   UserRole = "TENANT_ADMIN"
 	RoleUser          UserRole = "USER"
+
+	// RoleAPIKey is the role of requests made with a tenant API key. It holds
+	// no permissions of its own; the key's scopes are its permissions.
+	RoleAPIKey UserRole = "API_KEY"
 )
 
 // String returns the string representation of the UserRole
@@ -10,4 +10,30 @@ type UserSessionInfo struct {
 	LoginTime time.Time `json:"login_time"`
 	LastSeen  time.Time `json:"last_seen"`
 	IsActive  bool      `json:"is_active"`
+	FamilyID  string    `json:"family_id,omitempty"`
+
+	// ImpersonatorID is set on sessions an admin opened as the user
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+}
+
+// RefreshFamily tracks the chain of refresh tokens issued from one login.
+// Only CurrentTokenID may be exchanged; presenting any earlier token in the
+// family is treated as replay.
+type RefreshFamily struct {
+	FamilyID       string    `json:"family_id"`
+	UserID         string    `json:"user_id"`
+	CurrentTokenID string    `json:"current_token_id"`
+	SessionTokenID string    `json:"session_token_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MFAChallenge holds a password-verified login until the second factor is
+// supplied. Enrolled is false when the user must set up TOTP first.
+type MFAChallenge struct {
+	UserID   string          `json:"user_id"`
+	Email    string          `json:"email"`
+	TenantID string          `json:"tenant_id"`
+	Role     string          `json:"role"`
+	Enrolled bool            `json:"enrolled"`
+	Session  UserSessionInfo `json:"session"`
 }
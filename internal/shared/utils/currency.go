@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// minorUnits lists the ISO 4217 currencies whose minor unit is not a
+// hundredth; every other currency has two decimal places.
+var minorUnits = map[string]int32{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// MinorUnits returns the number of decimal places of a currency
+func MinorUnits(currency string) int32 {
+	if units, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return units
+	}
+	return 2
+}
+
+// ToMinorUnits converts a major-unit amount into the currency's smallest
+// unit, e.g. 10.50 NGN into 1050 kobo
+func ToMinorUnits(amount decimal.Decimal, currency string) decimal.Decimal {
+	return amount.Shift(MinorUnits(currency)).Round(0)
+}
+
+// FromMinorUnits converts an amount in the currency's smallest unit back to
+// major units
+func FromMinorUnits(amount int64, currency string) decimal.Decimal {
+	return decimal.New(amount, -MinorUnits(currency))
+}
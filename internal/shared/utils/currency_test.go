@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     int32
+	}{
+		{"JPY", 0},
+		{"jpy", 0},
+		{"KWD", 3},
+		{"NGN", 2},
+		{"USD", 2},
+		{"XYZ", 2},
+	}
+	for _, tt := range tests {
+		if got := MinorUnits(tt.currency); got != tt.want {
+			t.Errorf("MinorUnits(%q) = %d, want %d", tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestToMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     string
+	}{
+		{"10.50", "NGN", "1050"},
+		{"0.01", "USD", "1"},
+		{"1500", "JPY", "1500"},
+		{"1.234", "KWD", "1234"},
+		{"0", "KWD", "0"},
+		// Sub-minor amounts round half away from zero
+		{"10.504", "NGN", "1050"},
+		{"10.505", "NGN", "1051"},
+		{"-10.505", "NGN", "-1051"},
+		{"99.4", "JPY", "99"},
+		{"99.5", "JPY", "100"},
+		{"1.2344", "KWD", "1234"},
+		{"1.2345", "KWD", "1235"},
+		{"0.0004", "KWD", "0"},
+	}
+	for _, tt := range tests {
+		got := ToMinorUnits(decimal.RequireFromString(tt.amount), tt.currency)
+		if !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("ToMinorUnits(%s, %s) = %s, want %s", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFromMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{1050, "NGN", "10.50"},
+		{1, "USD", "0.01"},
+		{1500, "JPY", "1500"},
+		{1234, "KWD", "1.234"},
+		{1, "KWD", "0.001"},
+		{-250, "NGN", "-2.50"},
+		{0, "JPY", "0"},
+	}
+	for _, tt := range tests {
+		got := FromMinorUnits(tt.amount, tt.currency)
+		if !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("FromMinorUnits(%d, %s) = %s, want %s", tt.amount, tt.currency, got, tt.want)
+		}
+		// Converting back must give the same minor amount
+		if back := ToMinorUnits(got, tt.currency); back.IntPart() != tt.amount {
+			t.Errorf("ToMinorUnits(FromMinorUnits(%d, %s)) = %s", tt.amount, tt.currency, back)
+		}
+	}
+}
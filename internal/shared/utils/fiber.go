@@ -40,6 +40,16 @@ func ExtractUserEmailFromJWT(c *fiber.Ctx) string {
 	return claims.Email
 }
 
+// ExtractImpersonatorFromJWT returns the admin acting as the user on an
+// impersonation token, or "" for ordinary tokens
+func ExtractImpersonatorFromJWT(c *fiber.Ctx) string {
+	claims, ok := c.Locals("claims").(*model.Claims)
+	if !ok || claims == nil {
+		return ""
+	}
+	return claims.ImpersonatorID
+}
+
 // HasRole checks if the user has the specified role
 func HasRole(c *fiber.Ctx, requiredRole model.UserRole) bool {
 	userRole := ExtractUserRoleFromJWT(c)
@@ -57,6 +67,12 @@ func HasAnyRole(c *fiber.Ctx, requiredRoles ...model.UserRole) bool {
 	return false
 }
 
+// HasPermission checks the permissions carried in the request's JWT claims
+func HasPermission(c *fiber.Ctx, perm string) bool {
+	claims, ok := c.Locals("claims").(*model.Claims)
+	return ok && claims != nil && claims.HasPermission(perm)
+}
+
 // RequireRole middleware function to check if user has required role
 func RequireRole(requiredRole model.UserRole) fiber.Handler {
 	return func(c *fiber.Ctx) error {
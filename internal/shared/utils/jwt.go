@@ -3,41 +3,121 @@ package utils
 import (
 	"codematic/internal/shared/model"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// JWTManager signs access tokens with the active asymmetric signing key and
+// verifies them against every published key, selected by the token's kid.
+// Refresh tokens never leave the auth flow and stay HMAC signed.
 type JWTManager struct {
-	JWTSecret          []byte
 	RefreshTokenSecret []byte
+	AccessTokenExpiry  time.Duration
+	RefreshTokenExpiry time.Duration
+
+	mu   sync.RWMutex
+	keys []*SigningKey
 }
 
-func NewJWTManager(jwtSecret, refreshTokenSecret string) *JWTManager {
+func NewJWTManager(refreshTokenSecret string, accessTokenExpiry,
+	refreshTokenExpiry time.Duration) *JWTManager {
 	return &JWTManager{
-		JWTSecret:          []byte(jwtSecret),
 		RefreshTokenSecret: []byte(refreshTokenSecret),
+		AccessTokenExpiry:  accessTokenExpiry,
+		RefreshTokenExpiry: refreshTokenExpiry,
+	}
+}
+
+// SetKeys replaces the key set used for signing and verification
+func (j *JWTManager) SetKeys(keys []*SigningKey) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.keys = keys
+}
+
+// signingKey returns the newest key that has activated and not expired
+func (j *JWTManager) signingKey(now time.Time) *SigningKey {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var active *SigningKey
+	for _, k := range j.keys {
+		if k.PrivateKey == nil || !k.usable(now) || k.ActivatesAt.After(now) {
+			continue
+		}
+		if active == nil || k.ActivatesAt.After(active.ActivatesAt) {
+			active = k
+		}
 	}
+	return active
 }
 
-const SessionExpiry = 7 * 24 * time.Hour // 7 days
+// verificationKey returns the unexpired key with the given kid, including keys
+// published ahead of their activation.
+func (j *JWTManager) verificationKey(kid string, now time.Time) *SigningKey {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	for _, k := range j.keys {
+		if k.ID == kid && k.usable(now) {
+			return k
+		}
+	}
+	return nil
+}
+
+// JWKS returns the public half of every unexpired key
+func (j *JWTManager) JWKS() JWKSet {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	now := time.Now()
+	set := JWKSet{Keys: make([]JWK, 0, len(j.keys))}
+	for _, k := range j.keys {
+		if !k.usable(now) {
+			continue
+		}
+		if jwk, err := k.JWK(); err == nil {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
 
 func (j *JWTManager) GenerateJWT(data model.JWTData) (string, error) {
+	now := time.Now()
+	key := j.signingKey(now)
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	expiry := j.AccessTokenExpiry
+	if data.ExpiresIn > 0 && data.ExpiresIn < expiry {
+		expiry = data.ExpiresIn
+	}
+
 	claims := model.Claims{
-		UserID:   data.UserID,
-		Email:    data.Email,
-		TenantID: data.TenantID,
-		Role:     data.Role,
+		UserID:             data.UserID,
+		Email:              data.Email,
+		TenantID:           data.TenantID,
+		Role:               data.Role,
+		Permissions:        data.Permissions,
+		ImpersonatorID:     data.ImpersonatorID,
+		ImpersonationWrite: data.ImpersonationWrite,
+		ImpersonationMoney: data.ImpersonationMoney,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   data.UserID,
 			ID:        data.TokenID,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(SessionExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.JWTSecret)
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
 }
 
 func (j *JWTManager) GenerateRefreshToken(data model.JWTData) (string, error) {
@@ -46,10 +126,11 @@ func (j *JWTManager) GenerateRefreshToken(data model.JWTData) (string, error) {
 		Email:    data.Email,
 		TenantID: data.TenantID,
 		Role:     data.Role,
+		FamilyID: data.FamilyID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   data.UserID,
 			ID:        data.TokenID,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(SessionExpiry)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.RefreshTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -60,8 +141,17 @@ func (j *JWTManager) GenerateRefreshToken(data model.JWTData) (string, error) {
 
 func (j *JWTManager) ParseJWT(tokenStr string) (*model.Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &model.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return j.JWTSecret, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key := j.verificationKey(kid, time.Now())
+		if key == nil {
+			return nil, ErrUnknownSigningKey
+		}
+		// A key only verifies tokens of its own algorithm
+		if token.Method.Alg() != key.Algorithm {
+			return nil, ErrUnknownSigningKey
+		}
+		return key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{AlgorithmEdDSA, AlgorithmRS256}))
 
 	if err != nil {
 		return nil, err
@@ -76,7 +166,7 @@ func (j *JWTManager) ParseJWT(tokenStr string) (*model.Claims, error) {
 func (j *JWTManager) ParseRefreshToken(tokenStr string) (*model.Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &model.Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return j.RefreshTokenSecret, nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
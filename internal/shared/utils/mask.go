@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaskEmail keeps the first character of the local part and the domain,
+// e.g. jane.doe@example.com -> j*******@example.com
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return maskAll(email)
+	}
+	return local[:1] + strings.Repeat("*", len(local)-1) + "@" + domain
+}
+
+// MaskPhone keeps only the last four digits, e.g. +2348012345678 -> +*********5678
+func MaskPhone(phone string) string {
+	return maskDigits(phone, 0, 4)
+}
+
+// MaskCard keeps the BIN and last four digits of a card number (PCI DSS),
+// e.g. 4084084084084081 -> 408408******4081
+func MaskCard(pan string) string {
+	digits := 0
+	for _, r := range pan {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if digits < 12 {
+		return maskDigits(pan, 0, 4)
+	}
+	return maskDigits(pan, 6, 4)
+}
+
+// IsLuhnValid reports whether a digit string passes the Luhn checksum
+func IsLuhnValid(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// maskDigits replaces every digit except the first `head` and last `tail` digits
+func maskDigits(value string, head, tail int) string {
+	total := 0
+	for _, r := range value {
+		if unicode.IsDigit(r) {
+			total++
+		}
+	}
+	if total <= head+tail {
+		return maskAll(value)
+	}
+
+	var b strings.Builder
+	seen := 0
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			b.WriteRune(r)
+			continue
+		}
+		if seen < head || seen >= total-tail {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('*')
+		}
+		seen++
+	}
+	return b.String()
+}
+
+func maskAll(value string) string {
+	return strings.Repeat("*", len(value))
+}
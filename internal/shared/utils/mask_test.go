@@ -0,0 +1,74 @@
+package utils
+
+import "testing"
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"jane.doe@example.com", "j*******@example.com"},
+		{"a@example.com", "a@example.com"},
+		{"not-an-email", "************"},
+		{"@example.com", "************"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := MaskEmail(tt.in); got != tt.want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"+2348012345678", "+*********5678"},
+		{"0801 234 5678", "**** *** 5678"},
+		{"5678", "****"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := MaskPhone(tt.in); got != tt.want {
+			t.Errorf("MaskPhone(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMaskCard(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"4084084084084081", "408408******4081"},
+		{"4084 0840 8408 4081", "4084 08** **** 4081"},
+		{"5061460410120223210", "506146*********3210"},
+		{"12345678", "****5678"},
+		{"1234", "****"},
+	}
+	for _, tt := range tests {
+		if got := MaskCard(tt.in); got != tt.want {
+			t.Errorf("MaskCard(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsLuhnValid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"4084084084084081", true},
+		{"4111111111111111", true},
+		{"79927398713", true},
+		{"4084084084084082", false},
+		{"1234567890123456", false},
+		{"4111-1111-1111-1111", false},
+		{"0", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsLuhnValid(tt.in); got != tt.want {
+			t.Errorf("IsLuhnValid(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page layout of TextPDF: US Letter, 9pt Courier
+const (
+	pdfPageWidth  = 612
+	pdfPageHeight = 792
+	pdfMargin     = 40
+	pdfFontSize   = 9
+	pdfLeading    = 11
+
+	// Lines that fit between the margins, less two for the page footer
+	pdfLinesPerPage = (pdfPageHeight-2*pdfMargin)/pdfLeading - 2
+
+	// PDFLineWidth is the number of characters that fit on a TextPDF line
+	PDFLineWidth = 95
+)
+
+// TextPDF builds a plain PDF of monospaced text lines, paginated
+// automatically with a page number footer. Monospacing keeps columns laid out
+// with padded strings aligned. Characters outside ASCII are replaced with "?".
+type TextPDF struct {
+	pages [][]string
+}
+
+func NewTextPDF() *TextPDF {
+	return &TextPDF{pages: [][]string{nil}}
+}
+
+// Line adds a line of text, cut at PDFLineWidth characters
+func (p *TextPDF) Line(text string) {
+	if len(p.pages[len(p.pages)-1]) >= pdfLinesPerPage {
+		p.PageBreak()
+	}
+	if len(text) > PDFLineWidth {
+		text = text[:PDFLineWidth]
+	}
+	last := len(p.pages) - 1
+	p.pages[last] = append(p.pages[last], text)
+}
+
+// PageBreak starts a new page
+func (p *TextPDF) PageBreak() {
+	p.pages = append(p.pages, nil)
+}
+
+// WriteTo renders the document
+func (p *TextPDF) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-3 are the catalog, page tree and font; each page then takes
+	// a page object and a content stream
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLeading,
+			pdfMargin, pdfPageHeight-pdfMargin-pdfFontSize)
+		for _, line := range lines {
+			content.WriteString("(" + pdfEscape(line) + ") Tj T*\n")
+		}
+		content.WriteString("ET\n")
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", pdfFontSize, pdfMargin, pdfMargin,
+			pdfEscape(fmt.Sprintf("Page %d of %d", i+1, len(p.pages))))
+
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+
+	rsaKeyBits = 2048
+)
+
+var (
+	ErrNoSigningKey             = errors.New("no active JWT signing key")
+	ErrUnknownSigningKey        = errors.New("unknown JWT signing key")
+	ErrUnsupportedSigningMethod = errors.New("unsupported JWT signing algorithm")
+)
+
+// SigningKey is an access token key pair identified by ID (the JWT kid). A key
+// is published from creation, signs from ActivatesAt and stops verifying at
+// ExpiresAt; a zero ExpiresAt means it has not been superseded.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	PrivateKey  crypto.Signer
+	PublicKey   crypto.PublicKey
+	ActivatesAt time.Time
+	ExpiresAt   time.Time
+}
+
+func (k *SigningKey) usable(now time.Time) bool {
+	return k.ExpiresAt.IsZero() || k.ExpiresAt.After(now)
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// GenerateSigningKey creates a key pair for algorithm with a random kid
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	var signer crypto.Signer
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		signer = priv
+	case AlgorithmRS256:
+		priv, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		signer = priv
+	default:
+		return nil, ErrUnsupportedSigningMethod
+	}
+
+	kid := make([]byte, 16)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:         hex.EncodeToString(kid),
+		Algorithm:  algorithm,
+		PrivateKey: signer,
+		PublicKey:  signer.Public(),
+	}, nil
+}
+
+// MarshalPrivateKeyPEM encodes a private key as PKCS#8 PEM
+func MarshalPrivateKeyPEM(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// ParsePrivateKeyPEM decodes a PKCS#8 PEM private key
+func ParsePrivateKeyPEM(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid private key PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedSigningMethod
+	}
+	return signer, nil
+}
+
+// JWK is the public part of a signing key in RFC 7517 form
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *SigningKey) JWK() (JWK, error) {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Algorithm}
+
+	switch pub := k.PublicKey.(type) {
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	default:
+		return JWK{}, ErrUnsupportedSigningMethod
+	}
+	return jwk, nil
+}
@@ -0,0 +1,16 @@
+package utils
+
+// SpreadsheetText neutralises text a spreadsheet would evaluate as a formula,
+// i.e. text starting with =, +, -, @, a tab or a carriage return, by
+// prefixing it with a quote. Use it on user-controlled text written to CSV or
+// XLSX files so opening them cannot run formulas such as =HYPERLINK(...).
+func SpreadsheetText(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
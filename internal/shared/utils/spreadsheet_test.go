@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestSpreadsheetText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`=HYPERLINK("http://evil.example","click")`, `'=HYPERLINK("http://evil.example","click")`},
+		{"+2348012345678", "'+2348012345678"},
+		{"-1+1", "'-1+1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"ref-123", "ref-123"},
+		{"a=b", "a=b"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := SpreadsheetText(tt.in); got != tt.want {
+			t.Errorf("SpreadsheetText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
@@ -89,10 +90,13 @@ func (bc *BaseClient) MakeRequest(method, url string, body interface{}, headers
 		zap.String("body", serializedBody),
 	}
 
-	// Log headers individually
-	for key, value := range headers {
-		logFields = append(logFields, zap.String(fmt.Sprintf("header:%s", key), value))
+	// Log header names only; values carry provider credentials
+	headerNames := make([]string, 0, len(headers))
+	for key := range headers {
+		headerNames = append(headerNames, key)
 	}
+	sort.Strings(headerNames)
+	logFields = append(logFields, zap.Strings("headers", headerNames))
 
 	bc.Logger.Info("Making HTTP request", logFields...)
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"time"
 
 	"go.uber.org/zap"
@@ -85,6 +86,95 @@ func (c *Client) VerifyPayment(txID int) (*VerifyPaymentResponse, error) {
 	return &out, nil
 }
 
+// VerifyPaymentByReference looks a payment up by the tx_ref it was initialized with
+func (c *Client) VerifyPaymentByReference(txRef string) (*VerifyPaymentResponse, error) {
+	url := fmt.Sprintf("%s/transactions/verify_by_reference?tx_ref=%s", c.baseURL, neturl.QueryEscape(txRef))
+
+	resp, err := c.client.MakeRequest("GET", url, nil, c.authHeaders())
+	if err != nil {
+		c.logger.Error("verify payment by reference request failed", zap.Error(err))
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read verify response failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("verify payment error: %s", string(body))
+	}
+
+	var out VerifyPaymentResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal verify response: %w", err)
+	}
+
+	return &out, nil
+}
+
+// RefundPayment refunds amount, in major units, of the payment with ID txID
+func (c *Client) RefundPayment(txID int, amount float64) (*RefundResponse, error) {
+	url := fmt.Sprintf("%s/transactions/%d/refund", c.baseURL, txID)
+
+	resp, err := c.client.MakeRequest("POST", url, map[string]float64{"amount": amount}, c.authHeaders())
+	if err != nil {
+		c.logger.Error("refund payment request failed", zap.Error(err))
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read refund response failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("refund payment error: %s", string(body))
+	}
+
+	var out RefundResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal refund response: %w", err)
+	}
+
+	return &out, nil
+}
+
+// ListTransactions returns one page of the transactions made between the from
+// and to dates, both inclusive
+func (c *Client) ListTransactions(from, to time.Time, page int) (*ListTransactionsResponse, error) {
+	url := fmt.Sprintf("%s/transactions?from=%s&to=%s&page=%d", c.baseURL,
+		from.UTC().Format(time.DateOnly), to.UTC().Format(time.DateOnly), page)
+
+	resp, err := c.client.MakeRequest("GET", url, nil, c.authHeaders())
+	if err != nil {
+		c.logger.Error("list transactions request failed", zap.Error(err))
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read list response failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list transactions error: %s", string(body))
+	}
+
+	var out ListTransactionsResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal list response: %w", err)
+	}
+
+	return &out, nil
+}
+
 func (c *Client) authHeaders() map[string]string {
 	return map[string]string{
 		"Authorization": "Bearer " + c.secret,
@@ -65,3 +65,30 @@ type VerifyTransactionResponse struct {
 		TxRef    string `json:"tx_ref"`
 	} `json:"data"`
 }
+
+type ListTransactionsResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Data    []struct {
+		TxRef     string  `json:"tx_ref"`
+		Amount    float64 `json:"amount"`
+		Currency  string  `json:"currency"`
+		Status    string  `json:"status"`
+		CreatedAt string  `json:"created_at"`
+	} `json:"data"`
+	Meta struct {
+		PageInfo struct {
+			CurrentPage int `json:"current_page"`
+			TotalPages  int `json:"total_pages"`
+		} `json:"page_info"`
+	} `json:"meta"`
+}
+
+type RefundResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Data    struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	} `json:"data"`
+}
@@ -7,6 +7,7 @@ const (
 type InitializeTransactionRequest struct {
 	Email    string                 `json:"email"`
 	Amount   string                 `json:"amount"`
+	Currency string                 `json:"currency,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -31,3 +32,38 @@ type VerifyTransactionResponse struct {
 		// Add other fields as needed
 	} `json:"data"`
 }
+
+type ListTransactionsResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    []struct {
+		Reference string `json:"reference"`
+		Amount    int64  `json:"amount"`
+		Currency  string `json:"currency"`
+		Status    string `json:"status"`
+		PaidAt    string `json:"paid_at"`
+	} `json:"data"`
+	Meta struct {
+		Page      int `json:"page"`
+		PageCount int `json:"pageCount"`
+	} `json:"meta"`
+}
+
+// CreateRefundRequest refunds Amount, in minor units, of the transaction with
+// reference Transaction. An empty Amount refunds it in full.
+type CreateRefundRequest struct {
+	Transaction  string `json:"transaction"`
+	Amount       string `json:"amount,omitempty"`
+	Currency     string `json:"currency,omitempty"`
+	MerchantNote string `json:"merchant_note,omitempty"`
+}
+
+type CreateRefundResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    struct {
+		ID     int64  `json:"id"`
+		Status string `json:"status"`
+		Amount int64  `json:"amount"`
+	} `json:"data"`
+}
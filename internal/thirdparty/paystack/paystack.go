@@ -91,6 +91,64 @@ func (c *Client) VerifyTransaction(reference string) (*VerifyTransactionResponse
 	return &verifyResp, nil
 }
 
+// ListTransactions returns one page of the transactions created in [from, to)
+func (c *Client) ListTransactions(from, to time.Time, page, perPage int) (*ListTransactionsResponse, error) {
+	url := fmt.Sprintf("%s/transaction?from=%s&to=%s&page=%d&perPage=%d", c.baseURL,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339), page, perPage)
+
+	resp, err := c.MakeRequest(http.MethodGet, url, nil, c.authHeaders())
+	if err != nil {
+		c.logger.Error("list transactions request failed", zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := readResponseBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list transactions failed: %s", string(bodyBytes))
+	}
+
+	var listResp ListTransactionsResponse
+	if err := json.Unmarshal(bodyBytes, &listResp); err != nil {
+		return nil, fmt.Errorf("unmarshal list response failed: %w", err)
+	}
+
+	return &listResp, nil
+}
+
+// CreateRefund refunds all or part of a successful transaction to the
+// customer. Paystack processes refunds asynchronously.
+func (c *Client) CreateRefund(req *CreateRefundRequest) (*CreateRefundResponse, error) {
+	url := fmt.Sprintf("%s/refund", c.baseURL)
+
+	resp, err := c.MakeRequest(http.MethodPost, url, req, c.authHeaders())
+	if err != nil {
+		c.logger.Error("create refund request failed", zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := readResponseBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("create refund failed: %s", string(bodyBytes))
+	}
+
+	var refundResp CreateRefundResponse
+	if err := json.Unmarshal(bodyBytes, &refundResp); err != nil {
+		return nil, fmt.Errorf("unmarshal refund response failed: %w", err)
+	}
+
+	return &refundResp, nil
+}
+
 func (c *Client) authHeaders() map[string]string {
 	return map[string]string{
 		"Authorization": "Bearer " + c.apiKey,